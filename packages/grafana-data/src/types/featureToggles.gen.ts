@@ -118,4 +118,5 @@ export interface FeatureToggles {
   azureMonitorDataplane?: boolean;
   prometheusConfigOverhaulAuth?: boolean;
   configurableSchedulerTick?: boolean;
+  backendQuerySplitting?: boolean;
 }
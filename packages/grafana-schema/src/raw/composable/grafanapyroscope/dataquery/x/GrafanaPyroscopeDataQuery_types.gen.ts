@@ -13,11 +13,19 @@ import * as common from '@grafana/schema';
 
 export const pluginVersion = "10.2.0-pre";
 
-export type PhlareQueryType = ('metrics' | 'profile' | 'both');
+export type PhlareQueryType = ('metrics' | 'profile' | 'both' | 'diff');
 
 export const defaultPhlareQueryType: PhlareQueryType = 'both';
 
 export interface GrafanaPyroscopeDataQuery extends common.DataQuery {
+  /**
+   * Specifies the label selectors of the baseline profile of a diff query. Defaults to the label selectors of the query.
+   */
+  baselineLabelSelector?: string;
+  /**
+   * Shifts the time range of the baseline profile of a diff query back by the given duration, e.g. 1d.
+   */
+  baselineTimeShift?: string;
   /**
    * Allows to group the results.
    */
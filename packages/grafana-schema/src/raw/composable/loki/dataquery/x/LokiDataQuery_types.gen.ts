@@ -20,8 +20,10 @@ export enum QueryEditorMode {
 
 export enum LokiQueryType {
   Instant = 'instant',
+  Patterns = 'patterns',
   Range = 'range',
   Stream = 'stream',
+  Volume = 'volume',
 }
 
 export enum SupportingQueryType {
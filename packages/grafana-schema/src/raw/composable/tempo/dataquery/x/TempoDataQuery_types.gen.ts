@@ -51,6 +51,10 @@ export interface TempoQuery extends common.DataQuery {
    * @deprecated Query traces by span name
    */
   spanName?: string;
+  /**
+   * For TraceQL metrics queries, the step of the returned time series. Use duration format, for example: 30s, 1m
+   */
+  step?: string;
 }
 
 export const defaultTempoQuery: Partial<TempoQuery> = {
@@ -60,7 +64,7 @@ export const defaultTempoQuery: Partial<TempoQuery> = {
 /**
  * search = Loki search, nativeSearch = Tempo search for backwards compatibility
  */
-export type TempoQueryType = ('traceql' | 'traceqlSearch' | 'search' | 'serviceMap' | 'upload' | 'nativeSearch' | 'traceId' | 'clear');
+export type TempoQueryType = ('traceql' | 'traceqlSearch' | 'traceqlMetrics' | 'search' | 'serviceMap' | 'serviceMapFromTraces' | 'upload' | 'nativeSearch' | 'traceId' | 'clear');
 
 /**
  * The state of the TraceQL streaming search query
@@ -0,0 +1,309 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /annotations/webhooks annotations getAnnotationWebhooks
+//
+// Get annotation webhooks.
+//
+// Returns the annotation webhooks of the organization. The secrets of the webhooks are never returned.
+//
+// Responses:
+// 200: getAnnotationWebhooksResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetAnnotationWebhooks(c *contextmodel.ReqContext) response.Response {
+	webhooks, err := hs.annotationWebhooks.GetWebhooks(c.Req.Context(), &annotationwebhooks.GetWebhooksQuery{OrgID: c.OrgID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotation webhooks", err)
+	}
+
+	result := make([]*annotationwebhooks.WebhookDTO, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		result = append(result, webhook.ToDTO(hs.Cfg.AppURL))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route GET /annotations/webhooks/{webhook_uid} annotations getAnnotationWebhook
+//
+// Get annotation webhook by UID.
+//
+// Responses:
+// 200: annotationWebhookResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetAnnotationWebhook(c *contextmodel.ReqContext) response.Response {
+	webhook, err := hs.annotationWebhooks.GetWebhook(c.Req.Context(), &annotationwebhooks.GetWebhookQuery{OrgID: c.OrgID, UID: web.Params(c.Req)[":uid"]})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotation webhook", err)
+	}
+	return response.JSON(http.StatusOK, webhook.ToDTO(hs.Cfg.AppURL))
+}
+
+// swagger:route POST /annotations/webhooks annotations createAnnotationWebhook
+//
+// Create annotation webhook.
+//
+// Creates a webhook which turns the JSON events sent to its URL into annotations with its mapping template. The annotations are created by the service account of the webhook, so it requires the permission to update the service account.
+// Events are authenticated with a token of the service account, or with the secret of the webhook: either as the HMAC SHA-256 signature of the payload in the `X-Hub-Signature-256` header, or as is in the `X-Gitlab-Token` header.
+//
+// Responses:
+// 200: annotationWebhookResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) CreateAnnotationWebhook(c *contextmodel.ReqContext) response.Response {
+	cmd := annotationwebhooks.CreateWebhookCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+
+	if rsp := hs.checkAnnotationWebhookServiceAccount(c, cmd.ServiceAccountID); rsp != nil {
+		return rsp
+	}
+
+	webhook, err := hs.annotationWebhooks.CreateWebhook(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create annotation webhook", err)
+	}
+	return response.JSON(http.StatusOK, webhook.ToDTO(hs.Cfg.AppURL))
+}
+
+// swagger:route PUT /annotations/webhooks/{webhook_uid} annotations updateAnnotationWebhook
+//
+// Update annotation webhook.
+//
+// The secret of the webhook is kept if it is not part of the request, and removed if it is empty. It requires the permission to update the service accounts currently bound to the webhook and in the request.
+//
+// Responses:
+// 200: annotationWebhookResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateAnnotationWebhook(c *contextmodel.ReqContext) response.Response {
+	cmd := annotationwebhooks.UpdateWebhookCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.UID = web.Params(c.Req)[":uid"]
+
+	// the user must be allowed to update the service account currently bound to the webhook, and the new one
+	webhook, err := hs.annotationWebhooks.GetWebhook(c.Req.Context(), &annotationwebhooks.GetWebhookQuery{OrgID: c.OrgID, UID: cmd.UID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotation webhook", err)
+	}
+	if rsp := hs.checkAnnotationWebhookServiceAccount(c, webhook.ServiceAccountID); rsp != nil {
+		return rsp
+	}
+	if rsp := hs.checkAnnotationWebhookServiceAccount(c, cmd.ServiceAccountID); rsp != nil {
+		return rsp
+	}
+
+	webhook, err = hs.annotationWebhooks.UpdateWebhook(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update annotation webhook", err)
+	}
+	return response.JSON(http.StatusOK, webhook.ToDTO(hs.Cfg.AppURL))
+}
+
+// swagger:route DELETE /annotations/webhooks/{webhook_uid} annotations deleteAnnotationWebhook
+//
+// Delete annotation webhook.
+//
+// The annotations created by the webhook are kept. It requires the permission to update the service account bound to the webhook.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteAnnotationWebhook(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	webhook, err := hs.annotationWebhooks.GetWebhook(c.Req.Context(), &annotationwebhooks.GetWebhookQuery{OrgID: c.OrgID, UID: uid})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotation webhook", err)
+	}
+	if rsp := hs.checkAnnotationWebhookServiceAccount(c, webhook.ServiceAccountID); rsp != nil {
+		return rsp
+	}
+
+	err = hs.annotationWebhooks.DeleteWebhook(c.Req.Context(), &annotationwebhooks.DeleteWebhookCommand{OrgID: c.OrgID, UID: uid})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete annotation webhook", err)
+	}
+	return response.Success("Annotation webhook deleted")
+}
+
+// swagger:route POST /annotations/webhooks/{webhook_uid}/events annotations postAnnotationWebhookEvent
+//
+// Send an event to an annotation webhook.
+//
+// Creates an annotation from the JSON payload with the mapping template of the webhook. The request must be sent by the service account of the webhook, with a token or signed with the secret of the webhook.
+// The event is ignored, and no annotation is created, if the condition of the template is false.
+//
+// Responses:
+// 200: postAnnotationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) PostAnnotationWebhookEvent(c *contextmodel.ReqContext) response.Response {
+	webhook, err := hs.annotationWebhooks.GetWebhook(c.Req.Context(), &annotationwebhooks.GetWebhookQuery{OrgID: c.OrgID, UID: web.Params(c.Req)[":uid"]})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotation webhook", err)
+	}
+
+	// the annotations of a webhook are always created by its service account
+	if !c.SignedInUser.IsServiceAccount || c.UserID != webhook.ServiceAccountID {
+		return response.Error(http.StatusForbidden, "Events must be sent by the service account of the webhook", nil)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Req.Body, annotationwebhooks.MaxPayloadBytes+1))
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to read the event", err)
+	}
+	if len(body) > annotationwebhooks.MaxPayloadBytes {
+		return response.Err(annotationwebhooks.ErrWebhookPayloadTooLarge.Errorf("payload is larger than %d bytes", annotationwebhooks.MaxPayloadBytes))
+	}
+
+	mapping, err := webhook.Template.Compile()
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to compile the template of the webhook", err)
+	}
+	payload, err := annotationwebhooks.ParsePayload(body)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Failed to parse the event", err)
+	}
+	annotation, err := mapping.Render(payload)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Failed to map the event to an annotation", err)
+	}
+	if annotation == nil {
+		return response.Success("Event ignored")
+	}
+
+	var dashboardID int64
+	if annotation.DashboardUID != "" {
+		query := dashboards.GetDashboardQuery{OrgID: c.OrgID, UID: annotation.DashboardUID}
+		queryResult, err := hs.DashboardService.GetDashboard(c.Req.Context(), &query)
+		if err != nil {
+			return response.ErrOrFallback(http.StatusNotFound, "Dashboard not found", err)
+		}
+		dashboardID = queryResult.ID
+	}
+
+	if canSave, err := hs.canCreateAnnotation(c, dashboardID); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	item := annotations.Item{
+		OrgID:       c.OrgID,
+		UserID:      c.UserID,
+		DashboardID: dashboardID,
+		PanelID:     annotation.PanelID,
+		Epoch:       annotation.Time,
+		EpochEnd:    annotation.TimeEnd,
+		Text:        annotation.Text,
+		Tags:        annotation.Tags,
+	}
+
+	if err := hs.annotationsRepo.Save(c.Req.Context(), &item); err != nil {
+		if errors.Is(err, annotations.ErrTimerangeMissing) {
+			return response.Error(http.StatusBadRequest, "Failed to save annotation", err)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save annotation", err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"message": "Annotation added",
+		"id":      item.ID,
+	})
+}
+
+// checkAnnotationWebhookServiceAccount checks that the user can bind the service account to a webhook, or change a
+// webhook bound to it: the events of the webhook are handled with the permissions of the service account, so the user
+// must be allowed to update it.
+func (hs *HTTPServer) checkAnnotationWebhookServiceAccount(c *contextmodel.ReqContext, serviceAccountID int64) response.Response {
+	scope := accesscontrol.Scope("serviceaccounts", "id", strconv.FormatInt(serviceAccountID, 10))
+	canWrite, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, accesscontrol.EvalPermission(serviceaccounts.ActionWrite, scope))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to check service account permissions", err)
+	}
+	if !canWrite {
+		return response.Error(http.StatusForbidden, "Permission denied to bind the service account to the webhook", nil)
+	}
+	return nil
+}
+
+// swagger:parameters getAnnotationWebhook deleteAnnotationWebhook
+type AnnotationWebhookParams struct {
+	// in:path
+	// required:true
+	WebhookUID string `json:"webhook_uid"`
+}
+
+// swagger:parameters createAnnotationWebhook
+type CreateAnnotationWebhookParams struct {
+	// in:body
+	// required:true
+	Body annotationwebhooks.CreateWebhookCommand `json:"body"`
+}
+
+// swagger:parameters updateAnnotationWebhook
+type UpdateAnnotationWebhookParams struct {
+	// in:path
+	// required:true
+	WebhookUID string `json:"webhook_uid"`
+	// in:body
+	// required:true
+	Body annotationwebhooks.UpdateWebhookCommand `json:"body"`
+}
+
+// swagger:parameters postAnnotationWebhookEvent
+type PostAnnotationWebhookEventParams struct {
+	// in:path
+	// required:true
+	WebhookUID string `json:"webhook_uid"`
+	// The JSON payload of the event.
+	// in:body
+	// required:true
+	Body map[string]interface{} `json:"body"`
+}
+
+// swagger:response getAnnotationWebhooksResponse
+type GetAnnotationWebhooksResponse struct {
+	// in: body
+	Body []*annotationwebhooks.WebhookDTO `json:"body"`
+}
+
+// swagger:response annotationWebhookResponse
+type AnnotationWebhookResponse struct {
+	// in: body
+	Body *annotationwebhooks.WebhookDTO `json:"body"`
+}
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks"
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks/annotationwebhookstest"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_CreateAnnotationWebhook(t *testing.T) {
+	type testCase struct {
+		desc         string
+		permissions  []accesscontrol.Permission
+		expectedCode int
+	}
+
+	tests := []testCase{
+		{
+			desc: "should create the webhook when the user can update the service account",
+			permissions: []accesscontrol.Permission{
+				{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsAll},
+				{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:2"},
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc: "should not create the webhook when the user cannot update the service account",
+			permissions: []accesscontrol.Permission{
+				{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsAll},
+				{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:3"},
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not create the webhook without permission to create annotations",
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:2"}},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			webhookService := annotationwebhookstest.NewFakeAnnotationWebhookService()
+			webhookService.ExpectedWebhook = &annotationwebhooks.Webhook{UID: "abc", OrgID: 1, ServiceAccountID: 2}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.annotationWebhooks = webhookService
+				hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+			})
+
+			body := strings.NewReader(`{"name": "ci", "serviceAccountId": 2, "secret": "s3cr3t", "template": {"text": "Deployed {{ .ref }}"}}`)
+			req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodPost, "/api/annotations/webhooks", body), userWithPermissions(1, tt.permissions))
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+func TestAPI_UpdateAndDeleteAnnotationWebhook(t *testing.T) {
+	type testCase struct {
+		desc         string
+		method       string
+		permissions  []accesscontrol.Permission
+		expectedCode int
+	}
+
+	tests := []testCase{
+		{
+			desc:   "should update the webhook when the user can update both service accounts",
+			method: http.MethodPut,
+			permissions: []accesscontrol.Permission{
+				{Action: accesscontrol.ActionAnnotationsWrite, Scope: accesscontrol.ScopeAnnotationsAll},
+				{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:2"},
+				{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:3"},
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:   "should not update the webhook when the user cannot update its current service account",
+			method: http.MethodPut,
+			permissions: []accesscontrol.Permission{
+				{Action: accesscontrol.ActionAnnotationsWrite, Scope: accesscontrol.ScopeAnnotationsAll},
+				{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:3"},
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:   "should not update the webhook with the permission to create annotations only",
+			method: http.MethodPut,
+			permissions: []accesscontrol.Permission{
+				{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsAll},
+				{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:*"},
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:   "should delete the webhook when the user can update its service account",
+			method: http.MethodDelete,
+			permissions: []accesscontrol.Permission{
+				{Action: accesscontrol.ActionAnnotationsDelete, Scope: accesscontrol.ScopeAnnotationsAll},
+				{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:2"},
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:   "should not delete the webhook when the user cannot update its service account",
+			method: http.MethodDelete,
+			permissions: []accesscontrol.Permission{
+				{Action: accesscontrol.ActionAnnotationsDelete, Scope: accesscontrol.ScopeAnnotationsAll},
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:   "should not delete the webhook with the permission to create annotations only",
+			method: http.MethodDelete,
+			permissions: []accesscontrol.Permission{
+				{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsAll},
+				{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:*"},
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			webhookService := annotationwebhookstest.NewFakeAnnotationWebhookService()
+			webhookService.ExpectedWebhook = &annotationwebhooks.Webhook{UID: "abc", OrgID: 1, ServiceAccountID: 2}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.annotationWebhooks = webhookService
+				hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+			})
+
+			body := strings.NewReader(`{"name": "ci", "serviceAccountId": 3, "template": {"text": "Deployed {{ .ref }}"}}`)
+			req := webtest.RequestWithSignedInUser(server.NewRequest(tt.method, "/api/annotations/webhooks/abc", body), userWithPermissions(1, tt.permissions))
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+func TestAPI_PostAnnotationWebhookEvent(t *testing.T) {
+	permissions := []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsTypeOrganization}}
+
+	setupServer := func(t *testing.T) (*webtest.Server, *annotations.FakeAnnotationsRepo) {
+		t.Helper()
+		repo := annotations.NewFakeAnnotationsRepo(t)
+		webhookService := annotationwebhookstest.NewFakeAnnotationWebhookService()
+		webhookService.ExpectedWebhook = &annotationwebhooks.Webhook{
+			UID:              "abc",
+			OrgID:            1,
+			ServiceAccountID: 2,
+			Template: annotationwebhooks.MappingTemplate{
+				Condition: `{{ eq .status "success" }}`,
+				Text:      "Deployed {{ .ref }}",
+				Tags:      []string{"deploy", "{{ .env }}"},
+				Time:      "{{ .finished_at }}",
+			},
+		}
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.annotationsRepo = repo
+			hs.annotationWebhooks = webhookService
+			hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+		})
+		return server, repo
+	}
+
+	sendEvent := func(t *testing.T, server *webtest.Server, userID int64, isServiceAccount bool, payload string) int {
+		t.Helper()
+		usr := userWithPermissions(1, permissions)
+		usr.UserID = userID
+		usr.IsServiceAccount = isServiceAccount
+		req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodPost, "/api/annotations/webhooks/abc/events", strings.NewReader(payload)), usr)
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res.StatusCode
+	}
+
+	t.Run("should create the annotation mapped from the event", func(t *testing.T) {
+		server, repo := setupServer(t)
+		repo.On("Save", mock.Anything, mock.MatchedBy(func(item *annotations.Item) bool {
+			return item.Text == "Deployed v1.2.3" && item.UserID == 2 && item.Epoch == 1665490000000 &&
+				len(item.Tags) == 2 && item.Tags[1] == "prod"
+		})).Return(nil).Once()
+
+		code := sendEvent(t, server, 2, true, `{"status": "success", "ref": "v1.2.3", "env": "prod", "finished_at": 1665490000000}`)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("should ignore the event when the condition is false", func(t *testing.T) {
+		server, _ := setupServer(t)
+
+		code := sendEvent(t, server, 2, true, `{"status": "failed", "ref": "v1.2.3"}`)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("should reject an event which cannot be mapped", func(t *testing.T) {
+		server, _ := setupServer(t)
+
+		code := sendEvent(t, server, 2, true, `{"status": "success", "ref": "v1.2.3", "finished_at": "yesterday"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("should reject an event larger than the limit", func(t *testing.T) {
+		server, _ := setupServer(t)
+
+		code := sendEvent(t, server, 2, true, `{"ref": "`+strings.Repeat("a", annotationwebhooks.MaxPayloadBytes)+`"}`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+	})
+
+	t.Run("should reject an event sent by another service account", func(t *testing.T) {
+		server, _ := setupServer(t)
+
+		code := sendEvent(t, server, 3, true, `{"status": "success", "ref": "v1.2.3"}`)
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("should reject an event sent by a user", func(t *testing.T) {
+		server, _ := setupServer(t)
+
+		code := sendEvent(t, server, 2, false, `{"status": "success", "ref": "v1.2.3"}`)
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -159,6 +160,97 @@ func (hs *HTTPServer) PostAnnotation(c *contextmodel.ReqContext) response.Respon
 	})
 }
 
+// swagger:route POST /annotations/bulk annotations postBulkAnnotations
+//
+// Create Annotations in bulk.
+//
+// Creates many annotations in the Grafana database with a single request, for example deployment or CI events. Each annotation has the same format as in the Create Annotation operation. The annotations are created in a single transaction: if one of them is invalid, none are created. The maximum number of annotations per request is configured by `bulk_max_size` in the `[annotations]` section.
+// The response does not include the IDs of the created annotations.
+//
+// Responses:
+// 200: postBulkAnnotationsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) PostBulkAnnotations(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.PostBulkAnnotationsCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if len(cmd.Annotations) == 0 {
+		err := &AnnotationError{"annotations field should not be empty"}
+		return response.Error(http.StatusBadRequest, "Failed to save annotations", err)
+	}
+	if hs.Cfg.AnnotationBulkMaxSize > 0 && int64(len(cmd.Annotations)) > hs.Cfg.AnnotationBulkMaxSize {
+		err := &AnnotationError{fmt.Sprintf("number of annotations (%d) exceeds the maximum allowed (%d)", len(cmd.Annotations), hs.Cfg.AnnotationBulkMaxSize)}
+		return response.Error(http.StatusBadRequest, "Failed to save annotations", err)
+	}
+
+	// most bulk requests target a handful of dashboards, so the dashboard lookups and permission checks are cached
+	dashboardsByUID := make(map[string]*dashboards.Dashboard)
+	canSaveDashboard := make(map[int64]bool)
+	items := make([]annotations.Item, 0, len(cmd.Annotations))
+	for i, annotation := range cmd.Annotations {
+		if annotation.Text == "" {
+			err := &AnnotationError{fmt.Sprintf("annotation %d: text field should not be empty", i)}
+			return response.Error(http.StatusBadRequest, "Failed to save annotations", err)
+		}
+
+		// overwrite dashboardId when dashboardUID is not empty
+		if annotation.DashboardUID != "" {
+			dashboard, ok := dashboardsByUID[annotation.DashboardUID]
+			if !ok {
+				query := dashboards.GetDashboardQuery{OrgID: c.OrgID, UID: annotation.DashboardUID}
+				if queryResult, err := hs.DashboardService.GetDashboard(c.Req.Context(), &query); err == nil {
+					dashboard = queryResult
+				}
+				dashboardsByUID[annotation.DashboardUID] = dashboard
+			}
+			if dashboard != nil {
+				annotation.DashboardId = dashboard.ID
+			}
+		}
+
+		canSave, ok := canSaveDashboard[annotation.DashboardId]
+		if !ok {
+			var err error
+			if canSave, err = hs.canCreateAnnotation(c, annotation.DashboardId); err != nil {
+				return dashboardGuardianResponse(err)
+			}
+			canSaveDashboard[annotation.DashboardId] = canSave
+		}
+		if !canSave {
+			return dashboardGuardianResponse(nil)
+		}
+
+		items = append(items, annotations.Item{
+			OrgID:       c.OrgID,
+			UserID:      c.UserID,
+			DashboardID: annotation.DashboardId,
+			PanelID:     annotation.PanelId,
+			Epoch:       annotation.Time,
+			EpochEnd:    annotation.TimeEnd,
+			Text:        annotation.Text,
+			Data:        annotation.Data,
+			Tags:        annotation.Tags,
+		})
+	}
+
+	if err := hs.annotationsRepo.SaveMany(c.Req.Context(), items); err != nil {
+		if errors.Is(err, annotations.ErrTimerangeMissing) {
+			return response.Error(http.StatusBadRequest, "Failed to save annotations", err)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save annotations", err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"message": "Annotations added",
+		"count":   len(items),
+	})
+}
+
 func formatGraphiteAnnotation(what string, data string) string {
 	text := what
 	if data != "" {
@@ -723,6 +815,13 @@ type PostAnnotationParams struct {
 	Body dtos.PostAnnotationsCmd `json:"body"`
 }
 
+// swagger:parameters postBulkAnnotations
+type PostBulkAnnotationsParams struct {
+	// in:body
+	// required:true
+	Body dtos.PostBulkAnnotationsCmd `json:"body"`
+}
+
 // swagger:parameters postGraphiteAnnotation
 type PostGraphiteAnnotationParams struct {
 	// in:body
@@ -780,6 +879,22 @@ type PostAnnotationResponse struct {
 	} `json:"body"`
 }
 
+// swagger:response postBulkAnnotationsResponse
+type PostBulkAnnotationsResponse struct {
+	// The response message
+	// in: body
+	Body struct {
+		// Count Number of created annotations.
+		// required: true
+		// example: 1000
+		Count int64 `json:"count"`
+
+		// Message Message of the created annotations.
+		// required: true
+		Message string `json:"message"`
+	} `json:"body"`
+}
+
 // swagger:response getAnnotationTagsResponse
 type GetAnnotationTagsResponse struct {
 	// The response message
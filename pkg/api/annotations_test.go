@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -203,6 +204,38 @@ func TestAPI_Annotations(t *testing.T) {
 			expectedCode: http.StatusForbidden,
 			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsTypeDashboard}},
 		},
+		{
+			desc:         "should be able to create organization annotations in bulk with correct permission",
+			path:         "/api/annotations/bulk",
+			body:         "{\"annotations\": [{\"text\": \"deploy\", \"tags\": [\"deploy\"]}, {\"text\": \"rollback\"}]}",
+			method:       http.MethodPost,
+			expectedCode: http.StatusOK,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsTypeOrganization}},
+		},
+		{
+			desc:         "should not be able to create organization annotations in bulk without correct permission",
+			path:         "/api/annotations/bulk",
+			body:         "{\"annotations\": [{\"text\": \"deploy\"}]}",
+			method:       http.MethodPost,
+			expectedCode: http.StatusForbidden,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsTypeDashboard}},
+		},
+		{
+			desc:         "should not be able to create dashboard annotations in bulk without correct permission",
+			path:         "/api/annotations/bulk",
+			body:         "{\"annotations\": [{\"text\": \"deploy\"}, {\"dashboardId\": 2, \"text\": \"deploy\"}]}",
+			method:       http.MethodPost,
+			expectedCode: http.StatusForbidden,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsTypeOrganization}},
+		},
+		{
+			desc:         "should not be able to create annotations in bulk without text",
+			path:         "/api/annotations/bulk",
+			body:         "{\"annotations\": [{\"text\": \"deploy\"}, {\"time\": 1}]}",
+			method:       http.MethodPost,
+			expectedCode: http.StatusBadRequest,
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsTypeOrganization}},
+		},
 		{
 			desc:         "should be able to mass delete dashboard annotations with correct permission",
 			path:         "/api/annotations/mass-delete",
@@ -246,6 +279,48 @@ func TestAPI_Annotations(t *testing.T) {
 		})
 	}
 }
+func TestAPI_PostBulkAnnotations(t *testing.T) {
+	permissions := []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsCreate, Scope: accesscontrol.ScopeAnnotationsTypeOrganization}}
+
+	setupServer := func(t *testing.T, maxSize int64) (*webtest.Server, *annotations.FakeAnnotationsRepo) {
+		t.Helper()
+		setUpRBACGuardian(t)
+		repo := annotations.NewFakeAnnotationsRepo(t)
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.Cfg.AnnotationBulkMaxSize = maxSize
+			hs.annotationsRepo = repo
+			hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
+		})
+		return server, repo
+	}
+
+	t.Run("should save all the annotations at once", func(t *testing.T) {
+		server, repo := setupServer(t, 10)
+		repo.On("SaveMany", mock.Anything, mock.MatchedBy(func(items []annotations.Item) bool {
+			return len(items) == 2 && items[0].Text == "deploy" && items[0].Epoch == 1 && items[1].Text == "rollback"
+		})).Return(nil).Once()
+
+		body := strings.NewReader(`{"annotations": [{"text": "deploy", "time": 1, "tags": ["deploy"]}, {"text": "rollback", "time": 2}]}`)
+		req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodPost, "/api/annotations/bulk", body), userWithPermissions(1, permissions))
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should not save any annotation when the request exceeds the maximum size", func(t *testing.T) {
+		server, _ := setupServer(t, 1)
+
+		body := strings.NewReader(`{"annotations": [{"text": "deploy"}, {"text": "rollback"}]}`)
+		req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodPost, "/api/annotations/bulk", body), userWithPermissions(1, permissions))
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func TestService_AnnotationTypeScopeResolver(t *testing.T) {
 	type testCaseResolver struct {
 		desc    string
@@ -795,6 +795,80 @@ func (hs *HTTPServer) GetDashboardVersion(c *contextmodel.ReqContext) response.R
 	return response.JSON(http.StatusOK, dashVersionMeta)
 }
 
+// swagger:route GET /dashboards/uid/{uid}/diff dashboard_versions getDashboardVersionsDiffByUID
+//
+// Get the semantic diff between two versions of a dashboard.
+//
+// Reports the added, removed, moved and modified panels with their changed queries,
+// the changed template variables and dashboard properties, and a unified diff of the JSON models.
+//
+// Produces:
+// - application/json
+// - text/plain
+//
+// Responses:
+// 200: dashboardVersionsDiffResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardVersionsDiff(c *contextmodel.ReqContext) response.Response {
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.OrgID, 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	baseVersion := c.QueryInt("base")
+	newVersion := c.QueryInt("new")
+	if baseVersion <= 0 || newVersion <= 0 {
+		return response.Error(http.StatusBadRequest, "base and new versions are required", nil)
+	}
+
+	versions := make([]*dashver.DashboardVersionDTO, 0, 2)
+	for _, version := range []int{baseVersion, newVersion} {
+		res, err := hs.dashboardVersionService.Get(c.Req.Context(), &dashver.GetDashboardVersionQuery{
+			OrgID:        c.OrgID,
+			DashboardID:  dash.ID,
+			DashboardUID: dash.UID,
+			Version:      version,
+		})
+		if err != nil {
+			if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+				return response.Error(http.StatusNotFound, fmt.Sprintf("Dashboard version %d not found", version), err)
+			}
+			return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+		}
+		versions = append(versions, res)
+	}
+
+	diff, err := dashdiffs.CalculateSemanticDiff(versions[0].Data, versions[1].Data,
+		fmt.Sprintf("version %d", baseVersion), fmt.Sprintf("version %d", newVersion))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+	}
+
+	if c.Query("format") == "text" {
+		return response.Respond(http.StatusOK, dashdiffs.FormatSemanticDiff(diff)).SetHeader("Content-Type", "text/plain; charset=utf-8")
+	}
+
+	return response.JSON(http.StatusOK, dtos.DashboardVersionsDiff{
+		DashboardUID: dash.UID,
+		BaseVersion:  baseVersion,
+		NewVersion:   newVersion,
+		SemanticDiff: diff,
+	})
+}
+
 // swagger:route POST /dashboards/validate dashboards alpha validateDashboard
 //
 // Validates a dashboard JSON against the schema.
@@ -1146,6 +1220,29 @@ type GetDashboardVersionsParams struct {
 	Start int `json:"start"`
 }
 
+// swagger:parameters getDashboardVersionsDiffByUID
+type GetDashboardVersionsDiffByUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+
+	// Version to compare from
+	// in:query
+	// required:true
+	Base int `json:"base"`
+
+	// Version to compare to
+	// in:query
+	// required:true
+	New int `json:"new"`
+
+	// Response format, `text` returns a summary of the changes followed by the unified diff
+	// in:query
+	// required:false
+	// Enum: json,text
+	Format string `json:"format"`
+}
+
 // swagger:parameters getDashboardByUID
 type GetDashboardByUIDParams struct {
 	// in:path
@@ -1303,6 +1400,12 @@ type DashboardVersionResponse struct {
 	Body *dashver.DashboardVersionMeta `json:"body"`
 }
 
+// swagger:response dashboardVersionsDiffResponse
+type DashboardVersionsDiffResponse struct {
+	// in: body
+	Body dtos.DashboardVersionsDiff `json:"body"`
+}
+
 // swagger:response validateDashboardResponse
 type ValidateDashboardResponse struct {
 	IsValid bool   `json:"isValid"`
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
@@ -1068,6 +1069,55 @@ func TestDashboardVersionsAPIEndpoint(t *testing.T) {
 				assert.Equal(t, anonString, v.CreatedBy)
 			}
 		}, mockSQLStore)
+
+	loggedInUserScenarioWithRole(t, "When calling GET on", "GET", "/api/dashboards/uid/dash/diff",
+		"/api/dashboards/uid/:uid/diff", org.RoleEditor, func(sc *scenarioContext) {
+			setUp()
+			fakeDashboardVersionService.ExpectedDashboardVersions = []*dashver.DashboardVersionDTO{
+				{
+					Version: 1,
+					Data: simplejson.NewFromAny(map[string]interface{}{
+						"title":  "Child dash",
+						"panels": []interface{}{map[string]interface{}{"id": 1, "type": "text", "title": "Notes"}},
+					}),
+				},
+				{
+					Version: 2,
+					Data: simplejson.NewFromAny(map[string]interface{}{
+						"title": "Child dash",
+						"panels": []interface{}{
+							map[string]interface{}{"id": 1, "type": "text", "title": "Notes"},
+							map[string]interface{}{"id": 2, "type": "stat", "title": "Requests"},
+						},
+					}),
+				},
+			}
+			hs := getHS(&usertest.FakeUserService{})
+			sc.handlerFunc = hs.GetDashboardVersionsDiff
+			sc.fakeReqWithParams("GET", sc.url, map[string]string{"base": "1", "new": "2"}).exec()
+
+			assert.Equal(t, 200, sc.resp.Code)
+			var diff dtos.DashboardVersionsDiff
+			err := json.NewDecoder(sc.resp.Body).Decode(&diff)
+			require.NoError(t, err)
+			assert.Equal(t, 1, diff.BaseVersion)
+			assert.Equal(t, 2, diff.NewVersion)
+			require.Len(t, diff.Panels, 1)
+			assert.Equal(t, int64(2), diff.Panels[0].ID)
+			assert.Equal(t, dashdiffs.SemanticAdded, diff.Panels[0].Change)
+			assert.Empty(t, diff.Fields)
+			assert.Contains(t, diff.Unified, "--- version 1\n+++ version 2\n")
+		}, mockSQLStore)
+
+	loggedInUserScenarioWithRole(t, "When calling GET without versions on", "GET", "/api/dashboards/uid/dash/diff",
+		"/api/dashboards/uid/:uid/diff", org.RoleEditor, func(sc *scenarioContext) {
+			setUp()
+			hs := getHS(&usertest.FakeUserService{})
+			sc.handlerFunc = hs.GetDashboardVersionsDiff
+			sc.fakeReqWithParams("GET", sc.url, map[string]string{"base": "1"}).exec()
+
+			assert.Equal(t, 400, sc.resp.Code)
+		}, mockSQLStore)
 }
 
 func getDashboardShouldReturn200WithConfig(t *testing.T, sc *scenarioContext, provisioningService provisioning.ProvisioningService, dashboardStore dashboards.Store, dashboardService dashboards.DashboardService, folderStore folder.FolderStore) dtos.DashboardFullWithMeta {
@@ -1152,7 +1202,7 @@ func (hs *HTTPServer) callGetDashboardVersions(sc *scenarioContext) {
 func (hs *HTTPServer) callDeleteDashboardByUID(t *testing.T,
 	sc *scenarioContext, mockDashboard *dashboards.FakeDashboardService, mockPubdashService *publicdashboards.FakePublicDashboardService) {
 	hs.DashboardService = mockDashboard
-	pubdashApi := api.ProvideApi(mockPubdashService, nil, nil, featuremgmt.WithFeatures())
+	pubdashApi := api.ProvideApi(mockPubdashService, nil, nil, featuremgmt.WithFeatures(), nil, nil)
 	hs.PublicDashboardsApi = pubdashApi
 	sc.handlerFunc = hs.DeleteDashboardByUID
 	sc.fakeReqWithParams("DELETE", sc.url, map[string]string{}).exec()
@@ -3,6 +3,7 @@ package dtos
 import (
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -73,6 +74,13 @@ type CalculateDiffTarget struct {
 	UnsavedDashboard *simplejson.Json `json:"unsavedDashboard"`
 }
 
+type DashboardVersionsDiff struct {
+	DashboardUID string `json:"dashboardUid"`
+	BaseVersion  int    `json:"baseVersion"`
+	NewVersion   int    `json:"newVersion"`
+	*dashdiffs.SemanticDiff
+}
+
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
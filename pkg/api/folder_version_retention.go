@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /folders/{folder_uid}/version-retention folders getFolderVersionRetention
+//
+// Get the dashboard version retention policy of a folder.
+//
+// Responses:
+// 200: folderVersionRetentionResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetFolderVersionRetention(c *contextmodel.ReqContext) response.Response {
+	f, rsp := hs.getVersionRetentionFolder(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	policy, err := hs.dashboardVersionService.GetRetentionPolicy(c.Req.Context(), &dashver.GetRetentionPolicyQuery{OrgID: c.OrgID, FolderUID: f.UID})
+	if err != nil {
+		return versionRetentionErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
+// swagger:route PUT /folders/{folder_uid}/version-retention folders updateFolderVersionRetention
+//
+// Set the dashboard version retention policy of a folder.
+//
+// The policy replaces the global `versions_to_keep` setting for the dashboards of the folder.
+//
+// Responses:
+// 200: folderVersionRetentionResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateFolderVersionRetention(c *contextmodel.ReqContext) response.Response {
+	cmd := dashver.SetRetentionPolicyCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	f, rsp := hs.getVersionRetentionFolder(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	cmd.OrgID = c.OrgID
+	cmd.FolderUID = f.UID
+	policy, err := hs.dashboardVersionService.SetRetentionPolicy(c.Req.Context(), &cmd)
+	if err != nil {
+		return versionRetentionErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
+// swagger:route DELETE /folders/{folder_uid}/version-retention folders deleteFolderVersionRetention
+//
+// Delete the dashboard version retention policy of a folder.
+//
+// The dashboards of the folder are cleaned up with the global `versions_to_keep` setting again.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteFolderVersionRetention(c *contextmodel.ReqContext) response.Response {
+	f, rsp := hs.getVersionRetentionFolder(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	err := hs.dashboardVersionService.DeleteRetentionPolicy(c.Req.Context(), &dashver.DeleteRetentionPolicyCommand{OrgID: c.OrgID, FolderUID: f.UID})
+	if err != nil {
+		return versionRetentionErrorResponse(err)
+	}
+	return response.Success("Folder version retention policy deleted")
+}
+
+func (hs *HTTPServer) getVersionRetentionFolder(c *contextmodel.ReqContext) (*folder.Folder, response.Response) {
+	uid := web.Params(c.Req)[":uid"]
+	f, err := hs.folderService.Get(c.Req.Context(), &folder.GetFolderQuery{OrgID: c.OrgID, UID: &uid, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return nil, apierrors.ToFolderErrorResponse(err)
+	}
+	return f, nil
+}
+
+func versionRetentionErrorResponse(err error) response.Response {
+	switch {
+	case errors.Is(err, dashver.ErrRetentionPolicyNotFound):
+		return response.Error(http.StatusNotFound, "Folder has no version retention policy", err)
+	case errors.Is(err, dashver.ErrRetentionPolicyInvalid):
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+	return response.Error(http.StatusInternalServerError, "Failed to handle folder version retention policy", err)
+}
+
+// swagger:parameters getFolderVersionRetention deleteFolderVersionRetention
+type FolderVersionRetentionParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+}
+
+// swagger:parameters updateFolderVersionRetention
+type UpdateFolderVersionRetentionParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+	// in:body
+	// required:true
+	Body dashver.SetRetentionPolicyCommand
+}
+
+// swagger:response folderVersionRetentionResponse
+type FolderVersionRetentionResponse struct {
+	// in: body
+	Body *dashver.RetentionPolicy `json:"body"`
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestFolderVersionRetentionAPIEndpoint(t *testing.T) {
+	setUpRBACGuardian(t)
+
+	readPermissions := []accesscontrol.Permission{{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll}}
+	writePermissions := []accesscontrol.Permission{{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll}}
+
+	type testCase struct {
+		description   string
+		method        string
+		body          string
+		permissions   []accesscontrol.Permission
+		expectedError error
+		expectedCode  int
+	}
+	tcs := []testCase{
+		{
+			description:  "get retention policy succeeds",
+			method:       http.MethodGet,
+			permissions:  readPermissions,
+			expectedCode: http.StatusOK,
+		},
+		{
+			description:   "get retention policy returns not found when the folder has no policy",
+			method:        http.MethodGet,
+			permissions:   readPermissions,
+			expectedError: dashver.ErrRetentionPolicyNotFound,
+			expectedCode:  http.StatusNotFound,
+		},
+		{
+			description:  "get retention policy fails without permissions to read the folder",
+			method:       http.MethodGet,
+			permissions:  []accesscontrol.Permission{},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			description:  "update retention policy succeeds",
+			method:       http.MethodPut,
+			body:         `{"maxVersions": 10, "maxAge": "90d", "keepTagged": true}`,
+			permissions:  writePermissions,
+			expectedCode: http.StatusOK,
+		},
+		{
+			description:   "update retention policy fails given an invalid policy",
+			method:        http.MethodPut,
+			body:          `{"maxAge": "soon"}`,
+			permissions:   writePermissions,
+			expectedError: dashver.ErrRetentionPolicyInvalid,
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			description:  "update retention policy fails without permissions to update the folder",
+			method:       http.MethodPut,
+			body:         `{"maxVersions": 10}`,
+			permissions:  readPermissions,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			description:  "delete retention policy succeeds",
+			method:       http.MethodDelete,
+			permissions:  writePermissions,
+			expectedCode: http.StatusOK,
+		},
+		{
+			description:   "delete retention policy returns not found when the folder has no policy",
+			method:        http.MethodDelete,
+			permissions:   writePermissions,
+			expectedError: dashver.ErrRetentionPolicyNotFound,
+			expectedCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tcs {
+		srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = &setting.Cfg{
+				RBACEnabled: true,
+			}
+			hs.folderService = &foldertest.FakeService{ExpectedFolder: &folder.Folder{ID: 1, UID: "uid", Title: "Folder"}}
+			hs.dashboardVersionService = &dashvertest.FakeDashboardVersionService{
+				ExpectedRetentionPolicy: &dashver.RetentionPolicy{FolderUID: "uid", MaxVersions: 10, MaxAge: "90d", KeepTagged: true},
+				ExpectedError:           tc.expectedError,
+			}
+		})
+
+		t.Run(tc.description, func(t *testing.T) {
+			req := srv.NewRequest(tc.method, "/api/folders/uid/version-retention", strings.NewReader(tc.body))
+			req = webtest.RequestWithSignedInUser(req, userWithPermissions(1, tc.permissions))
+			resp, err := srv.SendJSON(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCode, resp.StatusCode)
+
+			if tc.expectedCode == http.StatusOK && tc.method != http.MethodDelete {
+				policy := dashver.RetentionPolicy{}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&policy))
+				assert.Equal(t, "uid", policy.FolderUID)
+				assert.Equal(t, 10, policy.MaxVersions)
+				assert.Equal(t, "90d", policy.MaxAge)
+				assert.True(t, policy.KeepTagged)
+			}
+			require.NoError(t, resp.Body.Close())
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// SemanticChange describes how a part of the dashboard changed between two versions.
+type SemanticChange string
+
+const (
+	SemanticAdded    SemanticChange = "added"
+	SemanticRemoved  SemanticChange = "removed"
+	SemanticModified SemanticChange = "modified"
+	SemanticMoved    SemanticChange = "moved"
+)
+
+// The properties compared separately, or not compared at all, when diffing the dashboard properties.
+var ignoredDashboardFields = map[string]bool{
+	"id":         true,
+	"version":    true,
+	"panels":     true,
+	"rows":       true,
+	"templating": true,
+}
+
+// The properties compared separately when diffing the panel properties.
+var ignoredPanelFields = map[string]bool{
+	"gridPos": true,
+	"targets": true,
+	"panels":  true,
+}
+
+// SemanticDiff is the structured diff of two dashboard versions.
+type SemanticDiff struct {
+	// Changed dashboard properties, like the title, tags or time range
+	Fields []FieldChange `json:"fields"`
+	// Added, removed, moved and modified panels, including the ones in collapsed rows
+	Panels []PanelChange `json:"panels"`
+	// Added, removed and modified template variables
+	Variables []VariableChange `json:"variables"`
+	// Unified diff of the indented JSON models
+	Unified string `json:"unified"`
+}
+
+// IsEmpty returns true when the versions are semantically identical.
+func (d *SemanticDiff) IsEmpty() bool {
+	return len(d.Fields) == 0 && len(d.Panels) == 0 && len(d.Variables) == 0
+}
+
+type FieldChange struct {
+	Path   string         `json:"path"`
+	Change SemanticChange `json:"change"`
+	Before interface{}    `json:"before,omitempty"`
+	After  interface{}    `json:"after,omitempty"`
+}
+
+type GridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type PanelChange struct {
+	ID     int64          `json:"id"`
+	Title  string         `json:"title"`
+	Type   string         `json:"type"`
+	Change SemanticChange `json:"change"`
+	// Position before and after the change, only set when the panel moved
+	From *GridPos `json:"from,omitempty"`
+	To   *GridPos `json:"to,omitempty"`
+	// Changed panel properties, other than the queries and the position
+	Fields  []string      `json:"fields,omitempty"`
+	Queries []QueryChange `json:"queries,omitempty"`
+}
+
+type QueryChange struct {
+	RefID  string         `json:"refId"`
+	Change SemanticChange `json:"change"`
+	Before interface{}    `json:"before,omitempty"`
+	After  interface{}    `json:"after,omitempty"`
+}
+
+type VariableChange struct {
+	Name   string         `json:"name"`
+	Type   string         `json:"type"`
+	Change SemanticChange `json:"change"`
+	// Changed variable properties
+	Fields []string `json:"fields,omitempty"`
+}
+
+// CalculateSemanticDiff computes the panel, variable and property level changes between
+// two versions of a dashboard, along with the unified diff of their JSON models.
+// baseName and newName label the versions in the unified diff.
+func CalculateSemanticDiff(baseData, newData *simplejson.Json, baseName, newName string) (*SemanticDiff, error) {
+	base, err := toMap(baseData)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := toMap(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	unified, err := unifiedDiff(base, updated, baseName, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SemanticDiff{
+		Fields:    diffFields(base, updated),
+		Panels:    diffPanels(base, updated),
+		Variables: diffVariables(base, updated),
+		Unified:   unified,
+	}, nil
+}
+
+func toMap(data *simplejson.Json) (map[string]interface{}, error) {
+	if data == nil {
+		return map[string]interface{}{}, nil
+	}
+	// simplejson keeps the numbers as json.Number, decode them as float64 like the other JSON models
+	raw, err := data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("dashboard model must be an object: %w", err)
+	}
+	return m, nil
+}
+
+func unifiedDiff(base, updated map[string]interface{}, baseName, newName string) (string, error) {
+	// maps are marshaled with sorted keys, so that the diff only shows the actual changes
+	baseJSON, err := json.MarshalIndent(base, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	newJSON, err := json.MarshalIndent(updated, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(baseJSON) + "\n"),
+		B:        difflib.SplitLines(string(newJSON) + "\n"),
+		FromFile: baseName,
+		ToFile:   newName,
+		Context:  3,
+	})
+}
+
+func diffFields(base, updated map[string]interface{}) []FieldChange {
+	changes := make([]FieldChange, 0)
+	for _, key := range sortedKeys(base, updated) {
+		if ignoredDashboardFields[key] {
+			continue
+		}
+		before, inBase := base[key]
+		after, inNew := updated[key]
+		switch {
+		case !inBase:
+			changes = append(changes, FieldChange{Path: key, Change: SemanticAdded, After: after})
+		case !inNew:
+			changes = append(changes, FieldChange{Path: key, Change: SemanticRemoved, Before: before})
+		case !reflect.DeepEqual(before, after):
+			changes = append(changes, FieldChange{Path: key, Change: SemanticModified, Before: before, After: after})
+		}
+	}
+	return changes
+}
+
+type panelInfo struct {
+	key   string
+	id    int64
+	model map[string]interface{}
+	row   string // title of the collapsed row holding the panel
+}
+
+func diffPanels(base, updated map[string]interface{}) []PanelChange {
+	basePanels := getPanels(base)
+	newPanels := getPanels(updated)
+	baseByKey := make(map[string]panelInfo, len(basePanels))
+	for _, p := range basePanels {
+		baseByKey[p.key] = p
+	}
+	newByKey := make(map[string]panelInfo, len(newPanels))
+	for _, p := range newPanels {
+		newByKey[p.key] = p
+	}
+
+	changes := make([]PanelChange, 0)
+	for _, p := range basePanels {
+		if _, ok := newByKey[p.key]; !ok {
+			changes = append(changes, newPanelChange(p, SemanticRemoved))
+		}
+	}
+	for _, p := range newPanels {
+		old, ok := baseByKey[p.key]
+		if !ok {
+			changes = append(changes, newPanelChange(p, SemanticAdded))
+			continue
+		}
+
+		change := newPanelChange(p, SemanticModified)
+		change.Fields = diffKeys(old.model, p.model, ignoredPanelFields)
+		change.Queries = diffQueries(old.model, p.model)
+
+		from, to := getGridPos(old.model), getGridPos(p.model)
+		moved := old.row != p.row || !reflect.DeepEqual(from, to)
+		if moved {
+			change.From, change.To = from, to
+		}
+
+		switch {
+		case len(change.Fields) > 0 || len(change.Queries) > 0:
+			changes = append(changes, change)
+		case moved:
+			change.Change = SemanticMoved
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func newPanelChange(p panelInfo, change SemanticChange) PanelChange {
+	title, _ := p.model["title"].(string)
+	panelType, _ := p.model["type"].(string)
+	return PanelChange{
+		ID:     p.id,
+		Title:  title,
+		Type:   panelType,
+		Change: change,
+	}
+}
+
+// getPanels returns the panels of the dashboard, the panels of collapsed rows are
+// returned after their row.
+func getPanels(dash map[string]interface{}) []panelInfo {
+	panels := make([]panelInfo, 0)
+	raw, _ := dash["panels"].([]interface{})
+	for idx, rp := range raw {
+		p, ok := rp.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		panels = append(panels, newPanelInfo(p, idx, ""))
+
+		nested, _ := p["panels"].([]interface{})
+		rowTitle, _ := p["title"].(string)
+		for nestedIdx, rn := range nested {
+			if n, ok := rn.(map[string]interface{}); ok {
+				panels = append(panels, newPanelInfo(n, nestedIdx, rowTitle))
+			}
+		}
+	}
+	return panels
+}
+
+func newPanelInfo(p map[string]interface{}, idx int, row string) panelInfo {
+	info := panelInfo{model: p, row: row}
+	if id, ok := p["id"].(float64); ok {
+		info.id = int64(id)
+		info.key = fmt.Sprintf("id:%d", info.id)
+	} else {
+		// panels without ID are matched by their position
+		info.key = fmt.Sprintf("row:%s/index:%d", row, idx)
+	}
+	return info
+}
+
+func getGridPos(p map[string]interface{}) *GridPos {
+	raw, ok := p["gridPos"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	pos := &GridPos{}
+	pos.X = toInt(raw["x"])
+	pos.Y = toInt(raw["y"])
+	pos.W = toInt(raw["w"])
+	pos.H = toInt(raw["h"])
+	return pos
+}
+
+func toInt(v interface{}) int {
+	f, _ := v.(float64)
+	return int(f)
+}
+
+func diffQueries(base, updated map[string]interface{}) []QueryChange {
+	baseTargets := getTargets(base)
+	newTargets := getTargets(updated)
+
+	changes := make([]QueryChange, 0)
+	for _, refID := range sortedKeys(baseTargets, newTargets) {
+		before, inBase := baseTargets[refID]
+		after, inNew := newTargets[refID]
+		switch {
+		case !inBase:
+			changes = append(changes, QueryChange{RefID: refID, Change: SemanticAdded, After: after})
+		case !inNew:
+			changes = append(changes, QueryChange{RefID: refID, Change: SemanticRemoved, Before: before})
+		case !reflect.DeepEqual(before, after):
+			changes = append(changes, QueryChange{RefID: refID, Change: SemanticModified, Before: before, After: after})
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// getTargets returns the queries of a panel by refId
+func getTargets(p map[string]interface{}) map[string]interface{} {
+	targets := map[string]interface{}{}
+	raw, _ := p["targets"].([]interface{})
+	for idx, rt := range raw {
+		t, ok := rt.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		refID, _ := t["refId"].(string)
+		if refID == "" {
+			refID = fmt.Sprintf("#%d", idx)
+		}
+		targets[refID] = t
+	}
+	return targets
+}
+
+func diffVariables(base, updated map[string]interface{}) []VariableChange {
+	baseVars := getVariables(base)
+	newVars := getVariables(updated)
+
+	changes := make([]VariableChange, 0)
+	for _, name := range sortedKeys(baseVars, newVars) {
+		before, inBase := baseVars[name].(map[string]interface{})
+		after, inNew := newVars[name].(map[string]interface{})
+		switch {
+		case !inBase:
+			changes = append(changes, newVariableChange(name, after, SemanticAdded))
+		case !inNew:
+			changes = append(changes, newVariableChange(name, before, SemanticRemoved))
+		default:
+			if fields := diffKeys(before, after, nil); len(fields) > 0 {
+				change := newVariableChange(name, after, SemanticModified)
+				change.Fields = fields
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes
+}
+
+func newVariableChange(name string, v map[string]interface{}, change SemanticChange) VariableChange {
+	varType, _ := v["type"].(string)
+	return VariableChange{Name: name, Type: varType, Change: change}
+}
+
+// getVariables returns the template variables by name
+func getVariables(dash map[string]interface{}) map[string]interface{} {
+	vars := map[string]interface{}{}
+	templating, _ := dash["templating"].(map[string]interface{})
+	list, _ := templating["list"].([]interface{})
+	for _, rv := range list {
+		v, ok := rv.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := v["name"].(string); name != "" {
+			vars[name] = v
+		}
+	}
+	return vars
+}
+
+// diffKeys returns the sorted keys with different values in the two objects
+func diffKeys(base, updated map[string]interface{}, ignored map[string]bool) []string {
+	var keys []string
+	for _, key := range sortedKeys(base, updated) {
+		if ignored[key] {
+			continue
+		}
+		if !reflect.DeepEqual(base[key], updated[key]) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func sortedKeys(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for _, m := range []map[string]interface{}{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FormatSemanticDiff renders the diff as text, a summary of the changes followed by the unified diff.
+func FormatSemanticDiff(d *SemanticDiff) string {
+	var sb strings.Builder
+	for _, f := range d.Fields {
+		fmt.Fprintf(&sb, "%s dashboard property %q\n", f.Change, f.Path)
+	}
+	for _, p := range d.Panels {
+		fmt.Fprintf(&sb, "%s panel %d %q", p.Change, p.ID, p.Title)
+		if len(p.Fields) > 0 {
+			fmt.Fprintf(&sb, " properties: %s", strings.Join(p.Fields, ", "))
+		}
+		for _, q := range p.Queries {
+			fmt.Fprintf(&sb, " query %s %s", q.RefID, q.Change)
+		}
+		sb.WriteString("\n")
+	}
+	for _, v := range d.Variables {
+		fmt.Fprintf(&sb, "%s variable %q", v.Change, v.Name)
+		if len(v.Fields) > 0 {
+			fmt.Fprintf(&sb, " properties: %s", strings.Join(v.Fields, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(d.Unified)
+	return sb.String()
+}
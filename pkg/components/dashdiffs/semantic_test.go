@@ -0,0 +1,164 @@
+package dashdiffs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestSemanticDiff(t *testing.T) {
+	const (
+		baseJSON = `{
+			"id": 1,
+			"version": 1,
+			"title": "Servers",
+			"tags": ["prod"],
+			"panels": [
+				{
+					"id": 1, "type": "timeseries", "title": "CPU",
+					"gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+					"targets": [{"refId": "A", "expr": "cpu"}, {"refId": "B", "expr": "load"}]
+				},
+				{
+					"id": 2, "type": "stat", "title": "Memory",
+					"gridPos": {"x": 12, "y": 0, "w": 12, "h": 8}
+				},
+				{
+					"id": 3, "type": "text", "title": "Notes",
+					"gridPos": {"x": 0, "y": 8, "w": 24, "h": 4}
+				},
+				{
+					"id": 4, "type": "row", "title": "Disks", "collapsed": true,
+					"gridPos": {"x": 0, "y": 12, "w": 24, "h": 1},
+					"panels": [
+						{"id": 5, "type": "gauge", "title": "Usage", "gridPos": {"x": 0, "y": 13, "w": 8, "h": 8}}
+					]
+				}
+			],
+			"templating": {
+				"list": [
+					{"name": "host", "type": "query", "query": "hosts()"},
+					{"name": "env", "type": "custom", "query": "prod,dev"}
+				]
+			}
+		}`
+
+		newJSON = `{
+			"id": 1,
+			"version": 2,
+			"title": "Servers overview",
+			"tags": ["prod"],
+			"refresh": "1m",
+			"panels": [
+				{
+					"id": 1, "type": "timeseries", "title": "CPU",
+					"gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+					"targets": [{"refId": "A", "expr": "cpu{host=\"$host\"}"}, {"refId": "C", "expr": "steal"}]
+				},
+				{
+					"id": 2, "type": "stat", "title": "Memory",
+					"gridPos": {"x": 0, "y": 8, "w": 12, "h": 8}
+				},
+				{
+					"id": 4, "type": "row", "title": "Disks", "collapsed": true,
+					"gridPos": {"x": 0, "y": 12, "w": 24, "h": 1},
+					"panels": [
+						{"id": 5, "type": "bargauge", "title": "Usage", "gridPos": {"x": 0, "y": 13, "w": 8, "h": 8}}
+					]
+				},
+				{
+					"id": 6, "type": "logs", "title": "Errors",
+					"gridPos": {"x": 0, "y": 20, "w": 24, "h": 8}
+				}
+			],
+			"templating": {
+				"list": [
+					{"name": "host", "type": "query", "query": "hosts(prod)"},
+					{"name": "region", "type": "custom", "query": "eu,us"}
+				]
+			}
+		}`
+	)
+
+	baseData, err := simplejson.NewJson([]byte(baseJSON))
+	require.NoError(t, err)
+	newData, err := simplejson.NewJson([]byte(newJSON))
+	require.NoError(t, err)
+
+	diff, err := CalculateSemanticDiff(baseData, newData, "version 1", "version 2")
+	require.NoError(t, err)
+	require.False(t, diff.IsEmpty())
+
+	t.Run("dashboard properties", func(t *testing.T) {
+		assert.Equal(t, []FieldChange{
+			{Path: "refresh", Change: SemanticAdded, After: "1m"},
+			{Path: "title", Change: SemanticModified, Before: "Servers", After: "Servers overview"},
+		}, diff.Fields)
+	})
+
+	t.Run("panels", func(t *testing.T) {
+		require.Len(t, diff.Panels, 5)
+		changes := map[int64]PanelChange{}
+		for _, p := range diff.Panels {
+			changes[p.ID] = p
+		}
+
+		assert.Equal(t, SemanticRemoved, changes[3].Change)
+		assert.Equal(t, "Notes", changes[3].Title)
+		assert.Equal(t, SemanticAdded, changes[6].Change)
+		assert.Equal(t, "logs", changes[6].Type)
+
+		assert.Equal(t, SemanticMoved, changes[2].Change)
+		assert.Equal(t, &GridPos{X: 12, Y: 0, W: 12, H: 8}, changes[2].From)
+		assert.Equal(t, &GridPos{X: 0, Y: 8, W: 12, H: 8}, changes[2].To)
+
+		assert.Equal(t, SemanticModified, changes[5].Change)
+		assert.Equal(t, []string{"type"}, changes[5].Fields)
+
+		cpu := changes[1]
+		assert.Equal(t, SemanticModified, cpu.Change)
+		assert.Empty(t, cpu.Fields)
+		assert.Nil(t, cpu.From)
+		require.Len(t, cpu.Queries, 3)
+		assert.Equal(t, "A", cpu.Queries[0].RefID)
+		assert.Equal(t, SemanticModified, cpu.Queries[0].Change)
+		assert.Equal(t, "B", cpu.Queries[1].RefID)
+		assert.Equal(t, SemanticRemoved, cpu.Queries[1].Change)
+		assert.Equal(t, "C", cpu.Queries[2].RefID)
+		assert.Equal(t, SemanticAdded, cpu.Queries[2].Change)
+	})
+
+	t.Run("variables", func(t *testing.T) {
+		assert.Equal(t, []VariableChange{
+			{Name: "env", Type: "custom", Change: SemanticRemoved},
+			{Name: "host", Type: "query", Change: SemanticModified, Fields: []string{"query"}},
+			{Name: "region", Type: "custom", Change: SemanticAdded},
+		}, diff.Variables)
+	})
+
+	t.Run("unified", func(t *testing.T) {
+		assert.Contains(t, diff.Unified, "--- version 1\n+++ version 2\n")
+		assert.Contains(t, diff.Unified, `-  "title": "Servers",`)
+		assert.Contains(t, diff.Unified, `+  "title": "Servers overview",`)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		text := FormatSemanticDiff(diff)
+		assert.Contains(t, text, "moved panel 2 \"Memory\"\n")
+		assert.Contains(t, text, "modified variable \"host\" properties: query\n")
+		assert.Contains(t, text, diff.Unified)
+	})
+}
+
+func TestSemanticDiff_Identical(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(`{"title": "Same", "panels": [{"id": 1, "type": "text"}]}`))
+	require.NoError(t, err)
+
+	diff, err := CalculateSemanticDiff(data, data, "a", "b")
+	require.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+	assert.Empty(t, diff.Unified)
+}
@@ -0,0 +1,108 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/util/converter"
+)
+
+// heatmapCellsToSeries converts a heatmap-cells frame, such as the ones returned for Prometheus
+// native histograms, to one cumulative series per bucket upper bound. Every series has the
+// labels of the histogram plus an "le" label, so the result can be used by functions like
+// histogram_quantile. The count of all cells is returned in the "+Inf" bucket.
+func heatmapCellsToSeries(frame *data.Frame) ([]mathexp.Series, error) {
+	timeField, _ := frame.FieldByName("xMax")
+	if timeField == nil {
+		timeField, _ = frame.FieldByName("xMin")
+	}
+	yMinField, _ := frame.FieldByName("yMin")
+	yMaxField, _ := frame.FieldByName("yMax")
+	countField, _ := frame.FieldByName("count")
+	if timeField == nil || yMaxField == nil || countField == nil {
+		return nil, fmt.Errorf("heatmap cells frame (refId %s) must have a time, yMax and count field", frame.RefID)
+	}
+
+	var labels data.Labels
+	if yMinField != nil {
+		labels = yMinField.Labels
+	}
+
+	// cumulative counts by bucket upper bound for each timestamp
+	times := []time.Time{}
+	countsAt := map[time.Time]map[float64]float64{}
+	boundSet := map[float64]struct{}{}
+	for i := 0; i < frame.Rows(); i++ {
+		t, ok := timeAt(timeField, i)
+		if !ok {
+			continue
+		}
+		upper, err := yMaxField.FloatAt(i)
+		if err != nil {
+			return nil, err
+		}
+		count, err := countField.FloatAt(i)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := countsAt[t]; !ok {
+			countsAt[t] = map[float64]float64{}
+			times = append(times, t)
+		}
+		countsAt[t][upper] += count
+		if !math.IsInf(upper, 1) {
+			boundSet[upper] = struct{}{}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	bounds := make([]float64, 0, len(boundSet)+1)
+	for b := range boundSet {
+		bounds = append(bounds, b)
+	}
+	sort.Float64s(bounds)
+	bounds = append(bounds, math.Inf(1))
+
+	series := make([]mathexp.Series, 0, len(bounds))
+	for _, b := range bounds {
+		l := labels.Copy()
+		if l == nil {
+			l = data.Labels{}
+		}
+		l[converter.BucketLabel] = formatBucketBound(b)
+		s := mathexp.NewSeries(frame.RefID, l, 0)
+		for _, t := range times {
+			var cumulative float64
+			for upper, count := range countsAt[t] {
+				if upper <= b {
+					cumulative += count
+				}
+			}
+			s.AppendPoint(t, &cumulative)
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+func timeAt(field *data.Field, idx int) (time.Time, bool) {
+	v, ok := field.ConcreteAt(idx)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, ok := v.(time.Time)
+	return t, ok
+}
+
+func formatBucketBound(b float64) string {
+	if math.IsInf(b, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(b, 'f', -1, 64)
+}
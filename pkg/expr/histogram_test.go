@@ -0,0 +1,55 @@
+package expr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util/converter"
+)
+
+func TestHeatmapCellsToSeries(t *testing.T) {
+	t1 := time.Unix(10, 0)
+	t2 := time.Unix(20, 0)
+	frame := data.NewFrame("",
+		data.NewField("xMax", nil, []time.Time{t1, t1, t2, t2}),
+		data.NewField("yMin", data.Labels{"job": "api"}, []float64{0.125, 0.25, 0.125, 0.25}),
+		data.NewField("yMax", nil, []float64{0.25, 0.5, 0.25, 0.5}),
+		data.NewField("count", nil, []float64{3, 2, 3, 3}),
+		data.NewField("yLayout", nil, []int8{0, 0, 0, 0}),
+	)
+	frame.RefID = "A"
+	frame.Meta = &data.FrameMeta{Type: "heatmap-cells"}
+	require.True(t, converter.IsHeatmapCellsFrame(frame))
+
+	series, err := heatmapCellsToSeries(frame)
+	require.NoError(t, err)
+	require.Len(t, series, 3)
+
+	expected := []struct {
+		le     string
+		values []float64
+	}{
+		{le: "0.25", values: []float64{3, 3}},
+		{le: "0.5", values: []float64{5, 6}},
+		{le: "+Inf", values: []float64{5, 6}},
+	}
+	for i, e := range expected {
+		s := series[i]
+		require.Equal(t, data.Labels{"job": "api", "le": e.le}, s.GetLabels())
+		require.Equal(t, "A", s.Frame.RefID)
+		require.Equal(t, len(e.values), s.Len())
+		for j, v := range e.values {
+			require.Equal(t, v, *s.GetValue(j))
+		}
+		require.Equal(t, t1, s.GetTime(0))
+		require.Equal(t, t2, s.GetTime(1))
+	}
+
+	t.Run("fails without count field", func(t *testing.T) {
+		_, err := heatmapCellsToSeries(data.NewFrame("", data.NewField("xMax", nil, []time.Time{t1})))
+		require.Error(t, err)
+	})
+}
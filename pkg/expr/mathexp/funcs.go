@@ -68,6 +68,11 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"histogram_quantile": {
+		Args:   []parse.ReturnType{parse.TypeScalar, parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      histogramQuantile,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
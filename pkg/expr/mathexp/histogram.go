@@ -0,0 +1,140 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/util/converter"
+)
+
+type bucket struct {
+	upperBound float64
+	count      float64
+}
+
+// histogramQuantile calculates the φ-quantile (0 ≤ φ ≤ 1) of cumulative histogram buckets, using the same
+// linear interpolation as the Prometheus histogram_quantile function. Series are grouped by their labels
+// without the "le" label, and one series is returned for each group. Series without a "le" label are ignored.
+func histogramQuantile(e *State, qRes Results, varSet Results) (Results, error) {
+	newRes := Results{}
+	if len(qRes.Values) != 1 {
+		return newRes, fmt.Errorf("histogram_quantile expects a single scalar quantile")
+	}
+	scalar, ok := qRes.Values[0].(Scalar)
+	if !ok {
+		return newRes, fmt.Errorf("histogram_quantile expects a scalar quantile but got %s", qRes.Values[0].Type())
+	}
+	q := scalar.GetFloat64Value()
+	if q == nil {
+		return newRes, fmt.Errorf("histogram_quantile expects a non-null quantile")
+	}
+
+	type group struct {
+		labels  data.Labels
+		buckets map[time.Time][]bucket
+		times   []time.Time
+	}
+	groups := map[string]*group{}
+	keys := []string{}
+	for _, res := range varSet.Values {
+		s, ok := res.(Series)
+		if !ok {
+			return newRes, fmt.Errorf("histogram_quantile expects a series set but got %s", res.Type())
+		}
+		le, ok := s.GetLabels()[converter.BucketLabel]
+		if !ok {
+			continue
+		}
+		upperBound, err := strconv.ParseFloat(le, 64)
+		if err != nil {
+			return newRes, fmt.Errorf("invalid bucket bound %q: %w", le, err)
+		}
+		labels := s.GetLabels().Copy()
+		delete(labels, converter.BucketLabel)
+		key := labels.String()
+		g, ok := groups[key]
+		if !ok {
+			g = &group{labels: labels, buckets: map[time.Time][]bucket{}}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		for i := 0; i < s.Len(); i++ {
+			t, v := s.GetPoint(i)
+			if v == nil {
+				continue
+			}
+			if _, ok := g.buckets[t]; !ok {
+				g.times = append(g.times, t)
+			}
+			g.buckets[t] = append(g.buckets[t], bucket{upperBound: upperBound, count: *v})
+		}
+	}
+
+	for _, key := range keys {
+		g := groups[key]
+		sort.Slice(g.times, func(i, j int) bool { return g.times[i].Before(g.times[j]) })
+		newSeries := NewSeries(e.RefID, g.labels, len(g.times))
+		for i, t := range g.times {
+			v := bucketQuantile(*q, g.buckets[t])
+			newSeries.SetPoint(i, t, &v)
+		}
+		newRes.Values = append(newRes.Values, newSeries)
+	}
+	return newRes, nil
+}
+
+// bucketQuantile calculates the quantile q of cumulative buckets. The buckets must include a +Inf bucket,
+// otherwise NaN is returned. The upper bound of the lowest bucket is returned when the quantile falls in
+// the lowest bucket and its bound is not positive, the upper bound of the highest finite bucket is returned
+// when the quantile falls in the +Inf bucket.
+func bucketQuantile(q float64, buckets []bucket) float64 {
+	if math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(1)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].upperBound < buckets[j].upperBound })
+	if len(buckets) < 2 || !math.IsInf(buckets[len(buckets)-1].upperBound, 1) {
+		return math.NaN()
+	}
+	// the counts of cumulative buckets can not decrease, precision issues are corrected
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i].count < buckets[i-1].count {
+			buckets[i].count = buckets[i-1].count
+		}
+	}
+
+	observations := buckets[len(buckets)-1].count
+	if observations == 0 {
+		return math.NaN()
+	}
+	rank := q * observations
+	b := sort.Search(len(buckets)-1, func(i int) bool { return buckets[i].count >= rank })
+
+	if b == len(buckets)-1 {
+		return buckets[len(buckets)-2].upperBound
+	}
+	if b == 0 && buckets[0].upperBound <= 0 {
+		return buckets[0].upperBound
+	}
+	var (
+		bucketStart float64
+		bucketEnd   = buckets[b].upperBound
+		count       = buckets[b].count
+	)
+	if b > 0 {
+		bucketStart = buckets[b-1].upperBound
+		count -= buckets[b-1].count
+		rank -= buckets[b-1].count
+	}
+	return bucketStart + (bucketEnd-bucketStart)*(rank/count)
+}
@@ -0,0 +1,104 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestHistogramQuantileFunc(t *testing.T) {
+	buckets := func(job string) []Value {
+		return []Value{
+			makeSeries("", data.Labels{"job": job, "le": "1"}, tp{time.Unix(5, 0), float64Pointer(2)}, tp{time.Unix(10, 0), float64Pointer(4)}),
+			makeSeries("", data.Labels{"job": job, "le": "2"}, tp{time.Unix(5, 0), float64Pointer(4)}, tp{time.Unix(10, 0), float64Pointer(4)}),
+			makeSeries("", data.Labels{"job": job, "le": "+Inf"}, tp{time.Unix(5, 0), float64Pointer(4)}, tp{time.Unix(10, 0), float64Pointer(8)}),
+		}
+	}
+
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "median of buckets",
+			expr:      "histogram_quantile(0.5, $A)",
+			vars:      Vars{"A": Results{buckets("api")}},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"job": "api"}, tp{time.Unix(5, 0), float64Pointer(1)}, tp{time.Unix(10, 0), float64Pointer(1)}),
+			}},
+		},
+		{
+			name:      "quantile interpolated in a bucket",
+			expr:      "histogram_quantile(0.75, $A)",
+			vars:      Vars{"A": Results{buckets("api")}},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"job": "api"}, tp{time.Unix(5, 0), float64Pointer(1.5)}, tp{time.Unix(10, 0), float64Pointer(2)}),
+			}},
+		},
+		{
+			name:      "one series per histogram",
+			expr:      "histogram_quantile(0.5, $A)",
+			vars:      Vars{"A": Results{append(buckets("api"), buckets("db")...)}},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"job": "api"}, tp{time.Unix(5, 0), float64Pointer(1)}, tp{time.Unix(10, 0), float64Pointer(1)}),
+				makeSeries("", data.Labels{"job": "db"}, tp{time.Unix(5, 0), float64Pointer(1)}, tp{time.Unix(10, 0), float64Pointer(1)}),
+			}},
+		},
+		{
+			name: "series without le label are ignored",
+			expr: "histogram_quantile(0.5, $A)",
+			vars: Vars{"A": Results{[]Value{
+				makeSeries("", data.Labels{"job": "api"}, tp{time.Unix(5, 0), float64Pointer(2)}),
+			}}},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   Results{},
+		},
+		{
+			name:     "quantile must be a scalar",
+			expr:     "histogram_quantile($A, $A)",
+			vars:     Vars{"A": Results{buckets("api")}},
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars, tracing.NewFakeTracer())
+				tt.execErrIs(t, err)
+				require.Equal(t, tt.results, res)
+			}
+		})
+	}
+}
+
+func TestBucketQuantile(t *testing.T) {
+	buckets := func() []bucket {
+		return []bucket{{upperBound: math.Inf(1), count: 10}, {upperBound: 0.5, count: 10}, {upperBound: 0.25, count: 5}}
+	}
+
+	require.Equal(t, 0.25, bucketQuantile(0.5, buckets()))
+	require.Equal(t, 0.375, bucketQuantile(0.75, buckets()))
+	require.Equal(t, math.Inf(-1), bucketQuantile(-1, buckets()))
+	require.Equal(t, math.Inf(1), bucketQuantile(2, buckets()))
+	require.True(t, math.IsNaN(bucketQuantile(0.5, []bucket{{upperBound: 1, count: 10}, {upperBound: 2, count: 10}})), "no +Inf bucket")
+	require.True(t, math.IsNaN(bucketQuantile(0.5, []bucket{{upperBound: 1, count: 0}, {upperBound: math.Inf(1), count: 0}})), "no observations")
+	require.Equal(t, 0.5, bucketQuantile(1, []bucket{{upperBound: 0.5, count: 5}, {upperBound: math.Inf(1), count: 10}}), "quantile in the +Inf bucket")
+}
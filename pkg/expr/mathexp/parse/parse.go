@@ -329,6 +329,8 @@ func (t *Tree) Func() (f *FuncNode) {
 				t.errorf("Unquoting error: %s", err)
 			}
 			f.append(newString(token.pos, token.val, s))
+		case itemComma:
+			// separates the parameters
 		case itemRightParen:
 			return
 		}
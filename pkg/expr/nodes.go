@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/util/converter"
 )
 
 // label that is used when all mathexp.Series have 0 labels to make them identifiable by labels. The value of this label is extracted from value field names
@@ -320,7 +321,17 @@ func convertDataFramesToResults(ctx context.Context, frames data.Frames, datasou
 
 	filtered := make([]*data.Frame, 0, len(frames))
 	totalLen := 0
+	var histogramSeries []mathexp.Series
 	for _, frame := range frames {
+		// Native histograms are returned as heatmap cells, they are read as cumulative bucket series
+		if converter.IsHeatmapCellsFrame(frame) {
+			series, err := heatmapCellsToSeries(frame)
+			if err != nil {
+				return "", mathexp.Results{}, err
+			}
+			histogramSeries = append(histogramSeries, series...)
+			continue
+		}
 		schema := frame.TimeSeriesSchema()
 		// Check for TimeSeriesTypeNot in InfluxDB queries. A data frame of this type will cause
 		// the WideToMany() function to error out, which results in unhealthy alerts.
@@ -336,13 +347,16 @@ func convertDataFramesToResults(ctx context.Context, frames data.Frames, datasou
 		totalLen += len(schema.ValueIndices)
 	}
 
-	if len(filtered) == 0 {
+	if len(filtered) == 0 && len(histogramSeries) == 0 {
 		return "no data", mathexp.Results{Values: mathexp.Values{mathexp.NoData{Frame: frames[0]}}}, nil
 	}
 
 	maybeFixerFn := checkIfSeriesNeedToBeFixed(filtered, datasourceType)
 
-	vals := make([]mathexp.Value, 0, totalLen)
+	vals := make([]mathexp.Value, 0, totalLen+len(histogramSeries))
+	for _, ser := range histogramSeries {
+		vals = append(vals, ser)
+	}
 	for _, frame := range filtered {
 		series, err := WideToMany(frame, maybeFixerFn)
 		if err != nil {
@@ -353,7 +367,9 @@ func convertDataFramesToResults(ctx context.Context, frames data.Frames, datasou
 		}
 	}
 	dataType := "single frame series"
-	if len(filtered) > 1 {
+	if len(filtered) == 0 {
+		dataType = "histogram series"
+	} else if len(filtered) > 1 {
 		dataType = "multi frame series"
 	}
 	return dataType, mathexp.Results{
@@ -261,6 +261,10 @@ schemas: [{
 			cacheTimeout?: bool
 		}
 
+		// For data source plugins, if the plugin supports having queries with
+		// long time ranges split into smaller chunks by the Grafana server.
+		querySplitting?: bool
+
 		// Routes is a list of proxy routes, if any. For datasource plugins only.
 		routes?: [...#Route]
 
@@ -368,6 +368,10 @@ type PluginDef struct {
 		MinInterval *bool `json:"minInterval,omitempty"`
 	} `json:"queryOptions,omitempty"`
 
+	// For data source plugins, if the plugin supports having queries with
+	// long time ranges split into smaller chunks by the Grafana server.
+	QuerySplitting *bool `json:"querySplitting,omitempty"`
+
 	// Optional list of RBAC RoleRegistrations.
 	// Describes and organizes the default permissions associated with any of the Grafana basic roles,
 	// which characterizes what viewers, editors, admins, or grafana admins can do on the plugin.
@@ -70,12 +70,13 @@ func (r *xormRepositoryImpl) Add(ctx context.Context, item *annotations.Item) er
 	})
 }
 
-// AddMany inserts large batches of annotations at once.
-// It does not return IDs associated with created annotations, and it does not support annotations with tags. If you need this functionality, use the single-item Add instead.
+// AddMany inserts large batches of annotations at once, in a single transaction.
+// It does not return IDs associated with created annotations. If you need this functionality, use the single-item Add instead.
 // This is due to a limitation with some supported databases:
 // We cannot correlate the IDs of batch-inserted records without acquiring a full table lock in MySQL.
 // Annotations have no other uniquifier field, so we also cannot re-query for them after the fact.
 // So, callers can only reliably use this endpoint if they don't care about returned IDs.
+// Annotations with tags are inserted one by one to get their IDs, but their tags are resolved and linked in batches.
 func (r *xormRepositoryImpl) AddMany(ctx context.Context, items []annotations.Item) error {
 	hasTags := make([]annotations.Item, 0)
 	hasNoTags := make([]annotations.Item, 0)
@@ -104,26 +105,78 @@ func (r *xormRepositoryImpl) AddMany(ctx context.Context, items []annotations.It
 		}
 	}
 
-	return r.db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
-		// We can batch-insert every annotation with no tags. If an annotation has tags, we need the ID.
-		opts := sqlstore.NativeSettingsForDialect(r.db.GetDialect())
-		if _, err := sess.BulkInsert("annotation", hasNoTags, opts); err != nil {
-			return err
-		}
-
-		for i, item := range hasTags {
-			if _, err := sess.Table("annotation").Insert(item); err != nil {
+	return r.db.InTransaction(ctx, func(ctx context.Context) error {
+		return r.db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+			// We can batch-insert every annotation with no tags. If an annotation has tags, we need the ID.
+			opts := sqlstore.NativeSettingsForDialect(r.db.GetDialect())
+			if _, err := sess.BulkInsert("annotation", hasNoTags, opts); err != nil {
 				return err
 			}
-			if err := r.synchronizeTags(ctx, &hasTags[i]); err != nil {
+			if len(hasTags) == 0 {
+				return nil
+			}
+
+			tagIDs, err := r.ensureTagsExist(ctx, hasTags)
+			if err != nil {
 				return err
 			}
-		}
 
-		return nil
+			annotationTags := make([]annotationTag, 0, len(hasTags))
+			for i := range hasTags {
+				item := &hasTags[i]
+				if _, err := sess.Table("annotation").Insert(item); err != nil {
+					return err
+				}
+				for _, t := range tag.ParseTagPairs(item.Tags) {
+					annotationTags = append(annotationTags, annotationTag{
+						AnnotationID: item.ID,
+						TagID:        tagIDs[tagPair{key: t.Key, value: t.Value}],
+					})
+				}
+			}
+
+			_, err = sess.BulkInsert("annotation_tag", annotationTags, opts)
+			return err
+		})
 	})
 }
 
+type annotationTag struct {
+	ID           int64 `xorm:"pk autoincr 'id'"`
+	AnnotationID int64 `xorm:"annotation_id"`
+	TagID        int64 `xorm:"tag_id"`
+}
+
+type tagPair struct {
+	key   string
+	value string
+}
+
+// ensureTagsExist creates the tags of all the items that do not exist yet, and returns the IDs of the tags.
+func (r *xormRepositoryImpl) ensureTagsExist(ctx context.Context, items []annotations.Item) (map[tagPair]int64, error) {
+	tagIDs := make(map[tagPair]int64)
+	tags := make([]*tag.Tag, 0)
+	for _, item := range items {
+		for _, t := range tag.ParseTagPairs(item.Tags) {
+			pair := tagPair{key: t.Key, value: t.Value}
+			if _, ok := tagIDs[pair]; ok {
+				continue
+			}
+			tagIDs[pair] = 0
+			tags = append(tags, t)
+		}
+	}
+
+	tags, err := r.tagService.EnsureTagsExist(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		tagIDs[tagPair{key: t.Key, value: t.Value}] = t.Id
+	}
+	return tagIDs, nil
+}
+
 func (r *xormRepositoryImpl) synchronizeTags(ctx context.Context, item *annotations.Item) error {
 	// Will re-use session if one has already been opened with the same ctx.
 	return r.db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
@@ -276,34 +329,10 @@ func (r *xormRepositoryImpl) Get(ctx context.Context, query *annotations.ItemQue
 		}
 
 		if len(query.Tags) > 0 {
-			keyValueFilters := []string{}
-
-			tags := tag.ParseTagPairs(query.Tags)
-			for _, tag := range tags {
-				if tag.Value == "" {
-					keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ?)")
-					params = append(params, tag.Key)
-				} else {
-					keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ? AND tag."+r.db.GetDialect().Quote("value")+" = ?)")
-					params = append(params, tag.Key, tag.Value)
-				}
-			}
-
-			if len(tags) > 0 {
-				tagsSubQuery := fmt.Sprintf(`
-			SELECT SUM(1) FROM annotation_tag at
-			INNER JOIN tag on tag.id = at.tag_id
-			WHERE at.annotation_id = a.id
-				AND (
-				%s
-				)
-		`, strings.Join(keyValueFilters, " OR "))
-
-				if query.MatchAny {
-					sql.WriteString(fmt.Sprintf(" AND (%s) > 0 ", tagsSubQuery))
-				} else {
-					sql.WriteString(fmt.Sprintf(" AND (%s) = %d ", tagsSubQuery, len(tags)))
-				}
+			tagFilter, tagParams := r.getTagFilter(tag.ParseTagPairs(query.Tags), query.MatchAny)
+			if tagFilter != "" {
+				sql.WriteString(" AND " + tagFilter)
+				params = append(params, tagParams...)
 			}
 		}
 
@@ -343,6 +372,46 @@ func (r *xormRepositoryImpl) Get(ctx context.Context, query *annotations.ItemQue
 	return items, err
 }
 
+// getTagFilter returns the condition matching the annotations with all the tags, or any of the tags if matchAny is set.
+// A tag without a value matches all the values of the key. The tags are looked up first in the tag table, which is
+// indexed by key and value, and then in annotation_tag, so that the database does not run a subquery per annotation.
+func (r *xormRepositoryImpl) getTagFilter(tags []*tag.Tag, matchAny bool) (string, []interface{}) {
+	if len(tags) == 0 {
+		return "", nil
+	}
+
+	tagKey := "tag." + r.db.GetDialect().Quote("key")
+	tagValue := "tag." + r.db.GetDialect().Quote("value")
+
+	keyValueFilters := make([]string, 0, len(tags))
+	params := make([]interface{}, 0, 2*len(tags))
+	for _, t := range tags {
+		if t.Value == "" {
+			keyValueFilters = append(keyValueFilters, "("+tagKey+" = ?)")
+			params = append(params, t.Key)
+		} else {
+			keyValueFilters = append(keyValueFilters, "("+tagKey+" = ? AND "+tagValue+" = ?)")
+			params = append(params, t.Key, t.Value)
+		}
+	}
+
+	tagsSubQuery := `a.id IN (
+			SELECT at.annotation_id FROM annotation_tag at
+			INNER JOIN tag ON tag.id = at.tag_id
+			WHERE %s
+		)`
+
+	if matchAny {
+		return fmt.Sprintf(tagsSubQuery, strings.Join(keyValueFilters, " OR ")), params
+	}
+
+	filters := make([]string, 0, len(keyValueFilters))
+	for _, filter := range keyValueFilters {
+		filters = append(filters, fmt.Sprintf(tagsSubQuery, filter))
+	}
+	return "(" + strings.Join(filters, " AND ") + ")", params
+}
+
 type acFilter struct {
 	where       string
 	whereParams []interface{}
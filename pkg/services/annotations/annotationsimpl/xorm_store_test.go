@@ -210,6 +210,7 @@ func TestIntegrationAnnotations(t *testing.T) {
 				}
 			}
 			items[0].Tags = []string{"type:test"}
+			items[1].Tags = []string{"type:test", "batch"}
 
 			err := repo.AddMany(context.Background(), items)
 
@@ -218,6 +219,17 @@ func TestIntegrationAnnotations(t *testing.T) {
 			inserted, err := repo.Get(context.Background(), query)
 			require.NoError(t, err)
 			assert.Len(t, inserted, count)
+
+			query = &annotations.ItemQuery{OrgID: 101, Tags: []string{"type:test"}, SignedInUser: testUser}
+			tagged, err := repo.Get(context.Background(), query)
+			require.NoError(t, err)
+			assert.Len(t, tagged, 2)
+
+			query = &annotations.ItemQuery{OrgID: 101, Tags: []string{"type:test", "batch"}, SignedInUser: testUser}
+			tagged, err = repo.Get(context.Background(), query)
+			require.NoError(t, err)
+			require.Len(t, tagged, 1)
+			assert.Equal(t, []string{"type:test", "batch"}, tagged[0].Tags)
 		})
 
 		t.Run("Can query for annotation by id", func(t *testing.T) {
@@ -308,6 +320,31 @@ func TestIntegrationAnnotations(t *testing.T) {
 			assert.Len(t, items, 1)
 		})
 
+		t.Run("Should find one when a tag key filter matches a key value tag", func(t *testing.T) {
+			items, err := repo.Get(context.Background(), &annotations.ItemQuery{
+				OrgID:        1,
+				DashboardID:  1,
+				From:         1,
+				To:           15,
+				Tags:         []string{"type", "server", "error"},
+				SignedInUser: testUser,
+			})
+			require.NoError(t, err)
+			assert.Len(t, items, 1)
+		})
+
+		t.Run("Should not find any when only some of the tag filters match", func(t *testing.T) {
+			items, err := repo.Get(context.Background(), &annotations.ItemQuery{
+				OrgID:        1,
+				From:         1,
+				To:           25,
+				Tags:         []string{"outage", "deploy"},
+				SignedInUser: testUser,
+			})
+			require.NoError(t, err)
+			assert.Empty(t, items)
+		})
+
 		t.Run("Can update annotation and remove all tags", func(t *testing.T) {
 			query := &annotations.ItemQuery{
 				OrgID:        1,
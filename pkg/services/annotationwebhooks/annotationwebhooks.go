@@ -0,0 +1,15 @@
+package annotationwebhooks
+
+import (
+	"context"
+)
+
+type Service interface {
+	CreateWebhook(ctx context.Context, cmd *CreateWebhookCommand) (*Webhook, error)
+	UpdateWebhook(ctx context.Context, cmd *UpdateWebhookCommand) (*Webhook, error)
+	GetWebhook(ctx context.Context, query *GetWebhookQuery) (*Webhook, error)
+	GetWebhooks(ctx context.Context, query *GetWebhooksQuery) ([]*Webhook, error)
+	DeleteWebhook(ctx context.Context, cmd *DeleteWebhookCommand) error
+	// GetWebhookSecret returns the decrypted secret of the webhook, or an empty string if it has none.
+	GetWebhookSecret(ctx context.Context, webhook *Webhook) (string, error)
+}
@@ -0,0 +1,142 @@
+package annotationwebhooksimpl
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type Service struct {
+	store          store
+	secretsService secrets.Service
+	userService    user.Service
+}
+
+func ProvideService(db db.DB, secretsService secrets.Service, userService user.Service) *Service {
+	return &Service{
+		store:          &sqlStore{db: db},
+		secretsService: secretsService,
+		userService:    userService,
+	}
+}
+
+func (s *Service) CreateWebhook(ctx context.Context, cmd *annotationwebhooks.CreateWebhookCommand) (*annotationwebhooks.Webhook, error) {
+	webhook := &annotationwebhooks.Webhook{
+		UID:              util.GenerateShortUID(),
+		OrgID:            cmd.OrgID,
+		Name:             strings.TrimSpace(cmd.Name),
+		ServiceAccountID: cmd.ServiceAccountID,
+		Template:         cmd.Template,
+	}
+	if err := s.validate(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if webhook.Secret, err = s.encryptSecret(ctx, cmd.Secret); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Insert(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *Service) UpdateWebhook(ctx context.Context, cmd *annotationwebhooks.UpdateWebhookCommand) (*annotationwebhooks.Webhook, error) {
+	webhook, err := s.store.Get(ctx, cmd.OrgID, cmd.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Name = strings.TrimSpace(cmd.Name)
+	webhook.ServiceAccountID = cmd.ServiceAccountID
+	webhook.Template = cmd.Template
+	if err := s.validate(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	if cmd.Secret != nil {
+		if webhook.Secret, err = s.encryptSecret(ctx, *cmd.Secret); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.store.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *Service) GetWebhook(ctx context.Context, query *annotationwebhooks.GetWebhookQuery) (*annotationwebhooks.Webhook, error) {
+	return s.store.Get(ctx, query.OrgID, query.UID)
+}
+
+func (s *Service) GetWebhooks(ctx context.Context, query *annotationwebhooks.GetWebhooksQuery) ([]*annotationwebhooks.Webhook, error) {
+	return s.store.List(ctx, query.OrgID)
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, cmd *annotationwebhooks.DeleteWebhookCommand) error {
+	return s.store.Delete(ctx, cmd.OrgID, cmd.UID)
+}
+
+func (s *Service) GetWebhookSecret(ctx context.Context, webhook *annotationwebhooks.Webhook) (string, error) {
+	if webhook.Secret == "" {
+		return "", nil
+	}
+
+	encrypted, err := base64.StdEncoding.DecodeString(webhook.Secret)
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := s.secretsService.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
+
+func (s *Service) validate(ctx context.Context, webhook *annotationwebhooks.Webhook) error {
+	if webhook.Name == "" {
+		return annotationwebhooks.ErrWebhookNameRequired.Errorf("name is required")
+	}
+
+	if err := webhook.Template.Validate(); err != nil {
+		return err
+	}
+
+	// the annotations of the webhook are created with the permissions of the service account, so it has to be a
+	// service account of the organization of the webhook
+	sa, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: webhook.ServiceAccountID})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return annotationwebhooks.ErrWebhookServiceAccount.Errorf("service account %d not found", webhook.ServiceAccountID)
+		}
+		return err
+	}
+	if !sa.IsServiceAccount || sa.OrgID != webhook.OrgID {
+		return annotationwebhooks.ErrWebhookServiceAccount.Errorf("user %d is not a service account of organization %d", webhook.ServiceAccountID, webhook.OrgID)
+	}
+	return nil
+}
+
+// encryptSecret encrypts the secret with the secrets service. The result is base64 encoded, since it is stored in a
+// text column.
+func (s *Service) encryptSecret(ctx context.Context, secret string) (string, error) {
+	if secret == "" {
+		return "", nil
+	}
+
+	encrypted, err := s.secretsService.Encrypt(ctx, []byte(secret), secrets.WithoutScope())
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
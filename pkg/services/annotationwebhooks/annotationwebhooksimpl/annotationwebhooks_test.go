@@ -0,0 +1,118 @@
+package annotationwebhooksimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestIntegrationAnnotationWebhooks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	userService := usertest.NewUserServiceFake()
+	userService.ExpectedUser = &user.User{ID: 2, OrgID: 1, IsServiceAccount: true}
+	s := ProvideService(db.InitTestDB(t), fakes.NewFakeSecretsService(), userService)
+
+	template := annotationwebhooks.MappingTemplate{Text: "Deployed {{ .ref }}", Tags: []string{"deploy"}}
+
+	created, err := s.CreateWebhook(ctx, &annotationwebhooks.CreateWebhookCommand{
+		OrgID:            1,
+		Name:             "ci",
+		ServiceAccountID: 2,
+		Secret:           "s3cr3t",
+		Template:         template,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.UID)
+
+	t.Run("Gets the webhook and its secret", func(t *testing.T) {
+		webhook, err := s.GetWebhook(ctx, &annotationwebhooks.GetWebhookQuery{OrgID: 1, UID: created.UID})
+		require.NoError(t, err)
+		assert.Equal(t, "ci", webhook.Name)
+		assert.Equal(t, template, webhook.Template)
+		assert.NotEqual(t, "s3cr3t", webhook.Secret)
+
+		secret, err := s.GetWebhookSecret(ctx, webhook)
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", secret)
+	})
+
+	t.Run("Gets the webhook only in its organization", func(t *testing.T) {
+		for _, orgID := range []int64{0, 2} {
+			_, err := s.GetWebhook(ctx, &annotationwebhooks.GetWebhookQuery{OrgID: orgID, UID: created.UID})
+			require.ErrorIs(t, err, annotationwebhooks.ErrWebhookNotFound)
+		}
+	})
+
+	t.Run("Rejects an invalid webhook", func(t *testing.T) {
+		_, err := s.CreateWebhook(ctx, &annotationwebhooks.CreateWebhookCommand{OrgID: 1, Name: "ci", ServiceAccountID: 2, Template: template})
+		require.ErrorIs(t, err, annotationwebhooks.ErrWebhookNameTaken)
+
+		_, err = s.CreateWebhook(ctx, &annotationwebhooks.CreateWebhookCommand{OrgID: 1, Name: "", ServiceAccountID: 2, Template: template})
+		require.ErrorIs(t, err, annotationwebhooks.ErrWebhookNameRequired)
+
+		_, err = s.CreateWebhook(ctx, &annotationwebhooks.CreateWebhookCommand{OrgID: 1, Name: "cd", ServiceAccountID: 2})
+		require.ErrorIs(t, err, annotationwebhooks.ErrWebhookInvalidTemplate)
+
+		_, err = s.CreateWebhook(ctx, &annotationwebhooks.CreateWebhookCommand{OrgID: 2, Name: "cd", ServiceAccountID: 2, Template: template})
+		require.ErrorIs(t, err, annotationwebhooks.ErrWebhookServiceAccount)
+	})
+
+	t.Run("Updates the webhook and keeps the secret if it is not set", func(t *testing.T) {
+		updated, err := s.UpdateWebhook(ctx, &annotationwebhooks.UpdateWebhookCommand{
+			OrgID:            1,
+			UID:              created.UID,
+			Name:             "deployments",
+			ServiceAccountID: 2,
+			Template:         annotationwebhooks.MappingTemplate{Text: "Released {{ .ref }}"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "deployments", updated.Name)
+
+		webhook, err := s.GetWebhook(ctx, &annotationwebhooks.GetWebhookQuery{OrgID: 1, UID: created.UID})
+		require.NoError(t, err)
+		assert.Equal(t, "Released {{ .ref }}", webhook.Template.Text)
+		secret, err := s.GetWebhookSecret(ctx, webhook)
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", secret)
+
+		noSecret := ""
+		_, err = s.UpdateWebhook(ctx, &annotationwebhooks.UpdateWebhookCommand{
+			OrgID:            1,
+			UID:              created.UID,
+			Name:             "deployments",
+			ServiceAccountID: 2,
+			Secret:           &noSecret,
+			Template:         webhook.Template,
+		})
+		require.NoError(t, err)
+		webhook, err = s.GetWebhook(ctx, &annotationwebhooks.GetWebhookQuery{OrgID: 1, UID: created.UID})
+		require.NoError(t, err)
+		assert.Empty(t, webhook.Secret)
+	})
+
+	t.Run("Lists and deletes the webhooks", func(t *testing.T) {
+		webhooks, err := s.GetWebhooks(ctx, &annotationwebhooks.GetWebhooksQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+
+		require.NoError(t, s.DeleteWebhook(ctx, &annotationwebhooks.DeleteWebhookCommand{OrgID: 1, UID: created.UID}))
+		err = s.DeleteWebhook(ctx, &annotationwebhooks.DeleteWebhookCommand{OrgID: 1, UID: created.UID})
+		require.ErrorIs(t, err, annotationwebhooks.ErrWebhookNotFound)
+
+		webhooks, err = s.GetWebhooks(ctx, &annotationwebhooks.GetWebhooksQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, webhooks)
+	})
+}
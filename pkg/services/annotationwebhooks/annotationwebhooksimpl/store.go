@@ -0,0 +1,15 @@
+package annotationwebhooksimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks"
+)
+
+type store interface {
+	Insert(context.Context, *annotationwebhooks.Webhook) error
+	Update(context.Context, *annotationwebhooks.Webhook) error
+	Get(ctx context.Context, orgID int64, uid string) (*annotationwebhooks.Webhook, error)
+	List(ctx context.Context, orgID int64) ([]*annotationwebhooks.Webhook, error)
+	Delete(ctx context.Context, orgID int64, uid string) error
+}
@@ -0,0 +1,96 @@
+package annotationwebhooksimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks"
+)
+
+type sqlStore struct {
+	db db.DB
+}
+
+func (ss *sqlStore) Insert(ctx context.Context, webhook *annotationwebhooks.Webhook) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := checkNameAvailable(sess, webhook); err != nil {
+			return err
+		}
+
+		webhook.Created = time.Now()
+		webhook.Updated = webhook.Created
+		_, err := sess.Insert(webhook)
+		return err
+	})
+}
+
+func (ss *sqlStore) Update(ctx context.Context, webhook *annotationwebhooks.Webhook) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := checkNameAvailable(sess, webhook); err != nil {
+			return err
+		}
+
+		webhook.Updated = time.Now()
+		affected, err := sess.ID(webhook.ID).AllCols().Omit("id", "uid", "org_id", "created").Update(webhook)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return annotationwebhooks.ErrWebhookNotFound.Errorf("webhook %s not found", webhook.UID)
+		}
+		return nil
+	})
+}
+
+func (ss *sqlStore) Get(ctx context.Context, orgID int64, uid string) (*annotationwebhooks.Webhook, error) {
+	var webhook annotationwebhooks.Webhook
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id=? AND uid=?", orgID, uid).Get(&webhook)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return annotationwebhooks.ErrWebhookNotFound.Errorf("webhook %s not found", uid)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (ss *sqlStore) List(ctx context.Context, orgID int64) ([]*annotationwebhooks.Webhook, error) {
+	webhooks := make([]*annotationwebhooks.Webhook, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id=?", orgID).OrderBy("name").Find(&webhooks)
+	})
+	return webhooks, err
+}
+
+func (ss *sqlStore) Delete(ctx context.Context, orgID int64, uid string) error {
+	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id=? AND uid=?", orgID, uid).Delete(&annotationwebhooks.Webhook{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return annotationwebhooks.ErrWebhookNotFound.Errorf("webhook %s not found", uid)
+		}
+		return nil
+	})
+}
+
+// checkNameAvailable returns an error if another webhook of the organization has the same name. The unique index
+// would reject it as well, but with an error that depends on the database.
+func checkNameAvailable(sess *db.Session, webhook *annotationwebhooks.Webhook) error {
+	exists, err := sess.Table("annotation_webhook").Where("org_id=? AND name=? AND uid<>?", webhook.OrgID, webhook.Name, webhook.UID).Exist()
+	if err != nil {
+		return err
+	}
+	if exists {
+		return annotationwebhooks.ErrWebhookNameTaken.Errorf("webhook %q already exists", webhook.Name)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package annotationwebhookstest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks"
+)
+
+type FakeAnnotationWebhookService struct {
+	ExpectedWebhook  *annotationwebhooks.Webhook
+	ExpectedWebhooks []*annotationwebhooks.Webhook
+	ExpectedSecret   string
+	ExpectedError    error
+}
+
+func NewFakeAnnotationWebhookService() *FakeAnnotationWebhookService {
+	return &FakeAnnotationWebhookService{}
+}
+
+func (f *FakeAnnotationWebhookService) CreateWebhook(ctx context.Context, cmd *annotationwebhooks.CreateWebhookCommand) (*annotationwebhooks.Webhook, error) {
+	return f.ExpectedWebhook, f.ExpectedError
+}
+
+func (f *FakeAnnotationWebhookService) UpdateWebhook(ctx context.Context, cmd *annotationwebhooks.UpdateWebhookCommand) (*annotationwebhooks.Webhook, error) {
+	return f.ExpectedWebhook, f.ExpectedError
+}
+
+func (f *FakeAnnotationWebhookService) GetWebhook(ctx context.Context, query *annotationwebhooks.GetWebhookQuery) (*annotationwebhooks.Webhook, error) {
+	return f.ExpectedWebhook, f.ExpectedError
+}
+
+func (f *FakeAnnotationWebhookService) GetWebhooks(ctx context.Context, query *annotationwebhooks.GetWebhooksQuery) ([]*annotationwebhooks.Webhook, error) {
+	return f.ExpectedWebhooks, f.ExpectedError
+}
+
+func (f *FakeAnnotationWebhookService) DeleteWebhook(ctx context.Context, cmd *annotationwebhooks.DeleteWebhookCommand) error {
+	return f.ExpectedError
+}
+
+func (f *FakeAnnotationWebhookService) GetWebhookSecret(ctx context.Context, webhook *annotationwebhooks.Webhook) (string, error) {
+	return f.ExpectedSecret, f.ExpectedError
+}
@@ -0,0 +1,131 @@
+package annotationwebhooks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrWebhookNotFound         = errutil.NewBase(errutil.StatusNotFound, "annotationwebhooks.not-found", errutil.WithPublicMessage("Annotation webhook not found"))
+	ErrWebhookNameTaken        = errutil.NewBase(errutil.StatusBadRequest, "annotationwebhooks.name-taken", errutil.WithPublicMessage("An annotation webhook with the same name already exists"))
+	ErrWebhookNameRequired     = errutil.NewBase(errutil.StatusBadRequest, "annotationwebhooks.name-required", errutil.WithPublicMessage("The name of the annotation webhook is required"))
+	ErrWebhookServiceAccount   = errutil.NewBase(errutil.StatusBadRequest, "annotationwebhooks.invalid-service-account", errutil.WithPublicMessage("The annotation webhook must be bound to a service account of the organization"))
+	ErrWebhookInvalidTemplate  = errutil.NewBase(errutil.StatusBadRequest, "annotationwebhooks.invalid-template")
+	ErrWebhookInvalidPayload   = errutil.NewBase(errutil.StatusBadRequest, "annotationwebhooks.invalid-payload")
+	ErrWebhookInvalidSignature = errutil.NewBase(errutil.StatusUnauthorized, "annotationwebhooks.invalid-signature", errutil.WithPublicMessage("Invalid webhook signature"))
+	ErrWebhookPayloadTooLarge  = errutil.NewBase(errutil.StatusRequestEntityTooLarge, "annotationwebhooks.payload-too-large", errutil.WithPublicMessage("Event payload is too large"))
+)
+
+// MaxPayloadBytes is the maximum size of the payload of an inbound event.
+const MaxPayloadBytes = 4 << 20
+
+// Webhook receives events from external systems, like CI/CD pipelines, and turns them into annotations with its
+// mapping template. Events are authenticated with a service account token, or with a signature computed with the
+// secret of the webhook. Signed events are handled as if they were sent by the service account of the webhook.
+type Webhook struct {
+	ID               int64           `xorm:"pk autoincr 'id'"`
+	UID              string          `xorm:"uid"`
+	OrgID            int64           `xorm:"org_id"`
+	Name             string          `xorm:"name"`
+	ServiceAccountID int64           `xorm:"service_account_id"`
+	Secret           string          `xorm:"secret"`
+	Template         MappingTemplate `xorm:"jsonb template"`
+	Created          time.Time       `xorm:"created"`
+	Updated          time.Time       `xorm:"updated"`
+}
+
+func (w Webhook) TableName() string {
+	return "annotation_webhook"
+}
+
+// ToDTO returns the representation of the webhook returned by the API. appURL is the root URL of Grafana.
+func (w *Webhook) ToDTO(appURL string) *WebhookDTO {
+	return &WebhookDTO{
+		UID:              w.UID,
+		OrgID:            w.OrgID,
+		Name:             w.Name,
+		ServiceAccountID: w.ServiceAccountID,
+		HasSecret:        w.Secret != "",
+		Template:         w.Template,
+		URL:              fmt.Sprintf("%s/api/annotations/webhooks/%s/events?targetOrgId=%d", strings.TrimSuffix(appURL, "/"), w.UID, w.OrgID),
+		Created:          w.Created,
+		Updated:          w.Updated,
+	}
+}
+
+// MappingTemplate maps the JSON payload of an event to an annotation. Each field is a Go text/template evaluated with
+// the payload as data, for example "Deployed {{ .deployment.ref }}". Fields missing from the payload render as empty.
+type MappingTemplate struct {
+	// Condition skips the event when it renders to an empty string, "false" or "0".
+	Condition string `json:"condition,omitempty"`
+	// Text of the annotation, required.
+	Text string `json:"text"`
+	// Tags of the annotation. Tags rendering to an empty string are ignored.
+	Tags []string `json:"tags,omitempty"`
+	// DashboardUID of the dashboard of the annotation. The annotation is an organization annotation if it is empty.
+	DashboardUID string `json:"dashboardUID,omitempty"`
+	// PanelID of the panel of the annotation.
+	PanelID string `json:"panelId,omitempty"`
+	// Time of the annotation, as epoch milliseconds or an RFC 3339 date. Defaults to the time the event is received.
+	Time string `json:"time,omitempty"`
+	// TimeEnd of a region annotation, as epoch milliseconds or an RFC 3339 date.
+	TimeEnd string `json:"timeEnd,omitempty"`
+}
+
+// WebhookDTO is the representation of a webhook returned by the API. It never contains the secret.
+type WebhookDTO struct {
+	UID              string          `json:"uid"`
+	OrgID            int64           `json:"orgId"`
+	Name             string          `json:"name"`
+	ServiceAccountID int64           `json:"serviceAccountId"`
+	HasSecret        bool            `json:"hasSecret"`
+	Template         MappingTemplate `json:"template"`
+	// URL events are sent to.
+	URL     string    `json:"url"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+type CreateWebhookCommand struct {
+	OrgID int64 `json:"-"`
+	// required: true
+	Name string `json:"name"`
+	// Service account the annotations are created by. Signed events are handled with its permissions.
+	// required: true
+	ServiceAccountID int64 `json:"serviceAccountId"`
+	// Secret used to verify the signature of the events. Events without a service account token are rejected if
+	// it is empty.
+	Secret string `json:"secret"`
+	// required: true
+	Template MappingTemplate `json:"template"`
+}
+
+type UpdateWebhookCommand struct {
+	UID   string `json:"-"`
+	OrgID int64  `json:"-"`
+	// required: true
+	Name string `json:"name"`
+	// required: true
+	ServiceAccountID int64 `json:"serviceAccountId"`
+	// Secret replaces the secret of the webhook when it is set. An empty string removes the secret.
+	Secret *string `json:"secret,omitempty"`
+	// required: true
+	Template MappingTemplate `json:"template"`
+}
+
+type GetWebhookQuery struct {
+	UID   string
+	OrgID int64
+}
+
+type GetWebhooksQuery struct {
+	OrgID int64
+}
+
+type DeleteWebhookCommand struct {
+	UID   string
+	OrgID int64
+}
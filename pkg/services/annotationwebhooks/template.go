@@ -0,0 +1,242 @@
+package annotationwebhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// noValue is what text/template prints for the keys missing from a map
+const noValue = "<no value>"
+
+var templateFuncs = template.FuncMap{
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"trim":    strings.TrimSpace,
+	"join":    join,
+	"default": defaultValue,
+	"json":    toJSON,
+}
+
+// timeLayouts are the date formats accepted for the time of an annotation, in addition to epoch milliseconds.
+// The second and third ones are used by GitLab.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05 -0700",
+}
+
+// Mapping is a compiled MappingTemplate.
+type Mapping struct {
+	condition    *template.Template
+	text         *template.Template
+	tags         []*template.Template
+	dashboardUID *template.Template
+	panelID      *template.Template
+	time         *template.Template
+	timeEnd      *template.Template
+}
+
+// Annotation is the annotation rendered from the payload of an event.
+type Annotation struct {
+	Text         string
+	Tags         []string
+	DashboardUID string
+	PanelID      int64
+	// Time and TimeEnd are epoch milliseconds, 0 if they are not set.
+	Time    int64
+	TimeEnd int64
+}
+
+// Validate checks that the template has a text and that all its fields are valid templates.
+func (t MappingTemplate) Validate() error {
+	_, err := t.Compile()
+	return err
+}
+
+// Compile parses all the fields of the template.
+func (t MappingTemplate) Compile() (*Mapping, error) {
+	if strings.TrimSpace(t.Text) == "" {
+		return nil, withPublicMessage(ErrWebhookInvalidTemplate.Errorf("text is required"), "The text of the template is required")
+	}
+
+	var err error
+	m := &Mapping{}
+	parse := func(name, text string) *template.Template {
+		if err != nil || text == "" {
+			return nil
+		}
+		var tmpl *template.Template
+		tmpl, err = template.New(name).Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			err = withPublicMessage(ErrWebhookInvalidTemplate.Errorf("invalid %s template: %w", name, err), fmt.Sprintf("Invalid %s template: %s", name, err))
+		}
+		return tmpl
+	}
+
+	m.condition = parse("condition", t.Condition)
+	m.text = parse("text", t.Text)
+	for i, tag := range t.Tags {
+		m.tags = append(m.tags, parse(fmt.Sprintf("tags[%d]", i), tag))
+	}
+	m.dashboardUID = parse("dashboardUID", t.DashboardUID)
+	m.panelID = parse("panelId", t.PanelID)
+	m.time = parse("time", t.Time)
+	m.timeEnd = parse("timeEnd", t.TimeEnd)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ParsePayload decodes the JSON payload of an event. Numbers are kept as written, so that IDs and timestamps are not
+// rendered in scientific notation.
+func ParsePayload(body []byte) (interface{}, error) {
+	var payload interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, withPublicMessage(ErrWebhookInvalidPayload.Errorf("failed to decode payload: %w", err), "The payload must be a JSON document")
+	}
+	return payload, nil
+}
+
+// Render renders the annotation of an event. It returns nil if the condition of the template skips the event.
+func (m *Mapping) Render(payload interface{}) (*Annotation, error) {
+	if m.condition != nil {
+		condition, err := execute(m.condition, payload)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(strings.TrimSpace(condition)) {
+		case "", "false", "0":
+			return nil, nil
+		}
+	}
+
+	text, err := execute(m.text, payload)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, withPublicMessage(ErrWebhookInvalidPayload.Errorf("rendered text is empty"), "The text rendered from the payload is empty")
+	}
+
+	a := &Annotation{Text: text, Tags: []string{}}
+	for _, tmpl := range m.tags {
+		tag, err := execute(tmpl, payload)
+		if err != nil {
+			return nil, err
+		}
+		if tag = strings.TrimSpace(tag); tag != "" {
+			a.Tags = append(a.Tags, tag)
+		}
+	}
+
+	if a.DashboardUID, err = execute(m.dashboardUID, payload); err != nil {
+		return nil, err
+	}
+	a.DashboardUID = strings.TrimSpace(a.DashboardUID)
+
+	panelID, err := execute(m.panelID, payload)
+	if err != nil {
+		return nil, err
+	}
+	if panelID = strings.TrimSpace(panelID); panelID != "" {
+		if a.PanelID, err = strconv.ParseInt(panelID, 10, 64); err != nil {
+			return nil, withPublicMessage(ErrWebhookInvalidPayload.Errorf("invalid panel ID %q: %w", panelID, err), fmt.Sprintf("Invalid panel ID %q", panelID))
+		}
+	}
+
+	if a.Time, err = renderTime(m.time, payload); err != nil {
+		return nil, err
+	}
+	if a.TimeEnd, err = renderTime(m.timeEnd, payload); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// withPublicMessage sets a public message with the details of an invalid template or payload
+func withPublicMessage(err errutil.Error, message string) error {
+	err.PublicMessage = message
+	return err
+}
+
+func execute(tmpl *template.Template, payload interface{}) (string, error) {
+	if tmpl == nil {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, payload); err != nil {
+		return "", withPublicMessage(ErrWebhookInvalidPayload.Errorf("failed to render %s template: %w", tmpl.Name(), err), fmt.Sprintf("Failed to render the %s template", tmpl.Name()))
+	}
+	return strings.ReplaceAll(buf.String(), noValue, ""), nil
+}
+
+func renderTime(tmpl *template.Template, payload interface{}) (int64, error) {
+	value, err := execute(tmpl, payload)
+	if err != nil {
+		return 0, err
+	}
+	ms, err := parseTime(value)
+	if err != nil {
+		return 0, withPublicMessage(ErrWebhookInvalidPayload.Errorf("invalid %s: %w", tmpl.Name(), err), fmt.Sprintf("Invalid %s %q", tmpl.Name(), value))
+	}
+	return ms, nil
+}
+
+// parseTime parses epoch milliseconds or a date in one of the timeLayouts. It returns 0 for an empty string.
+func parseTime(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return ms, nil
+	}
+	if ms, err := strconv.ParseFloat(value, 64); err == nil {
+		return int64(ms), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+	return 0, fmt.Errorf("%q is neither epoch milliseconds nor an RFC 3339 date", value)
+}
+
+// join joins the values of a list, for example {{ .labels | join "," }}
+func join(sep string, values interface{}) string {
+	list, ok := values.([]interface{})
+	if !ok {
+		return fmt.Sprint(values)
+	}
+	parts := make([]string, 0, len(list))
+	for _, v := range list {
+		parts = append(parts, fmt.Sprint(v))
+	}
+	return strings.Join(parts, sep)
+}
+
+// defaultValue returns the default when the value is missing or empty, for example {{ .ref | default "main" }}
+func defaultValue(def interface{}, value interface{}) interface{} {
+	if value == nil || value == "" {
+		return def
+	}
+	return value
+}
+
+func toJSON(value interface{}) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
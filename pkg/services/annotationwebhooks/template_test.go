@@ -0,0 +1,132 @@
+package annotationwebhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMappingTemplateValidate(t *testing.T) {
+	type test struct {
+		name      string
+		template  MappingTemplate
+		assertion require.ErrorAssertionFunc
+	}
+
+	tests := []test{
+		{name: "text", template: MappingTemplate{Text: "Deployed {{ .ref }}"}, assertion: require.NoError},
+		{name: "all fields", template: MappingTemplate{
+			Condition:    `{{ eq .status "success" }}`,
+			Text:         "Deployed {{ .ref }}",
+			Tags:         []string{"deploy", "{{ .env | lower }}"},
+			DashboardUID: "{{ .dashboard }}",
+			PanelID:      "{{ .panel }}",
+			Time:         "{{ .started_at }}",
+			TimeEnd:      "{{ .finished_at }}",
+		}, assertion: require.NoError},
+		{name: "without text", template: MappingTemplate{Tags: []string{"deploy"}}, assertion: require.Error},
+		{name: "invalid text", template: MappingTemplate{Text: "Deployed {{ .ref "}, assertion: require.Error},
+		{name: "invalid tag", template: MappingTemplate{Text: "Deployed", Tags: []string{"{{ unknown .env }}"}}, assertion: require.Error},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.template.Validate()
+			tc.assertion(t, err)
+			if err != nil {
+				require.ErrorIs(t, err, ErrWebhookInvalidTemplate)
+			}
+		})
+	}
+}
+
+func TestMappingRender(t *testing.T) {
+	render := func(t *testing.T, tmpl MappingTemplate, body string) (*Annotation, error) {
+		t.Helper()
+		mapping, err := tmpl.Compile()
+		require.NoError(t, err)
+		payload, err := ParsePayload([]byte(body))
+		require.NoError(t, err)
+		return mapping.Render(payload)
+	}
+
+	t.Run("Maps the payload to an annotation", func(t *testing.T) {
+		annotation, err := render(t, MappingTemplate{
+			Text:         "Deployed {{ .deployment.ref }} to {{ .deployment.environment | upper }}",
+			Tags:         []string{"deploy", "{{ .deployment.environment }}", "{{ .missing }}"},
+			DashboardUID: "{{ .dashboard }}",
+			PanelID:      "{{ .panel }}",
+			Time:         "{{ .deployment.created_at }}",
+			TimeEnd:      "{{ .finished }}",
+		}, `{
+			"deployment": {"ref": "v1.2.3", "environment": "prod", "created_at": "2022-10-11T12:00:00Z"},
+			"dashboard": "abc",
+			"panel": 4,
+			"finished": 1665490000123
+		}`)
+		require.NoError(t, err)
+
+		assert.Equal(t, &Annotation{
+			Text:         "Deployed v1.2.3 to PROD",
+			Tags:         []string{"deploy", "prod"},
+			DashboardUID: "abc",
+			PanelID:      4,
+			Time:         time.Date(2022, 10, 11, 12, 0, 0, 0, time.UTC).UnixMilli(),
+			TimeEnd:      1665490000123,
+		}, annotation)
+	})
+
+	t.Run("Supports the helper functions", func(t *testing.T) {
+		annotation, err := render(t, MappingTemplate{
+			Text: `{{ .labels | join ", " }} on {{ .branch | default "main" }} {{ .meta | json }}`,
+		}, `{"labels": ["a", "b"], "branch": "", "meta": {"id": 1}}`)
+		require.NoError(t, err)
+		assert.Equal(t, `a, b on main {"id":1}`, annotation.Text)
+	})
+
+	t.Run("Skips the event if the condition is false", func(t *testing.T) {
+		tmpl := MappingTemplate{Condition: `{{ eq .status "success" }}`, Text: "Deployed"}
+
+		annotation, err := render(t, tmpl, `{"status": "failed"}`)
+		require.NoError(t, err)
+		require.Nil(t, annotation)
+
+		annotation, err = render(t, tmpl, `{"status": "success"}`)
+		require.NoError(t, err)
+		require.Equal(t, "Deployed", annotation.Text)
+	})
+
+	t.Run("Skips the event if the condition renders empty", func(t *testing.T) {
+		annotation, err := render(t, MappingTemplate{Condition: "{{ .deployment }}", Text: "Deployed"}, `{"push": {}}`)
+		require.NoError(t, err)
+		require.Nil(t, annotation)
+	})
+
+	t.Run("Parses GitLab dates", func(t *testing.T) {
+		annotation, err := render(t, MappingTemplate{Text: "Pipeline", Time: "{{ .created_at }}"}, `{"created_at": "2022-10-11 12:00:00 UTC"}`)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2022, 10, 11, 12, 0, 0, 0, time.UTC).UnixMilli(), annotation.Time)
+	})
+
+	t.Run("Returns an error if the text is empty", func(t *testing.T) {
+		_, err := render(t, MappingTemplate{Text: "{{ .missing }}"}, `{}`)
+		require.ErrorIs(t, err, ErrWebhookInvalidPayload)
+	})
+
+	t.Run("Returns an error if the panel ID is not a number", func(t *testing.T) {
+		_, err := render(t, MappingTemplate{Text: "Deployed", PanelID: "{{ .panel }}"}, `{"panel": "graph"}`)
+		require.ErrorIs(t, err, ErrWebhookInvalidPayload)
+	})
+
+	t.Run("Returns an error if the time is invalid", func(t *testing.T) {
+		_, err := render(t, MappingTemplate{Text: "Deployed", Time: "{{ .time }}"}, `{"time": "yesterday"}`)
+		require.ErrorIs(t, err, ErrWebhookInvalidPayload)
+	})
+}
+
+func TestParsePayload(t *testing.T) {
+	_, err := ParsePayload([]byte("not json"))
+	require.ErrorIs(t, err, ErrWebhookInvalidPayload)
+}
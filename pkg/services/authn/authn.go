@@ -32,6 +32,8 @@ const (
 	ClientForm        = "auth.client.form"
 	ClientProxy       = "auth.client.proxy"
 	ClientSAML        = "auth.client.saml"
+
+	ClientAnnotationWebhook = "auth.client.annotation-webhook"
 )
 
 const (
@@ -0,0 +1,144 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	// signatureHeader carries the HMAC SHA-256 of the payload, in the format used by GitHub: sha256=<hex digest>
+	signatureHeader = "X-Hub-Signature-256"
+	signaturePrefix = "sha256="
+	// gitlabTokenHeader carries the secret of the webhook as is
+	gitlabTokenHeader = "X-Gitlab-Token"
+)
+
+var (
+	errAnnotationWebhookNoServiceAccount = errutil.NewBase(errutil.StatusUnauthorized, "annotation-webhook.invalid-service-account", errutil.WithPublicMessage("Invalid webhook service account"))
+)
+
+var eventsPathPattern = regexp.MustCompile(`^/api/annotations/webhooks/([^/]+)/events$`)
+
+var _ authn.ContextAwareClient = new(AnnotationWebhook)
+
+func ProvideAnnotationWebhook(webhookService annotationwebhooks.Service, userService user.Service) *AnnotationWebhook {
+	return &AnnotationWebhook{
+		webhookService: webhookService,
+		userService:    userService,
+	}
+}
+
+// AnnotationWebhook authenticates the events sent to annotation webhooks with the secret of the webhook, for the
+// systems which sign their webhooks instead of sending a token. The events are handled as if they were sent by the
+// service account of the webhook. Events sent with a service account token are authenticated by the API key client.
+type AnnotationWebhook struct {
+	webhookService annotationwebhooks.Service
+	userService    user.Service
+}
+
+func (c *AnnotationWebhook) Name() string {
+	return authn.ClientAnnotationWebhook
+}
+
+func (c *AnnotationWebhook) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	uid := eventsPathPattern.FindStringSubmatch(r.HTTPRequest.URL.Path)[1]
+	// the organization is part of the URL of the webhook, as the targetOrgId query parameter
+	webhook, err := c.webhookService.GetWebhook(ctx, &annotationwebhooks.GetWebhookQuery{OrgID: r.OrgID, UID: uid})
+	if err != nil {
+		if errors.Is(err, annotationwebhooks.ErrWebhookNotFound) {
+			return nil, annotationwebhooks.ErrWebhookInvalidSignature.Errorf("webhook %s not found in organization %d", uid, r.OrgID)
+		}
+		return nil, err
+	}
+
+	secret, err := c.webhookService.GetWebhookSecret(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		return nil, annotationwebhooks.ErrWebhookInvalidSignature.Errorf("webhook %s has no secret", uid)
+	}
+
+	if err := verifyWebhookRequest(r.HTTPRequest, secret); err != nil {
+		return nil, err
+	}
+
+	usr, err := c.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{
+		UserID: webhook.ServiceAccountID,
+		OrgID:  webhook.OrgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !usr.IsServiceAccount {
+		return nil, errAnnotationWebhookNoServiceAccount.Errorf("user %d of webhook %s is not a service account", usr.UserID, uid)
+	}
+
+	return authn.IdentityFromSignedInUser(authn.NamespacedID(authn.NamespaceServiceAccount, usr.UserID), usr, authn.ClientParams{SyncPermissions: true}, login.AnnotationWebhookModule), nil
+}
+
+func (c *AnnotationWebhook) Test(ctx context.Context, r *authn.Request) bool {
+	if r.HTTPRequest == nil || r.HTTPRequest.Method != http.MethodPost {
+		return false
+	}
+	// requests with a token are authenticated by the API key client
+	if getTokenFromRequest(r) != "" {
+		return false
+	}
+	if !eventsPathPattern.MatchString(r.HTTPRequest.URL.Path) {
+		return false
+	}
+	return r.HTTPRequest.Header.Get(signatureHeader) != "" || r.HTTPRequest.Header.Get(gitlabTokenHeader) != ""
+}
+
+func (c *AnnotationWebhook) Priority() uint {
+	return 40
+}
+
+// verifyWebhookRequest checks the signature, or the token, of the request against the secret of the webhook. The
+// body of the request is restored, so that it can be read again by the handler.
+func verifyWebhookRequest(r *http.Request, secret string) error {
+	if token := r.Header.Get(gitlabTokenHeader); token != "" {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return annotationwebhooks.ErrWebhookInvalidSignature.Errorf("invalid token")
+		}
+		return nil
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(r.Header.Get(signatureHeader), signaturePrefix))
+	if err != nil {
+		return annotationwebhooks.ErrWebhookInvalidSignature.Errorf("failed to decode signature: %w", err)
+	}
+
+	// one more byte than the limit is read, so that larger payloads are rejected instead of being truncated
+	body, err := io.ReadAll(io.LimitReader(r.Body, annotationwebhooks.MaxPayloadBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > annotationwebhooks.MaxPayloadBytes {
+		return annotationwebhooks.ErrWebhookPayloadTooLarge.Errorf("payload is larger than %d bytes", annotationwebhooks.MaxPayloadBytes)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return annotationwebhooks.ErrWebhookInvalidSignature.Errorf("invalid signature")
+	}
+	return nil
+}
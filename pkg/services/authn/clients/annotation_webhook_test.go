@@ -0,0 +1,213 @@
+package clients
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks"
+	"github.com/grafana/grafana/pkg/services/annotationwebhooks/annotationwebhookstest"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+const webhookPayload = `{"ref": "v1.2.3"}`
+
+func TestAnnotationWebhook_Authenticate(t *testing.T) {
+	type TestCase struct {
+		desc             string
+		headers          map[string]string
+		payload          string
+		secret           string
+		user             *user.SignedInUser
+		expectedErr      error
+		expectedIdentity *authn.Identity
+	}
+
+	serviceAccount := &user.SignedInUser{UserID: 2, OrgID: 1, Login: "sa-ci", OrgRole: org.RoleEditor, IsServiceAccount: true}
+	largePayload := `{"text": "` + strings.Repeat("a", annotationwebhooks.MaxPayloadBytes) + `"}`
+	tests := []TestCase{
+		{
+			desc:    "should authenticate the service account of the webhook with a valid signature",
+			headers: map[string]string{"X-Hub-Signature-256": signWebhookPayload("s3cr3t", webhookPayload)},
+			secret:  "s3cr3t",
+			user:    serviceAccount,
+			expectedIdentity: &authn.Identity{
+				ID:              "service-account:2",
+				OrgID:           1,
+				Login:           "sa-ci",
+				OrgRoles:        map[int64]org.RoleType{1: org.RoleEditor},
+				IsGrafanaAdmin:  boolPtr(false),
+				AuthenticatedBy: login.AnnotationWebhookModule,
+				ClientParams:    authn.ClientParams{SyncPermissions: true},
+			},
+		},
+		{
+			desc:    "should authenticate the service account of the webhook with a valid GitLab token",
+			headers: map[string]string{"X-Gitlab-Token": "s3cr3t"},
+			secret:  "s3cr3t",
+			user:    serviceAccount,
+			expectedIdentity: &authn.Identity{
+				ID:              "service-account:2",
+				OrgID:           1,
+				Login:           "sa-ci",
+				OrgRoles:        map[int64]org.RoleType{1: org.RoleEditor},
+				IsGrafanaAdmin:  boolPtr(false),
+				AuthenticatedBy: login.AnnotationWebhookModule,
+				ClientParams:    authn.ClientParams{SyncPermissions: true},
+			},
+		},
+		{
+			desc:        "should fail with an invalid signature",
+			headers:     map[string]string{"X-Hub-Signature-256": signWebhookPayload("other", webhookPayload)},
+			secret:      "s3cr3t",
+			user:        serviceAccount,
+			expectedErr: annotationwebhooks.ErrWebhookInvalidSignature,
+		},
+		{
+			desc:        "should fail with an invalid GitLab token",
+			headers:     map[string]string{"X-Gitlab-Token": "other"},
+			secret:      "s3cr3t",
+			user:        serviceAccount,
+			expectedErr: annotationwebhooks.ErrWebhookInvalidSignature,
+		},
+		{
+			desc:        "should fail when the payload is larger than the limit",
+			headers:     map[string]string{"X-Hub-Signature-256": signWebhookPayload("s3cr3t", largePayload)},
+			payload:     largePayload,
+			secret:      "s3cr3t",
+			user:        serviceAccount,
+			expectedErr: annotationwebhooks.ErrWebhookPayloadTooLarge,
+		},
+		{
+			desc:        "should fail when the webhook has no secret",
+			headers:     map[string]string{"X-Hub-Signature-256": signWebhookPayload("", webhookPayload)},
+			user:        serviceAccount,
+			expectedErr: annotationwebhooks.ErrWebhookInvalidSignature,
+		},
+		{
+			desc:        "should fail when the user of the webhook is not a service account",
+			headers:     map[string]string{"X-Gitlab-Token": "s3cr3t"},
+			secret:      "s3cr3t",
+			user:        &user.SignedInUser{UserID: 2, OrgID: 1},
+			expectedErr: errAnnotationWebhookNoServiceAccount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			webhookService := annotationwebhookstest.NewFakeAnnotationWebhookService()
+			webhookService.ExpectedWebhook = &annotationwebhooks.Webhook{UID: "abc", OrgID: 1, ServiceAccountID: 2}
+			webhookService.ExpectedSecret = tt.secret
+			c := ProvideAnnotationWebhook(webhookService, &usertest.FakeUserService{ExpectedSignedInUser: tt.user})
+
+			payload := webhookPayload
+			if tt.payload != "" {
+				payload = tt.payload
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/annotations/webhooks/abc/events?targetOrgId=1", strings.NewReader(payload))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			identity, err := c.Authenticate(context.Background(), &authn.Request{HTTPRequest: req, OrgID: 1})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, identity)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.EqualValues(t, *tt.expectedIdentity, *identity)
+
+			// the handler reads the payload again
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.Equal(t, webhookPayload, string(body))
+		})
+	}
+}
+
+func TestAnnotationWebhook_Test(t *testing.T) {
+	type TestCase struct {
+		desc     string
+		method   string
+		path     string
+		headers  map[string]string
+		expected bool
+	}
+
+	tests := []TestCase{
+		{
+			desc:     "should succeed for a signed event",
+			method:   http.MethodPost,
+			path:     "/api/annotations/webhooks/abc/events",
+			headers:  map[string]string{"X-Hub-Signature-256": "sha256=00"},
+			expected: true,
+		},
+		{
+			desc:     "should succeed for a GitLab event",
+			method:   http.MethodPost,
+			path:     "/api/annotations/webhooks/abc/events",
+			headers:  map[string]string{"X-Gitlab-Token": "s3cr3t"},
+			expected: true,
+		},
+		{
+			desc:     "should fail for an event sent with a service account token",
+			method:   http.MethodPost,
+			path:     "/api/annotations/webhooks/abc/events",
+			headers:  map[string]string{"X-Hub-Signature-256": "sha256=00", "Authorization": "Bearer glsa_123"},
+			expected: false,
+		},
+		{
+			desc:     "should fail for an unsigned event",
+			method:   http.MethodPost,
+			path:     "/api/annotations/webhooks/abc/events",
+			expected: false,
+		},
+		{
+			desc:     "should fail for other endpoints",
+			method:   http.MethodPost,
+			path:     "/api/annotations",
+			headers:  map[string]string{"X-Hub-Signature-256": "sha256=00"},
+			expected: false,
+		},
+		{
+			desc:     "should fail for other methods",
+			method:   http.MethodGet,
+			path:     "/api/annotations/webhooks/abc/events",
+			headers:  map[string]string{"X-Hub-Signature-256": "sha256=00"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			c := ProvideAnnotationWebhook(annotationwebhookstest.NewFakeAnnotationWebhookService(), usertest.NewUserServiceFake())
+			assert.Equal(t, tt.expected, c.Test(context.Background(), &authn.Request{HTTPRequest: req}))
+		})
+	}
+}
+
+func signWebhookPayload(key, payload string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
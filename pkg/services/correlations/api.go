@@ -27,6 +27,7 @@ func (s *CorrelationsService) registerAPIEndpoints() {
 			entities.Get("/", authorize(ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationHandler))
 			entities.Delete("/", authorize(ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.deleteHandler))
 			entities.Patch("/", authorize(ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.updateHandler))
+			entities.Post("/resolve", authorize(ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.resolveHandler))
 		})
 	}, middleware.ReqSignedIn)
 }
@@ -245,6 +246,61 @@ type GetCorrelationResponse struct {
 	Body Correlation `json:"body"`
 }
 
+// swagger:route POST /datasources/uid/{sourceUID}/correlations/{correlationUID}/resolve correlations resolveCorrelation
+//
+// Resolves the target query of a correlation for a row of the source data.
+//
+// Responses:
+// 200: resolveCorrelationResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) resolveHandler(c *contextmodel.ReqContext) response.Response {
+	query := ResolveCorrelationQuery{}
+	if err := web.Bind(c.Req, &query); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	query.UID = web.Params(c.Req)[":correlationUID"]
+	query.SourceUID = web.Params(c.Req)[":uid"]
+	query.OrgId = c.OrgID
+
+	resolved, err := s.ResolveCorrelation(c.Req.Context(), query)
+	if err != nil {
+		if errors.Is(err, ErrCorrelationNotFound) {
+			return response.Error(http.StatusNotFound, "Correlation not found", err)
+		}
+		if errors.Is(err, ErrSourceDataSourceDoesNotExists) {
+			return response.Error(http.StatusNotFound, "Source data source not found", err)
+		}
+
+		// the source row doesn't provide the variables of the target query
+		return response.Error(http.StatusBadRequest, "Failed to resolve correlation", err)
+	}
+
+	return response.JSON(http.StatusOK, resolved)
+}
+
+// swagger:parameters resolveCorrelation
+type ResolveCorrelationParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"sourceUID"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+	// in:body
+	// required:true
+	Body ResolveCorrelationQuery `json:"body"`
+}
+
+// swagger:response resolveCorrelationResponse
+type ResolveCorrelationResponse struct {
+	// in: body
+	Body ResolveCorrelationResponseBody `json:"body"`
+}
+
 // swagger:route GET /datasources/uid/{sourceUID}/correlations correlations getCorrelationsBySourceUID
 //
 // Gets all correlations originating from the given data source.
@@ -54,6 +54,7 @@ type Service interface {
 	DeleteCorrelation(ctx context.Context, cmd DeleteCorrelationCommand) error
 	DeleteCorrelationsBySourceUID(ctx context.Context, cmd DeleteCorrelationsBySourceUIDCommand) error
 	DeleteCorrelationsByTargetUID(ctx context.Context, cmd DeleteCorrelationsByTargetUIDCommand) error
+	ResolveCorrelation(ctx context.Context, cmd ResolveCorrelationQuery) (ResolveCorrelationResponseBody, error)
 }
 
 type CorrelationsService struct {
@@ -98,6 +99,22 @@ func (s CorrelationsService) GetCorrelations(ctx context.Context, cmd GetCorrela
 	return s.getCorrelations(ctx, cmd)
 }
 
+// ResolveCorrelation returns the target query of a correlation for a row of the source data, so that correlations
+// can be followed without the frontend
+func (s CorrelationsService) ResolveCorrelation(ctx context.Context, cmd ResolveCorrelationQuery) (ResolveCorrelationResponseBody, error) {
+	correlation, err := s.getCorrelation(ctx, GetCorrelationQuery{UID: cmd.UID, SourceUID: cmd.SourceUID, OrgId: cmd.OrgId})
+	if err != nil {
+		return ResolveCorrelationResponseBody{}, err
+	}
+
+	query, variables, err := correlation.Config.Resolve(cmd.Fields, cmd.Labels)
+	if err != nil {
+		return ResolveCorrelationResponseBody{}, err
+	}
+
+	return ResolveCorrelationResponseBody{TargetUID: correlation.TargetUID, Query: query, Variables: variables}, nil
+}
+
 func (s CorrelationsService) DeleteCorrelationsBySourceUID(ctx context.Context, cmd DeleteCorrelationsBySourceUIDCommand) error {
 	return s.deleteCorrelationsBySourceUID(ctx, cmd)
 }
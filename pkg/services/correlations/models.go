@@ -19,6 +19,10 @@ var (
 	ErrInvalidTransformationType          = errors.New("invalid transformation type")
 	ErrTransformationNotNested            = errors.New("transformations must be nested under config")
 	ErrTransformationRegexReqExp          = errors.New("regex transformations require expression")
+	ErrTransformationReqExp               = errors.New("transformation requires expression")
+	ErrTransformationReqMapValue          = errors.New("transformation requires mapValue")
+	ErrTransformationInvalidExpression    = errors.New("invalid transformation expression")
+	ErrCorrelationVariablesUndefined      = errors.New("correlation variables are undefined")
 	ErrCorrelationsQuotaFailed            = errors.New("error getting correlations quota")
 	ErrCorrelationsQuotaReached           = errors.New("correlations quota reached")
 )
@@ -31,7 +35,7 @@ const (
 type CorrelationConfigType string
 
 type Transformation struct {
-	//Enum: regex,logfmt
+	//Enum: regex,logfmt,jsonpath,label,template
 	Type       string `json:"type"`
 	Expression string `json:"expression,omitempty"`
 	Field      string `json:"field,omitempty"`
@@ -51,10 +55,8 @@ func (t CorrelationConfigType) Validate() error {
 
 func (t Transformations) Validate() error {
 	for _, v := range t {
-		if v.Type != "regex" && v.Type != "logfmt" {
-			return fmt.Errorf("%s: \"%s\"", ErrInvalidTransformationType, t)
-		} else if v.Type == "regex" && len(v.Expression) == 0 {
-			return fmt.Errorf("%s: \"%s\"", ErrTransformationRegexReqExp, t)
+		if err := v.Validate(); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -214,6 +216,10 @@ func (c CorrelationConfigUpdateDTO) Validate() error {
 		}
 	}
 
+	if err := Transformations(c.Transformations).Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -242,7 +248,7 @@ func (c UpdateCorrelationCommand) Validate() error {
 		}
 	}
 
-	if c.Label == nil && c.Description == nil && (c.Config == nil || (c.Config.Field == nil && c.Config.Type == nil && c.Config.Target == nil)) {
+	if c.Label == nil && c.Description == nil && (c.Config == nil || (c.Config.Field == nil && c.Config.Type == nil && c.Config.Target == nil && c.Config.Transformations == nil)) {
 		return ErrUpdateCorrelationEmptyParams
 	}
 
@@ -284,6 +290,34 @@ type GetCorrelationsQuery struct {
 	SourceUIDs []string `json:"sourceuid"`
 }
 
+// ResolveCorrelationQuery is the query to resolve the target query of a correlation for a row of the source data
+// swagger:model
+type ResolveCorrelationQuery struct {
+	UID       string `json:"-"`
+	SourceUID string `json:"-"`
+	OrgId     int64  `json:"-"`
+	// Values of the fields of the source row, by field name. Values of any type are accepted.
+	// required:true
+	// example: {"message":"level=error traceID=abc123","duration":1.5}
+	Fields map[string]interface{} `json:"fields" binding:"Required"`
+	// Labels of the field the correlation is attached to
+	// required:false
+	// example: {"job":"api"}
+	Labels map[string]string `json:"labels"`
+}
+
+// swagger:model
+type ResolveCorrelationResponseBody struct {
+	// UID of the data source the target query is sent to
+	// example: PE1C5CBDA0504A6A3
+	TargetUID *string `json:"targetUID"`
+	// Target data query with its variables replaced
+	// example: {"expr":"{traceID=\"abc123\"}"}
+	Query map[string]interface{} `json:"query"`
+	// Variables available to the target query, from the source row and the transformations
+	Variables map[string]string `json:"variables"`
+}
+
 type DeleteCorrelationsBySourceUIDCommand struct {
 	SourceUID string
 }
@@ -57,6 +57,28 @@ func TestCorrelationModels(t *testing.T) {
 		})
 	})
 
+	t.Run("UpdateCorrelationCommand Validate", func(t *testing.T) {
+		t.Run("Successfully validates an update of the transformations only", func(t *testing.T) {
+			cmd := &UpdateCorrelationCommand{
+				Config: &CorrelationConfigUpdateDTO{
+					Transformations: []Transformation{{Type: "jsonpath", Expression: "$.traceId"}},
+				},
+			}
+
+			require.NoError(t, cmd.Validate())
+		})
+
+		t.Run("Fails if a transformation is invalid", func(t *testing.T) {
+			cmd := &UpdateCorrelationCommand{
+				Config: &CorrelationConfigUpdateDTO{
+					Transformations: []Transformation{{Type: "template", Expression: "${service}"}},
+				},
+			}
+
+			require.ErrorIs(t, cmd.Validate(), ErrTransformationReqMapValue)
+		})
+	})
+
 	t.Run("CorrelationConfigType Validate", func(t *testing.T) {
 		t.Run("Successfully validates a correct type", func(t *testing.T) {
 			type test struct {
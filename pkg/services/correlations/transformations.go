@@ -0,0 +1,317 @@
+package correlations
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-logfmt/logfmt"
+)
+
+const (
+	// TransformationTypeRegex extracts the first capture group, or the named capture groups, of a regular expression
+	TransformationTypeRegex = "regex"
+	// TransformationTypeLogfmt extracts all the keys of a logfmt line
+	TransformationTypeLogfmt = "logfmt"
+	// TransformationTypeJSONPath extracts the value at a JSONPath of a JSON document, like $.user.ids[0]
+	TransformationTypeJSONPath = "jsonpath"
+	// TransformationTypeLabel extracts a label of the source field
+	TransformationTypeLabel = "label"
+	// TransformationTypeTemplate builds a variable from a template string referencing other variables, like ${service}-${env}
+	TransformationTypeTemplate = "template"
+)
+
+func (t Transformation) Validate() error {
+	switch t.Type {
+	case TransformationTypeRegex:
+		if len(t.Expression) == 0 {
+			return fmt.Errorf("%w: \"%s\"", ErrTransformationRegexReqExp, t.Type)
+		}
+		if _, err := regexp.Compile(t.Expression); err != nil {
+			return fmt.Errorf("%w: %s", ErrTransformationInvalidExpression, err)
+		}
+	case TransformationTypeLogfmt:
+	case TransformationTypeJSONPath:
+		if len(t.Expression) == 0 {
+			return fmt.Errorf("%w: \"%s\"", ErrTransformationReqExp, t.Type)
+		}
+		if _, err := parseJSONPath(t.Expression); err != nil {
+			return fmt.Errorf("%w: %s", ErrTransformationInvalidExpression, err)
+		}
+	case TransformationTypeLabel:
+		if len(t.Expression) == 0 {
+			return fmt.Errorf("%w: \"%s\"", ErrTransformationReqExp, t.Type)
+		}
+	case TransformationTypeTemplate:
+		if len(t.Expression) == 0 {
+			return fmt.Errorf("%w: \"%s\"", ErrTransformationReqExp, t.Type)
+		}
+		if len(t.MapValue) == 0 {
+			return fmt.Errorf("%w: \"%s\"", ErrTransformationReqMapValue, t.Type)
+		}
+	default:
+		return fmt.Errorf("%w: \"%s\"", ErrInvalidTransformationType, t.Type)
+	}
+	return nil
+}
+
+// apply runs the transformation on the value of its field, or of the correlation field, and adds the extracted
+// values to vars. The field can be a field of the source row or a variable set by a previous transformation.
+func (t Transformation) apply(correlationField string, vars map[string]string, labels map[string]string) error {
+	field := t.Field
+	if field == "" {
+		field = correlationField
+	}
+	value := vars[field]
+
+	// the frontend names the extracted value after the correlation field when no variable name is set
+	name := t.MapValue
+	if name == "" {
+		name = correlationField
+	}
+
+	switch t.Type {
+	case TransformationTypeRegex:
+		re, err := regexp.Compile("(?i)" + t.Expression)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrTransformationInvalidExpression, err)
+		}
+		for _, match := range re.FindAllStringSubmatch(value, -1) {
+			named := false
+			for i, group := range re.SubexpNames() {
+				if i > 0 && group != "" {
+					vars[group] = match[i]
+					named = true
+				}
+			}
+			if named {
+				continue
+			}
+			if len(match) > 1 && match[1] != "" {
+				vars[name] = match[1]
+			} else {
+				vars[name] = match[0]
+			}
+		}
+	case TransformationTypeLogfmt:
+		decoder := logfmt.NewDecoder(strings.NewReader(value))
+		for decoder.ScanRecord() {
+			for decoder.ScanKeyval() {
+				vars[string(decoder.Key())] = string(decoder.Value())
+			}
+		}
+		if err := decoder.Err(); err != nil {
+			return fmt.Errorf("failed to parse logfmt field %s: %w", field, err)
+		}
+	case TransformationTypeJSONPath:
+		path, err := parseJSONPath(t.Expression)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrTransformationInvalidExpression, err)
+		}
+		var document interface{}
+		if err := json.Unmarshal([]byte(value), &document); err != nil {
+			return fmt.Errorf("failed to parse JSON field %s: %w", field, err)
+		}
+		if result, ok := path.lookup(document); ok {
+			vars[name] = formatValue(result)
+		}
+	case TransformationTypeLabel:
+		if t.MapValue == "" {
+			name = t.Expression
+		}
+		if label, ok := labels[t.Expression]; ok {
+			vars[name] = label
+		}
+	case TransformationTypeTemplate:
+		result, _ := interpolate(t.Expression, vars)
+		vars[name] = result
+	default:
+		return fmt.Errorf("%w: \"%s\"", ErrInvalidTransformationType, t.Type)
+	}
+	return nil
+}
+
+type jsonPathSegment struct {
+	key   string
+	index int
+	// isIndex is set for array indexes like [0]
+	isIndex bool
+}
+
+type jsonPath []jsonPathSegment
+
+// parseJSONPath parses the subset of JSONPath made of child keys and array indexes, like $.user['first name'].ids[0]
+func parseJSONPath(expression string) (jsonPath, error) {
+	if !strings.HasPrefix(expression, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expression)
+	}
+
+	path := jsonPath{}
+	rest := expression[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("JSONPath %q has an empty key", expression)
+			}
+			path = append(path, jsonPathSegment{key: key})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %q has an unclosed bracket", expression)
+			}
+			inner := rest[1:end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				path = append(path, jsonPathSegment{key: inner[1 : len(inner)-1]})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("JSONPath %q has an invalid index %q", expression, inner)
+				}
+				path = append(path, jsonPathSegment{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSONPath %q has an unexpected character %q", expression, rest[0])
+		}
+	}
+	return path, nil
+}
+
+func (p jsonPath) lookup(document interface{}) (interface{}, bool) {
+	current := document
+	for _, segment := range p {
+		if segment.isIndex {
+			values, ok := current.([]interface{})
+			if !ok || segment.index >= len(values) {
+				return nil, false
+			}
+			current = values[segment.index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[segment.key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// formatValue formats a field value of any type as a variable value
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+var variableRegex = regexp.MustCompile(`\$\{([\w.]+)(?::\w+)?\}|\$(\w+)`)
+
+// interpolate replaces the variables of s, written $name or ${name}, with their values. Grafana global variables
+// starting with __, like $__interval, are left as is. It returns the names of the variables without value.
+func interpolate(s string, vars map[string]string) (string, []string) {
+	var undefined []string
+	result := variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name := groups[1]
+		if name == "" {
+			name = groups[2]
+		}
+		name = strings.TrimPrefix(name, "__data.fields.")
+
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		if !strings.HasPrefix(name, "__") {
+			undefined = append(undefined, name)
+		}
+		return match
+	})
+	return result, undefined
+}
+
+// interpolateTarget interpolates all the strings of the target query
+func interpolateTarget(value interface{}, vars map[string]string, undefined *[]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		result, missing := interpolate(v, vars)
+		*undefined = append(*undefined, missing...)
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, nested := range v {
+			result[key] = interpolateTarget(nested, vars, undefined)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, nested := range v {
+			result[i] = interpolateTarget(nested, vars, undefined)
+		}
+		return result
+	}
+	return value
+}
+
+// Resolve returns the target query of the correlation for a row of the source data source, with its variables
+// replaced by the values of the row fields and of the transformations
+func (c CorrelationConfig) Resolve(fields map[string]interface{}, labels map[string]string) (map[string]interface{}, map[string]string, error) {
+	vars := make(map[string]string, len(fields)+1)
+	for name, value := range fields {
+		vars[name] = formatValue(value)
+	}
+	if value, ok := fields[c.Field]; ok {
+		vars["__targetField"] = formatValue(value)
+	}
+
+	for _, transformation := range c.Transformations {
+		if err := transformation.apply(c.Field, vars, labels); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var undefined []string
+	target, _ := interpolateTarget(c.Target, vars, &undefined).(map[string]interface{})
+	if target == nil {
+		target = map[string]interface{}{}
+	}
+	if len(undefined) > 0 {
+		names := make([]string, 0, len(undefined))
+		seen := make(map[string]bool, len(undefined))
+		for _, name := range undefined {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		return nil, nil, fmt.Errorf("%w: %s", ErrCorrelationVariablesUndefined, strings.Join(names, ", "))
+	}
+
+	return target, vars, nil
+}
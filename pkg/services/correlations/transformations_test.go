@@ -0,0 +1,110 @@
+package correlations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformationValidate(t *testing.T) {
+	type test struct {
+		name           string
+		transformation Transformation
+		assertion      require.ErrorAssertionFunc
+	}
+
+	tests := []test{
+		{name: "logfmt", transformation: Transformation{Type: "logfmt"}, assertion: require.NoError},
+		{name: "regex", transformation: Transformation{Type: "regex", Expression: `id=(\w+)`}, assertion: require.NoError},
+		{name: "regex without expression", transformation: Transformation{Type: "regex"}, assertion: require.Error},
+		{name: "regex with invalid expression", transformation: Transformation{Type: "regex", Expression: `id=(\w+`}, assertion: require.Error},
+		{name: "jsonpath", transformation: Transformation{Type: "jsonpath", Expression: "$.user['first name'].ids[0]"}, assertion: require.NoError},
+		{name: "jsonpath with invalid expression", transformation: Transformation{Type: "jsonpath", Expression: "user.id"}, assertion: require.Error},
+		{name: "label", transformation: Transformation{Type: "label", Expression: "job"}, assertion: require.NoError},
+		{name: "label without expression", transformation: Transformation{Type: "label"}, assertion: require.Error},
+		{name: "template", transformation: Transformation{Type: "template", Expression: "${service}-${env}", MapValue: "app"}, assertion: require.NoError},
+		{name: "template without mapValue", transformation: Transformation{Type: "template", Expression: "${service}-${env}"}, assertion: require.Error},
+		{name: "unknown type", transformation: Transformation{Type: "unknown"}, assertion: require.Error},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.assertion(t, tc.transformation.Validate())
+		})
+	}
+}
+
+func TestCorrelationConfigResolve(t *testing.T) {
+	t.Run("Replaces the variables with the values of the row fields", func(t *testing.T) {
+		config := CorrelationConfig{
+			Field:  "traceID",
+			Type:   ConfigTypeQuery,
+			Target: map[string]interface{}{"query": "${traceID}", "limit": "$count", "tags": []interface{}{"$__targetField"}},
+		}
+
+		query, _, err := config.Resolve(map[string]interface{}{"traceID": "abc123", "count": float64(20)}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"query": "abc123", "limit": "20", "tags": []interface{}{"abc123"}}, query)
+	})
+
+	t.Run("Applies the transformations in order", func(t *testing.T) {
+		config := CorrelationConfig{
+			Field:  "message",
+			Type:   ConfigTypeQuery,
+			Target: map[string]interface{}{"expr": `{app="${app}"} |= "${user}" |= "${traceID}" $__interval`},
+			Transformations: Transformations{
+				{Type: "logfmt"},
+				{Type: "regex", Field: "path", Expression: `/api/(\w+)/`, MapValue: "service"},
+				{Type: "jsonpath", Field: "payload", Expression: "$.user.ids[1]", MapValue: "user"},
+				{Type: "label", Expression: "env"},
+				{Type: "template", Expression: "${service}-${env}", MapValue: "app"},
+			},
+		}
+
+		fields := map[string]interface{}{
+			"message": `level=error path=/api/orders/42 traceID=abc123`,
+			"payload": map[string]interface{}{"user": map[string]interface{}{"ids": []interface{}{1, 2}}},
+		}
+		query, variables, err := config.Resolve(fields, map[string]string{"env": "prod"})
+		require.NoError(t, err)
+
+		assert.Equal(t, `{app="orders-prod"} |= "2" |= "abc123" $__interval`, query["expr"])
+		assert.Equal(t, "error", variables["level"])
+		assert.Equal(t, "orders", variables["service"])
+	})
+
+	t.Run("Extracts the named groups of a regex", func(t *testing.T) {
+		config := CorrelationConfig{
+			Field:           "message",
+			Type:            ConfigTypeQuery,
+			Target:          map[string]interface{}{"query": "${method} ${status}"},
+			Transformations: Transformations{{Type: "regex", Expression: `(?P<method>GET|POST) \S+ (?P<status>\d+)`}},
+		}
+
+		query, _, err := config.Resolve(map[string]interface{}{"message": "get /api 500"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "get 500", query["query"])
+	})
+
+	t.Run("Fails when a variable of the target query is undefined", func(t *testing.T) {
+		config := CorrelationConfig{
+			Field:  "message",
+			Type:   ConfigTypeQuery,
+			Target: map[string]interface{}{"query": "${traceID} $traceID ${spanID}"},
+		}
+
+		_, _, err := config.Resolve(map[string]interface{}{"message": "no trace"}, nil)
+		require.ErrorIs(t, err, ErrCorrelationVariablesUndefined)
+		assert.Contains(t, err.Error(), "traceID, spanID")
+	})
+}
+
+func TestFormatValue(t *testing.T) {
+	assert.Equal(t, "", formatValue(nil))
+	assert.Equal(t, "text", formatValue("text"))
+	assert.Equal(t, "1.5", formatValue(1.5))
+	assert.Equal(t, "1000000", formatValue(float64(1000000)))
+	assert.Equal(t, "true", formatValue(true))
+	assert.Equal(t, `{"a":[1,2]}`, formatValue(map[string]interface{}{"a": []int{1, 2}}))
+}
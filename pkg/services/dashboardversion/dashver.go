@@ -8,4 +8,8 @@ type Service interface {
 	Get(context.Context, *GetDashboardVersionQuery) (*DashboardVersionDTO, error)
 	DeleteExpired(context.Context, *DeleteExpiredVersionsCommand) error
 	List(context.Context, *ListDashboardVersionsQuery) ([]*DashboardVersionDTO, error)
+	GetRetentionPolicy(context.Context, *GetRetentionPolicyQuery) (*RetentionPolicy, error)
+	ListRetentionPolicies(context.Context, *ListRetentionPoliciesQuery) ([]*RetentionPolicy, error)
+	SetRetentionPolicy(context.Context, *SetRetentionPolicyCommand) (*RetentionPolicy, error)
+	DeleteRetentionPolicy(context.Context, *DeleteRetentionPolicyCommand) error
 }
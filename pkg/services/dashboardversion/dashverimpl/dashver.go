@@ -3,17 +3,36 @@ package dashverimpl
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 const (
 	maxVersionsToDeletePerBatch = 100
 	maxVersionDeletionBatches   = 50
+
+	retentionPolicyGlobal = "global"
+	retentionPolicyFolder = "folder"
+)
+
+var deletedVersionsCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "dashboard_versions_deleted_total",
+		Help:      "Number of dashboard versions deleted by the cleanup job, by retention policy",
+	},
+	[]string{"policy"},
 )
 
 type Service struct {
@@ -68,6 +87,7 @@ func (s *Service) DeleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredV
 		versionsToKeep = 1
 	}
 
+	// The store skips the dashboards in folders with a retention policy.
 	for batch := 0; batch < maxVersionDeletionBatches; batch++ {
 		versionIdsToDelete, batchErr := s.store.GetBatch(ctx, cmd, maxVersionsToDeletePerBatch, versionsToKeep)
 		if batchErr != nil {
@@ -75,7 +95,7 @@ func (s *Service) DeleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredV
 		}
 
 		if len(versionIdsToDelete) < 1 {
-			return nil
+			break
 		}
 
 		deleted, err := s.store.DeleteBatch(ctx, cmd, versionIdsToDelete)
@@ -84,14 +104,119 @@ func (s *Service) DeleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredV
 		}
 
 		cmd.DeletedRows += deleted
+		deletedVersionsCounter.WithLabelValues(retentionPolicyGlobal).Add(float64(deleted))
 
 		if deleted < int64(maxVersionsToDeletePerBatch) {
 			break
 		}
 	}
+
+	policies, err := s.store.ListRetentionPolicies(ctx, &dashver.ListRetentionPoliciesQuery{})
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if err := s.deleteExpiredInFolder(ctx, cmd, policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteExpiredInFolder deletes the versions of the dashboards in the folder of the policy which the policy expires
+func (s *Service) deleteExpiredInFolder(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand, policy *dashver.RetentionPolicy) error {
+	versions, err := s.store.ListFolderVersions(ctx, policy.OrgID, policy.FolderUID)
+	if err != nil {
+		return err
+	}
+
+	versionIdsToDelete := expiredVersions(policy, versions, time.Now())
+	for batch := 0; batch < maxVersionDeletionBatches && len(versionIdsToDelete) > 0; batch++ {
+		size := maxVersionsToDeletePerBatch
+		if size > len(versionIdsToDelete) {
+			size = len(versionIdsToDelete)
+		}
+
+		deleted, err := s.store.DeleteBatch(ctx, cmd, versionIdsToDelete[:size])
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows += deleted
+		deletedVersionsCounter.WithLabelValues(retentionPolicyFolder).Add(float64(deleted))
+		versionIdsToDelete = versionIdsToDelete[size:]
+	}
 	return nil
 }
 
+// expiredVersions returns the IDs of the versions expired by the policy. The versions must be
+// ordered by dashboard and from the newest version, the newest version of each dashboard is kept.
+func expiredVersions(policy *dashver.RetentionPolicy, versions []*dashver.DashboardVersion, now time.Time) []interface{} {
+	var maxAge time.Duration
+	if policy.MaxAge != "" {
+		// the age is validated when the policy is saved
+		maxAge, _ = gtime.ParseDuration(policy.MaxAge)
+	}
+
+	expired := make([]interface{}, 0)
+	kept := 0
+	for i, v := range versions {
+		if i == 0 || v.DashboardID != versions[i-1].DashboardID {
+			kept = 1
+			continue
+		}
+		// tagged versions are neither deleted nor counted in the versions to keep
+		if policy.KeepTagged && v.Message != "" {
+			continue
+		}
+		if (policy.MaxVersions > 0 && kept >= policy.MaxVersions) || (maxAge > 0 && now.Sub(v.Created) > maxAge) {
+			expired = append(expired, v.ID)
+			continue
+		}
+		kept++
+	}
+	return expired
+}
+
+func (s *Service) GetRetentionPolicy(ctx context.Context, query *dashver.GetRetentionPolicyQuery) (*dashver.RetentionPolicy, error) {
+	return s.store.GetRetentionPolicy(ctx, query)
+}
+
+func (s *Service) ListRetentionPolicies(ctx context.Context, query *dashver.ListRetentionPoliciesQuery) ([]*dashver.RetentionPolicy, error) {
+	return s.store.ListRetentionPolicies(ctx, query)
+}
+
+func (s *Service) SetRetentionPolicy(ctx context.Context, cmd *dashver.SetRetentionPolicyCommand) (*dashver.RetentionPolicy, error) {
+	if cmd.FolderUID == "" || cmd.FolderUID == folder.GeneralFolderUID {
+		return nil, fmt.Errorf("%w: the policy must apply to a folder", dashver.ErrRetentionPolicyInvalid)
+	}
+	if cmd.MaxVersions < 0 {
+		return nil, fmt.Errorf("%w: maxVersions must not be negative", dashver.ErrRetentionPolicyInvalid)
+	}
+	if cmd.MaxAge != "" {
+		maxAge, err := gtime.ParseDuration(cmd.MaxAge)
+		if err != nil || maxAge <= 0 {
+			return nil, fmt.Errorf("%w: invalid maxAge %q", dashver.ErrRetentionPolicyInvalid, cmd.MaxAge)
+		}
+	}
+
+	policy := &dashver.RetentionPolicy{
+		OrgID:       cmd.OrgID,
+		FolderUID:   cmd.FolderUID,
+		MaxVersions: cmd.MaxVersions,
+		MaxAge:      cmd.MaxAge,
+		KeepTagged:  cmd.KeepTagged,
+	}
+	if err := s.store.SetRetentionPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *Service) DeleteRetentionPolicy(ctx context.Context, cmd *dashver.DeleteRetentionPolicyCommand) error {
+	return s.store.DeleteRetentionPolicy(ctx, cmd)
+}
+
 // List all dashboard versions for the given dashboard ID.
 func (s *Service) List(ctx context.Context, query *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersionDTO, error) {
 	// Get the DashboardUID if not populated
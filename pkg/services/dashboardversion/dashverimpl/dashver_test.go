@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -61,6 +62,105 @@ func TestDeleteExpiredVersions(t *testing.T) {
 	})
 }
 
+func TestDeleteExpiredVersionsWithRetentionPolicies(t *testing.T) {
+	setting.DashboardVersionsToKeep = 5
+
+	dashboardVersionStore := newDashboardVersionStoreFake()
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardVersionService := Service{store: dashboardVersionStore, dashSvc: dashboardService}
+
+	now := time.Now()
+	dashboardVersionStore.ExpectedPolicies = []*dashver.RetentionPolicy{{OrgID: 1, FolderUID: "sandbox", MaxVersions: 2}}
+	dashboardVersionStore.ExpectedFolderVersions = []*dashver.DashboardVersion{
+		{ID: 13, DashboardID: 1, Version: 3, Created: now},
+		{ID: 12, DashboardID: 1, Version: 2, Created: now},
+		{ID: 11, DashboardID: 1, Version: 1, Created: now},
+		{ID: 21, DashboardID: 2, Version: 1, Created: now},
+	}
+	dashboardVersionStore.ExptectedDeletedVersions = 1
+
+	cmd := &dashver.DeleteExpiredVersionsCommand{}
+	err := dashboardVersionService.DeleteExpired(context.Background(), cmd)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{int64(11)}, dashboardVersionStore.DeletedVersions)
+	require.EqualValues(t, 1, cmd.DeletedRows)
+}
+
+func TestExpiredVersions(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	versions := []*dashver.DashboardVersion{
+		{ID: 14, DashboardID: 1, Version: 4, Created: now.Add(-40 * day)},
+		{ID: 13, DashboardID: 1, Version: 3, Created: now.Add(-35 * day), Message: "release 1.2"},
+		{ID: 12, DashboardID: 1, Version: 2, Created: now.Add(-20 * day)},
+		{ID: 11, DashboardID: 1, Version: 1, Created: now.Add(-10 * day)},
+		{ID: 22, DashboardID: 2, Version: 2, Created: now.Add(-2 * day)},
+		{ID: 21, DashboardID: 2, Version: 1, Created: now.Add(-1 * day)},
+	}
+
+	testCases := []struct {
+		desc     string
+		policy   dashver.RetentionPolicy
+		expected []interface{}
+	}{
+		{
+			desc:     "keeps everything without limits",
+			policy:   dashver.RetentionPolicy{},
+			expected: []interface{}{},
+		},
+		{
+			desc:     "by count",
+			policy:   dashver.RetentionPolicy{MaxVersions: 2},
+			expected: []interface{}{int64(12), int64(11)},
+		},
+		{
+			desc:     "by age keeps the latest version",
+			policy:   dashver.RetentionPolicy{MaxAge: "30d"},
+			expected: []interface{}{int64(13)},
+		},
+		{
+			desc:     "by count keeping tagged versions",
+			policy:   dashver.RetentionPolicy{MaxVersions: 2, KeepTagged: true},
+			expected: []interface{}{int64(11)},
+		},
+		{
+			desc:     "by count and age",
+			policy:   dashver.RetentionPolicy{MaxVersions: 3, MaxAge: "15d", KeepTagged: true},
+			expected: []interface{}{int64(12)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			require.Equal(t, tc.expected, expiredVersions(&tc.policy, versions, now))
+		})
+	}
+}
+
+func TestSetRetentionPolicy(t *testing.T) {
+	dashboardVersionStore := newDashboardVersionStoreFake()
+	dashboardVersionService := Service{store: dashboardVersionStore, dashSvc: dashboards.NewFakeDashboardService(t)}
+
+	t.Run("saves a valid policy", func(t *testing.T) {
+		policy, err := dashboardVersionService.SetRetentionPolicy(context.Background(), &dashver.SetRetentionPolicyCommand{
+			OrgID: 1, FolderUID: "runbooks", MaxAge: "1y", KeepTagged: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, policy, dashboardVersionStore.SavedPolicy)
+		require.Equal(t, "1y", policy.MaxAge)
+	})
+
+	for _, cmd := range []dashver.SetRetentionPolicyCommand{
+		{OrgID: 1, FolderUID: "general", MaxVersions: 1},
+		{OrgID: 1, FolderUID: "sandbox", MaxVersions: -1},
+		{OrgID: 1, FolderUID: "sandbox", MaxAge: "soon"},
+	} {
+		cmd := cmd
+		_, err := dashboardVersionService.SetRetentionPolicy(context.Background(), &cmd)
+		require.ErrorIs(t, err, dashver.ErrRetentionPolicyInvalid)
+	}
+}
+
 func TestListDashboardVersions(t *testing.T) {
 	t.Run("List all versions for a given Dashboard ID", func(t *testing.T) {
 		dashboardVersionStore := newDashboardVersionStoreFake()
@@ -149,7 +249,11 @@ type FakeDashboardVersionStore struct {
 	ExptectedDeletedVersions int64
 	ExpectedVersions         []interface{}
 	ExpectedListVersions     []*dashver.DashboardVersion
+	ExpectedFolderVersions   []*dashver.DashboardVersion
+	ExpectedPolicies         []*dashver.RetentionPolicy
 	ExpectedError            error
+	DeletedVersions          []interface{}
+	SavedPolicy              *dashver.RetentionPolicy
 }
 
 func newDashboardVersionStoreFake() *FakeDashboardVersionStore {
@@ -165,9 +269,34 @@ func (f *FakeDashboardVersionStore) GetBatch(ctx context.Context, cmd *dashver.D
 }
 
 func (f *FakeDashboardVersionStore) DeleteBatch(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand, versionIdsToDelete []interface{}) (int64, error) {
+	f.DeletedVersions = append(f.DeletedVersions, versionIdsToDelete...)
 	return f.ExptectedDeletedVersions, f.ExpectedError
 }
 
 func (f *FakeDashboardVersionStore) List(ctx context.Context, query *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersion, error) {
 	return f.ExpectedListVersions, f.ExpectedError
 }
+
+func (f *FakeDashboardVersionStore) ListFolderVersions(ctx context.Context, orgID int64, folderUID string) ([]*dashver.DashboardVersion, error) {
+	return f.ExpectedFolderVersions, f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) GetRetentionPolicy(ctx context.Context, query *dashver.GetRetentionPolicyQuery) (*dashver.RetentionPolicy, error) {
+	if len(f.ExpectedPolicies) == 0 {
+		return nil, dashver.ErrRetentionPolicyNotFound
+	}
+	return f.ExpectedPolicies[0], f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) ListRetentionPolicies(ctx context.Context, query *dashver.ListRetentionPoliciesQuery) ([]*dashver.RetentionPolicy, error) {
+	return f.ExpectedPolicies, f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) SetRetentionPolicy(ctx context.Context, policy *dashver.RetentionPolicy) error {
+	f.SavedPolicy = policy
+	return f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) DeleteRetentionPolicy(ctx context.Context, cmd *dashver.DeleteRetentionPolicyCommand) error {
+	return f.ExpectedError
+}
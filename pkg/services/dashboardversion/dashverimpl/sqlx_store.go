@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/sqlstore/session"
@@ -38,8 +39,9 @@ func (ss *sqlxStore) GetBatch(ctx context.Context, cmd *dashver.DeleteExpiredVer
 	) AS vtd
 	WHERE dashboard_version.dashboard_id=vtd.dashboard_id
 	AND version < vtd.min + vtd.count - ?
+	` + excludeRetentionPolicyFoldersSQL + `
 	LIMIT ?`
-	err := ss.sess.Get(ctx, &versionIds, versionIdsToDeleteQuery, versionsToKeep, perBatch)
+	err := ss.sess.Select(ctx, &versionIds, versionIdsToDeleteQuery, versionsToKeep, perBatch)
 	return versionIds, err
 }
 
@@ -84,3 +86,68 @@ func (ss *sqlxStore) List(ctx context.Context, query *dashver.ListDashboardVersi
 	}
 	return dashboardVersion, nil
 }
+
+func (ss *sqlxStore) ListFolderVersions(ctx context.Context, orgID int64, folderUID string) ([]*dashver.DashboardVersion, error) {
+	versions := make([]*dashver.DashboardVersion, 0)
+	err := ss.sess.Select(ctx, &versions, listFolderVersionsSQL, listFolderVersionsArgs(orgID, folderUID)...)
+	return versions, err
+}
+
+func (ss *sqlxStore) GetRetentionPolicy(ctx context.Context, query *dashver.GetRetentionPolicyQuery) (*dashver.RetentionPolicy, error) {
+	var policy dashver.RetentionPolicy
+	err := ss.sess.Get(ctx, &policy, `SELECT * FROM dashboard_version_retention WHERE org_id=? AND folder_uid=?`, query.OrgID, query.FolderUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, dashver.ErrRetentionPolicyNotFound
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (ss *sqlxStore) ListRetentionPolicies(ctx context.Context, query *dashver.ListRetentionPoliciesQuery) ([]*dashver.RetentionPolicy, error) {
+	policies := make([]*dashver.RetentionPolicy, 0)
+	qr := `SELECT * FROM dashboard_version_retention`
+	args := []interface{}{}
+	if query.OrgID > 0 {
+		qr += ` WHERE org_id=?`
+		args = append(args, query.OrgID)
+	}
+	qr += ` ORDER BY org_id, folder_uid`
+	err := ss.sess.Select(ctx, &policies, qr, args...)
+	return policies, err
+}
+
+func (ss *sqlxStore) SetRetentionPolicy(ctx context.Context, policy *dashver.RetentionPolicy) error {
+	return ss.sess.WithTransaction(ctx, func(tx *session.SessionTx) error {
+		var existing dashver.RetentionPolicy
+		err := tx.Get(ctx, &existing, `SELECT * FROM dashboard_version_retention WHERE org_id=? AND folder_uid=?`, policy.OrgID, policy.FolderUID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		policy.Updated = time.Now()
+		if err == nil {
+			policy.ID = existing.ID
+			policy.Created = existing.Created
+			_, err = tx.Exec(ctx, `UPDATE dashboard_version_retention SET max_versions=?, max_age=?, keep_tagged=?, updated=? WHERE id=?`,
+				policy.MaxVersions, policy.MaxAge, policy.KeepTagged, policy.Updated, policy.ID)
+			return err
+		}
+		policy.Created = policy.Updated
+		policy.ID, err = tx.ExecWithReturningId(ctx, `INSERT INTO dashboard_version_retention (org_id, folder_uid, max_versions, max_age, keep_tagged, created, updated) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			policy.OrgID, policy.FolderUID, policy.MaxVersions, policy.MaxAge, policy.KeepTagged, policy.Created, policy.Updated)
+		return err
+	})
+}
+
+func (ss *sqlxStore) DeleteRetentionPolicy(ctx context.Context, cmd *dashver.DeleteRetentionPolicyCommand) error {
+	res, err := ss.sess.Exec(ctx, `DELETE FROM dashboard_version_retention WHERE org_id=? AND folder_uid=?`, cmd.OrgID, cmd.FolderUID)
+	if err != nil {
+		return err
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return dashver.ErrRetentionPolicyNotFound
+	}
+	return nil
+}
@@ -2,8 +2,11 @@ package dashverimpl
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/folder"
 )
 
 type store interface {
@@ -11,4 +14,82 @@ type store interface {
 	GetBatch(context.Context, *dashver.DeleteExpiredVersionsCommand, int, int) ([]interface{}, error)
 	DeleteBatch(context.Context, *dashver.DeleteExpiredVersionsCommand, []interface{}) (int64, error)
 	List(context.Context, *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersion, error)
+	// ListFolderVersions returns the versions of the dashboards in a folder, without their data,
+	// ordered by dashboard and from the newest version.
+	ListFolderVersions(ctx context.Context, orgID int64, folderUID string) ([]*dashver.DashboardVersion, error)
+	GetRetentionPolicy(context.Context, *dashver.GetRetentionPolicyQuery) (*dashver.RetentionPolicy, error)
+	ListRetentionPolicies(context.Context, *dashver.ListRetentionPoliciesQuery) ([]*dashver.RetentionPolicy, error)
+	SetRetentionPolicy(context.Context, *dashver.RetentionPolicy) error
+	DeleteRetentionPolicy(context.Context, *dashver.DeleteRetentionPolicyCommand) error
+}
+
+// folderAncestors is the number of ancestors of nested folders joined to find the folders of a dashboard, a folder
+// and its ancestors are at most the maximum depth of nested folders.
+const folderAncestors = folder.MaxNestedFolderDepth - 1
+
+// The dashboards in folders with a retention policy, directly or in a nested folder, are not cleaned up with the
+// global versions_to_keep setting.
+var excludeRetentionPolicyFoldersSQL = `AND dashboard_version.dashboard_id NOT IN (
+		SELECT dashboard.id
+		FROM dashboard
+		` + dashboardFoldersSQL() + `
+		INNER JOIN dashboard_version_retention AS retention ON retention.org_id = folder.org_id
+			AND retention.folder_uid IN (` + strings.Join(folderUIDColumns(folderAncestors), ", ") + `)
+	)`
+
+// listFolderVersionsSQL returns the versions of the dashboards in a folder and in its nested folders, except the
+// nested folders which have their own retention policy.
+var listFolderVersionsSQL = `SELECT dashboard_version.id,
+		dashboard_version.dashboard_id,
+		dashboard_version.version,
+		dashboard_version.created,
+		dashboard_version.message
+	FROM dashboard_version
+	INNER JOIN dashboard ON dashboard.id = dashboard_version.dashboard_id
+	` + dashboardFoldersSQL() + `
+	WHERE folder.org_id = ? AND (` + folderOrClosestAncestorSQL() + `)
+	ORDER BY dashboard_version.dashboard_id, dashboard_version.version DESC`
+
+// listFolderVersionsArgs returns the arguments of listFolderVersionsSQL.
+func listFolderVersionsArgs(orgID int64, folderUID string) []interface{} {
+	args := []interface{}{orgID}
+	for i := 0; i <= folderAncestors; i++ {
+		args = append(args, folderUID)
+	}
+	return args
+}
+
+// dashboardFoldersSQL joins the folder of a dashboard as "folder" and its ancestors, which are only stored in the
+// folder table when nested folders are enabled, from its parent "ancestor_1" to "ancestor_N".
+func dashboardFoldersSQL() string {
+	var sb strings.Builder
+	sb.WriteString("INNER JOIN dashboard AS folder ON folder.id = dashboard.folder_id\n")
+	sb.WriteString("\tLEFT JOIN folder AS ancestor_0 ON ancestor_0.org_id = folder.org_id AND ancestor_0.uid = folder.uid")
+	for i := 1; i <= folderAncestors; i++ {
+		fmt.Fprintf(&sb, "\n\tLEFT JOIN folder AS ancestor_%d ON ancestor_%d.org_id = folder.org_id AND ancestor_%d.uid = ancestor_%d.parent_uid",
+			i, i, i, i-1)
+	}
+	return sb.String()
+}
+
+// folderUIDColumns returns the UID columns of the folder of a dashboard and of its first n ancestors.
+func folderUIDColumns(n int) []string {
+	columns := []string{"folder.uid"}
+	for i := 1; i <= n; i++ {
+		columns = append(columns, fmt.Sprintf("ancestor_%d.uid", i))
+	}
+	return columns
+}
+
+// folderOrClosestAncestorSQL matches the dashboards whose folder is the folder, or whose closest ancestor with a
+// retention policy is the folder.
+func folderOrClosestAncestorSQL() string {
+	conditions := []string{"folder.uid = ?"}
+	for i := 1; i <= folderAncestors; i++ {
+		conditions = append(conditions, fmt.Sprintf(`(ancestor_%d.uid = ? AND NOT EXISTS (
+			SELECT 1 FROM dashboard_version_retention AS retention
+			WHERE retention.org_id = folder.org_id AND retention.folder_uid IN (%s)
+		))`, i, strings.Join(folderUIDColumns(i-1), ", ")))
+	}
+	return strings.Join(conditions, " OR ")
 }
@@ -78,6 +78,87 @@ func testIntegrationGetDashboardVersion(t *testing.T, fn getStore) {
 		assert.EqualValues(t, 4, res)
 	})
 
+	t.Run("Retention policies", func(t *testing.T) {
+		folder := insertTestDashboard(t, ss, "runbooks", 1, 0, true)
+		dash := insertTestDashboard(t, ss, "runbook", 1, folder.ID, false)
+
+		policy := &dashver.RetentionPolicy{OrgID: 1, FolderUID: folder.UID, MaxVersions: 10}
+		err := dashVerStore.SetRetentionPolicy(context.Background(), policy)
+		require.NoError(t, err)
+
+		// saving the policy of a folder again updates it
+		updated := &dashver.RetentionPolicy{OrgID: 1, FolderUID: folder.UID, MaxAge: "90d", KeepTagged: true}
+		err = dashVerStore.SetRetentionPolicy(context.Background(), updated)
+		require.NoError(t, err)
+		assert.Equal(t, policy.ID, updated.ID)
+
+		res, err := dashVerStore.GetRetentionPolicy(context.Background(), &dashver.GetRetentionPolicyQuery{OrgID: 1, FolderUID: folder.UID})
+		require.NoError(t, err)
+		assert.Equal(t, 0, res.MaxVersions)
+		assert.Equal(t, "90d", res.MaxAge)
+		assert.True(t, res.KeepTagged)
+
+		policies, err := dashVerStore.ListRetentionPolicies(context.Background(), &dashver.ListRetentionPoliciesQuery{})
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+
+		versions, err := dashVerStore.ListFolderVersions(context.Background(), 1, folder.UID)
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+		assert.Equal(t, dash.ID, versions[0].DashboardID)
+
+		_, err = dashVerStore.GetBatch(context.Background(), &dashver.DeleteExpiredVersionsCommand{}, 100, 1)
+		require.NoError(t, err)
+
+		err = dashVerStore.DeleteRetentionPolicy(context.Background(), &dashver.DeleteRetentionPolicyCommand{OrgID: 1, FolderUID: folder.UID})
+		require.NoError(t, err)
+		_, err = dashVerStore.GetRetentionPolicy(context.Background(), &dashver.GetRetentionPolicyQuery{OrgID: 1, FolderUID: folder.UID})
+		require.ErrorIs(t, err, dashver.ErrRetentionPolicyNotFound)
+		err = dashVerStore.DeleteRetentionPolicy(context.Background(), &dashver.DeleteRetentionPolicyCommand{OrgID: 1, FolderUID: folder.UID})
+		require.ErrorIs(t, err, dashver.ErrRetentionPolicyNotFound)
+	})
+
+	t.Run("Retention policies of nested folders", func(t *testing.T) {
+		parent := insertTestDashboard(t, ss, "services", 1, 0, true)
+		subfolder := insertTestDashboard(t, ss, "payments", 1, parent.ID, true)
+		insertTestFolder(t, ss, parent, "")
+		insertTestFolder(t, ss, subfolder, parent.UID)
+		dash := insertTestDashboard(t, ss, "payments runbook", 1, subfolder.ID, false)
+
+		err := dashVerStore.SetRetentionPolicy(context.Background(), &dashver.RetentionPolicy{OrgID: 1, FolderUID: parent.UID, MaxVersions: 10})
+		require.NoError(t, err)
+
+		// the dashboards of the subfolders are cleaned up with the policy of the parent folder
+		versions, err := dashVerStore.ListFolderVersions(context.Background(), 1, parent.UID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{subfolder.ID, dash.ID}, versionDashboardIDs(versions))
+
+		// and not with the global setting
+		versionIDs, err := dashVerStore.GetBatch(context.Background(), &dashver.DeleteExpiredVersionsCommand{}, 100, 0)
+		require.NoError(t, err)
+		require.NotEmpty(t, versionIDs)
+		for _, v := range versions {
+			for _, id := range versionIDs {
+				assert.NotEqualValues(t, v.ID, id)
+			}
+		}
+
+		// the policy of a subfolder takes precedence over the policy of its parent
+		err = dashVerStore.SetRetentionPolicy(context.Background(), &dashver.RetentionPolicy{OrgID: 1, FolderUID: subfolder.UID, MaxVersions: 5})
+		require.NoError(t, err)
+		versions, err = dashVerStore.ListFolderVersions(context.Background(), 1, parent.UID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{subfolder.ID}, versionDashboardIDs(versions))
+		versions, err = dashVerStore.ListFolderVersions(context.Background(), 1, subfolder.UID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{dash.ID}, versionDashboardIDs(versions))
+
+		for _, uid := range []string{parent.UID, subfolder.UID} {
+			err = dashVerStore.DeleteRetentionPolicy(context.Background(), &dashver.DeleteRetentionPolicyCommand{OrgID: 1, FolderUID: uid})
+			require.NoError(t, err)
+		}
+	})
+
 	savedDash := insertTestDashboard(t, ss, "test dash 43", 1, 0, false, "diff-all")
 	t.Run("Get all versions for a given Dashboard ID", func(t *testing.T) {
 		query := dashver.ListDashboardVersionsQuery{
@@ -133,6 +214,25 @@ var (
 	createdById = int64(3)
 )
 
+func versionDashboardIDs(versions []*dashver.DashboardVersion) []int64 {
+	ids := make([]int64, 0, len(versions))
+	for _, v := range versions {
+		ids = append(ids, v.DashboardID)
+	}
+	return ids
+}
+
+// insertTestFolder stores a folder in the folder table, like the folder service does when nested folders are enabled.
+func insertTestFolder(t *testing.T, sqlStore db.DB, f *dashboards.Dashboard, parentUID string) {
+	t.Helper()
+	err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		_, err := sess.Exec("INSERT INTO folder (uid, org_id, title, parent_uid, created, updated) VALUES (?, ?, ?, ?, ?, ?)",
+			f.UID, f.OrgID, f.Title, parentUID, time.Now(), time.Now())
+		return err
+	})
+	require.NoError(t, err)
+}
+
 func insertTestDashboard(t *testing.T, sqlStore db.DB, title string, orgId int64,
 	folderId int64, isFolder bool, tags ...interface{}) *dashboards.Dashboard {
 	t.Helper()
@@ -3,6 +3,7 @@ package dashverimpl
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
@@ -47,6 +48,7 @@ func (ss *sqlStore) GetBatch(ctx context.Context, cmd *dashver.DeleteExpiredVers
 			) AS vtd
 			WHERE dashboard_version.dashboard_id=vtd.dashboard_id
 			AND version < vtd.min + vtd.count - ?
+			` + excludeRetentionPolicyFoldersSQL + `
 			LIMIT ?`
 
 		err := sess.SQL(versionIdsToDeleteQuery, versionsToKeep, perBatch).Find(&versionIds)
@@ -103,3 +105,75 @@ func (ss *sqlStore) List(ctx context.Context, query *dashver.ListDashboardVersio
 	}
 	return dashboardVersion, nil
 }
+
+func (ss *sqlStore) ListFolderVersions(ctx context.Context, orgID int64, folderUID string) ([]*dashver.DashboardVersion, error) {
+	versions := make([]*dashver.DashboardVersion, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(listFolderVersionsSQL, listFolderVersionsArgs(orgID, folderUID)...).Find(&versions)
+	})
+	return versions, err
+}
+
+func (ss *sqlStore) GetRetentionPolicy(ctx context.Context, query *dashver.GetRetentionPolicyQuery) (*dashver.RetentionPolicy, error) {
+	var policy dashver.RetentionPolicy
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Table("dashboard_version_retention").Where("org_id=? AND folder_uid=?", query.OrgID, query.FolderUID).Get(&policy)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return dashver.ErrRetentionPolicyNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (ss *sqlStore) ListRetentionPolicies(ctx context.Context, query *dashver.ListRetentionPoliciesQuery) ([]*dashver.RetentionPolicy, error) {
+	policies := make([]*dashver.RetentionPolicy, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Table("dashboard_version_retention")
+		if query.OrgID > 0 {
+			sess.Where("org_id=?", query.OrgID)
+		}
+		return sess.OrderBy("org_id, folder_uid").Find(&policies)
+	})
+	return policies, err
+}
+
+func (ss *sqlStore) SetRetentionPolicy(ctx context.Context, policy *dashver.RetentionPolicy) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing dashver.RetentionPolicy
+		has, err := sess.Table("dashboard_version_retention").Where("org_id=? AND folder_uid=?", policy.OrgID, policy.FolderUID).Get(&existing)
+		if err != nil {
+			return err
+		}
+
+		policy.Updated = time.Now()
+		if has {
+			policy.ID = existing.ID
+			policy.Created = existing.Created
+			_, err = sess.Table("dashboard_version_retention").ID(policy.ID).AllCols().Update(policy)
+			return err
+		}
+		policy.Created = policy.Updated
+		_, err = sess.Table("dashboard_version_retention").Insert(policy)
+		return err
+	})
+}
+
+func (ss *sqlStore) DeleteRetentionPolicy(ctx context.Context, cmd *dashver.DeleteRetentionPolicyCommand) error {
+	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM dashboard_version_retention WHERE org_id=? AND folder_uid=?", cmd.OrgID, cmd.FolderUID)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return dashver.ErrRetentionPolicyNotFound
+		}
+		return nil
+	})
+}
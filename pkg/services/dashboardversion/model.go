@@ -10,6 +10,8 @@ import (
 var (
 	ErrDashboardVersionNotFound = errors.New("dashboard version not found")
 	ErrNoVersionsForDashboardID = errors.New("no dashboard versions found for the given DashboardId")
+	ErrRetentionPolicyNotFound  = errors.New("dashboard version retention policy not found")
+	ErrRetentionPolicyInvalid   = errors.New("invalid dashboard version retention policy")
 )
 
 // DashboardVersion represents a dashboard version in the database. Ideally this
@@ -59,6 +61,47 @@ type DeleteExpiredVersionsCommand struct {
 	DeletedRows int64
 }
 
+// RetentionPolicy replaces the global versions_to_keep setting for the dashboards of a folder.
+// The latest version of a dashboard is always kept.
+type RetentionPolicy struct {
+	ID        int64  `json:"-" xorm:"pk autoincr 'id'" db:"id"`
+	OrgID     int64  `json:"-" xorm:"org_id" db:"org_id"`
+	FolderUID string `json:"folderUid" xorm:"folder_uid" db:"folder_uid"`
+	// MaxVersions is the number of versions kept per dashboard, 0 does not limit the count
+	MaxVersions int `json:"maxVersions" xorm:"max_versions" db:"max_versions"`
+	// MaxAge is the age of the versions to delete, like 90d, empty does not limit the age
+	MaxAge string `json:"maxAge" xorm:"max_age" db:"max_age"`
+	// KeepTagged keeps the versions saved with a message forever
+	KeepTagged bool `json:"keepTagged" xorm:"keep_tagged" db:"keep_tagged"`
+
+	Created time.Time `json:"created" xorm:"created" db:"created"`
+	Updated time.Time `json:"updated" xorm:"updated" db:"updated"`
+}
+
+type GetRetentionPolicyQuery struct {
+	OrgID     int64
+	FolderUID string
+}
+
+// ListRetentionPoliciesQuery lists the retention policies of an organization,
+// or of all the organizations when OrgID is 0.
+type ListRetentionPoliciesQuery struct {
+	OrgID int64
+}
+
+type SetRetentionPolicyCommand struct {
+	OrgID       int64  `json:"-"`
+	FolderUID   string `json:"-"`
+	MaxVersions int    `json:"maxVersions"`
+	MaxAge      string `json:"maxAge"`
+	KeepTagged  bool   `json:"keepTagged"`
+}
+
+type DeleteRetentionPolicyCommand struct {
+	OrgID     int64
+	FolderUID string
+}
+
 type ListDashboardVersionsQuery struct {
 	DashboardID  int64
 	DashboardUID string
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -155,6 +156,10 @@ func (ss *SqlStore) DeleteDataSource(ctx context.Context, cmd *datasources.Delet
 				ac.Scope(datasources.ScopeProvider.GetResourceScope(ds.UID))); errDeletingPerms != nil {
 				return errDeletingPerms
 			}
+
+			if _, err := sess.Insert(createEntityEvent(ds, store.EntityEventTypeDelete)); err != nil {
+				return err
+			}
 		}
 
 		if cmd.UpdateSecretFn != nil {
@@ -278,6 +283,9 @@ func (ss *SqlStore) AddDataSource(ctx context.Context, cmd *datasources.AddDataS
 		if err := updateIsDefaultFlag(ds, sess); err != nil {
 			return err
 		}
+		if _, err := sess.Insert(createEntityEvent(ds, store.EntityEventTypeCreate)); err != nil {
+			return err
+		}
 
 		if cmd.UpdateSecretFn != nil {
 			if err := cmd.UpdateSecretFn(); err != nil {
@@ -297,6 +305,10 @@ func (ss *SqlStore) AddDataSource(ctx context.Context, cmd *datasources.AddDataS
 	})
 }
 
+func createEntityEvent(ds *datasources.DataSource, eventType store.EntityEventType) *store.EntityEvent {
+	return store.NewDatabaseEntityEvent(ds.UID, ds.OrgID, store.EntityTypeDatasource, eventType)
+}
+
 func updateIsDefaultFlag(ds *datasources.DataSource, sess *db.Session) error {
 	// Handle is default flag
 	if ds.IsDefault {
@@ -371,6 +383,9 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 		}
 
 		err = updateIsDefaultFlag(ds, sess)
+		if err == nil && ds.UID != "" {
+			_, err = sess.Insert(createEntityEvent(ds, store.EntityEventTypeUpdate))
+		}
 
 		if cmd.UpdateSecretFn != nil {
 			if err := cmd.UpdateSecretFn(); err != nil {
@@ -690,5 +690,13 @@ var (
 			RequiresRestart: true,
 			HideFromDocs:    true,
 		},
+		{
+			Name:            "backendQuerySplitting",
+			Description:     "Split data source queries with long time ranges into smaller chunks for plugins that support it",
+			Stage:           FeatureStageExperimental,
+			FrontendOnly:    false,
+			Owner:           grafanaPluginsPlatformSquad,
+			RequiresRestart: true,
+		},
 	}
 )
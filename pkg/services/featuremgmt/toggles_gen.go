@@ -406,4 +406,8 @@ const (
 	// FlagConfigurableSchedulerTick
 	// Enable changing the scheduler base interval via configuration option unified_alerting.scheduler_tick_interval
 	FlagConfigurableSchedulerTick = "configurableSchedulerTick"
+
+	// FlagBackendQuerySplitting
+	// Split data source queries with long time ranges into smaller chunks for plugins that support it
+	FlagBackendQuerySplitting = "backendQuerySplitting"
 )
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -160,7 +161,8 @@ func (l *LibraryElementService) createLibraryElement(c context.Context, signedIn
 			}
 			return err
 		}
-		return nil
+		_, err := session.Insert(createEntityEvent(element.UID, element.OrgID, store.EntityEventTypeCreate))
+		return err
 	})
 
 	dto := model.LibraryElementDTO{
@@ -230,6 +232,9 @@ func (l *LibraryElementService) deleteLibraryElement(c context.Context, signedIn
 		} else if rowsAffected != 1 {
 			return model.ErrLibraryElementNotFound
 		}
+		if _, err := session.Insert(createEntityEvent(element.UID, element.OrgID, store.EntityEventTypeDelete)); err != nil {
+			return err
+		}
 
 		elementID = element.ID
 		return nil
@@ -569,6 +574,9 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 		} else if rowsAffected != 1 {
 			return model.ErrLibraryElementNotFound
 		}
+		if _, err := session.Insert(createEntityEvent(libraryElement.UID, libraryElement.OrgID, store.EntityEventTypeUpdate)); err != nil {
+			return err
+		}
 
 		dto = model.LibraryElementDTO{
 			ID:          libraryElement.ID,
@@ -812,3 +820,7 @@ func (l *LibraryElementService) deleteLibraryElementsInFolderUID(c context.Conte
 		return nil
 	})
 }
+
+func createEntityEvent(uid string, orgID int64, eventType store.EntityEventType) *store.EntityEvent {
+	return store.NewDatabaseEntityEvent(uid, orgID, store.EntityTypeLibraryPanel, eventType)
+}
@@ -44,6 +44,8 @@ const (
 	JWTModule           = "jwt"
 	ExtendedJWTModule   = "extendedjwt"
 	RenderModule        = "render"
+	// AnnotationWebhookModule authenticates the signed events of annotation webhooks
+	AnnotationWebhookModule = "annotation_webhook"
 	// OAuth provider modules
 	AzureADAuthModule    = "oauth_azuread"
 	GoogleAuthModule     = "oauth_google"
@@ -52,6 +52,10 @@ func (i InstanceStateType) IsValid() bool {
 type ListAlertInstancesQuery struct {
 	RuleUID   string
 	RuleOrgID int64 `json:"-"`
+	// RuleUIDs limits the instances to the ones of these rules when not empty
+	RuleUIDs []string
+	// States limits the instances to the ones in these states when not empty
+	States []InstanceStateType
 }
 
 // ValidateAlertInstance validates that the alert instance contains an alert rule id,
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/search/model"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	storesrv "github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/store/entity"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
@@ -66,7 +67,7 @@ func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUI
 			return err
 		}
 		logger.Debug("deleted alert instances", "count", rows)
-		return nil
+		return insertAlertRuleEvents(sess, orgID, ruleUID, storesrv.EntityEventTypeDelete)
 	})
 }
 
@@ -168,6 +169,9 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 					return fmt.Errorf("failed to create new rules: %w", err)
 				}
 				ids[newRules[i].UID] = newRules[i].ID
+				if err := insertAlertRuleEvents(sess, newRules[i].OrgID, []string{newRules[i].UID}, storesrv.EntityEventTypeCreate); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -229,6 +233,9 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
 			})
+			if err := insertAlertRuleEvents(sess, r.New.OrgID, []string{r.New.UID}, storesrv.EntityEventTypeUpdate); err != nil {
+				return err
+			}
 		}
 		if len(ruleVersions) > 0 {
 			if _, err := sess.Insert(&ruleVersions); err != nil {
@@ -239,6 +246,16 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 	})
 }
 
+// insertAlertRuleEvents notifies the consumers of the entity events, like the search index, about changed rules.
+func insertAlertRuleEvents(sess *db.Session, orgID int64, ruleUIDs []string, eventType storesrv.EntityEventType) error {
+	for _, uid := range ruleUIDs {
+		if _, err := sess.Insert(storesrv.NewDatabaseEntityEvent(uid, orgID, storesrv.EntityTypeAlertRule, eventType)); err != nil {
+			return fmt.Errorf("failed to create entity event: %w", err)
+		}
+	}
+	return nil
+}
+
 // preventIntermediateUniqueConstraintViolations prevents unique constraint violations caused by an intermediate update.
 // The uniqueness constraint for titles within an org+folder is enforced on every update within a transaction
 // instead of on commit (deferred constraint). This means that there could be a set of updates that will throw
@@ -30,6 +30,20 @@ func (st DBstore) ListAlertInstances(ctx context.Context, cmd *models.ListAlertI
 		if cmd.RuleUID != "" {
 			addToQuery(` AND rule_uid = ?`, cmd.RuleUID)
 		}
+		if len(cmd.RuleUIDs) > 0 {
+			uids := make([]interface{}, 0, len(cmd.RuleUIDs))
+			for _, uid := range cmd.RuleUIDs {
+				uids = append(uids, uid)
+			}
+			addToQuery(` AND rule_uid IN (?`+strings.Repeat(",?", len(uids)-1)+`)`, uids...)
+		}
+		if len(cmd.States) > 0 {
+			states := make([]interface{}, 0, len(cmd.States))
+			for _, state := range cmd.States {
+				states = append(states, string(state))
+			}
+			addToQuery(` AND current_state IN (?`+strings.Repeat(",?", len(states)-1)+`)`, states...)
+		}
 		if st.FeatureToggles.IsEnabled(featuremgmt.FlagAlertingNoNormalState) {
 			s.WriteString(fmt.Sprintf(" AND NOT (current_state = '%s' AND current_reason = '')", models.InstanceStateNormal))
 		}
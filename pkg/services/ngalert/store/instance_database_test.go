@@ -196,6 +196,20 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Len(t, alerts, 4)
 	})
 
+	t.Run("can list the instances of rules in states", func(t *testing.T) {
+		listQuery := &models.ListAlertInstancesQuery{
+			RuleOrgID: orgID,
+			RuleUIDs:  []string{alertRule1.UID, alertRule2.UID},
+			States:    []models.InstanceStateType{models.InstanceStateFiring},
+		}
+
+		alerts, err := dbstore.ListAlertInstances(ctx, listQuery)
+		require.NoError(t, err)
+
+		require.Len(t, alerts, 1)
+		require.Equal(t, alertRule1.UID, alerts[0].RuleUID)
+	})
+
 	t.Run("should ignore Normal state with no reason if feature flag is enabled", func(t *testing.T) {
 		labels := models.InstanceLabels{"test": util.GenerateShortUID()}
 		instance1 := models.AlertInstance{
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/services/star"
+	storesrv "github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -45,7 +46,11 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComma
 		}
 
 		_, err = sess.Insert(&playlistItems)
+		if err != nil {
+			return err
+		}
 
+		_, err = sess.Insert(createEntityEvent(&p, storesrv.EntityEventTypeCreate))
 		return err
 	})
 	return &p, err
@@ -99,6 +104,11 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 		}
 
 		_, err = sess.Insert(&playlistItems)
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(createEntityEvent(&p, storesrv.EntityEventTypeUpdate))
 		return err
 	})
 	return &dto, err
@@ -142,7 +152,11 @@ func (s *sqlStore) Delete(ctx context.Context, cmd *playlist.DeletePlaylistComma
 
 		var rawItemSQL = "DELETE FROM playlist_item WHERE playlist_id = ?"
 		_, err = sess.Exec(rawItemSQL, playlist.Id)
+		if err != nil {
+			return err
+		}
 
+		_, err = sess.Insert(createEntityEvent(&playlist, storesrv.EntityEventTypeDelete))
 		return err
 	})
 }
@@ -210,3 +224,7 @@ func generateAndValidateNewPlaylistUid(sess *db.Session, orgId int64) (string, e
 }
 
 var generateNewUid func() string = util.GenerateShortUID
+
+func createEntityEvent(p *playlist.Playlist, eventType storesrv.EntityEventType) *storesrv.EntityEvent {
+	return storesrv.NewDatabaseEntityEvent(p.UID, p.OrgId, storesrv.EntityTypePlaylist, eventType)
+}
@@ -0,0 +1,531 @@
+package clientmiddleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/manager/registry"
+)
+
+const (
+	// defaultQuerySplitInterval is the chunk size used when the data source does not configure one.
+	defaultQuerySplitInterval = 24 * time.Hour
+	// maxQuerySplitChunks caps the number of chunks a single query can be split into.
+	// The split interval is widened when a time range would result in more chunks.
+	maxQuerySplitChunks = 100
+	// defaultSplitConcurrentQueryLimit is the number of chunks executed concurrently when no limit is configured.
+	defaultSplitConcurrentQueryLimit = 4
+	// defaultElasticsearchLogsLimit is the line limit of Elasticsearch logs queries which do not set one.
+	defaultElasticsearchLogsLimit = 500
+)
+
+// NewQuerySplittingMiddleware creates a new plugins.ClientMiddleware that will split
+// queries with long time ranges into smaller chunks, execute them with limited concurrency
+// and merge the resulting time series and log lines by time. Queries whose results cannot be
+// merged by time, e.g. term aggregations, raw documents or log lines without a line limit, are
+// executed unsplit. Only plugins that have the querySplitting capability set in their plugin.json
+// are affected.
+func NewQuerySplittingMiddleware(pluginRegistry registry.Service, concurrentQueryLimit int) plugins.ClientMiddleware {
+	if concurrentQueryLimit <= 0 {
+		concurrentQueryLimit = defaultSplitConcurrentQueryLimit
+	}
+	return plugins.ClientMiddlewareFunc(func(next plugins.Client) plugins.Client {
+		return &QuerySplittingMiddleware{
+			next:                 next,
+			pluginRegistry:       pluginRegistry,
+			concurrentQueryLimit: concurrentQueryLimit,
+			log:                  log.New("query_splitting_middleware"),
+		}
+	})
+}
+
+type QuerySplittingMiddleware struct {
+	next                 plugins.Client
+	pluginRegistry       registry.Service
+	concurrentQueryLimit int
+	log                  log.Logger
+}
+
+// querySplittingSettings are the data source settings controlling how queries are split.
+type querySplittingSettings struct {
+	// QuerySplitInterval is the maximum time range of a single chunk, e.g. "1d".
+	// Setting it to "0" disables splitting for the data source.
+	QuerySplitInterval string `json:"querySplitInterval"`
+}
+
+func (m *QuerySplittingMiddleware) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	if req == nil || req.PluginContext.DataSourceInstanceSettings == nil || !m.supportsQuerySplitting(ctx, req.PluginContext.PluginID) {
+		return m.next.QueryData(ctx, req)
+	}
+
+	interval, err := splitInterval(req.PluginContext.DataSourceInstanceSettings)
+	if err != nil {
+		m.log.Warn("Invalid query split interval, falling back to default", "datasource", req.PluginContext.DataSourceInstanceSettings.UID, "error", err)
+		interval = defaultQuerySplitInterval
+	}
+	if interval <= 0 {
+		return m.next.QueryData(ctx, req)
+	}
+
+	chunkedQueries, unsplitQueries := splitQueries(req.Queries, interval)
+	if len(chunkedQueries) == 0 {
+		return m.next.QueryData(ctx, req)
+	}
+
+	// Each sub request contains the n-th chunk of every split query, so the number of
+	// requests is bounded by the query with the most chunks.
+	var subRequests []*backend.QueryDataRequest
+	if len(unsplitQueries) > 0 {
+		subRequests = append(subRequests, cloneQueryDataRequest(req, unsplitQueries))
+	}
+	for _, queries := range chunkedQueries {
+		subRequests = append(subRequests, cloneQueryDataRequest(req, queries))
+	}
+
+	subResponses := make([]*backend.QueryDataResponse, len(subRequests))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(m.concurrentQueryLimit)
+	for i, subReq := range subRequests {
+		i, subReq := i, subReq
+		g.Go(func() error {
+			resp, err := m.next.QueryData(gctx, subReq)
+			if err != nil {
+				return err
+			}
+			subResponses[i] = resp
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Every split query has at least two chunks, so the first chunk contains all of them.
+	splitQueriesByRefID := make(map[string]backend.DataQuery, len(chunkedQueries[0]))
+	for _, q := range chunkedQueries[0] {
+		splitQueriesByRefID[q.RefID] = q
+	}
+	return mergeQueryDataResponses(subResponses, splitQueriesByRefID), nil
+}
+
+func (m *QuerySplittingMiddleware) supportsQuerySplitting(ctx context.Context, pluginID string) bool {
+	p, exists := m.pluginRegistry.Plugin(ctx, pluginID)
+	if !exists {
+		return false
+	}
+	return p.QuerySplitting
+}
+
+func splitInterval(settings *backend.DataSourceInstanceSettings) (time.Duration, error) {
+	if len(settings.JSONData) == 0 {
+		return defaultQuerySplitInterval, nil
+	}
+
+	var s querySplittingSettings
+	if err := json.Unmarshal(settings.JSONData, &s); err != nil {
+		return 0, err
+	}
+	if s.QuerySplitInterval == "" {
+		return defaultQuerySplitInterval, nil
+	}
+	if s.QuerySplitInterval == "0" {
+		return 0, nil
+	}
+
+	interval, err := gtime.ParseDuration(s.QuerySplitInterval)
+	if err != nil {
+		return 0, err
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("query split interval must be positive, got %q", s.QuerySplitInterval)
+	}
+	return interval, nil
+}
+
+// splitQueries splits every range query spanning more than interval into chunks.
+// The returned chunks are grouped by chunk index, in chronological order.
+// Queries that do not need or cannot be split are returned separately.
+func splitQueries(queries []backend.DataQuery, interval time.Duration) ([][]backend.DataQuery, []backend.DataQuery) {
+	var chunked [][]backend.DataQuery
+	var unsplit []backend.DataQuery
+
+	for _, q := range queries {
+		if !canSplitQuery(q) {
+			unsplit = append(unsplit, q)
+			continue
+		}
+		ranges := splitTimeRange(q.TimeRange, interval, q.Interval)
+		if len(ranges) < 2 {
+			unsplit = append(unsplit, q)
+			continue
+		}
+
+		for i, tr := range ranges {
+			if i == len(chunked) {
+				chunked = append(chunked, []backend.DataQuery{})
+			}
+			chunk := q
+			chunk.TimeRange = tr
+			chunked[i] = append(chunked[i], chunk)
+		}
+	}
+
+	return chunked, unsplit
+}
+
+// canSplitQuery returns whether the results of the query can be merged by time, based on its model.
+// This holds for Loki metric queries, Elasticsearch date histograms and log lines with a line limit.
+// Aggregations over the whole time range, such as Elasticsearch terms, and raw documents cannot be split.
+func canSplitQuery(q backend.DataQuery) bool {
+	if strings.EqualFold(q.QueryType, "instant") {
+		return false
+	}
+
+	model, err := simplejson.NewJson(q.JSON)
+	if err != nil {
+		return true
+	}
+
+	// Loki log queries start with a stream selector, metric queries with a function or an aggregation.
+	if expr, ok := model.CheckGet("expr"); ok && strings.HasPrefix(strings.TrimSpace(expr.MustString()), "{") {
+		return queryRowLimit(q) > 0
+	}
+
+	metrics := model.Get("metrics").MustArray()
+	if len(metrics) == 0 {
+		return true
+	}
+	for i := range metrics {
+		switch model.Get("metrics").GetIndex(i).Get("type").MustString() {
+		case "logs":
+			return true
+		case "raw_data", "raw_document":
+			return false
+		}
+	}
+	bucketAggs := model.Get("bucketAggs").MustArray()
+	if len(bucketAggs) == 0 {
+		return false
+	}
+	for i := range bucketAggs {
+		if model.Get("bucketAggs").GetIndex(i).Get("type").MustString() != "date_histogram" {
+			return false
+		}
+	}
+	return true
+}
+
+// splitTimeRange splits the time range into consecutive ranges of at most interval. The chunks do not
+// overlap: each one ends right before the next one starts. When step is set, the chunk boundaries are
+// aligned to multiples of it, so that every step is evaluated in exactly one chunk.
+func splitTimeRange(tr backend.TimeRange, interval time.Duration, step time.Duration) []backend.TimeRange {
+	duration := tr.Duration()
+	if duration <= interval {
+		return []backend.TimeRange{tr}
+	}
+
+	if duration/interval >= maxQuerySplitChunks {
+		interval = duration / (maxQuerySplitChunks - 1)
+	}
+
+	boundary := tr.From
+	if step > 0 {
+		if interval%step != 0 {
+			interval = (interval/step + 1) * step
+		}
+		boundary = boundary.Add(-time.Duration(boundary.UnixNano() % int64(step)))
+	}
+	boundary = boundary.Add(interval)
+
+	var ranges []backend.TimeRange
+	for from := tr.From; from.Before(tr.To); from, boundary = boundary, boundary.Add(interval) {
+		if !boundary.Before(tr.To) {
+			ranges = append(ranges, backend.TimeRange{From: from, To: tr.To})
+			break
+		}
+		ranges = append(ranges, backend.TimeRange{From: from, To: boundary.Add(-time.Nanosecond)})
+	}
+	return ranges
+}
+
+func cloneQueryDataRequest(req *backend.QueryDataRequest, queries []backend.DataQuery) *backend.QueryDataRequest {
+	return &backend.QueryDataRequest{
+		PluginContext: req.PluginContext,
+		Headers:       req.Headers,
+		Queries:       queries,
+	}
+}
+
+// mergeQueryDataResponses merges the responses of all sub requests. Time series frames of the same
+// query with the same name and fields are concatenated, sorted by their time field and deduplicated
+// by time. Log lines are merged the same way and truncated to the line limit of the query.
+func mergeQueryDataResponses(responses []*backend.QueryDataResponse, splitQueries map[string]backend.DataQuery) *backend.QueryDataResponse {
+	merged := backend.NewQueryDataResponse()
+	type refFrames struct {
+		keys       []string
+		frames     map[string]*data.Frame
+		descending map[string]bool
+	}
+	framesByRefID := map[string]*refFrames{}
+
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+
+		refIDs := make([]string, 0, len(resp.Responses))
+		for refID := range resp.Responses {
+			refIDs = append(refIDs, refID)
+		}
+		sort.Strings(refIDs)
+
+		for _, refID := range refIDs {
+			dr := resp.Responses[refID]
+			current := merged.Responses[refID]
+			if current.Error == nil && dr.Error != nil {
+				current.Error = dr.Error
+				current.Status = dr.Status
+			}
+			merged.Responses[refID] = current
+
+			rf, ok := framesByRefID[refID]
+			if !ok {
+				rf = &refFrames{frames: map[string]*data.Frame{}, descending: map[string]bool{}}
+				framesByRefID[refID] = rf
+			}
+
+			for _, frame := range dr.Frames {
+				key := frameKey(frame)
+				existing, ok := rf.frames[key]
+				if !ok {
+					rf.keys = append(rf.keys, key)
+					rf.frames[key] = frame
+					rf.descending[key] = isSortedDescending(frame)
+					continue
+				}
+				appendFrameRows(existing, frame)
+			}
+		}
+	}
+
+	for refID, rf := range framesByRefID {
+		dr := merged.Responses[refID]
+		if dr.Error != nil {
+			continue
+		}
+		q, split := splitQueries[refID]
+		frames := make(data.Frames, 0, len(rf.keys))
+		for _, key := range rf.keys {
+			frame := rf.frames[key]
+			sortFrameByTime(frame, rf.descending[key])
+			if split && isLogsFrame(frame) {
+				truncateFrame(frame, queryRowLimit(q))
+			} else if split {
+				dedupeFrameByTime(frame)
+			}
+			frames = append(frames, frame)
+		}
+		dr.Frames = frames
+		merged.Responses[refID] = dr
+	}
+
+	return merged
+}
+
+func isLogsFrame(frame *data.Frame) bool {
+	return frame.Meta != nil && (frame.Meta.Type.IsLogs() || frame.Meta.PreferredVisualization == data.VisTypeLogs)
+}
+
+// queryRowLimit returns the line limit of Loki and Elasticsearch logs queries, or 0 when it is unknown.
+func queryRowLimit(q backend.DataQuery) int {
+	model, err := simplejson.NewJson(q.JSON)
+	if err != nil {
+		return 0
+	}
+	if maxLines := model.Get("maxLines").MustInt(0); maxLines > 0 {
+		return maxLines
+	}
+	metric := model.Get("metrics").GetIndex(0)
+	if metric.Get("type").MustString() != "logs" {
+		return 0
+	}
+	limit, err := strconv.Atoi(metric.Get("settings").Get("limit").MustString())
+	if err != nil || limit <= 0 {
+		return defaultElasticsearchLogsLimit
+	}
+	return limit
+}
+
+// frameKey identifies frames that represent the same series across chunks.
+func frameKey(frame *data.Frame) string {
+	var sb strings.Builder
+	sb.WriteString(frame.Name)
+	for _, f := range frame.Fields {
+		sb.WriteString("|")
+		sb.WriteString(f.Name)
+		sb.WriteString(":")
+		sb.WriteString(f.Type().ItemTypeString())
+		if f.Labels != nil {
+			sb.WriteString(f.Labels.String())
+		}
+	}
+	return sb.String()
+}
+
+func appendFrameRows(dst *data.Frame, src *data.Frame) {
+	for i, f := range src.Fields {
+		for row := 0; row < f.Len(); row++ {
+			dst.Fields[i].Append(f.At(row))
+		}
+	}
+}
+
+// truncateFrame keeps the first limit rows of the frame.
+func truncateFrame(frame *data.Frame, limit int) {
+	rows, err := frame.RowLen()
+	if err != nil || limit <= 0 || rows <= limit {
+		return
+	}
+	for i, f := range frame.Fields {
+		truncated := data.NewFieldFromFieldType(f.Type(), limit)
+		truncated.Name = f.Name
+		truncated.Labels = f.Labels
+		truncated.Config = f.Config
+		for row := 0; row < limit; row++ {
+			truncated.Set(row, f.At(row))
+		}
+		frame.Fields[i] = truncated
+	}
+}
+
+// dedupeFrameByTime removes the rows of a frame sorted by time whose time equals the one of the previous
+// row, e.g. samples on a chunk boundary returned by both chunks. The first of these rows is kept.
+func dedupeFrameByTime(frame *data.Frame) {
+	timeIndex := timeFieldIndex(frame)
+	rows, err := frame.RowLen()
+	if timeIndex == -1 || err != nil || rows < 2 {
+		return
+	}
+
+	timeField := frame.Fields[timeIndex]
+	keep := []int{0}
+	for row := 1; row < rows; row++ {
+		if !timeAt(timeField, row).Equal(timeAt(timeField, keep[len(keep)-1])) {
+			keep = append(keep, row)
+		}
+	}
+	if len(keep) == rows {
+		return
+	}
+
+	for i, f := range frame.Fields {
+		deduped := data.NewFieldFromFieldType(f.Type(), len(keep))
+		deduped.Name = f.Name
+		deduped.Labels = f.Labels
+		deduped.Config = f.Config
+		for row, idx := range keep {
+			deduped.Set(row, f.At(idx))
+		}
+		frame.Fields[i] = deduped
+	}
+}
+
+// timeFieldIndex returns the index of the first time field of the frame, or -1 when there is none.
+func timeFieldIndex(frame *data.Frame) int {
+	for i, f := range frame.Fields {
+		if f.Type() == data.FieldTypeTime || f.Type() == data.FieldTypeNullableTime {
+			return i
+		}
+	}
+	return -1
+}
+
+func timeAt(f *data.Field, i int) time.Time {
+	t, _ := f.ConcreteAt(i)
+	if v, ok := t.(time.Time); ok {
+		return v
+	}
+	return time.Time{}
+}
+
+// isSortedDescending returns whether the rows of the frame are sorted from the newest to the oldest,
+// e.g. for log lines queried backwards.
+func isSortedDescending(frame *data.Frame) bool {
+	timeIndex := timeFieldIndex(frame)
+	rows, err := frame.RowLen()
+	if timeIndex == -1 || err != nil || rows < 2 {
+		return false
+	}
+	timeField := frame.Fields[timeIndex]
+	return timeAt(timeField, 0).After(timeAt(timeField, rows-1))
+}
+
+// sortFrameByTime sorts the rows of the frame by its first time field.
+func sortFrameByTime(frame *data.Frame, descending bool) {
+	timeIndex := timeFieldIndex(frame)
+	rows, err := frame.RowLen()
+	if timeIndex == -1 || err != nil || rows < 2 {
+		return
+	}
+
+	timeField := frame.Fields[timeIndex]
+	order := make([]int, rows)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if descending {
+			return timeAt(timeField, order[a]).After(timeAt(timeField, order[b]))
+		}
+		return timeAt(timeField, order[a]).Before(timeAt(timeField, order[b]))
+	})
+
+	for i, f := range frame.Fields {
+		sorted := data.NewFieldFromFieldType(f.Type(), rows)
+		sorted.Name = f.Name
+		sorted.Labels = f.Labels
+		sorted.Config = f.Config
+		for row, idx := range order {
+			sorted.Set(row, f.At(idx))
+		}
+		frame.Fields[i] = sorted
+	}
+}
+
+func (m *QuerySplittingMiddleware) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return m.next.CallResource(ctx, req, sender)
+}
+
+func (m *QuerySplittingMiddleware) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	return m.next.CheckHealth(ctx, req)
+}
+
+func (m *QuerySplittingMiddleware) CollectMetrics(ctx context.Context, req *backend.CollectMetricsRequest) (*backend.CollectMetricsResult, error) {
+	return m.next.CollectMetrics(ctx, req)
+}
+
+func (m *QuerySplittingMiddleware) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	return m.next.SubscribeStream(ctx, req)
+}
+
+func (m *QuerySplittingMiddleware) PublishStream(ctx context.Context, req *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return m.next.PublishStream(ctx, req)
+}
+
+func (m *QuerySplittingMiddleware) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	return m.next.RunStream(ctx, req, sender)
+}
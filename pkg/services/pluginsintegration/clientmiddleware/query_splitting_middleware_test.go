@@ -0,0 +1,303 @@
+package clientmiddleware
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/manager/client"
+	"github.com/grafana/grafana/pkg/plugins/manager/client/clienttest"
+	"github.com/grafana/grafana/pkg/plugins/manager/registry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuerySplittingMiddleware(t *testing.T) {
+	from := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(72 * time.Hour)
+
+	setup := func(t *testing.T, querySplitting bool) (*client.Decorator, *[]*backend.QueryDataRequest) {
+		t.Helper()
+
+		reg := registry.NewInMemory()
+		err := reg.Add(context.Background(), &plugins.Plugin{
+			JSONData: plugins.JSONData{ID: "loki", Type: plugins.TypeDataSource, QuerySplitting: querySplitting},
+		})
+		require.NoError(t, err)
+
+		var mu sync.Mutex
+		var requests []*backend.QueryDataRequest
+		c := &clienttest.TestClient{
+			QueryDataFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				mu.Lock()
+				requests = append(requests, req)
+				mu.Unlock()
+
+				resp := backend.NewQueryDataResponse()
+				for _, q := range req.Queries {
+					resp.Responses[q.RefID] = backend.DataResponse{
+						Frames: data.Frames{
+							data.NewFrame("series",
+								data.NewField("time", nil, []time.Time{q.TimeRange.From}),
+								data.NewField("value", data.Labels{"job": "grafana"}, []float64{float64(q.TimeRange.From.Unix())}),
+							),
+						},
+					}
+				}
+				return resp, nil
+			},
+		}
+
+		d, err := client.NewDecorator(c, NewQuerySplittingMiddleware(reg, 2))
+		require.NoError(t, err)
+		return d, &requests
+	}
+
+	pluginCtx := func(jsonData string) backend.PluginContext {
+		return backend.PluginContext{
+			PluginID: "loki",
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				UID:      "loki-uid",
+				JSONData: []byte(jsonData),
+			},
+		}
+	}
+
+	t.Run("Should not split queries when plugin does not support query splitting", func(t *testing.T) {
+		d, requests := setup(t, false)
+
+		resp, err := d.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx(`{}`),
+			Queries:       []backend.DataQuery{{RefID: "A", TimeRange: backend.TimeRange{From: from, To: to}}},
+		})
+		require.NoError(t, err)
+		require.Len(t, *requests, 1)
+		require.Len(t, resp.Responses["A"].Frames, 1)
+	})
+
+	t.Run("Should split queries into chunks of the default interval and merge frames by time", func(t *testing.T) {
+		d, requests := setup(t, true)
+
+		resp, err := d.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx(`{}`),
+			Queries:       []backend.DataQuery{{RefID: "A", TimeRange: backend.TimeRange{From: from, To: to}}},
+		})
+		require.NoError(t, err)
+		require.Len(t, *requests, 3)
+		chunks := make([]backend.TimeRange, 0, len(*requests))
+		for _, req := range *requests {
+			require.Len(t, req.Queries, 1)
+			chunks = append(chunks, req.Queries[0].TimeRange)
+		}
+		sort.Slice(chunks, func(i, j int) bool { return chunks[i].From.Before(chunks[j].From) })
+		require.Equal(t, []backend.TimeRange{
+			{From: from, To: from.Add(24*time.Hour - time.Nanosecond)},
+			{From: from.Add(24 * time.Hour), To: from.Add(48*time.Hour - time.Nanosecond)},
+			{From: from.Add(48 * time.Hour), To: to},
+		}, chunks)
+
+		frames := resp.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 3, frames[0].Fields[0].Len())
+		require.Equal(t, from, frames[0].Fields[0].At(0))
+		require.Equal(t, from.Add(24*time.Hour), frames[0].Fields[0].At(1))
+		require.Equal(t, from.Add(48*time.Hour), frames[0].Fields[0].At(2))
+	})
+
+	t.Run("Should use the split interval configured for the data source", func(t *testing.T) {
+		d, requests := setup(t, true)
+
+		_, err := d.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx(`{"querySplitInterval":"12h"}`),
+			Queries:       []backend.DataQuery{{RefID: "A", TimeRange: backend.TimeRange{From: from, To: to}}},
+		})
+		require.NoError(t, err)
+		require.Len(t, *requests, 6)
+	})
+
+	t.Run("Should not split queries when splitting is disabled for the data source", func(t *testing.T) {
+		d, requests := setup(t, true)
+
+		_, err := d.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx(`{"querySplitInterval":"0"}`),
+			Queries:       []backend.DataQuery{{RefID: "A", TimeRange: backend.TimeRange{From: from, To: to}}},
+		})
+		require.NoError(t, err)
+		require.Len(t, *requests, 1)
+	})
+
+	t.Run("Should not split instant queries or queries shorter than the split interval", func(t *testing.T) {
+		d, requests := setup(t, true)
+
+		resp, err := d.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx(`{}`),
+			Queries: []backend.DataQuery{
+				{RefID: "A", QueryType: "instant", TimeRange: backend.TimeRange{From: from, To: to}},
+				{RefID: "B", TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)}},
+				{RefID: "C", TimeRange: backend.TimeRange{From: from, To: from.Add(36 * time.Hour)}},
+			},
+		})
+		require.NoError(t, err)
+		// One request for the unsplit queries and two for the chunks of query C.
+		require.Len(t, *requests, 3)
+		require.Equal(t, 1, resp.Responses["A"].Frames[0].Fields[0].Len())
+		require.Equal(t, 1, resp.Responses["B"].Frames[0].Fields[0].Len())
+		require.Equal(t, 2, resp.Responses["C"].Frames[0].Fields[0].Len())
+	})
+}
+
+func TestQuerySplittingMiddleware_NonTimeSeriesFrames(t *testing.T) {
+	from := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(72 * time.Hour)
+
+	setup := func(t *testing.T, newFrame func(q backend.DataQuery) *data.Frame) (*client.Decorator, *[]*backend.QueryDataRequest) {
+		t.Helper()
+
+		reg := registry.NewInMemory()
+		err := reg.Add(context.Background(), &plugins.Plugin{
+			JSONData: plugins.JSONData{ID: "loki", Type: plugins.TypeDataSource, QuerySplitting: true},
+		})
+		require.NoError(t, err)
+
+		var mu sync.Mutex
+		var requests []*backend.QueryDataRequest
+		c := &clienttest.TestClient{
+			QueryDataFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				mu.Lock()
+				requests = append(requests, req)
+				mu.Unlock()
+
+				resp := backend.NewQueryDataResponse()
+				for _, q := range req.Queries {
+					resp.Responses[q.RefID] = backend.DataResponse{Frames: data.Frames{newFrame(q)}}
+				}
+				return resp, nil
+			},
+		}
+
+		d, err := client.NewDecorator(c, NewQuerySplittingMiddleware(reg, 2))
+		require.NoError(t, err)
+		return d, &requests
+	}
+
+	pluginCtx := backend.PluginContext{
+		PluginID:                   "loki",
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "loki-uid", JSONData: []byte(`{}`)},
+	}
+
+	t.Run("Should merge log lines and keep the newest lines up to the line limit", func(t *testing.T) {
+		d, requests := setup(t, func(q backend.DataQuery) *data.Frame {
+			// two lines per chunk, from the newest to the oldest
+			frame := data.NewFrame("logs",
+				data.NewField("time", nil, []time.Time{q.TimeRange.To.Add(-time.Minute), q.TimeRange.From}),
+				data.NewField("line", nil, []string{"newer", "older"}),
+			)
+			frame.SetMeta(&data.FrameMeta{PreferredVisualization: data.VisTypeLogs})
+			return frame
+		})
+
+		resp, err := d.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx,
+			Queries: []backend.DataQuery{
+				{RefID: "A", JSON: []byte(`{"expr": "{job=\"grafana\"}", "maxLines": 3}`), TimeRange: backend.TimeRange{From: from, To: to}},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, *requests, 3)
+
+		frames := resp.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 3, frames[0].Fields[0].Len())
+		require.Equal(t, to.Add(-time.Minute), frames[0].Fields[0].At(0))
+		require.Equal(t, from.Add(48*time.Hour), frames[0].Fields[0].At(1))
+		require.Equal(t, from.Add(48*time.Hour-time.Minute-time.Nanosecond), frames[0].Fields[0].At(2))
+	})
+
+	t.Run("Should deduplicate samples on a chunk boundary", func(t *testing.T) {
+		d, requests := setup(t, func(q backend.DataQuery) *data.Frame {
+			// a sample at the start of the chunk and one at the end of the chunk, rounded up to the hour
+			end := q.TimeRange.To.Add(time.Hour - time.Nanosecond).Truncate(time.Hour)
+			return data.NewFrame("series",
+				data.NewField("time", nil, []time.Time{q.TimeRange.From, end}),
+				data.NewField("value", nil, []float64{float64(q.TimeRange.From.Unix()), float64(end.Unix())}),
+			)
+		})
+
+		resp, err := d.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx,
+			Queries: []backend.DataQuery{
+				{RefID: "A", TimeRange: backend.TimeRange{From: from, To: to}},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, *requests, 3)
+
+		frames := resp.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 4, frames[0].Fields[0].Len())
+		for i := 0; i < 4; i++ {
+			require.Equal(t, from.Add(time.Duration(i)*24*time.Hour), frames[0].Fields[0].At(i))
+		}
+	})
+
+	t.Run("Should execute queries that cannot be split once over the whole time range", func(t *testing.T) {
+		d, requests := setup(t, func(q backend.DataQuery) *data.Frame {
+			return data.NewFrame("terms",
+				data.NewField("host", nil, []string{"a", "b"}),
+				data.NewField("count", nil, []float64{q.TimeRange.Duration().Hours(), 1}),
+			)
+		})
+
+		queries := []backend.DataQuery{
+			{RefID: "A", JSON: []byte(`{"metrics": [{"type": "count"}], "bucketAggs": [{"type": "terms"}]}`)},
+			{RefID: "B", JSON: []byte(`{"metrics": [{"type": "raw_data"}], "bucketAggs": []}`)},
+			{RefID: "C", JSON: []byte(`{"expr": "{job=\"grafana\"}"}`)},
+		}
+		for i := range queries {
+			queries[i].TimeRange = backend.TimeRange{From: from, To: to}
+		}
+
+		resp, err := d.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx,
+			Queries:       queries,
+		})
+		require.NoError(t, err)
+		require.Len(t, *requests, 1)
+		require.Len(t, (*requests)[0].Queries, 3)
+		for _, refID := range []string{"A", "B", "C"} {
+			frames := resp.Responses[refID].Frames
+			require.Len(t, frames, 1)
+			require.Equal(t, float64(72), frames[0].Fields[1].At(0))
+		}
+	})
+}
+
+func TestSplitTimeRange(t *testing.T) {
+	from := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Chunks should not overlap and the last chunk should end at the end of the time range", func(t *testing.T) {
+		ranges := splitTimeRange(backend.TimeRange{From: from, To: from.Add(30 * time.Hour)}, 24*time.Hour, 0)
+		require.Len(t, ranges, 2)
+		require.Equal(t, backend.TimeRange{From: from, To: from.Add(24*time.Hour - time.Nanosecond)}, ranges[0])
+		require.Equal(t, backend.TimeRange{From: from.Add(24 * time.Hour), To: from.Add(30 * time.Hour)}, ranges[1])
+	})
+
+	t.Run("Chunk boundaries should be aligned to the query step", func(t *testing.T) {
+		start := from.Add(90 * time.Minute)
+		ranges := splitTimeRange(backend.TimeRange{From: start, To: start.Add(30 * time.Hour)}, 10*time.Hour+time.Minute, time.Hour)
+		require.Len(t, ranges, 3)
+		require.Equal(t, backend.TimeRange{From: start, To: from.Add(12*time.Hour - time.Nanosecond)}, ranges[0])
+		require.Equal(t, backend.TimeRange{From: from.Add(12 * time.Hour), To: from.Add(23*time.Hour - time.Nanosecond)}, ranges[1])
+		require.Equal(t, backend.TimeRange{From: from.Add(23 * time.Hour), To: start.Add(30 * time.Hour)}, ranges[2])
+	})
+
+	t.Run("Number of chunks should be capped", func(t *testing.T) {
+		ranges := splitTimeRange(backend.TimeRange{From: from, To: from.Add(365 * 24 * time.Hour)}, time.Hour, time.Minute)
+		require.LessOrEqual(t, len(ranges), maxQuerySplitChunks)
+		require.Equal(t, from.Add(365*24*time.Hour), ranges[len(ranges)-1].To)
+	})
+}
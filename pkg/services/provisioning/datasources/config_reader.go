@@ -108,6 +108,10 @@ func (cr *configReader) validateDefaultUniqueness(ctx context.Context, datasourc
 				return fmt.Errorf("failed to provision %q data source: %w", ds.Name, err)
 			}
 
+			if err := validateCorrelations(ds); err != nil {
+				return fmt.Errorf("failed to provision %q data source: %w", ds.Name, err)
+			}
+
 			if ds.IsDefault {
 				defaultCount[ds.OrgID]++
 				if defaultCount[ds.OrgID] > 1 {
@@ -145,3 +149,13 @@ func (cr *configReader) validateAccessAndOrgID(ctx context.Context, ds *upsertDa
 	}
 	return nil
 }
+
+// validateCorrelations rejects the invalid correlations before any data source is provisioned
+func validateCorrelations(ds *upsertDataSourceFromConfig) error {
+	for i, correlation := range ds.Correlations {
+		if _, err := makeCreateCorrelationCommand(correlation, ds.UID, ds.OrgID); err != nil {
+			return fmt.Errorf("invalid correlation %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
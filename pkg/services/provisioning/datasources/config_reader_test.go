@@ -29,6 +29,7 @@ var (
 	invalidAccess                   = "testdata/invalid-access"
 
 	oneDatasourceWithTwoCorrelations = "testdata/one-datasource-two-correlations"
+	invalidCorrelationTransformation = "testdata/invalid-correlation-transformation"
 )
 
 func TestDatasourceAsConfig(t *testing.T) {
@@ -267,6 +268,19 @@ func TestDatasourceAsConfig(t *testing.T) {
 			require.Equal(t, 0, len(correlationsStore.deletedByTargetUID))
 		})
 
+		t.Run("Fails before provisioning the datasource given an invalid transformation", func(t *testing.T) {
+			store := &spyStore{}
+			orgFake := &orgtest.FakeOrgService{}
+			correlationsStore := &mockCorrelationsStore{}
+			dc := newDatasourceProvisioner(logger, store, correlationsStore, orgFake)
+			err := dc.applyChanges(context.Background(), invalidCorrelationTransformation)
+			require.ErrorIs(t, err, correlations.ErrTransformationInvalidExpression)
+			require.Contains(t, err.Error(), `"Loki" data source`)
+
+			require.Equal(t, 0, len(store.inserted))
+			require.Equal(t, 0, len(correlationsStore.created))
+		})
+
 		t.Run("Deleting datasource deletes existing correlations", func(t *testing.T) {
 			store := &spyStore{items: []*datasources.DataSource{{Name: "old-data-source", OrgID: 1, ID: 1, UID: "some-uid"}}}
 			orgFake := &orgtest.FakeOrgService{}
@@ -139,10 +139,12 @@ func (dc *DatasourceProvisioner) applyChanges(ctx context.Context, configPath st
 
 func makeCreateCorrelationCommand(correlation map[string]interface{}, SourceUID string, OrgId int64) (correlations.CreateCorrelationCommand, error) {
 	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	label, _ := correlation["label"].(string)
+	description, _ := correlation["description"].(string)
 	createCommand := correlations.CreateCorrelationCommand{
 		SourceUID:         SourceUID,
-		Label:             correlation["label"].(string),
-		Description:       correlation["description"].(string),
+		Label:             label,
+		Description:       description,
 		OrgId:             OrgId,
 		SkipReadOnlyCheck: true,
 	}
@@ -167,6 +169,11 @@ func makeCreateCorrelationCommand(correlation map[string]interface{}, SourceUID
 			return correlations.CreateCorrelationCommand{}, err
 		}
 
+		// the type is optional in the config too, query is the only type
+		if config.Type == "" {
+			config.Type = correlations.ConfigTypeQuery
+		}
+
 		createCommand.Config = config
 	} else {
 		// when provisioning correlations without config we default to type="query"
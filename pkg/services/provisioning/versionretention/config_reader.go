@@ -0,0 +1,94 @@
+package versionretention
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*policiesAsConfig, error) {
+	var policies []*policiesAsConfig
+	cr.log.Debug("Looking for version retention provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read version retention provisioning files from directory", "path", path, "error", err)
+		return policies, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing version retention provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parsePolicyConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				policies = append(policies, cfg)
+			}
+		}
+	}
+
+	if err := validatePolicies(policies); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+func (cr *configReader) parsePolicyConfig(path string, file fs.DirEntry) (*policiesAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *policiesAsConfigV0
+	err = yaml.Unmarshal(yamlFile, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToPoliciesFromConfig(), nil
+}
+
+func validatePolicies(configs []*policiesAsConfig) error {
+	for _, cfg := range configs {
+		for index, policy := range cfg.Policies {
+			if policy.FolderUID == "" {
+				return fmt.Errorf("policy item %d in configuration doesn't contain required field folderUid", index+1)
+			}
+			if policy.OrgID < 1 {
+				policy.OrgID = 1
+			}
+		}
+
+		for index, policy := range cfg.DeletePolicies {
+			if policy.FolderUID == "" {
+				return fmt.Errorf("delete policy item %d in configuration doesn't contain required field folderUid", index+1)
+			}
+			if policy.OrgID < 1 {
+				policy.OrgID = 1
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package versionretention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+)
+
+// Provision scans a directory for provisioning config files
+// and sets the dashboard version retention policies in those files.
+func Provision(ctx context.Context, configDirectory string, dashboardVersionService dashver.Service) error {
+	logger := log.New("provisioning.version_retention")
+	p := &provisioner{
+		log:                     logger,
+		cfgProvider:             &configReader{log: logger},
+		dashboardVersionService: dashboardVersionService,
+	}
+	return p.applyChanges(ctx, configDirectory)
+}
+
+// provisioner is responsible for provisioning dashboard version retention
+// policies based on configuration read by the `configReader`
+type provisioner struct {
+	log                     log.Logger
+	cfgProvider             *configReader
+	dashboardVersionService dashver.Service
+}
+
+func (p *provisioner) apply(ctx context.Context, cfg *policiesAsConfig) error {
+	for _, policy := range cfg.DeletePolicies {
+		p.log.Info("Deleting version retention policy from configuration", "orgId", policy.OrgID, "folderUid", policy.FolderUID)
+		err := p.dashboardVersionService.DeleteRetentionPolicy(ctx, &dashver.DeleteRetentionPolicyCommand{
+			OrgID:     policy.OrgID,
+			FolderUID: policy.FolderUID,
+		})
+		if err != nil && !errors.Is(err, dashver.ErrRetentionPolicyNotFound) {
+			return err
+		}
+	}
+
+	for _, policy := range cfg.Policies {
+		p.log.Info("Updating version retention policy from configuration", "orgId", policy.OrgID, "folderUid", policy.FolderUID)
+		_, err := p.dashboardVersionService.SetRetentionPolicy(ctx, &dashver.SetRetentionPolicyCommand{
+			OrgID:       policy.OrgID,
+			FolderUID:   policy.FolderUID,
+			MaxVersions: policy.MaxVersions,
+			MaxAge:      policy.MaxAge,
+			KeepTagged:  policy.KeepTagged,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to provision version retention policy for folder %q: %w", policy.FolderUID, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *provisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := p.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := p.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
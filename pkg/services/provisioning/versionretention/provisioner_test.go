@@ -0,0 +1,87 @@
+package versionretention
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
+)
+
+const (
+	brokenYaml        = "./testdata/broken-yaml"
+	missingFolder     = "./testdata/missing-folder"
+	correctProperties = "./testdata/correct-properties"
+	emptyFolder       = "./testdata/empty-folder"
+)
+
+func TestConfigReader(t *testing.T) {
+	reader := &configReader{log: log.New("test logger")}
+
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		cfg, err := reader.readConfig(emptyFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Policy without folder should return error", func(t *testing.T) {
+		_, err := reader.readConfig(missingFolder)
+		require.EqualError(t, err, "policy item 1 in configuration doesn't contain required field folderUid")
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+
+		require.Equal(t, []*policyFromConfig{
+			{OrgID: 2, FolderUID: "production", MaxVersions: 50, MaxAge: "90d", KeepTagged: true},
+			{OrgID: 1, FolderUID: "scratch", MaxVersions: 5},
+		}, cfg[0].Policies)
+		require.Equal(t, []*deletePolicyConfig{{OrgID: 1, FolderUID: "archived"}}, cfg[0].DeletePolicies)
+	})
+}
+
+func TestProvision(t *testing.T) {
+	t.Run("Missing policies are ignored on delete", func(t *testing.T) {
+		svc := &fakeDashboardVersionService{deleteErr: dashver.ErrRetentionPolicyNotFound}
+		require.NoError(t, Provision(context.Background(), correctProperties, svc))
+		require.Len(t, svc.set, 2)
+		require.Equal(t, "production", svc.set[0].FolderUID)
+		require.Equal(t, int64(2), svc.set[0].OrgID)
+		require.Equal(t, "90d", svc.set[0].MaxAge)
+		require.Len(t, svc.deleted, 1)
+	})
+
+	t.Run("Invalid policies fail provisioning", func(t *testing.T) {
+		svc := &fakeDashboardVersionService{setErr: dashver.ErrRetentionPolicyInvalid}
+		err := Provision(context.Background(), correctProperties, svc)
+		require.ErrorIs(t, err, dashver.ErrRetentionPolicyInvalid)
+	})
+}
+
+type fakeDashboardVersionService struct {
+	dashvertest.FakeDashboardVersionService
+	set       []*dashver.SetRetentionPolicyCommand
+	deleted   []*dashver.DeleteRetentionPolicyCommand
+	setErr    error
+	deleteErr error
+}
+
+func (f *fakeDashboardVersionService) SetRetentionPolicy(ctx context.Context, cmd *dashver.SetRetentionPolicyCommand) (*dashver.RetentionPolicy, error) {
+	f.set = append(f.set, cmd)
+	return &dashver.RetentionPolicy{}, f.setErr
+}
+
+func (f *fakeDashboardVersionService) DeleteRetentionPolicy(ctx context.Context, cmd *dashver.DeleteRetentionPolicyCommand) error {
+	f.deleted = append(f.deleted, cmd)
+	return f.deleteErr
+}
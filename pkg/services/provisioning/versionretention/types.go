@@ -0,0 +1,70 @@
+package versionretention
+
+import "github.com/grafana/grafana/pkg/services/provisioning/values"
+
+// policiesAsConfig is a normalized data object for version retention config data. Any config version should be mappable
+// to this type.
+type policiesAsConfig struct {
+	Policies       []*policyFromConfig
+	DeletePolicies []*deletePolicyConfig
+}
+
+type policyFromConfig struct {
+	OrgID       int64
+	FolderUID   string
+	MaxVersions int
+	MaxAge      string
+	KeepTagged  bool
+}
+
+type deletePolicyConfig struct {
+	OrgID     int64
+	FolderUID string
+}
+
+type policyFromConfigV0 struct {
+	OrgID       values.Int64Value  `json:"orgId" yaml:"orgId"`
+	FolderUID   values.StringValue `json:"folderUid" yaml:"folderUid"`
+	MaxVersions values.IntValue    `json:"maxVersions" yaml:"maxVersions"`
+	MaxAge      values.StringValue `json:"maxAge" yaml:"maxAge"`
+	KeepTagged  values.BoolValue   `json:"keepTagged" yaml:"keepTagged"`
+}
+
+type deletePolicyConfigV0 struct {
+	OrgID     values.Int64Value  `json:"orgId" yaml:"orgId"`
+	FolderUID values.StringValue `json:"folderUid" yaml:"folderUid"`
+}
+
+// policiesAsConfigV0 is a mapping for zero version configs. This is mapped to its normalised version.
+type policiesAsConfigV0 struct {
+	Policies       []*policyFromConfigV0   `json:"policies" yaml:"policies"`
+	DeletePolicies []*deletePolicyConfigV0 `json:"deletePolicies" yaml:"deletePolicies"`
+}
+
+// mapToPoliciesFromConfig maps config syntax to a normalized policiesAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *policiesAsConfigV0) mapToPoliciesFromConfig() *policiesAsConfig {
+	r := &policiesAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, policy := range cfg.Policies {
+		r.Policies = append(r.Policies, &policyFromConfig{
+			OrgID:       policy.OrgID.Value(),
+			FolderUID:   policy.FolderUID.Value(),
+			MaxVersions: policy.MaxVersions.Value(),
+			MaxAge:      policy.MaxAge.Value(),
+			KeepTagged:  policy.KeepTagged.Value(),
+		})
+	}
+
+	for _, policy := range cfg.DeletePolicies {
+		r.DeletePolicies = append(r.DeletePolicies, &deletePolicyConfig{
+			OrgID:     policy.OrgID.Value(),
+			FolderUID: policy.FolderUID.Value(),
+		})
+	}
+
+	return r
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/response"
@@ -13,8 +14,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/metric"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -23,6 +26,8 @@ type Api struct {
 	RouteRegister          routing.RouteRegister
 	AccessControl          accesscontrol.AccessControl
 	Features               *featuremgmt.FeatureManager
+	Usage                  *metric.Service
+	RateLimiter            *RateLimiter
 	Log                    log.Logger
 }
 
@@ -31,12 +36,21 @@ func ProvideApi(
 	rr routing.RouteRegister,
 	ac accesscontrol.AccessControl,
 	features *featuremgmt.FeatureManager,
+	usage *metric.Service,
+	cfg *setting.Cfg,
 ) *Api {
+	var settings setting.PublicDashboardsSettings
+	if cfg != nil {
+		settings = cfg.PublicDashboards
+	}
+
 	api := &Api{
 		PublicDashboardService: pd,
 		RouteRegister:          rr,
 		AccessControl:          ac,
 		Features:               features,
+		Usage:                  usage,
+		RateLimiter:            NewRateLimiter(settings.RateLimitPerToken, settings.RateLimitPerIP),
 		Log:                    log.New("publicdashboards.api"),
 	}
 
@@ -55,9 +69,10 @@ func (api *Api) RegisterAPIEndpoints() {
 	// because it is deeply dependent on the HTTPServer.Index() method and would result in a
 	// circular dependency
 
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.ViewPublicDashboard))
-	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", routing.Wrap(api.GetAnnotations))
+	rateLimit := RateLimitPublicDashboards(api.RateLimiter)
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken", rateLimit, routing.Wrap(api.ViewPublicDashboard))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", rateLimit, routing.Wrap(api.QueryPublicDashboard))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", rateLimit, routing.Wrap(api.GetAnnotations))
 
 	// Auth endpoints
 	auth := accesscontrol.Middleware(api.AccessControl)
@@ -85,6 +100,11 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.RouteRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid",
 		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.DeletePublicDashboard))
+
+	// Get Public Dashboard usage
+	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/usage",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, uidScope)),
+		routing.Wrap(api.GetPublicDashboardUsage))
 }
 
 // ListPublicDashboards Gets list of public dashboards by orgId
@@ -219,6 +239,57 @@ func (api *Api) DeletePublicDashboard(c *contextmodel.ReqContext) response.Respo
 	return response.JSON(http.StatusOK, nil)
 }
 
+// GetPublicDashboardUsage Gets the views and queries of a public dashboard
+// GET /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/usage
+func (api *Api) GetPublicDashboardUsage(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	dashboardUid := web.Params(c.Req)[":dashboardUid"]
+	if !validation.IsValidShortUID(uid) || !validation.IsValidShortUID(dashboardUid) {
+		return response.Err(ErrPublicDashboardIdentifierNotSet.Errorf("GetPublicDashboardUsage: invalid Uid for public dashboard %s", uid))
+	}
+
+	pd, err := api.PublicDashboardService.Find(c.Req.Context(), uid)
+	if err != nil {
+		return response.Err(err)
+	}
+	if pd == nil || pd.DashboardUid != dashboardUid || pd.OrgId != c.OrgID {
+		return response.Err(ErrPublicDashboardNotFound.Errorf("GetPublicDashboardUsage: public dashboard not found"))
+	}
+
+	// from and to are epoch milliseconds, the last 7 days by default
+	to := time.Now()
+	if c.Query("to") != "" {
+		to = time.UnixMilli(c.QueryInt64("to"))
+	}
+	from := to.Add(-7 * 24 * time.Hour)
+	if c.Query("from") != "" {
+		from = time.UnixMilli(c.QueryInt64("from"))
+	}
+	if !from.Before(to) {
+		return response.Err(ErrBadRequest.Errorf("GetPublicDashboardUsage: from must be before to"))
+	}
+
+	interval := UsageIntervalDay
+	if i := c.Query("interval"); i != "" {
+		interval = UsageInterval(i)
+	}
+	if !interval.IsValid() {
+		return response.Err(ErrBadRequest.Errorf("GetPublicDashboardUsage: invalid interval %s", interval))
+	}
+
+	usage, err := api.Usage.FindUsage(c.Req.Context(), UsageQuery{
+		PublicDashboardUid: uid,
+		From:               from,
+		To:                 to,
+		Interval:           interval,
+	})
+	if err != nil {
+		return response.Err(ErrInternalServerError.Errorf("GetPublicDashboardUsage: failed to find usage: %w", err))
+	}
+
+	return response.JSON(http.StatusOK, usage)
+}
+
 // Copied from pkg/api/metrics.go
 func toJsonStreamingResponse(features *featuremgmt.FeatureManager, qdr *backend.QueryDataResponse) response.Response {
 	statusWhenError := http.StatusBadRequest
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -16,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/metric"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -602,3 +604,93 @@ func TestAPIUpdatePublicDashboard(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIGetPublicDashboardUsage(t *testing.T) {
+	dashboardUid := "abc1234"
+	publicDashboardUid := "pubdash1234"
+	userDashboardReader := &user.SignedInUser{UserID: 4, OrgID: 1, OrgRole: org.RoleViewer, Login: "testViewerUser", Permissions: map[int64]map[string][]string{1: {dashboards.ActionDashboardsRead: {fmt.Sprintf("dashboards:uid:%s", dashboardUid)}}}}
+	userAnotherDashboardReader := &user.SignedInUser{UserID: 4, OrgID: 1, OrgRole: org.RoleViewer, Login: "testViewerUser", Permissions: map[int64]map[string][]string{1: {dashboards.ActionDashboardsRead: {"dashboards:uid:another-uid"}}}}
+
+	testCases := []struct {
+		Name                 string
+		User                 *user.SignedInUser
+		Query                string
+		PublicDashboardRes   *PublicDashboard
+		ExpectedHttpResponse int
+		ShouldCallService    bool
+	}{
+		{
+			Name:                 "Returns the usage of the public dashboard",
+			User:                 userDashboardReader,
+			Query:                "?from=0&to=7200000&interval=hour",
+			PublicDashboardRes:   &PublicDashboard{Uid: publicDashboardUid, DashboardUid: dashboardUid, OrgId: 1},
+			ExpectedHttpResponse: http.StatusOK,
+			ShouldCallService:    true,
+		},
+		{
+			Name:                 "Returns not found when the public dashboard belongs to another dashboard",
+			User:                 userDashboardReader,
+			PublicDashboardRes:   &PublicDashboard{Uid: publicDashboardUid, DashboardUid: "another-uid", OrgId: 1},
+			ExpectedHttpResponse: http.StatusNotFound,
+			ShouldCallService:    true,
+		},
+		{
+			Name:                 "Returns not found when the public dashboard does not exist",
+			User:                 userDashboardReader,
+			ExpectedHttpResponse: http.StatusNotFound,
+			ShouldCallService:    true,
+		},
+		{
+			Name:                 "Returns bad request given an invalid interval",
+			User:                 userDashboardReader,
+			Query:                "?interval=week",
+			PublicDashboardRes:   &PublicDashboard{Uid: publicDashboardUid, DashboardUid: dashboardUid, OrgId: 1},
+			ExpectedHttpResponse: http.StatusBadRequest,
+			ShouldCallService:    true,
+		},
+		{
+			Name:                 "User without access to the dashboard cannot get the usage",
+			User:                 userAnotherDashboardReader,
+			ExpectedHttpResponse: http.StatusForbidden,
+			ShouldCallService:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			if test.ShouldCallService {
+				service.On("Find", mock.Anything, publicDashboardUid).Return(test.PublicDashboardRes, nil)
+			}
+
+			store := publicdashboards.NewFakePublicDashboardStore(t)
+			if test.ExpectedHttpResponse == http.StatusOK {
+				store.On("FindUsage", mock.Anything, publicDashboardUid, int64(0), int64(7200)).
+					Return([]*UsageBucket{{Bucket: 3600, Views: 2, Queries: 10}}, nil)
+			}
+			usage, err := metric.ProvideService(store, prometheus.NewRegistry())
+			require.NoError(t, err)
+
+			cfg := setting.NewCfg()
+			features := featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards)
+			testServer := setupTestServerWithUsage(t, cfg, features, service, usage, test.User)
+
+			url := fmt.Sprintf("/api/dashboards/uid/%s/public-dashboards/%s/usage%s", dashboardUid, publicDashboardUid, test.Query)
+			response := callAPI(testServer, http.MethodGet, url, nil, t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+
+			if test.ExpectedHttpResponse == http.StatusOK {
+				var usageResp UsageResponse
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &usageResp))
+				assert.Equal(t, int64(2), usageResp.Views)
+				assert.Equal(t, int64(10), usageResp.Queries)
+				require.Len(t, usageResp.Buckets, 1)
+				assert.Equal(t, int64(3600), usageResp.Buckets[0].Time.Unix())
+			}
+
+			if !test.ShouldCallService {
+				service.AssertNotCalled(t, "Find")
+			}
+		})
+	}
+}
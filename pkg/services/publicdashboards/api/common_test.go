@@ -29,6 +29,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
 	pluginSettings "github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings/service"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/metric"
 	"github.com/grafana/grafana/pkg/services/query"
 	fakeSecrets "github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -43,6 +44,17 @@ func setupTestServer(
 	service publicdashboards.Service,
 	db db.DB,
 	user *user.SignedInUser,
+) *web.Mux {
+	return setupTestServerWithUsage(t, cfg, features, service, nil, user)
+}
+
+func setupTestServerWithUsage(
+	t *testing.T,
+	cfg *setting.Cfg,
+	features *featuremgmt.FeatureManager,
+	service publicdashboards.Service,
+	usage *metric.Service,
+	user *user.SignedInUser,
 ) *web.Mux {
 	// build router to register routes
 	rr := routing.NewRouteRegister()
@@ -71,7 +83,7 @@ func setupTestServer(
 
 	// build api, this will mount the routes at the same time if
 	// featuremgmt.FlagPublicDashboard is enabled
-	ProvideApi(service, rr, ac, features)
+	ProvideApi(service, rr, ac, features, usage, cfg)
 
 	// connect routes to mux
 	rr.Register(m.Router)
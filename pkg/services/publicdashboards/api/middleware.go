@@ -1,7 +1,12 @@
 package api
 
 import (
+	"container/list"
 	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
 
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -67,3 +72,108 @@ func CountPublicDashboardRequest() func(c *contextmodel.ReqContext) {
 		metrics.MPublicDashboardRequestCount.Inc()
 	}
 }
+
+// RateLimitPublicDashboards Middleware to reject the requests of a client IP address or to a public dashboard above the
+// rates of the limiter
+func RateLimitPublicDashboards(limiter *RateLimiter) func(c *contextmodel.ReqContext) {
+	return func(c *contextmodel.ReqContext) {
+		if !limiter.perIP.allow(web.RemoteAddr(c.Req)) {
+			c.JsonApiErr(http.StatusTooManyRequests, "Too many requests", nil)
+			return
+		}
+
+		// invalid access tokens are rejected by the handlers, they are not limited so that they can't fill the limiter
+		accessToken := web.Params(c.Req)[":accessToken"]
+		if validation.IsValidAccessToken(accessToken) && !limiter.perToken.allow(accessToken) {
+			c.JsonApiErr(http.StatusTooManyRequests, "Too many requests", nil)
+			return
+		}
+	}
+}
+
+const (
+	// limiterIdleTimeout is how long the limiter of an access token or an IP address is kept after its last request
+	limiterIdleTimeout = 10 * time.Minute
+	// maxLimiters is the number of limiters kept, the least recently used one is removed to add another one
+	maxLimiters = 10000
+)
+
+// RateLimiter limits the requests to the public dashboards API by access token and by client IP address
+type RateLimiter struct {
+	perToken *keyedLimiter
+	perIP    *keyedLimiter
+}
+
+// NewRateLimiter returns a limiter allowing the given numbers of requests per minute, 0 does not limit the requests.
+// A full minute of requests can be made at once, so that all the panels of a dashboard can be loaded together.
+func NewRateLimiter(perTokenPerMinute, perIPPerMinute int) *RateLimiter {
+	return &RateLimiter{
+		perToken: newKeyedLimiter(perTokenPerMinute),
+		perIP:    newKeyedLimiter(perIPPerMinute),
+	}
+}
+
+type limiterEntry struct {
+	key      string
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// keyedLimiter keeps the limiters in a list from the most to the least recently used, so that the idle and the
+// least recently used limiters are removed from its back without scanning all of them
+type keyedLimiter struct {
+	mu       sync.Mutex
+	limit    rate.Limit
+	burst    int
+	limiters map[string]*list.Element
+	recent   *list.List
+	now      func() time.Time
+}
+
+func newKeyedLimiter(perMinute int) *keyedLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+
+	return &keyedLimiter{
+		limit:    rate.Limit(float64(perMinute) / 60),
+		burst:    perMinute,
+		limiters: make(map[string]*list.Element),
+		recent:   list.New(),
+		now:      time.Now,
+	}
+}
+
+func (l *keyedLimiter) allow(key string) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for oldest := l.recent.Back(); oldest != nil && now.Sub(oldest.Value.(*limiterEntry).lastSeen) > limiterIdleTimeout; oldest = l.recent.Back() {
+		l.remove(oldest)
+	}
+
+	var entry *limiterEntry
+	if elem, ok := l.limiters[key]; ok {
+		entry = elem.Value.(*limiterEntry)
+		l.recent.MoveToFront(elem)
+	} else {
+		if l.recent.Len() >= maxLimiters {
+			l.remove(l.recent.Back())
+		}
+		entry = &limiterEntry{key: key, limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = l.recent.PushFront(entry)
+	}
+	entry.lastSeen = now
+
+	return entry.limiter.AllowN(now, 1)
+}
+
+func (l *keyedLimiter) remove(elem *list.Element) {
+	l.recent.Remove(elem)
+	delete(l.limiters, elem.Value.(*limiterEntry).key)
+}
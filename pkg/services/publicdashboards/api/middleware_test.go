@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"errors"
 
@@ -152,6 +153,108 @@ func TestSetPublicDashboardFlag(t *testing.T) {
 	})
 }
 
+func TestRateLimitPublicDashboards(t *testing.T) {
+	otherAccessToken, _ := service.GenerateAccessToken()
+
+	t.Run("Does not limit requests by default", func(t *testing.T) {
+		mw := RateLimitPublicDashboards(NewRateLimiter(0, 0))
+		for i := 0; i < 100; i++ {
+			_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccessToken", map[string]string{":accessToken": validAccessToken}, mw)
+			require.Equal(t, http.StatusOK, resp.Code)
+		}
+	})
+
+	t.Run("Returns 429 when the requests to a public dashboard are above the limit", func(t *testing.T) {
+		mw := RateLimitPublicDashboards(NewRateLimiter(2, 0))
+		for i := 0; i < 2; i++ {
+			_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccessToken", map[string]string{":accessToken": validAccessToken}, mw)
+			require.Equal(t, http.StatusOK, resp.Code)
+		}
+
+		_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccessToken", map[string]string{":accessToken": validAccessToken}, mw)
+		assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+
+		_, resp = runMw(t, nil, "GET", "/api/public/dashboards/myAccessToken", map[string]string{":accessToken": otherAccessToken}, mw)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Returns 429 when the requests of an IP address are above the limit", func(t *testing.T) {
+		mw := RateLimitPublicDashboards(NewRateLimiter(0, 2))
+		for _, accessToken := range []string{validAccessToken, otherAccessToken} {
+			_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccessToken", map[string]string{":accessToken": accessToken}, mw)
+			require.Equal(t, http.StatusOK, resp.Code)
+		}
+
+		_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccessToken", map[string]string{":accessToken": validAccessToken}, mw)
+		assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	})
+
+	t.Run("Limits the client IP address forwarded by the proxy", func(t *testing.T) {
+		limit := RateLimitPublicDashboards(NewRateLimiter(0, 2))
+		request := func(forwardedFor string) int {
+			mw := func(c *contextmodel.ReqContext) {
+				c.Req.RemoteAddr = "192.0.2.1:1234"
+				c.Req.Header.Set("X-Forwarded-For", forwardedFor)
+				limit(c)
+			}
+			_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccessToken", map[string]string{":accessToken": validAccessToken}, mw)
+			return resp.Code
+		}
+
+		for i := 0; i < 2; i++ {
+			require.Equal(t, http.StatusOK, request("10.0.0.1"))
+		}
+		assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1"))
+		// other clients behind the same proxy have their own limit
+		assert.Equal(t, http.StatusOK, request("10.0.0.2"))
+	})
+
+	t.Run("Does not limit invalid access tokens", func(t *testing.T) {
+		mw := RateLimitPublicDashboards(NewRateLimiter(1, 0))
+		for i := 0; i < 3; i++ {
+			_, resp := runMw(t, nil, "GET", "/api/public/dashboards/myAccessToken", map[string]string{":accessToken": "invalid"}, mw)
+			require.Equal(t, http.StatusOK, resp.Code)
+		}
+	})
+}
+
+func TestKeyedLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := newKeyedLimiter(60)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 60; i++ {
+		require.True(t, limiter.allow("10.0.0.1"))
+	}
+	assert.False(t, limiter.allow("10.0.0.1"))
+	assert.True(t, limiter.allow("10.0.0.2"))
+
+	// one request is allowed again each second
+	now = now.Add(time.Second)
+	assert.True(t, limiter.allow("10.0.0.1"))
+	assert.False(t, limiter.allow("10.0.0.1"))
+
+	// idle limiters are removed
+	now = now.Add(limiterIdleTimeout + time.Second)
+	assert.True(t, limiter.allow("10.0.0.3"))
+	assert.Len(t, limiter.limiters, 1)
+
+	assert.True(t, (*keyedLimiter)(nil).allow("10.0.0.1"))
+
+	// the least recently used limiter is removed when there are too many
+	for i := len(limiter.limiters); i < maxLimiters; i++ {
+		now = now.Add(time.Millisecond)
+		require.True(t, limiter.allow(fmt.Sprintf("key-%d", i)))
+	}
+	require.Len(t, limiter.limiters, maxLimiters)
+	now = now.Add(time.Millisecond)
+	assert.True(t, limiter.allow("10.0.0.4"))
+	assert.Len(t, limiter.limiters, maxLimiters)
+	assert.Equal(t, maxLimiters, limiter.recent.Len())
+	assert.NotContains(t, limiter.limiters, "10.0.0.3")
+	assert.Contains(t, limiter.limiters, "10.0.0.4")
+}
+
 // This is a helper to test middleware. It handles creating a
 // proper contextmodel.ReqContext, setting web parameters, executing middleware, and
 // returning a response. Response will default to result of
@@ -48,6 +48,7 @@ func (api *Api) ViewPublicDashboard(c *contextmodel.ReqContext) response.Respons
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
 	dto := dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}
+	api.Usage.RecordView(accessToken)
 
 	return response.JSON(http.StatusOK, dto)
 }
@@ -74,6 +75,7 @@ func (api *Api) QueryPublicDashboard(c *contextmodel.ReqContext) response.Respon
 	if err != nil {
 		return response.Err(err)
 	}
+	api.Usage.RecordQuery(accessToken)
 
 	return toJsonStreamingResponse(api.Features, resp)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -252,7 +253,8 @@ func (d *PublicDashboardStoreImpl) Create(ctx context.Context, cmd SavePublicDas
 	var affectedRows int64
 	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
 		var err error
-		affectedRows, err = sess.UseBool("is_enabled").Insert(&cmd.PublicDashboard)
+		// the restrictions are stored as NULL when they are not set, so that they are read back as nil
+		affectedRows, err = sess.UseBool("is_enabled").Nullable("exposed_panels", "allowed_variables").Insert(&cmd.PublicDashboard)
 		return err
 	})
 
@@ -268,12 +270,32 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		// the restrictions are stored as NULL when they are not set, so that they are read back as nil
+		var exposedPanelsJSON, allowedVariablesJSON interface{}
+		if cmd.PublicDashboard.ExposedPanels != nil {
+			data, err := json.Marshal(cmd.PublicDashboard.ExposedPanels)
+			if err != nil {
+				return err
+			}
+			exposedPanelsJSON = string(data)
+		}
+
+		if cmd.PublicDashboard.AllowedVariables != nil {
+			data, err := json.Marshal(cmd.PublicDashboard.AllowedVariables)
+			if err != nil {
+				return err
+			}
+			allowedVariablesJSON = string(data)
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, exposed_panels = ?, allowed_variables = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			cmd.PublicDashboard.Share,
 			string(timeSettingsJSON),
+			exposedPanelsJSON,
+			allowedVariablesJSON,
 			cmd.PublicDashboard.UpdatedBy,
 			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
 			cmd.PublicDashboard.Uid)
@@ -297,7 +319,11 @@ func (d *PublicDashboardStoreImpl) Delete(ctx context.Context, uid string) (int6
 	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
 		var err error
 		affectedRows, err = sess.Delete(dashboard)
+		if err != nil {
+			return err
+		}
 
+		_, err = sess.Delete(&UsageBucket{PublicDashboardUid: uid})
 		return err
 	})
 
@@ -334,3 +360,50 @@ func (d *PublicDashboardStoreImpl) GetMetrics(ctx context.Context) (*Metrics, er
 
 	return metrics, nil
 }
+
+// AddUsage adds the views and queries of the buckets to the stored ones. The buckets are upserted in a single
+// statement, so that instances flushing the same bucket concurrently do not conflict.
+func (d *PublicDashboardStoreImpl) AddUsage(ctx context.Context, buckets []*UsageBucket) error {
+	upsertSQL := addUsageSQL(d.sqlStore.GetDialect())
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, b := range buckets {
+			if _, err := sess.Exec(upsertSQL, b.PublicDashboardUid, b.Bucket, b.Views, b.Queries); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func addUsageSQL(dialect migrator.Dialect) string {
+	insert := "INSERT INTO dashboard_public_usage (public_dashboard_uid, bucket, views, queries) VALUES (?, ?, ?, ?)"
+	if dialect.DriverName() == migrator.MySQL {
+		return insert + " ON DUPLICATE KEY UPDATE views = views + VALUES(views), queries = queries + VALUES(queries)"
+	}
+	return insert + " ON CONFLICT (public_dashboard_uid, bucket) DO UPDATE SET views = dashboard_public_usage.views + excluded.views, queries = dashboard_public_usage.queries + excluded.queries"
+}
+
+// FindUsage returns the usage buckets of a public dashboard between from and to, as unix timestamps in seconds
+func (d *PublicDashboardStoreImpl) FindUsage(ctx context.Context, uid string, from, to int64) ([]*UsageBucket, error) {
+	buckets := []*UsageBucket{}
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("public_dashboard_uid = ? AND bucket >= ? AND bucket < ?", uid, from, to).Asc("bucket").Find(&buckets)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// DeleteUsageBefore deletes the usage buckets older than bucket, as a unix timestamp in seconds
+func (d *PublicDashboardStoreImpl) DeleteUsageBefore(ctx context.Context, bucket int64) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Where("bucket < ?", bucket).Delete(&UsageBucket{})
+		return err
+	})
+
+	return affectedRows, err
+}
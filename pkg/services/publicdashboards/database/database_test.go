@@ -3,7 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
-
+	"sync"
 	"testing"
 	"time"
 
@@ -617,6 +617,8 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 			TimeSelectionEnabled: true,
 			Share:                EmailShareType,
 			TimeSettings:         &TimeSettings{From: "now-8", To: "now"},
+			ExposedPanels:        &ExposedPanels{1, 3},
+			AllowedVariables:     &VariableAllowList{"host": {"web-1", "web-2"}},
 			UpdatedAt:            time.Now().UTC().Round(time.Second),
 			UpdatedBy:            8,
 		}
@@ -638,6 +640,8 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.Equal(t, updatedPublicDashboard.AnnotationsEnabled, pdRetrieved.AnnotationsEnabled)
 		assert.Equal(t, updatedPublicDashboard.TimeSelectionEnabled, pdRetrieved.TimeSelectionEnabled)
 		assert.Equal(t, updatedPublicDashboard.Share, pdRetrieved.Share)
+		assert.Equal(t, updatedPublicDashboard.ExposedPanels, pdRetrieved.ExposedPanels)
+		assert.Equal(t, updatedPublicDashboard.AllowedVariables, pdRetrieved.AllowedVariables)
 
 		// not updated dashboard shouldn't have changed
 		pdNotUpdatedRetrieved, err := publicdashboardStore.FindByDashboardUid(context.Background(), anotherSavedDashboard.OrgID, anotherSavedDashboard.UID)
@@ -764,6 +768,104 @@ func TestIntegrationDelete(t *testing.T) {
 	})
 }
 
+func TestIntegrationUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var publicdashboardStore *PublicDashboardStoreImpl
+	var savedPublicDashboard *PublicDashboard
+
+	setup := func() {
+		sqlStore, cfg := db.InitTestDBwithCfg(t)
+		dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+		require.NoError(t, err)
+		publicdashboardStore = ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+		savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true)
+		savedPublicDashboard = insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true, PublicShareType)
+	}
+
+	t.Run("adds the usage to the existing buckets", func(t *testing.T) {
+		setup()
+		uid := savedPublicDashboard.Uid
+
+		err := publicdashboardStore.AddUsage(context.Background(), []*UsageBucket{
+			{PublicDashboardUid: uid, Bucket: 3600, Views: 1, Queries: 2},
+			{PublicDashboardUid: uid, Bucket: 7200, Views: 3, Queries: 4},
+		})
+		require.NoError(t, err)
+		err = publicdashboardStore.AddUsage(context.Background(), []*UsageBucket{{PublicDashboardUid: uid, Bucket: 3600, Views: 1, Queries: 1}})
+		require.NoError(t, err)
+
+		buckets, err := publicdashboardStore.FindUsage(context.Background(), uid, 0, 10800)
+		require.NoError(t, err)
+		require.Len(t, buckets, 2)
+		assert.Equal(t, int64(3600), buckets[0].Bucket)
+		assert.Equal(t, int64(2), buckets[0].Views)
+		assert.Equal(t, int64(3), buckets[0].Queries)
+		assert.Equal(t, int64(3), buckets[1].Views)
+
+		buckets, err = publicdashboardStore.FindUsage(context.Background(), uid, 7200, 10800)
+		require.NoError(t, err)
+		assert.Len(t, buckets, 1)
+	})
+
+	t.Run("adds the usage of concurrent flushes of the same bucket", func(t *testing.T) {
+		setup()
+		uid := savedPublicDashboard.Uid
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- publicdashboardStore.AddUsage(context.Background(), []*UsageBucket{{PublicDashboardUid: uid, Bucket: 3600, Views: 1, Queries: 2}})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		buckets, err := publicdashboardStore.FindUsage(context.Background(), uid, 0, 10800)
+		require.NoError(t, err)
+		require.Len(t, buckets, 1)
+		assert.Equal(t, int64(10), buckets[0].Views)
+		assert.Equal(t, int64(20), buckets[0].Queries)
+	})
+
+	t.Run("deletes the old buckets", func(t *testing.T) {
+		setup()
+		uid := savedPublicDashboard.Uid
+
+		err := publicdashboardStore.AddUsage(context.Background(), []*UsageBucket{
+			{PublicDashboardUid: uid, Bucket: 3600, Views: 1},
+			{PublicDashboardUid: uid, Bucket: 7200, Views: 1},
+		})
+		require.NoError(t, err)
+
+		deleted, err := publicdashboardStore.DeleteUsageBefore(context.Background(), 7200)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+	})
+
+	t.Run("deletes the usage with the public dashboard", func(t *testing.T) {
+		setup()
+		uid := savedPublicDashboard.Uid
+
+		err := publicdashboardStore.AddUsage(context.Background(), []*UsageBucket{{PublicDashboardUid: uid, Bucket: 3600, Views: 1}})
+		require.NoError(t, err)
+
+		_, err = publicdashboardStore.Delete(context.Background(), uid)
+		require.NoError(t, err)
+
+		buckets, err := publicdashboardStore.FindUsage(context.Background(), uid, 0, 10800)
+		require.NoError(t, err)
+		assert.Empty(t, buckets)
+	})
+}
+
 func TestGetDashboardByFolder(t *testing.T) {
 	t.Run("returns nil when dashboard is not a folder", func(t *testing.T) {
 		sqlStore, _ := db.InitTestDBwithCfg(t)
@@ -15,6 +15,7 @@ type Service struct {
 	store   publicdashboards.Store
 	Metrics *Metrics
 	log     log.Logger
+	usage   *usageRecorder
 }
 
 func ProvideService(
@@ -25,6 +26,7 @@ func ProvideService(
 		store:   store,
 		Metrics: newMetrics(),
 		log:     log.New("publicdashboards.metric"),
+		usage:   newUsageRecorder(),
 	}
 
 	if err := s.registerMetrics(prom); err != nil {
@@ -50,12 +52,20 @@ func (s *Service) Run(ctx context.Context) error {
 	s.recordMetrics(ctx)
 
 	ticker := time.NewTicker(12 * time.Hour)
+	usageTicker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+	defer usageTicker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
+			// the usage of the last minute is stored before shutting down
+			s.flushUsage(context.Background())
 			return ctx.Err()
 		case <-ticker.C:
 			s.recordMetrics(ctx)
+			s.deleteOldUsage(ctx)
+		case <-usageTicker.C:
+			s.flushUsage(ctx)
 		}
 	}
 }
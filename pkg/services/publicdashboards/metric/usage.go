@@ -0,0 +1,154 @@
+package metric
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const (
+	// usageFlushInterval is how often the usage counted in memory is added to the store
+	usageFlushInterval = time.Minute
+	// usageRetention is how long the usage buckets are kept
+	usageRetention = 90 * 24 * time.Hour
+)
+
+type usageKey struct {
+	accessToken string
+	bucket      int64
+}
+
+type usageCount struct {
+	views   int64
+	queries int64
+}
+
+// usageRecorder counts the views and queries of public dashboards by access token and hour
+type usageRecorder struct {
+	mu     sync.Mutex
+	counts map[usageKey]*usageCount
+	now    func() time.Time
+}
+
+func newUsageRecorder() *usageRecorder {
+	return &usageRecorder{
+		counts: make(map[usageKey]*usageCount),
+		now:    time.Now,
+	}
+}
+
+func (r *usageRecorder) record(accessToken string, views, queries int64) {
+	key := usageKey{accessToken: accessToken, bucket: r.now().Truncate(time.Hour).Unix()}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count, ok := r.counts[key]
+	if !ok {
+		count = &usageCount{}
+		r.counts[key] = count
+	}
+	count.views += views
+	count.queries += queries
+}
+
+// take returns the counts recorded since the last call
+func (r *usageRecorder) take() map[usageKey]*usageCount {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := r.counts
+	r.counts = make(map[usageKey]*usageCount)
+	return counts
+}
+
+// RecordView counts a view of the public dashboard
+func (s *Service) RecordView(accessToken string) {
+	if s == nil {
+		return
+	}
+	s.usage.record(accessToken, 1, 0)
+}
+
+// RecordQuery counts a query of a panel of the public dashboard
+func (s *Service) RecordQuery(accessToken string) {
+	if s == nil {
+		return
+	}
+	s.usage.record(accessToken, 0, 1)
+}
+
+// FindUsage returns the views and queries of a public dashboard, summed over buckets of the query interval
+func (s *Service) FindUsage(ctx context.Context, query models.UsageQuery) (*models.UsageResponse, error) {
+	buckets, err := s.store.FindUsage(ctx, query.PublicDashboardUid, query.From.Truncate(time.Hour).Unix(), query.To.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &models.UsageResponse{Buckets: []*models.UsageDTO{}}
+	var last *models.UsageDTO
+	for _, b := range buckets {
+		t := time.Unix(b.Bucket, 0).UTC().Truncate(query.Interval.Duration())
+		if last == nil || !last.Time.Equal(t) {
+			last = &models.UsageDTO{Time: t}
+			res.Buckets = append(res.Buckets, last)
+		}
+		last.Views += b.Views
+		last.Queries += b.Queries
+		res.Views += b.Views
+		res.Queries += b.Queries
+	}
+
+	return res, nil
+}
+
+// flushUsage adds the usage counted in memory to the store. The counts are dropped when they can't be stored, so
+// that a failing database doesn't grow the memory usage.
+func (s *Service) flushUsage(ctx context.Context) {
+	counts := s.usage.take()
+	if len(counts) == 0 {
+		return
+	}
+
+	uids := make(map[string]string)
+	buckets := make([]*models.UsageBucket, 0, len(counts))
+	for key, count := range counts {
+		uid, ok := uids[key.accessToken]
+		if !ok {
+			pubdash, err := s.store.FindByAccessToken(ctx, key.accessToken)
+			if err != nil {
+				s.log.Error("error finding public dashboard of usage", "err", err)
+				continue
+			}
+			if pubdash != nil {
+				uid = pubdash.Uid
+			}
+			uids[key.accessToken] = uid
+		}
+		// the public dashboard has been deleted in the meantime
+		if uid == "" {
+			continue
+		}
+
+		buckets = append(buckets, &models.UsageBucket{PublicDashboardUid: uid, Bucket: key.bucket, Views: count.views, Queries: count.queries})
+	}
+
+	if len(buckets) == 0 {
+		return
+	}
+
+	if err := s.store.AddUsage(ctx, buckets); err != nil {
+		s.log.Error("error storing public dashboards usage", "err", err)
+	}
+}
+
+func (s *Service) deleteOldUsage(ctx context.Context) {
+	deleted, err := s.store.DeleteUsageBefore(ctx, s.usage.now().Add(-usageRetention).Unix())
+	if err != nil {
+		s.log.Error("error deleting old public dashboards usage", "err", err)
+		return
+	}
+	s.log.Debug("deleted old public dashboards usage", "count", deleted)
+}
@@ -0,0 +1,111 @@
+package metric
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+func newTestService(t *testing.T, store publicdashboards.Store, now time.Time) *Service {
+	t.Helper()
+	s, err := ProvideService(store, prometheus.NewRegistry())
+	require.NoError(t, err)
+	s.usage.now = func() time.Time { return now }
+	return s
+}
+
+func TestFlushUsage(t *testing.T) {
+	now := time.Date(2023, 3, 1, 10, 30, 0, 0, time.UTC)
+	hour := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC).Unix()
+
+	t.Run("adds the recorded views and queries to the store", func(t *testing.T) {
+		store := publicdashboards.NewFakePublicDashboardStore(t)
+		store.On("FindByAccessToken", mock.Anything, "token").Return(&models.PublicDashboard{Uid: "pubdash"}, nil).Once()
+		store.On("AddUsage", mock.Anything, []*models.UsageBucket{{PublicDashboardUid: "pubdash", Bucket: hour, Views: 1, Queries: 2}}).Return(nil).Once()
+
+		s := newTestService(t, store, now)
+		s.RecordView("token")
+		s.RecordQuery("token")
+		s.RecordQuery("token")
+		s.flushUsage(context.Background())
+
+		// the counts are reset once flushed
+		s.flushUsage(context.Background())
+	})
+
+	t.Run("drops the usage of deleted public dashboards", func(t *testing.T) {
+		store := publicdashboards.NewFakePublicDashboardStore(t)
+		store.On("FindByAccessToken", mock.Anything, "deleted").Return(nil, nil).Once()
+
+		s := newTestService(t, store, now)
+		s.RecordView("deleted")
+		s.flushUsage(context.Background())
+	})
+
+	t.Run("drops the usage when it can't be stored", func(t *testing.T) {
+		store := publicdashboards.NewFakePublicDashboardStore(t)
+		store.On("FindByAccessToken", mock.Anything, "token").Return(&models.PublicDashboard{Uid: "pubdash"}, nil).Once()
+		store.On("AddUsage", mock.Anything, mock.Anything).Return(errors.New("database error")).Once()
+
+		s := newTestService(t, store, now)
+		s.RecordView("token")
+		s.flushUsage(context.Background())
+		assert.Empty(t, s.usage.take())
+	})
+
+	t.Run("ignores recording without a service", func(t *testing.T) {
+		var s *Service
+		s.RecordView("token")
+		s.RecordQuery("token")
+	})
+}
+
+func TestFindUsage(t *testing.T) {
+	day := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	store := publicdashboards.NewFakePublicDashboardStore(t)
+	store.On("FindUsage", mock.Anything, "pubdash", day.Unix(), day.Add(48*time.Hour).Unix()).Return([]*models.UsageBucket{
+		{Bucket: day.Add(time.Hour).Unix(), Views: 1, Queries: 4},
+		{Bucket: day.Add(5 * time.Hour).Unix(), Views: 2, Queries: 8},
+		{Bucket: day.Add(25 * time.Hour).Unix(), Views: 3, Queries: 12},
+	}, nil)
+
+	s := newTestService(t, store, day)
+
+	t.Run("sums the usage by day", func(t *testing.T) {
+		res, err := s.FindUsage(context.Background(), models.UsageQuery{
+			PublicDashboardUid: "pubdash",
+			From:               day,
+			To:                 day.Add(48 * time.Hour),
+			Interval:           models.UsageIntervalDay,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(6), res.Views)
+		assert.Equal(t, int64(24), res.Queries)
+		require.Len(t, res.Buckets, 2)
+		assert.Equal(t, models.UsageDTO{Time: day, Views: 3, Queries: 12}, *res.Buckets[0])
+		assert.Equal(t, models.UsageDTO{Time: day.Add(24 * time.Hour), Views: 3, Queries: 12}, *res.Buckets[1])
+	})
+
+	t.Run("returns the hourly usage", func(t *testing.T) {
+		res, err := s.FindUsage(context.Background(), models.UsageQuery{
+			PublicDashboardUid: "pubdash",
+			From:               day,
+			To:                 day.Add(48 * time.Hour),
+			Interval:           models.UsageIntervalHour,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, res.Buckets, 3)
+		assert.Equal(t, day.Add(5*time.Hour), res.Buckets[1].Time)
+	})
+}
@@ -21,6 +21,9 @@ var (
 	ErrInvalidTimeRange                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
 	ErrInvalidShareType                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidShareType", errutil.WithPublicMessage("Invalid share type"))
 	ErrDashboardIsPublic                   = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
+	ErrInvalidExposedPanels                = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidExposedPanels", errutil.WithPublicMessage("Exposed panels are not panels of the dashboard"))
+	ErrInvalidAllowedVariables             = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidAllowedVariables", errutil.WithPublicMessage("Allowed variables are not template variables of the dashboard"))
+	ErrVariableValueNotAllowed             = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.variableValueNotAllowed", errutil.WithPublicMessage("Template variable value is not allowed"))
 
 	ErrPublicDashboardNotEnabled = errutil.NewBase(errutil.StatusForbidden, "publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
 )
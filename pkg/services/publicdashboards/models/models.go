@@ -53,6 +53,10 @@ type PublicDashboard struct {
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	Share                ShareType     `json:"share" xorm:"share"`
 	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	// ExposedPanels lists the public panels, all panels are public when empty
+	ExposedPanels *ExposedPanels `json:"exposedPanels" xorm:"exposed_panels"`
+	// AllowedVariables lists the template variable values viewers can select
+	AllowedVariables *VariableAllowList `json:"allowedVariables" xorm:"allowed_variables"`
 }
 
 type PublicDashboardDTO struct {
@@ -60,6 +64,9 @@ type PublicDashboardDTO struct {
 	IsEnabled            *bool     `json:"isEnabled"`
 	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
 	Share                ShareType `json:"share"`
+	// nil keeps the current value on update
+	ExposedPanels    *ExposedPanels     `json:"exposedPanels"`
+	AllowedVariables *VariableAllowList `json:"allowedVariables"`
 }
 
 type EmailDTO struct {
@@ -130,6 +137,66 @@ func (ts *TimeSettings) ToDB() ([]byte, error) {
 	return json.Marshal(ts)
 }
 
+// ExposedPanels are the ids of the panels of a public dashboard that are public
+type ExposedPanels []int64
+
+// Exposes returns whether the panel is public, every panel is public when no panel is listed
+func (p *ExposedPanels) Exposes(panelId int64) bool {
+	if p == nil || len(*p) == 0 {
+		return true
+	}
+	for _, id := range *p {
+		if id == panelId {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ExposedPanels) FromDB(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+func (p *ExposedPanels) ToDB() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// VariableAllowList maps the name of a template variable to the values viewers of a public dashboard can select.
+// Variables that are not listed keep the value saved with the dashboard.
+type VariableAllowList map[string][]string
+
+// Allows returns whether all the values are allowed for the variable
+func (v *VariableAllowList) Allows(name string, values []string) bool {
+	if v == nil {
+		return false
+	}
+	allowed, ok := (*v)[name]
+	if !ok {
+		return false
+	}
+	for _, value := range values {
+		found := false
+		for _, a := range allowed {
+			if a == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *VariableAllowList) FromDB(data []byte) error {
+	return json.Unmarshal(data, v)
+}
+
+func (v *VariableAllowList) ToDB() ([]byte, error) {
+	return json.Marshal(v)
+}
+
 // DTO for transforming user input in the api
 type SavePublicDashboardDTO struct {
 	Uid             string
@@ -150,6 +217,8 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// Variables are the template variable values selected by the viewer, they must be allow-listed
+	Variables map[string][]string
 }
 
 type AnnotationsQueryDTO struct {
@@ -9,3 +9,23 @@ import (
 func TestPublicDashboardTableName(t *testing.T) {
 	assert.Equal(t, "dashboard_public", PublicDashboard{}.TableName())
 }
+
+func TestExposedPanels(t *testing.T) {
+	var none *ExposedPanels
+	assert.True(t, none.Exposes(1))
+	assert.True(t, (&ExposedPanels{}).Exposes(1))
+	assert.True(t, (&ExposedPanels{1, 2}).Exposes(2))
+	assert.False(t, (&ExposedPanels{1, 2}).Exposes(3))
+}
+
+func TestVariableAllowList(t *testing.T) {
+	allowList := &VariableAllowList{"host": {"web-1", "web-2"}}
+
+	assert.True(t, allowList.Allows("host", []string{"web-1"}))
+	assert.True(t, allowList.Allows("host", []string{"web-1", "web-2"}))
+	assert.False(t, allowList.Allows("host", []string{"web-1", "db-1"}))
+	assert.False(t, allowList.Allows("env", []string{"prod"}))
+
+	var none *VariableAllowList
+	assert.False(t, none.Allows("host", []string{"web-1"}))
+}
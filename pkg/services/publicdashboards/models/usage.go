@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// UsageBucket counts the views and queries of a public dashboard during an hour
+type UsageBucket struct {
+	Id                 int64  `json:"-" xorm:"pk autoincr 'id'"`
+	PublicDashboardUid string `json:"-" xorm:"public_dashboard_uid"`
+	// Bucket is the start of the hour as a unix timestamp in seconds
+	Bucket  int64 `json:"-" xorm:"bucket"`
+	Views   int64 `json:"views" xorm:"views"`
+	Queries int64 `json:"queries" xorm:"queries"`
+}
+
+func (b UsageBucket) TableName() string {
+	return "dashboard_public_usage"
+}
+
+// UsageInterval is the size of the buckets returned by the usage API
+type UsageInterval string
+
+const (
+	UsageIntervalHour UsageInterval = "hour"
+	UsageIntervalDay  UsageInterval = "day"
+)
+
+func (i UsageInterval) IsValid() bool {
+	return i == UsageIntervalHour || i == UsageIntervalDay
+}
+
+func (i UsageInterval) Duration() time.Duration {
+	if i == UsageIntervalHour {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+type UsageQuery struct {
+	PublicDashboardUid string
+	From               time.Time
+	To                 time.Time
+	Interval           UsageInterval
+}
+
+type UsageDTO struct {
+	Time    time.Time `json:"time"`
+	Views   int64     `json:"views"`
+	Queries int64     `json:"queries"`
+}
+
+type UsageResponse struct {
+	Views   int64       `json:"views"`
+	Queries int64       `json:"queries"`
+	Buckets []*UsageDTO `json:"buckets"`
+}
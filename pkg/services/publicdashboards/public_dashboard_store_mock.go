@@ -16,6 +16,20 @@ type FakePublicDashboardStore struct {
 	mock.Mock
 }
 
+// AddUsage provides a mock function with given fields: ctx, buckets
+func (_m *FakePublicDashboardStore) AddUsage(ctx context.Context, buckets []*models.UsageBucket) error {
+	ret := _m.Called(ctx, buckets)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.UsageBucket) error); ok {
+		r0 = rf(ctx, buckets)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, cmd
 func (_m *FakePublicDashboardStore) Create(ctx context.Context, cmd models.SavePublicDashboardCommand) (int64, error) {
 	ret := _m.Called(ctx, cmd)
@@ -58,6 +72,27 @@ func (_m *FakePublicDashboardStore) Delete(ctx context.Context, uid string) (int
 	return r0, r1
 }
 
+// DeleteUsageBefore provides a mock function with given fields: ctx, bucket
+func (_m *FakePublicDashboardStore) DeleteUsageBefore(ctx context.Context, bucket int64) (int64, error) {
+	ret := _m.Called(ctx, bucket)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, bucket)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, bucket)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsEnabledByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -238,6 +273,29 @@ func (_m *FakePublicDashboardStore) FindDashboard(ctx context.Context, orgId int
 	return r0, r1
 }
 
+// FindUsage provides a mock function with given fields: ctx, uid, from, to
+func (_m *FakePublicDashboardStore) FindUsage(ctx context.Context, uid string, from int64, to int64) ([]*models.UsageBucket, error) {
+	ret := _m.Called(ctx, uid, from, to)
+
+	var r0 []*models.UsageBucket
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) []*models.UsageBucket); ok {
+		r0 = rf(ctx, uid, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.UsageBucket)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int64) error); ok {
+		r1 = rf(ctx, uid, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMetrics provides a mock function with given fields: ctx
 func (_m *FakePublicDashboardStore) GetMetrics(ctx context.Context) (*models.Metrics, error) {
 	ret := _m.Called(ctx)
@@ -62,4 +62,8 @@ type Store interface {
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
 	GetMetrics(ctx context.Context) (*Metrics, error)
+
+	AddUsage(ctx context.Context, buckets []*UsageBucket) error
+	FindUsage(ctx context.Context, uid string, from, to int64) ([]*UsageBucket, error)
+	DeleteUsageBefore(ctx context.Context, bucket int64) (int64, error)
 }
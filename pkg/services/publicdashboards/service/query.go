@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -35,6 +37,11 @@ func (pd *PublicDashboardServiceImpl) FindAnnotations(ctx context.Context, reqDT
 		return nil, models.ErrInternalServerError.Errorf("FindAnnotations: failed to unmarshal dashboard annotations: %w", err)
 	}
 
+	// the time range is capped like the panel queries
+	if maxTimeRange := pd.publicDashboardsSettings().MaxTimeRange.Milliseconds(); maxTimeRange > 0 && reqDTO.To-reqDTO.From > maxTimeRange {
+		reqDTO.From = reqDTO.To - maxTimeRange
+	}
+
 	anonymousUser := buildAnonymousUser(ctx, dash)
 
 	uniqueEvents := make(map[int64]models.AnnotationEvent, 0)
@@ -85,6 +92,11 @@ func (pd *PublicDashboardServiceImpl) FindAnnotations(ctx context.Context, reqDT
 				event.PanelId = item.PanelID
 			}
 
+			// annotations of panels that are not public are skipped
+			if event.PanelId != 0 && !pub.ExposedPanels.Exposes(event.PanelId) {
+				continue
+			}
+
 			// We want events from tag queries to overwrite existing events
 			_, has := uniqueEvents[event.Id]
 			if !has || (has && anno.Target != nil && anno.Target.Type == "tags") {
@@ -137,6 +149,13 @@ func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context,
 		return nil, models.ErrPanelQueriesNotFound.Errorf("GetQueryDataResponse: failed to extract queries from panel")
 	}
 
+	cacheKey := queryCacheKey(accessToken, panelId, dashboard, publicDashboard, queryDto, metricReq, time.Now())
+	if pd.queryCache != nil && cacheKey != "" {
+		if cached, ok := pd.queryCache.Get(cacheKey); ok {
+			return cached.(*backend.QueryDataResponse), nil
+		}
+	}
+
 	anonymousUser := buildAnonymousUser(ctx, dashboard)
 	res, err := pd.QueryDataService.QueryData(ctx, anonymousUser, skipDSCache, metricReq)
 
@@ -148,10 +167,34 @@ func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context,
 	LogQuerySuccess(reqDatasources, pd.log)
 
 	sanitizeMetadataFromQueryData(res)
+	truncateQueryData(res, pd.publicDashboardsSettings().MaxRows)
+
+	if pd.queryCache != nil && cacheKey != "" {
+		pd.queryCache.SetDefault(cacheKey, res)
+	}
 
 	return res, nil
 }
 
+// queryCacheKey identifies the results of a panel for the time range and variables of the request, so that viewers
+// looking at the same dashboard share them. Relative time ranges are resolved to the current time on every request,
+// so the key uses the range as requested and the current interval of the queries instead of the resolved range.
+func queryCacheKey(accessToken string, panelId int64, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, queryDto models.PublicDashboardQueryDTO, metricReq dtos.MetricRequest, now time.Time) string {
+	queries, err := json.Marshal(metricReq.Queries)
+	if err != nil || len(metricReq.Queries) == 0 {
+		return ""
+	}
+
+	from, to, timezone := getTimeRangeValuesOrDefault(queryDto, dashboard, publicDashboard.TimeSelectionEnabled)
+	intervalMs := metricReq.Queries[0].Get("intervalMs").MustInt64()
+	if intervalMs <= 0 {
+		return ""
+	}
+	bucket := now.UnixMilli() / intervalMs
+
+	return fmt.Sprintf("%s:%d:%s:%s:%s:%d:%s", accessToken, panelId, from, to, timezone, bucket, queries)
+}
+
 // buildMetricRequest merges public dashboard parameters with dashboard and returns a metrics request to be sent to query backend
 func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
 	// group queries by panel
@@ -161,7 +204,9 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.D
 		return dtos.MetricRequest{}, models.ErrPanelNotFound.Errorf("buildMetricRequest: public dashboard panel not found")
 	}
 
-	ts := buildTimeSettings(dashboard, reqDTO, publicDashboard)
+	ts := capTimeSettings(buildTimeSettings(dashboard, reqDTO, publicDashboard), pd.publicDashboardsSettings().MaxTimeRange)
+
+	interpolateVariables(queries, dashboard.Data, publicDashboard.AllowedVariables, reqDTO.Variables)
 
 	// determine safe resolution to query data at
 	safeInterval, safeResolution := pd.getSafeIntervalAndMaxDataPoints(reqDTO, ts)
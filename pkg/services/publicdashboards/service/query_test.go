@@ -9,8 +9,10 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	dashboard2 "github.com/grafana/grafana/pkg/kinds/dashboard"
 	"github.com/grafana/grafana/pkg/services/annotations"
@@ -715,6 +717,69 @@ func TestGetQueryDataResponse(t *testing.T) {
 		resp, _ := service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdashDto.AccessToken)
 		require.NotNil(t, resp)
 	})
+
+	t.Run("Returns cached query data to the next viewers", func(t *testing.T) {
+		cachingQueryService := &query.FakeQueryService{}
+		cachingQueryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{}, nil).Once()
+		cachingService := &PublicDashboardServiceImpl{
+			log:                log.New("test.logger"),
+			store:              publicdashboardStore,
+			intervalCalculator: intervalv2.NewCalculator(),
+			QueryDataService:   cachingQueryService,
+			serviceWrapper:     serviceWrapper,
+			queryCache:         localcache.New(time.Minute, time.Minute),
+		}
+
+		customPanels := []interface{}{
+			map[string]interface{}{
+				"id":         1,
+				"datasource": map[string]interface{}{"uid": "ds1"},
+				"targets":    []interface{}{map[string]interface{}{"datasource": map[string]interface{}{"type": "mysql", "uid": "ds1"}, "refId": "A"}},
+			}}
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashWithCachedQuery", 1, 0, true, []map[string]interface{}{}, customPanels)
+		isEnabled := true
+		pubdashDto, err := cachingService.Create(context.Background(), SignedInUser, &SavePublicDashboardDTO{
+			DashboardUid:    dashboard.UID,
+			UserId:          7,
+			OrgID:           dashboard.OrgID,
+			PublicDashboard: &PublicDashboardDTO{IsEnabled: &isEnabled},
+		})
+		require.NoError(t, err)
+
+		first, err := cachingService.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdashDto.AccessToken)
+		require.NoError(t, err)
+		second, err := cachingService.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdashDto.AccessToken)
+		require.NoError(t, err)
+
+		assert.Same(t, first, second)
+		cachingQueryService.AssertNumberOfCalls(t, "QueryData", 1)
+	})
+}
+
+func TestQueryCacheKey(t *testing.T) {
+	dashboard := &dashboards.Dashboard{Data: simplejson.NewFromAny(map[string]interface{}{
+		"time": map[string]interface{}{"from": "now-6h", "to": "now"},
+	})}
+	publicDashboard := &PublicDashboard{}
+	metricReq := func(from, to string) dtos.MetricRequest {
+		return dtos.MetricRequest{From: from, To: to, Queries: []*simplejson.Json{
+			simplejson.NewFromAny(map[string]interface{}{"refId": "A", "intervalMs": int64(60000)}),
+		}}
+	}
+	now := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	key := queryCacheKey("token", 1, dashboard, publicDashboard, PublicDashboardQueryDTO{}, metricReq("1", "2"), now)
+	require.NotEmpty(t, key)
+
+	// the relative range resolves to another range a second later, the key is the same within the interval
+	assert.Equal(t, key, queryCacheKey("token", 1, dashboard, publicDashboard, PublicDashboardQueryDTO{}, metricReq("1001", "1002"), now.Add(time.Second)))
+	assert.NotEqual(t, key, queryCacheKey("token", 1, dashboard, publicDashboard, PublicDashboardQueryDTO{}, metricReq("60001", "60002"), now.Add(time.Minute)))
+	assert.NotEqual(t, key, queryCacheKey("token", 2, dashboard, publicDashboard, PublicDashboardQueryDTO{}, metricReq("1", "2"), now))
+
+	// the range selected by the viewer is part of the key
+	publicDashboard.TimeSelectionEnabled = true
+	selected := PublicDashboardQueryDTO{TimeRange: TimeRangeDTO{From: "now-1h", To: "now"}}
+	assert.NotEqual(t, key, queryCacheKey("token", 1, dashboard, publicDashboard, selected, metricReq("1", "2"), now))
 }
 
 func TestFindAnnotations(t *testing.T) {
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// hideVariable is the dashboard value hiding a template variable and its label
+const hideVariable = 2
+
+// restrictDashboard removes the panels that are not exposed from the dashboard and limits the template variable
+// options to the allowed values, so that viewers can't see or query anything else
+func restrictDashboard(pubdash *PublicDashboard, dash *dashboards.Dashboard) {
+	if pubdash.ExposedPanels != nil && len(*pubdash.ExposedPanels) > 0 {
+		dash.Data.Set("panels", filterExposedPanels(dash.Data.Get("panels").MustArray(), pubdash.ExposedPanels))
+	}
+
+	if pubdash.AllowedVariables == nil || len(*pubdash.AllowedVariables) == 0 {
+		return
+	}
+
+	for _, variableObj := range dash.Data.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		allowed, ok := (*pubdash.AllowedVariables)[variable.Get("name").MustString()]
+		if !ok {
+			// the saved value is used and can't be changed
+			variable.Set("hide", hideVariable)
+			variable.Set("options", []interface{}{})
+			continue
+		}
+
+		current := getVariableValues(variable)
+		if !pubdash.AllowedVariables.Allows(variable.Get("name").MustString(), current) && len(allowed) > 0 {
+			current = allowed[:1]
+			variable.Set("current", map[string]interface{}{"text": allowed[0], "value": allowed[0]})
+		}
+
+		options := make([]interface{}, 0, len(allowed))
+		for _, value := range allowed {
+			options = append(options, map[string]interface{}{
+				"text":     value,
+				"value":    value,
+				"selected": containsString(current, value),
+			})
+		}
+		variable.Set("options", options)
+	}
+}
+
+func filterExposedPanels(panels []interface{}, exposed *ExposedPanels) []interface{} {
+	result := make([]interface{}, 0, len(panels))
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("type").MustString() == "row" {
+			// expanded rows are kept as headers, collapsed rows only when they contain an exposed panel
+			nested := filterExposedPanels(panel.Get("panels").MustArray(), exposed)
+			if panel.Get("collapsed").MustBool() && len(nested) == 0 {
+				continue
+			}
+			panel.Set("panels", nested)
+			result = append(result, panelObj)
+			continue
+		}
+
+		if exposed.Exposes(panel.Get("id").MustInt64()) {
+			result = append(result, panelObj)
+		}
+	}
+	return result
+}
+
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+)\]\]|\$\{(\w+)(?::(\w+))?\}`)
+
+// interpolateVariables replaces the template variables of the queries with the values selected by the viewer
+// or with the values saved with the dashboard. The queries are only interpolated when viewers can select values,
+// multiple values are formatted like the frontend does for the data source of the query.
+func interpolateVariables(queries []*simplejson.Json, dashboardData *simplejson.Json, allowed *VariableAllowList, selected map[string][]string) {
+	if allowed == nil || len(*allowed) == 0 {
+		return
+	}
+
+	values := make(map[string][]string)
+	for _, variableObj := range dashboardData.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+
+		current := getVariableValues(variable)
+		if s, ok := selected[name]; ok {
+			current = s
+		}
+		// the value of the All option depends on the data source, the query is left as is
+		if len(current) == 0 || containsString(current, "$__all") {
+			continue
+		}
+		values[name] = current
+	}
+
+	if len(values) == 0 {
+		return
+	}
+
+	for _, query := range queries {
+		format := defaultVariableFormat(query.GetPath("datasource", "type").MustString())
+		for key, value := range query.MustMap() {
+			if key == "datasource" {
+				continue
+			}
+			query.Set(key, interpolateValue(value, values, format))
+		}
+	}
+}
+
+func interpolateValue(value interface{}, values map[string][]string, format string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return variableRegex.ReplaceAllStringFunc(v, func(match string) string {
+			groups := variableRegex.FindStringSubmatch(match)
+			for _, name := range groups[1:4] {
+				if name == "" {
+					continue
+				}
+				replacement, ok := values[name]
+				if !ok {
+					return match
+				}
+				// a format in the query like ${var:csv} takes precedence, single values are used as is otherwise
+				if explicit := groups[4]; explicit != "" {
+					return formatVariableValues(replacement, explicit)
+				}
+				if len(replacement) == 1 {
+					return replacement[0]
+				}
+				return formatVariableValues(replacement, format)
+			}
+			return match
+		})
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = interpolateValue(nested, values, format)
+		}
+		return v
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = interpolateValue(nested, values, format)
+		}
+		return v
+	}
+	return value
+}
+
+// defaultVariableFormat returns the format of multiple values of the data source type, glob like the frontend when
+// the data source does not format them itself
+func defaultVariableFormat(dsType string) string {
+	switch dsType {
+	case "prometheus", "loki", "influxdb":
+		return "regex"
+	case "mysql", "postgres", "grafana-postgresql-datasource", "mssql":
+		return "sqlstring"
+	case "elasticsearch":
+		return "lucene"
+	default:
+		return "glob"
+	}
+}
+
+// formatVariableValues formats the values with one of the variable formats of the frontend
+func formatVariableValues(values []string, format string) string {
+	formatted := make([]string, 0, len(values))
+	switch format {
+	case "regex":
+		for _, value := range values {
+			formatted = append(formatted, regexp.QuoteMeta(value))
+		}
+		if len(formatted) == 1 {
+			return formatted[0]
+		}
+		return "(" + strings.Join(formatted, "|") + ")"
+	case "sqlstring", "singlequote":
+		for _, value := range values {
+			formatted = append(formatted, "'"+strings.ReplaceAll(value, "'", "''")+"'")
+		}
+		return strings.Join(formatted, ",")
+	case "doublequote":
+		for _, value := range values {
+			formatted = append(formatted, strconv.Quote(value))
+		}
+		return strings.Join(formatted, ",")
+	case "lucene":
+		for _, value := range values {
+			formatted = append(formatted, strconv.Quote(value))
+		}
+		if len(formatted) == 1 {
+			return formatted[0]
+		}
+		return "(" + strings.Join(formatted, " OR ") + ")"
+	case "csv", "raw":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	default:
+		if len(values) == 1 {
+			return values[0]
+		}
+		return fmt.Sprintf("{%s}", strings.Join(values, ","))
+	}
+}
+
+func getVariableValues(variable *simplejson.Json) []string {
+	value := variable.GetPath("current", "value")
+	if s, err := value.String(); err == nil {
+		return []string{s}
+	}
+	return value.MustStringArray()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// capTimeSettings shortens the time range to maxTimeRange, keeping its end
+func capTimeSettings(ts TimeSettings, maxTimeRange time.Duration) TimeSettings {
+	if maxTimeRange <= 0 {
+		return ts
+	}
+
+	from, errFrom := strconv.ParseInt(ts.From, 10, 64)
+	to, errTo := strconv.ParseInt(ts.To, 10, 64)
+	if errFrom != nil || errTo != nil {
+		return ts
+	}
+
+	if to-from > maxTimeRange.Milliseconds() {
+		ts.From = strconv.FormatInt(to-maxTimeRange.Milliseconds(), 10)
+	}
+	return ts
+}
+
+// truncateQueryData drops the rows of the data frames after maxRows
+func truncateQueryData(res *backend.QueryDataResponse, maxRows int) {
+	if maxRows <= 0 {
+		return
+	}
+
+	for k := range res.Responses {
+		for _, frame := range res.Responses[k].Frames {
+			rows := frame.Rows()
+			if rows <= maxRows {
+				continue
+			}
+			for i := rows - 1; i >= maxRows; i-- {
+				frame.DeleteRow(i)
+			}
+			frame.AppendNotices(data.Notice{
+				Severity: data.NoticeSeverityWarning,
+				Text:     fmt.Sprintf("Results have been limited to %d rows", maxRows),
+			})
+		}
+	}
+}
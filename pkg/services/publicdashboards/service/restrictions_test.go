@@ -0,0 +1,175 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const restrictedDashboardJSON = `{
+	"panels": [
+		{"id": 1, "type": "timeseries", "targets": [{"refId": "A", "expr": "up{host=\"$host\", env=\"${env}\"}"}]},
+		{"id": 2, "type": "stat", "targets": [{"refId": "A", "expr": "secret"}]},
+		{"id": 3, "type": "row", "collapsed": true, "panels": [
+			{"id": 4, "type": "stat", "targets": [{"refId": "A", "expr": "hidden"}]}
+		]},
+		{"id": 5, "type": "row", "collapsed": true, "panels": [
+			{"id": 6, "type": "stat", "targets": [{"refId": "A", "expr": "[[host]]"}]}
+		]}
+	],
+	"templating": {
+		"list": [
+			{"name": "host", "current": {"text": "db-1", "value": "db-1"}, "options": [{"text": "db-1", "value": "db-1"}]},
+			{"name": "env", "current": {"text": "prod", "value": ["prod", "dev"]}, "options": [{"text": "prod", "value": "prod"}]}
+		]
+	}
+}`
+
+func newRestrictedDashboard(t *testing.T) *dashboards.Dashboard {
+	t.Helper()
+	data, err := simplejson.NewJson([]byte(restrictedDashboardJSON))
+	require.NoError(t, err)
+	return &dashboards.Dashboard{UID: "restricted", Data: data}
+}
+
+func TestRestrictDashboard(t *testing.T) {
+	t.Run("keeps the dashboard when nothing is restricted", func(t *testing.T) {
+		dash := newRestrictedDashboard(t)
+		restrictDashboard(&PublicDashboard{ExposedPanels: &ExposedPanels{}, AllowedVariables: &VariableAllowList{}}, dash)
+
+		assert.Len(t, dash.Data.Get("panels").MustArray(), 4)
+		assert.Len(t, groupQueriesByPanelId(dash.Data), 4)
+		assert.Equal(t, 0, dash.Data.GetPath("templating", "list").GetIndex(1).Get("hide").MustInt())
+	})
+
+	t.Run("removes the panels that are not exposed", func(t *testing.T) {
+		dash := newRestrictedDashboard(t)
+		restrictDashboard(&PublicDashboard{ExposedPanels: &ExposedPanels{1, 6}}, dash)
+
+		queries := groupQueriesByPanelId(dash.Data)
+		assert.Len(t, queries, 2)
+		assert.Contains(t, queries, int64(1))
+		assert.Contains(t, queries, int64(6))
+
+		// the empty collapsed row is removed
+		panels := dash.Data.Get("panels").MustArray()
+		require.Len(t, panels, 2)
+		assert.Equal(t, int64(5), simplejson.NewFromAny(panels[1]).Get("id").MustInt64())
+	})
+
+	t.Run("limits the variable options to the allowed values", func(t *testing.T) {
+		dash := newRestrictedDashboard(t)
+		restrictDashboard(&PublicDashboard{AllowedVariables: &VariableAllowList{"host": {"web-1", "web-2"}}}, dash)
+
+		host := dash.Data.GetPath("templating", "list").GetIndex(0)
+		assert.Equal(t, "web-1", host.GetPath("current", "value").MustString())
+		assert.Len(t, host.Get("options").MustArray(), 2)
+
+		env := dash.Data.GetPath("templating", "list").GetIndex(1)
+		assert.Equal(t, hideVariable, env.Get("hide").MustInt())
+		assert.Empty(t, env.Get("options").MustArray())
+	})
+}
+
+func TestInterpolateVariables(t *testing.T) {
+	allowed := &VariableAllowList{"host": {"db-1", "web-2"}}
+
+	t.Run("leaves the queries when no variable is allowed", func(t *testing.T) {
+		dash := newRestrictedDashboard(t)
+		queries := groupQueriesByPanelId(dash.Data)
+
+		interpolateVariables(queries[1], dash.Data, nil, nil)
+		interpolateVariables(queries[6], dash.Data, &VariableAllowList{}, nil)
+
+		assert.Equal(t, `up{host="$host", env="${env}"}`, queries[1][0].Get("expr").MustString())
+		assert.Equal(t, "[[host]]", queries[6][0].Get("expr").MustString())
+	})
+
+	t.Run("uses the values saved with the dashboard", func(t *testing.T) {
+		dash := newRestrictedDashboard(t)
+		queries := groupQueriesByPanelId(dash.Data)
+
+		interpolateVariables(queries[1], dash.Data, allowed, nil)
+		interpolateVariables(queries[6], dash.Data, allowed, nil)
+
+		assert.Equal(t, `up{host="db-1", env="{prod,dev}"}`, queries[1][0].Get("expr").MustString())
+		assert.Equal(t, "db-1", queries[6][0].Get("expr").MustString())
+	})
+
+	t.Run("uses the values selected by the viewer", func(t *testing.T) {
+		dash := newRestrictedDashboard(t)
+		queries := groupQueriesByPanelId(dash.Data)
+
+		interpolateVariables(queries[1], dash.Data, allowed, map[string][]string{"host": {"web-2"}})
+
+		assert.Equal(t, `up{host="web-2", env="{prod,dev}"}`, queries[1][0].Get("expr").MustString())
+	})
+
+	t.Run("formats multiple values for the data source", func(t *testing.T) {
+		for dsType, expected := range map[string]string{
+			"prometheus":    `up{host="db-1", env="(prod|dev)"}`,
+			"mysql":         `up{host="db-1", env="'prod','dev'"}`,
+			"elasticsearch": `up{host="db-1", env="("prod" OR "dev")"}`,
+			"graphite":      `up{host="db-1", env="{prod,dev}"}`,
+		} {
+			dash := newRestrictedDashboard(t)
+			queries := groupQueriesByPanelId(dash.Data)
+			queries[1][0].Set("datasource", map[string]interface{}{"type": dsType, "uid": "ds"})
+
+			interpolateVariables(queries[1], dash.Data, allowed, nil)
+
+			assert.Equal(t, expected, queries[1][0].Get("expr").MustString(), dsType)
+		}
+	})
+
+	t.Run("uses the format of the query", func(t *testing.T) {
+		dash := newRestrictedDashboard(t)
+		queries := groupQueriesByPanelId(dash.Data)
+		queries[1][0].Set("datasource", map[string]interface{}{"type": "prometheus", "uid": "ds"})
+		queries[1][0].Set("expr", "${env:csv} ${host:singlequote}")
+
+		interpolateVariables(queries[1], dash.Data, allowed, nil)
+
+		assert.Equal(t, "prod,dev 'db-1'", queries[1][0].Get("expr").MustString())
+	})
+
+	t.Run("leaves unknown variables and the all option", func(t *testing.T) {
+		dash := newRestrictedDashboard(t)
+		queries := groupQueriesByPanelId(dash.Data)
+		queries[1][0].Set("expr", "rate(up[$__interval]) $unknown")
+
+		interpolateVariables(queries[1], dash.Data, allowed, map[string][]string{"host": {"$__all"}})
+
+		assert.Equal(t, "rate(up[$__interval]) $unknown", queries[1][0].Get("expr").MustString())
+	})
+}
+
+func TestCapTimeSettings(t *testing.T) {
+	ts := TimeSettings{From: "0", To: "172800000"}
+
+	assert.Equal(t, ts, capTimeSettings(ts, 0))
+	assert.Equal(t, ts, capTimeSettings(ts, 72*time.Hour))
+	assert.Equal(t, TimeSettings{From: "86400000", To: "172800000"}, capTimeSettings(ts, 24*time.Hour))
+}
+
+func TestTruncateQueryData(t *testing.T) {
+	frame := data.NewFrame("test", data.NewField("value", nil, []int64{1, 2, 3, 4, 5}))
+	res := &backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: data.Frames{frame}}}}
+
+	truncateQueryData(res, 0)
+	assert.Equal(t, 5, frame.Rows())
+
+	truncateQueryData(res, 3)
+	assert.Equal(t, 3, frame.Rows())
+	assert.Equal(t, int64(3), frame.At(0, 2))
+	require.Len(t, frame.Meta.Notices, 1)
+	assert.Equal(t, data.NoticeSeverityWarning, frame.Meta.Notices[0].Severity)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
@@ -34,6 +35,8 @@ type PublicDashboardServiceImpl struct {
 	AnnotationsRepo    annotations.Repository
 	ac                 accesscontrol.AccessControl
 	serviceWrapper     publicdashboards.ServiceWrapper
+	// queryCache holds the query results shared between viewers, nil when the cache is disabled
+	queryCache *localcache.CacheService
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -52,7 +55,7 @@ func ProvideService(
 	ac accesscontrol.AccessControl,
 	serviceWrapper publicdashboards.ServiceWrapper,
 ) *PublicDashboardServiceImpl {
-	return &PublicDashboardServiceImpl{
+	pd := &PublicDashboardServiceImpl{
 		log:                log.New(LogPrefix),
 		cfg:                cfg,
 		store:              store,
@@ -62,6 +65,12 @@ func ProvideService(
 		ac:                 ac,
 		serviceWrapper:     serviceWrapper,
 	}
+
+	if ttl := pd.publicDashboardsSettings().QueryCacheTTL; ttl > 0 {
+		pd.queryCache = localcache.New(ttl, 2*ttl)
+	}
+
+	return pd
 }
 
 // FindByDashboardUid this method would be replaced by another implementation for Enterprise version
@@ -116,6 +125,9 @@ func (pd *PublicDashboardServiceImpl) FindEnabledPublicDashboardAndDashboardByAc
 		return nil, nil, ErrPublicDashboardNotEnabled.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard is not enabled accessToken: %s", accessToken)
 	}
 
+	// viewers only get the exposed panels and the allowed variable values
+	restrictDashboard(pubdash, dash)
+
 	return pubdash, dash, err
 }
 
@@ -147,7 +159,12 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 	}
 
 	// ensure dashboard exists
-	_, err = pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	dashboard, err := pd.FindDashboard(ctx, u.OrgID, dto.DashboardUid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validation.ValidateExposedPanelsAndVariables(dto, dashboard)
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +224,11 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 		return nil, ErrDashboardNotFound.Errorf("Update: dashboard not found by orgId: %d and dashboardUid: %s", u.OrgID, dto.DashboardUid)
 	}
 
+	err = validation.ValidateExposedPanelsAndVariables(dto, dashboard)
+	if err != nil {
+		return nil, err
+	}
+
 	// get existing public dashboard if exists
 	existingPubdash, err := pd.store.Find(ctx, dto.Uid)
 	if err != nil {
@@ -418,6 +440,16 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		share = PublicShareType
 	}
 
+	exposedPanels := dto.PublicDashboard.ExposedPanels
+	if exposedPanels == nil {
+		exposedPanels = &ExposedPanels{}
+	}
+
+	allowedVariables := dto.PublicDashboard.AllowedVariables
+	if allowedVariables == nil {
+		allowedVariables = &VariableAllowList{}
+	}
+
 	now := time.Now()
 
 	return &PublicDashboard{
@@ -429,6 +461,8 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         &TimeSettings{},
 		Share:                share,
+		ExposedPanels:        exposedPanels,
+		AllowedVariables:     allowedVariables,
 		CreatedBy:            dto.UserId,
 		CreatedAt:            now,
 		UpdatedBy:            dto.UserId,
@@ -448,6 +482,16 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		share = pd.Share
 	}
 
+	exposedPanels := pubdashDTO.ExposedPanels
+	if exposedPanels == nil {
+		exposedPanels = pd.ExposedPanels
+	}
+
+	allowedVariables := pubdashDTO.AllowedVariables
+	if allowedVariables == nil {
+		allowedVariables = pd.AllowedVariables
+	}
+
 	return &PublicDashboard{
 		Uid:                  pd.Uid,
 		IsEnabled:            isEnabled,
@@ -455,11 +499,21 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         pd.TimeSettings,
 		Share:                share,
+		ExposedPanels:        exposedPanels,
+		AllowedVariables:     allowedVariables,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            time.Now(),
 	}
 }
 
+// publicDashboardsSettings returns the limits applied to public dashboard queries
+func (pd *PublicDashboardServiceImpl) publicDashboardsSettings() setting.PublicDashboardsSettings {
+	if pd.cfg == nil {
+		return setting.PublicDashboardsSettings{}
+	}
+	return pd.cfg.PublicDashboards
+}
+
 func returnValueOrDefault(value *bool, defaultValue bool) bool {
 	if value != nil {
 		return *value
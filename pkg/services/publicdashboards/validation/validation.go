@@ -2,6 +2,8 @@ package validation
 
 import (
 	"github.com/google/uuid"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 	"github.com/grafana/grafana/pkg/util"
@@ -16,6 +18,42 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 	return nil
 }
 
+// ValidateExposedPanelsAndVariables checks that the exposed panels and the allowed variables belong to the dashboard
+func ValidateExposedPanelsAndVariables(dto *SavePublicDashboardDTO, dashboard *dashboards.Dashboard) error {
+	if dto.PublicDashboard.ExposedPanels != nil {
+		panelIds := make(map[int64]bool)
+		collectPanelIds(dashboard.Data.Get("panels").MustArray(), panelIds)
+		for _, id := range *dto.PublicDashboard.ExposedPanels {
+			if !panelIds[id] {
+				return ErrInvalidExposedPanels.Errorf("ValidateExposedPanelsAndVariables: panel %d not found in dashboard %s", id, dashboard.UID)
+			}
+		}
+	}
+
+	if dto.PublicDashboard.AllowedVariables != nil {
+		names := make(map[string]bool)
+		for _, variable := range dashboard.Data.GetPath("templating", "list").MustArray() {
+			names[simplejson.NewFromAny(variable).Get("name").MustString()] = true
+		}
+		for name := range *dto.PublicDashboard.AllowedVariables {
+			if !names[name] {
+				return ErrInvalidAllowedVariables.Errorf("ValidateExposedPanelsAndVariables: variable %s not found in dashboard %s", name, dashboard.UID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func collectPanelIds(panels []interface{}, ids map[int64]bool) {
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+		ids[panel.Get("id").MustInt64()] = true
+		// collapsed rows contain their panels
+		collectPanelIds(panel.Get("panels").MustArray(), ids)
+	}
+}
+
 func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *PublicDashboard) error {
 	if req.IntervalMs < 0 {
 		return ErrInvalidInterval.Errorf("ValidateQueryPublicDashboardRequest: intervalMS should be greater than 0")
@@ -38,6 +76,12 @@ func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *Public
 		}
 	}
 
+	for name, values := range req.Variables {
+		if !pd.AllowedVariables.Allows(name, values) {
+			return ErrVariableValueNotAllowed.Errorf("ValidateQueryPublicDashboardRequest: value of variable %s is not allowed", name)
+		}
+	}
+
 	return nil
 }
 
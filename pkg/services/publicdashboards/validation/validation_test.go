@@ -3,6 +3,8 @@ package validation
 import (
 	"testing"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +26,45 @@ func TestValidatePublicDashboard(t *testing.T) {
 	})
 }
 
+func TestValidateExposedPanelsAndVariables(t *testing.T) {
+	dashboard := &dashboards.Dashboard{UID: "abc123", Data: simplejson.NewFromAny(map[string]interface{}{
+		"panels": []interface{}{
+			map[string]interface{}{"id": 1, "type": "timeseries"},
+			map[string]interface{}{"id": 2, "type": "row", "collapsed": true, "panels": []interface{}{
+				map[string]interface{}{"id": 3, "type": "stat"},
+			}},
+		},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{"name": "host"},
+			},
+		},
+	})}
+
+	t.Run("Returns no error when panels and variables belong to the dashboard", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{PublicDashboard: &PublicDashboardDTO{
+			ExposedPanels:    &ExposedPanels{1, 3},
+			AllowedVariables: &VariableAllowList{"host": {"web-1"}},
+		}}
+		require.NoError(t, ValidateExposedPanelsAndVariables(dto, dashboard))
+	})
+
+	t.Run("Returns no error when nothing is restricted", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{PublicDashboard: &PublicDashboardDTO{}}
+		require.NoError(t, ValidateExposedPanelsAndVariables(dto, dashboard))
+	})
+
+	t.Run("Returns error when a panel is not in the dashboard", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{PublicDashboard: &PublicDashboardDTO{ExposedPanels: &ExposedPanels{1, 4}}}
+		require.ErrorIs(t, ValidateExposedPanelsAndVariables(dto, dashboard), ErrInvalidExposedPanels)
+	})
+
+	t.Run("Returns error when a variable is not in the dashboard", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{PublicDashboard: &PublicDashboardDTO{AllowedVariables: &VariableAllowList{"env": {"prod"}}}}
+		require.ErrorIs(t, ValidateExposedPanelsAndVariables(dto, dashboard), ErrInvalidAllowedVariables)
+	})
+}
+
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 	type args struct {
 		req PublicDashboardQueryDTO
@@ -114,6 +155,40 @@ func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Returns no error when variable values are allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"host": {"web-1"}},
+				},
+				pd: &PublicDashboard{
+					AllowedVariables: &VariableAllowList{"host": {"web-1", "web-2"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Returns validation error when variable value is not allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"host": {"db-1"}},
+				},
+				pd: &PublicDashboard{
+					AllowedVariables: &VariableAllowList{"host": {"web-1", "web-2"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Returns validation error when variable is not allowed",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					Variables: map[string][]string{"env": {"prod"}},
+				},
+				pd: &PublicDashboard{},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Returns validation error when time range from or to is blank",
 			args: args{
@@ -1,7 +1,10 @@
 package queryhistory
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -20,6 +23,12 @@ func (s *QueryHistoryService) registerAPIEndpoints() {
 		entities.Post("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.starHandler))
 		entities.Delete("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.unstarHandler))
 		entities.Patch("/:uid", middleware.ReqSignedIn, routing.Wrap(s.patchCommentHandler))
+		entities.Get("/search", middleware.ReqSignedIn, routing.Wrap(s.fullTextSearchHandler))
+		entities.Get("/shared", middleware.ReqSignedIn, routing.Wrap(s.searchSharedHandler))
+		entities.Post("/share/:uid", middleware.ReqSignedIn, routing.Wrap(s.shareHandler))
+		entities.Delete("/share/:uid/:teamId", middleware.ReqSignedIn, routing.Wrap(s.unshareHandler))
+		entities.Get("/export", middleware.ReqSignedIn, routing.Wrap(s.exportHandler))
+		entities.Post("/import", middleware.ReqSignedIn, routing.Wrap(s.importHandler))
 	})
 }
 
@@ -187,7 +196,195 @@ func (s *QueryHistoryService) unstarHandler(c *contextmodel.ReqContext) response
 	return response.JSON(http.StatusOK, QueryHistoryResponse{Result: query})
 }
 
-// swagger:parameters starQuery patchQueryComment deleteQuery unstarQuery
+// swagger:route GET /query-history/search query_history fullTextSearchQueries
+//
+// Query history full-text search.
+//
+// Returns the queries in the query history that match all the terms of the search in their text or comment, sorted by relevance.
+// The queries shared with the teams of the user are included when `includeShared` is set.
+//
+// Responses:
+// 200: getQueryHistorySearchResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *QueryHistoryService) fullTextSearchHandler(c *contextmodel.ReqContext) response.Response {
+	query := FullTextSearchInQueryHistoryQuery{
+		Query:          c.Query("query"),
+		DatasourceUIDs: c.QueryStrings("datasourceUid"),
+		IncludeShared:  c.QueryBoolWithDefault("includeShared", false),
+		Page:           c.QueryInt("page"),
+		Limit:          c.QueryInt("limit"),
+	}
+
+	result, err := s.FullTextSearchInQueryHistory(c.Req.Context(), c.SignedInUser, query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to search query history", err)
+	}
+
+	return response.JSON(http.StatusOK, QueryHistorySearchResponse{Result: result})
+}
+
+// swagger:route GET /query-history/shared query_history searchSharedQueries
+//
+// Search the queries shared with the teams of the user.
+//
+// Returns the queries shared with the teams of the user that match the search criteria.
+//
+// Responses:
+// 200: getQueryHistorySearchResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *QueryHistoryService) searchSharedHandler(c *contextmodel.ReqContext) response.Response {
+	query := SearchSharedInQueryHistoryQuery{
+		TeamID:         c.QueryInt64("teamId"),
+		DatasourceUIDs: c.QueryStrings("datasourceUid"),
+		SearchString:   c.Query("searchString"),
+		Sort:           c.Query("sort"),
+		Page:           c.QueryInt("page"),
+		Limit:          c.QueryInt("limit"),
+	}
+
+	result, err := s.SearchSharedInQueryHistory(c.Req.Context(), c.SignedInUser, query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get shared queries", err)
+	}
+
+	return response.JSON(http.StatusOK, QueryHistorySearchResponse{Result: result})
+}
+
+// swagger:route POST /query-history/share/{query_history_uid} query_history shareQuery
+//
+// Share query in query history with a team.
+//
+// Shares the query in query history as specified by the UID with a team the user is a member of.
+//
+// Responses:
+// 200: getQueryHistoryShareResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (s *QueryHistoryService) shareHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if len(queryUID) > 0 && !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query history not found", nil)
+	}
+
+	cmd := ShareQueryInQueryHistoryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if cmd.TeamID <= 0 {
+		return response.Error(http.StatusBadRequest, "teamId is required", nil)
+	}
+
+	err := s.ShareQueryInQueryHistory(c.Req.Context(), c.SignedInUser, queryUID, cmd)
+	if err != nil {
+		return shareErrorResponse(err, "Failed to share query in query history")
+	}
+
+	return response.JSON(http.StatusOK, QueryHistoryShareResponse{Message: "Query shared"})
+}
+
+// swagger:route DELETE /query-history/share/{query_history_uid}/{team_id} query_history unshareQuery
+//
+// Stop sharing query in query history with a team.
+//
+// Stops sharing the query in query history as specified by the UID with the team.
+//
+// Responses:
+// 200: getQueryHistoryShareResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) unshareHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if len(queryUID) > 0 && !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query history not found", nil)
+	}
+
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	err = s.UnshareQueryInQueryHistory(c.Req.Context(), c.SignedInUser, queryUID, teamID)
+	if err != nil {
+		return shareErrorResponse(err, "Failed to unshare query in query history")
+	}
+
+	return response.JSON(http.StatusOK, QueryHistoryShareResponse{Message: "Query unshared"})
+}
+
+func shareErrorResponse(err error, message string) response.Response {
+	switch {
+	case errors.Is(err, ErrQueryNotFound), errors.Is(err, ErrSharedQueryNotFound):
+		return response.Error(http.StatusNotFound, err.Error(), err)
+	case errors.Is(err, ErrNotTeamMember):
+		return response.Error(http.StatusForbidden, err.Error(), err)
+	case errors.Is(err, ErrQueryAlreadyShared):
+		return response.Error(http.StatusConflict, err.Error(), err)
+	}
+	return response.Error(http.StatusInternalServerError, message, err)
+}
+
+// swagger:route GET /query-history/export query_history exportQueries
+//
+// Export query history.
+//
+// Returns the queries in the query history of the user, with their comments and stars, in a format that can be imported.
+//
+// Responses:
+// 200: getQueryHistoryExportResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *QueryHistoryService) exportHandler(c *contextmodel.ReqContext) response.Response {
+	export, err := s.ExportQueryHistory(c.Req.Context(), c.SignedInUser, c.QueryStrings("datasourceUid"))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to export query history", err)
+	}
+
+	return response.JSON(http.StatusOK, export)
+}
+
+// swagger:route POST /query-history/import query_history importQueries
+//
+// Import query history.
+//
+// Adds the exported queries into the query history of the user.
+//
+// Responses:
+// 200: getQueryHistoryImportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *QueryHistoryService) importHandler(c *contextmodel.ReqContext) response.Response {
+	export := QueryHistoryExport{}
+	if err := web.Bind(c.Req, &export); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if export.Version != queryHistoryExportVersion {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("unsupported query history export version %d", export.Version), nil)
+	}
+
+	imported, err := s.ImportQueryHistory(c.Req.Context(), c.SignedInUser, export)
+	if err != nil {
+		if errors.Is(err, ErrTooManyQueries) {
+			return response.Error(http.StatusBadRequest, fmt.Sprintf("%s, the limit is %d", err, maxImportedQueries), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to import query history", err)
+	}
+
+	return response.JSON(http.StatusOK, QueryHistoryImportResponse{
+		Imported: imported,
+		Message:  "Queries imported",
+	})
+}
+
+// swagger:parameters starQuery patchQueryComment deleteQuery unstarQuery shareQuery unshareQuery
 type QueryHistoryByUID struct {
 	// in:path
 	// required:true
@@ -234,6 +431,95 @@ type SearchQueriesParams struct {
 	To int64 `json:"to"`
 }
 
+// swagger:parameters fullTextSearchQueries
+type FullTextSearchQueriesParams struct {
+	// Terms searched for in the text of the queries and in the comments, all the terms must match
+	// in:query
+	// required: false
+	Query string `json:"query"`
+	// List of data source UIDs to search for
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	DatasourceUid []string `json:"datasourceUid"`
+	// Flag indicating if the queries shared with the teams of the user should be returned
+	// in:query
+	// required: false
+	IncludeShared bool `json:"includeShared"`
+	// Use this parameter to access hits beyond limit. Numbering starts at 1. limit param acts as page size.
+	// in:query
+	// required: false
+	Page int `json:"page"`
+	// Limit the number of returned results
+	// in:query
+	// required: false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters searchSharedQueries
+type SearchSharedQueriesParams struct {
+	// ID of the team the queries are shared with, all the teams of the user by default
+	// in:query
+	// required: false
+	TeamId int64 `json:"teamId"`
+	// List of data source UIDs to search for
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	DatasourceUid []string `json:"datasourceUid"`
+	// Text inside query or comments that is searched for
+	// in:query
+	// required: false
+	SearchString string `json:"searchString"`
+	// Sort method
+	// in:query
+	// required: false
+	// default: time-desc
+	// Enum: time-desc,time-asc
+	Sort string `json:"sort"`
+	// Use this parameter to access hits beyond limit. Numbering starts at 1. limit param acts as page size.
+	// in:query
+	// required: false
+	Page int `json:"page"`
+	// Limit the number of returned results
+	// in:query
+	// required: false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters shareQuery
+type ShareQueryParams struct {
+	// in:body
+	// required:true
+	Body ShareQueryInQueryHistoryCommand `json:"body"`
+}
+
+// swagger:parameters unshareQuery
+type UnshareQueryParams struct {
+	// in:path
+	// required:true
+	TeamID int64 `json:"team_id"`
+}
+
+// swagger:parameters exportQueries
+type ExportQueriesParams struct {
+	// List of data source UIDs to export, all the queries by default
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	DatasourceUid []string `json:"datasourceUid"`
+}
+
+// swagger:parameters importQueries
+type ImportQueriesParams struct {
+	// in:body
+	// required:true
+	Body QueryHistoryExport `json:"body"`
+}
+
 // swagger:parameters createQuery
 type CreateQueryParams struct {
 	// in:body
@@ -265,3 +551,21 @@ type GetQueryHistoryDeleteQueryResponse struct {
 	// in: body
 	Body QueryHistoryDeleteQueryResponse `json:"body"`
 }
+
+// swagger:response getQueryHistoryShareResponse
+type GetQueryHistoryShareResponse struct {
+	// in: body
+	Body QueryHistoryShareResponse `json:"body"`
+}
+
+// swagger:response getQueryHistoryExportResponse
+type GetQueryHistoryExportResponse struct {
+	// in: body
+	Body QueryHistoryExport `json:"body"`
+}
+
+// swagger:response getQueryHistoryImportResponse
+type GetQueryHistoryImportResponse struct {
+	// in: body
+	Body QueryHistoryImportResponse `json:"body"`
+}
@@ -2,13 +2,23 @@ package queryhistory
 
 import (
 	"context"
+	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 )
 
+const (
+	// queryHistoryExportVersion is the version of the export format
+	queryHistoryExportVersion = 1
+
+	// maxImportedQueries limits the number of queries imported at once
+	maxImportedQueries = 10000
+)
+
 // createQuery adds a query into query history
 func (s QueryHistoryService) createQuery(ctx context.Context, user *user.SignedInUser, cmd CreateQueryInQueryHistoryCommand) (QueryHistoryDTO, error) {
 	queryHistory := QueryHistory{
@@ -90,7 +100,22 @@ func (s QueryHistoryService) searchQueries(ctx context.Context, user *user.Signe
 		writeStarredSQL(query, s.store, &countBuilder)
 		writeFiltersSQL(query, user, s.store, &countBuilder)
 		err = session.SQL(countBuilder.GetSQLString(), countBuilder.GetParams()...).Find(&allQueries)
-		return err
+		if err != nil {
+			return err
+		}
+
+		uids := make([]string, 0, len(dtos))
+		for _, dto := range dtos {
+			uids = append(uids, dto.UID)
+		}
+		shares, err := getQueryShares(session, user.OrgID, uids)
+		if err != nil {
+			return err
+		}
+		for i := range dtos {
+			dtos[i].SharedWithTeams = shares[dtos[i].UID]
+		}
+		return nil
 	})
 
 	if err != nil {
@@ -116,6 +141,12 @@ func (s QueryHistoryService) deleteQuery(ctx context.Context, user *user.SignedI
 			s.log.Error("Failed to unstar query while deleting it from query history", "query", UID, "user", user.UserID, "error", err)
 		}
 
+		// And stop sharing it
+		_, err = session.Table("query_history_share").Where("org_id = ? AND query_uid = ?", user.OrgID, UID).Delete(QueryHistoryShare{})
+		if err != nil {
+			s.log.Error("Failed to unshare query while deleting it from query history", "query", UID, "user", user.UserID, "error", err)
+		}
+
 		// Then delete it
 		id, err := session.Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, UID).Delete(QueryHistory{})
 		if err != nil {
@@ -298,7 +329,7 @@ func (s QueryHistoryService) deleteStaleQueries(ctx context.Context, olderThan i
 			return err
 		}
 
-		return nil
+		return deleteOrphanedQueryShares(session)
 	})
 
 	if err != nil {
@@ -366,6 +397,9 @@ func (s QueryHistoryService) enforceQueryHistoryRowLimit(ctx context.Context, li
 			if err != nil {
 				return err
 			}
+			if !starredQueries {
+				return deleteOrphanedQueryShares(session)
+			}
 		}
 		return nil
 	})
@@ -375,3 +409,324 @@ func (s QueryHistoryService) enforceQueryHistoryRowLimit(ctx context.Context, li
 
 	return int(deletedRowsCount), nil
 }
+
+// deleteOrphanedQueryShares removes the shares of the deleted queries. The cleanups delete the queries that are not
+// starred, whether they are shared or not, so their shares are removed as well.
+func deleteOrphanedQueryShares(session *db.Session) error {
+	_, err := session.Exec(`DELETE FROM query_history_share WHERE query_uid NOT IN (SELECT uid FROM query_history)`)
+	return err
+}
+
+// shareQuery shares a query of the user with a team the user is a member of
+func (s QueryHistoryService) shareQuery(ctx context.Context, user *user.SignedInUser, UID string, cmd ShareQueryInQueryHistoryCommand) error {
+	return s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		exists, err := session.Table("query_history").Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, UID).Exist()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrQueryNotFound
+		}
+
+		isMember, err := session.Table("team_member").Where("org_id = ? AND team_id = ? AND user_id = ?", user.OrgID, cmd.TeamID, user.UserID).Exist()
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return ErrNotTeamMember
+		}
+
+		share := QueryHistoryShare{
+			OrgID:     user.OrgID,
+			QueryUID:  UID,
+			TeamID:    cmd.TeamID,
+			SharedBy:  user.UserID,
+			CreatedAt: s.now().Unix(),
+		}
+		_, err = session.Insert(&share)
+		if err != nil {
+			if s.store.GetDialect().IsUniqueConstraintViolation(err) {
+				return ErrQueryAlreadyShared
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// unshareQuery stops sharing a query of the user with a team
+func (s QueryHistoryService) unshareQuery(ctx context.Context, user *user.SignedInUser, UID string, teamID int64) error {
+	return s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		exists, err := session.Table("query_history").Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, UID).Exist()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrQueryNotFound
+		}
+
+		id, err := session.Table("query_history_share").Where("org_id = ? AND query_uid = ? AND team_id = ?", user.OrgID, UID, teamID).Delete(QueryHistoryShare{})
+		if err != nil {
+			return err
+		}
+		if id == 0 {
+			return ErrSharedQueryNotFound
+		}
+		return nil
+	})
+}
+
+// searchSharedQueries searches for the queries shared with the teams of the user
+func (s QueryHistoryService) searchSharedQueries(ctx context.Context, user *user.SignedInUser, query SearchSharedInQueryHistoryQuery) (QueryHistorySearchResult, error) {
+	dtos := make([]QueryHistoryDTO, 0)
+	var totalCount int64
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = 100
+	}
+
+	if query.Sort == "" {
+		query.Sort = "time-desc"
+	}
+
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		dtosBuilder := db.SQLBuilder{}
+		dtosBuilder.Write(`SELECT
+			query_history.uid,
+			query_history.datasource_uid,
+			query_history.created_by,
+			query_history.created_at AS created_at,
+			query_history.comment,
+			query_history.queries,
+		`)
+		writeSharedStarredSQL(user, s.store, &dtosBuilder)
+		writeSharedFiltersSQL(query, user, s.store, &dtosBuilder)
+		writeSortSQL(SearchInQueryHistoryQuery{Sort: query.Sort}, s.store, &dtosBuilder)
+		dtosBuilder.Write(" LIMIT ? OFFSET ? ", query.Limit, query.Limit*(query.Page-1))
+
+		err := session.SQL(dtosBuilder.GetSQLString(), dtosBuilder.GetParams()...).Find(&dtos)
+		if err != nil {
+			return err
+		}
+
+		countBuilder := db.SQLBuilder{}
+		countBuilder.Write(`SELECT COUNT(*) FROM query_history `)
+		writeSharedFiltersSQL(query, user, s.store, &countBuilder)
+		_, err = session.SQL(countBuilder.GetSQLString(), countBuilder.GetParams()...).Get(&totalCount)
+		return err
+	})
+
+	if err != nil {
+		return QueryHistorySearchResult{}, err
+	}
+
+	return QueryHistorySearchResult{
+		QueryHistory: dtos,
+		TotalCount:   int(totalCount),
+		Page:         query.Page,
+		PerPage:      query.Limit,
+	}, nil
+}
+
+// fullTextSearchQueries searches the full-text index for the queries of the user and the queries shared with their
+// teams, the results are sorted by relevance
+func (s QueryHistoryService) fullTextSearchQueries(ctx context.Context, user *user.SignedInUser, query FullTextSearchInQueryHistoryQuery) (QueryHistorySearchResult, error) {
+	if s.index == nil {
+		return QueryHistorySearchResult{}, errors.New("full-text search is not available")
+	}
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = 100
+	}
+
+	var teams []int64
+	if query.IncludeShared {
+		var err error
+		teams, err = getUserTeams(ctx, s.store, user.OrgID, user.UserID)
+		if err != nil {
+			return QueryHistorySearchResult{}, err
+		}
+	}
+
+	uids, totalCount, err := s.index.search(ctx, user.OrgID, user.UserID, teams, query)
+	if err != nil {
+		return QueryHistorySearchResult{}, err
+	}
+
+	// The queries are loaded from the database, which also checks the user can still see them
+	dtos, err := s.getQueriesByUID(ctx, user, uids)
+	if err != nil {
+		return QueryHistorySearchResult{}, err
+	}
+
+	return QueryHistorySearchResult{
+		QueryHistory: dtos,
+		TotalCount:   totalCount,
+		Page:         query.Page,
+		PerPage:      query.Limit,
+	}, nil
+}
+
+// getQueriesByUID returns the queries visible to the user, their own and the ones shared with their teams, in the
+// order of the UIDs
+func (s QueryHistoryService) getQueriesByUID(ctx context.Context, user *user.SignedInUser, uids []string) ([]QueryHistoryDTO, error) {
+	if len(uids) == 0 {
+		return []QueryHistoryDTO{}, nil
+	}
+
+	var dtos []QueryHistoryDTO
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		builder := db.SQLBuilder{}
+		builder.Write(`SELECT
+			query_history.uid,
+			query_history.datasource_uid,
+			query_history.created_by,
+			query_history.created_at AS created_at,
+			query_history.comment,
+			query_history.queries,
+		`)
+		writeSharedStarredSQL(user, s.store, &builder)
+
+		params := []interface{}{user.OrgID}
+		for _, uid := range uids {
+			params = append(params, uid)
+		}
+		params = append(params, user.UserID, user.OrgID, user.UserID)
+		builder.Write(` WHERE query_history.org_id = ? AND query_history.uid IN (?`+strings.Repeat(",?", len(uids)-1)+`)
+			AND (query_history.created_by = ? OR query_history.uid IN (`+sharedWithUserSQL+`)) `, params...)
+
+		return session.SQL(builder.GetSQLString(), builder.GetParams()...).Find(&dtos)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byUID := make(map[string]QueryHistoryDTO, len(dtos))
+	for _, dto := range dtos {
+		byUID[dto.UID] = dto
+	}
+	ordered := make([]QueryHistoryDTO, 0, len(dtos))
+	for _, uid := range uids {
+		if dto, ok := byUID[uid]; ok {
+			ordered = append(ordered, dto)
+		}
+	}
+	return ordered, nil
+}
+
+// getQueryShares returns the teams each of the queries is shared with
+func getQueryShares(session *db.Session, orgID int64, uids []string) (map[string][]int64, error) {
+	shares := make(map[string][]int64)
+	if len(uids) == 0 {
+		return shares, nil
+	}
+
+	var rows []QueryHistoryShare
+	err := session.Table("query_history_share").Where("org_id = ?", orgID).In("query_uid", uids).Asc("team_id").Find(&rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		shares[row.QueryUID] = append(shares[row.QueryUID], row.TeamID)
+	}
+	return shares, nil
+}
+
+// exportQueries returns the queries of the user in the export format
+func (s QueryHistoryService) exportQueries(ctx context.Context, user *user.SignedInUser, datasourceUIDs []string) (QueryHistoryExport, error) {
+	var dtos []QueryHistoryDTO
+
+	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		builder := db.SQLBuilder{}
+		builder.Write(`SELECT
+			query_history.uid,
+			query_history.datasource_uid,
+			query_history.created_by,
+			query_history.created_at AS created_at,
+			query_history.comment,
+			query_history.queries,
+		`)
+		writeStarredSQL(SearchInQueryHistoryQuery{}, s.store, &builder)
+		builder.Write(" WHERE query_history.org_id = ? AND query_history.created_by = ? ", user.OrgID, user.UserID)
+		if len(datasourceUIDs) > 0 {
+			params := make([]interface{}, 0, len(datasourceUIDs))
+			for _, uid := range datasourceUIDs {
+				params = append(params, uid)
+			}
+			builder.Write(" AND query_history.datasource_uid IN (?"+strings.Repeat(",?", len(datasourceUIDs)-1)+") ", params...)
+		}
+		writeSortSQL(SearchInQueryHistoryQuery{Sort: "time-asc"}, s.store, &builder)
+
+		return session.SQL(builder.GetSQLString(), builder.GetParams()...).Find(&dtos)
+	})
+	if err != nil {
+		return QueryHistoryExport{}, err
+	}
+
+	export := QueryHistoryExport{
+		Version: queryHistoryExportVersion,
+		Queries: make([]QueryHistoryExportQuery, 0, len(dtos)),
+	}
+	for _, dto := range dtos {
+		export.Queries = append(export.Queries, QueryHistoryExportQuery{
+			DatasourceUID: dto.DatasourceUID,
+			Queries:       dto.Queries,
+			Comment:       dto.Comment,
+			CreatedAt:     dto.CreatedAt,
+			Starred:       dto.Starred,
+		})
+	}
+	return export, nil
+}
+
+// importQueries adds the exported queries into the query history of the user
+func (s QueryHistoryService) importQueries(ctx context.Context, user *user.SignedInUser, export QueryHistoryExport) ([]QueryHistory, error) {
+	if len(export.Queries) > maxImportedQueries {
+		return nil, ErrTooManyQueries
+	}
+
+	imported := make([]QueryHistory, 0, len(export.Queries))
+	err := s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		now := s.now().Unix()
+		for _, q := range export.Queries {
+			createdAt := q.CreatedAt
+			if createdAt <= 0 || createdAt > now {
+				createdAt = now
+			}
+
+			queryHistory := QueryHistory{
+				OrgID:         user.OrgID,
+				UID:           util.GenerateShortUID(),
+				Queries:       q.Queries,
+				DatasourceUID: q.DatasourceUID,
+				CreatedBy:     user.UserID,
+				CreatedAt:     createdAt,
+				Comment:       q.Comment,
+			}
+			if _, err := session.Insert(&queryHistory); err != nil {
+				return err
+			}
+
+			if q.Starred {
+				if _, err := session.Insert(&QueryHistoryStar{UserID: user.UserID, QueryUID: queryHistory.UID}); err != nil {
+					return err
+				}
+			}
+			imported = append(imported, queryHistory)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return imported, nil
+}
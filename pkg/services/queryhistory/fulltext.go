@@ -0,0 +1,404 @@
+package queryhistory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blugelabs/bluge"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	documentFieldID        = "_id" // the field of the document ID set by bluge
+	documentFieldCreatedBy = "created_by"
+	documentFieldTeam      = "team"
+	documentFieldDSUID     = "ds_uid"
+	documentFieldText      = "text"
+	documentFieldComment   = "comment"
+	documentFieldCreatedAt = "created_at"
+)
+
+// The keys of the queries that are not useful for the full-text search
+var ignoredQueryKeys = map[string]bool{
+	"refId":      true,
+	"key":        true,
+	"datasource": true,
+}
+
+// indexIdleTimeout is how long the full-text index of an org is kept in memory after its last search
+const indexIdleTimeout = 30 * time.Minute
+
+// fullTextIndex is an in-memory index of the query history of each org. The index of an org is built by its first
+// search and removed when it hasn't been searched for indexIdleTimeout, so only the orgs using the full-text search
+// are kept in memory. Changes made by this instance are indexed right away, the index is synced with the database
+// after maxAge to pick up the changes made by other instances and the cleanup. Each org has its own lock, so a sync
+// only blocks the searches of its org.
+type fullTextIndex struct {
+	store  db.DB
+	log    log.Logger
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu          sync.Mutex // protects orgs and lastEvicted
+	orgs        map[int64]*orgFullTextIndex
+	lastEvicted time.Time
+}
+
+type orgFullTextIndex struct {
+	mu     sync.Mutex // serializes the syncs and updates of the index
+	writer *bluge.Writer
+	// docs is the state of the indexed queries, used to only index again the queries changed by other instances
+	docs   map[string]indexedState
+	synced time.Time
+	// lastUsed is the time of the last search, it is protected by the lock of fullTextIndex
+	lastUsed time.Time
+}
+
+// indexedState is the part of an indexed query that can change after it has been created
+type indexedState struct {
+	comment string
+	teams   string
+}
+
+func newIndexedState(comment string, teams []int64) indexedState {
+	sorted := make([]string, 0, len(teams))
+	for _, team := range teams {
+		sorted = append(sorted, strconv.FormatInt(team, 10))
+	}
+	sort.Strings(sorted)
+	return indexedState{comment: comment, teams: strings.Join(sorted, ",")}
+}
+
+func newFullTextIndex(store db.DB, maxAge time.Duration) *fullTextIndex {
+	return &fullTextIndex{
+		store:       store,
+		log:         log.New("query-history.index"),
+		maxAge:      maxAge,
+		now:         time.Now,
+		orgs:        make(map[int64]*orgFullTextIndex),
+		lastEvicted: time.Now(),
+	}
+}
+
+// indexedQuery is a query of the query history with the teams it is shared with
+type indexedQuery struct {
+	QueryHistory
+	Teams []int64
+}
+
+func newQueryDocument(q indexedQuery) *bluge.Document {
+	doc := bluge.NewDocument(q.UID).
+		AddField(bluge.NewKeywordField(documentFieldCreatedBy, strconv.FormatInt(q.CreatedBy, 10))).
+		AddField(bluge.NewKeywordField(documentFieldDSUID, q.DatasourceUID)).
+		AddField(bluge.NewTextField(documentFieldText, queryText(q.Queries))).
+		AddField(bluge.NewTextField(documentFieldComment, q.Comment)).
+		AddField(bluge.NewDateTimeField(documentFieldCreatedAt, time.Unix(q.CreatedAt, 0)).Sortable())
+
+	for _, team := range q.Teams {
+		doc.AddField(bluge.NewKeywordField(documentFieldTeam, strconv.FormatInt(team, 10)))
+	}
+	return doc
+}
+
+// queryText returns the string values of the queries, like the expressions, metrics and labels
+func queryText(queries *simplejson.Json) string {
+	if queries == nil {
+		return ""
+	}
+	var text []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch val := v.(type) {
+		case string:
+			text = append(text, val)
+		case []interface{}:
+			for _, item := range val {
+				walk(item)
+			}
+		case map[string]interface{}:
+			for key, item := range val {
+				if !ignoredQueryKeys[key] {
+					walk(item)
+				}
+			}
+		}
+	}
+	walk(queries.Interface())
+	return strings.Join(text, " ")
+}
+
+// reader returns a reader of the index of the org, building the index when missing and syncing it when older than
+// maxAge. The caller must close the reader.
+func (i *fullTextIndex) reader(ctx context.Context, orgID int64) (*bluge.Reader, error) {
+	now := i.now()
+	i.mu.Lock()
+	evicted := i.evictIdle(now)
+	index, ok := i.orgs[orgID]
+	if !ok {
+		index = &orgFullTextIndex{}
+		i.orgs[orgID] = index
+	}
+	index.lastUsed = now
+	i.mu.Unlock()
+
+	for _, idle := range evicted {
+		idle.close()
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	if index.writer == nil {
+		writer, err := bluge.OpenWriter(bluge.InMemoryOnlyConfig())
+		if err != nil {
+			return nil, fmt.Errorf("error opening writer: %v", err)
+		}
+		index.writer = writer
+		index.docs = make(map[string]indexedState)
+	}
+	if index.synced.IsZero() || now.Sub(index.synced) >= i.maxAge {
+		if err := i.sync(ctx, orgID, index); err != nil {
+			return nil, err
+		}
+	}
+	return index.writer.Reader()
+}
+
+// evictIdle removes the indexes of the orgs that haven't been searched for indexIdleTimeout, at most once every
+// indexIdleTimeout. The caller holds the lock of fullTextIndex and closes the returned indexes.
+func (i *fullTextIndex) evictIdle(now time.Time) []*orgFullTextIndex {
+	if now.Sub(i.lastEvicted) < indexIdleTimeout {
+		return nil
+	}
+	i.lastEvicted = now
+
+	var evicted []*orgFullTextIndex
+	for orgID, index := range i.orgs {
+		if now.Sub(index.lastUsed) >= indexIdleTimeout {
+			delete(i.orgs, orgID)
+			evicted = append(evicted, index)
+		}
+	}
+	return evicted
+}
+
+// close releases the memory of the index, the updates made after it are ignored
+func (index *orgFullTextIndex) close() {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	if index.writer != nil {
+		_ = index.writer.Close()
+		index.writer = nil
+		index.docs = nil
+	}
+}
+
+// syncBatchSize is the number of changed queries loaded at once when syncing an index
+const syncBatchSize = 500
+
+// sync compares the comments and shares of the queries in the database with the index, and only loads and indexes
+// the queries that are new or changed. The deleted queries are removed from the index. The caller holds the lock
+// of the index.
+func (i *fullTextIndex) sync(ctx context.Context, orgID int64, index *orgFullTextIndex) error {
+	start := i.now()
+
+	var rows []struct {
+		UID     string `xorm:"uid"`
+		Comment string
+	}
+	var shares []QueryHistoryShare
+	err := i.store.WithDbSession(ctx, func(session *db.Session) error {
+		if err := session.Table("query_history").Where("org_id = ?", orgID).Cols("uid", "comment").Find(&rows); err != nil {
+			return err
+		}
+		return session.Where("org_id = ?", orgID).Find(&shares)
+	})
+	if err != nil {
+		return err
+	}
+
+	teams := make(map[string][]int64)
+	for _, share := range shares {
+		teams[share.QueryUID] = append(teams[share.QueryUID], share.TeamID)
+	}
+
+	docs := make(map[string]indexedState, len(rows))
+	var changed []string
+	for _, row := range rows {
+		state := newIndexedState(row.Comment, teams[row.UID])
+		docs[row.UID] = state
+		if old, ok := index.docs[row.UID]; !ok || old != state {
+			changed = append(changed, row.UID)
+		}
+	}
+
+	batch := bluge.NewBatch()
+	deleted := 0
+	for uid := range index.docs {
+		if _, ok := docs[uid]; !ok {
+			batch.Delete(bluge.NewDocument(uid).ID())
+			deleted++
+		}
+	}
+
+	for len(changed) > 0 {
+		n := syncBatchSize
+		if n > len(changed) {
+			n = len(changed)
+		}
+		var queries []QueryHistory
+		err := i.store.WithDbSession(ctx, func(session *db.Session) error {
+			return session.Where("org_id = ?", orgID).In("uid", changed[:n]).Find(&queries)
+		})
+		if err != nil {
+			return err
+		}
+		for _, q := range queries {
+			doc := newQueryDocument(indexedQuery{QueryHistory: q, Teams: teams[q.UID]})
+			batch.Update(doc.ID(), doc)
+		}
+		changed = changed[n:]
+	}
+
+	if err := index.writer.Batch(batch); err != nil {
+		return err
+	}
+	index.docs = docs
+	index.synced = start
+	i.log.Debug("Query history index synced", "orgId", orgID, "queries", len(docs), "deleted", deleted, "elapsed", time.Since(start))
+	return nil
+}
+
+// update indexes the query again, it is a no-op when the index of the org has not been built yet
+func (i *fullTextIndex) update(ctx context.Context, orgID int64, uid string) {
+	i.mu.Lock()
+	index, ok := i.orgs[orgID]
+	i.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	if index.writer == nil {
+		return
+	}
+
+	var queries []QueryHistory
+	var shares []QueryHistoryShare
+	err := i.store.WithDbSession(ctx, func(session *db.Session) error {
+		if err := session.Where("org_id = ? AND uid = ?", orgID, uid).Find(&queries); err != nil {
+			return err
+		}
+		return session.Where("org_id = ? AND query_uid = ?", orgID, uid).Find(&shares)
+	})
+	if err != nil {
+		i.log.Error("Failed to load query to index", "query", uid, "error", err)
+		return
+	}
+
+	if len(queries) == 0 {
+		err = index.writer.Delete(bluge.NewDocument(uid).ID())
+		delete(index.docs, uid)
+	} else {
+		q := indexedQuery{QueryHistory: queries[0]}
+		for _, share := range shares {
+			q.Teams = append(q.Teams, share.TeamID)
+		}
+		doc := newQueryDocument(q)
+		err = index.writer.Update(doc.ID(), doc)
+		index.docs[uid] = newIndexedState(q.Comment, q.Teams)
+	}
+	if err != nil {
+		i.log.Error("Failed to index query", "query", uid, "error", err)
+	}
+}
+
+// search returns the UIDs of the matching queries by relevance and the total count of matches
+func (i *fullTextIndex) search(ctx context.Context, orgID int64, userID int64, teams []int64, query FullTextSearchInQueryHistoryQuery) ([]string, int, error) {
+	reader, err := i.reader(ctx, orgID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	fullQuery := bluge.NewBooleanQuery()
+
+	owners := bluge.NewBooleanQuery()
+	owners.AddShould(bluge.NewTermQuery(strconv.FormatInt(userID, 10)).SetField(documentFieldCreatedBy))
+	if query.IncludeShared {
+		for _, team := range teams {
+			owners.AddShould(bluge.NewTermQuery(strconv.FormatInt(team, 10)).SetField(documentFieldTeam))
+		}
+	}
+	fullQuery.AddMust(owners)
+
+	if len(query.DatasourceUIDs) > 0 {
+		bq := bluge.NewBooleanQuery()
+		for _, uid := range query.DatasourceUIDs {
+			bq.AddShould(bluge.NewTermQuery(uid).SetField(documentFieldDSUID))
+		}
+		fullQuery.AddMust(bq)
+	}
+
+	if strings.TrimSpace(query.Query) != "" {
+		bq := bluge.NewBooleanQuery()
+		bq.AddShould(bluge.NewMatchQuery(query.Query).
+			SetField(documentFieldText).
+			SetOperator(bluge.MatchQueryOperatorAnd)) // all terms must match
+		bq.AddShould(bluge.NewMatchQuery(query.Query).
+			SetField(documentFieldComment).
+			SetOperator(bluge.MatchQueryOperatorAnd).
+			SetBoost(2)) // comments are written to be found
+		fullQuery.AddMust(bq)
+	}
+
+	req := bluge.NewTopNSearch(query.Limit, fullQuery).
+		SetFrom(query.Limit * (query.Page - 1)).
+		WithStandardAggregations()
+	if strings.TrimSpace(query.Query) == "" {
+		req.SortBy([]string{"-" + documentFieldCreatedAt})
+	}
+
+	documentMatchIterator, err := reader.Search(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var uids []string
+	match, err := documentMatchIterator.Next()
+	for err == nil && match != nil {
+		err = match.VisitStoredFields(func(field string, value []byte) bool {
+			if field == documentFieldID {
+				uids = append(uids, string(value))
+			}
+			return true
+		})
+		if err != nil {
+			break
+		}
+		match, err = documentMatchIterator.Next()
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return uids, int(documentMatchIterator.Aggregations().Count()), nil
+}
+
+// getUserTeams returns the IDs of the teams of the user
+func getUserTeams(ctx context.Context, store db.DB, orgID int64, userID int64) ([]int64, error) {
+	var teams []int64
+	err := store.WithDbSession(ctx, func(session *db.Session) error {
+		return session.Table("team_member").Where("org_id = ? AND user_id = ?", orgID, userID).Cols("team_id").Find(&teams)
+	})
+	return teams, err
+}
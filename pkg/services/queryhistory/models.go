@@ -10,6 +10,10 @@ var (
 	ErrQueryNotFound        = errors.New("query in query history not found")
 	ErrStarredQueryNotFound = errors.New("starred query not found")
 	ErrQueryAlreadyStarred  = errors.New("query was already starred")
+	ErrQueryAlreadyShared   = errors.New("query was already shared with the team")
+	ErrSharedQueryNotFound  = errors.New("query is not shared with the team")
+	ErrNotTeamMember        = errors.New("user is not a member of the team")
+	ErrTooManyQueries       = errors.New("too many queries to import")
 )
 
 // QueryHistory is the model for query history definitions
@@ -31,6 +35,16 @@ type QueryHistoryStar struct {
 	UserID   int64  `xorm:"user_id"`
 }
 
+// QueryHistoryShare is the model for sharing a query with a team
+type QueryHistoryShare struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	OrgID     int64  `xorm:"org_id"`
+	QueryUID  string `xorm:"query_uid"`
+	TeamID    int64  `xorm:"team_id"`
+	SharedBy  int64
+	CreatedAt int64
+}
+
 type SearchInQueryHistoryQuery struct {
 	DatasourceUIDs []string `json:"datasourceUids"`
 	SearchString   string   `json:"searchString"`
@@ -42,6 +56,26 @@ type SearchInQueryHistoryQuery struct {
 	To             int64    `json:"to"`
 }
 
+type SearchSharedInQueryHistoryQuery struct {
+	// TeamID limits the results to the queries shared with the team, all the teams of the user by default
+	TeamID         int64    `json:"teamId"`
+	DatasourceUIDs []string `json:"datasourceUids"`
+	SearchString   string   `json:"searchString"`
+	Sort           string   `json:"sort"`
+	Page           int      `json:"page"`
+	Limit          int      `json:"limit"`
+}
+
+type FullTextSearchInQueryHistoryQuery struct {
+	// Query is matched against the text of the queries and the comments, all the terms must match
+	Query          string   `json:"query"`
+	DatasourceUIDs []string `json:"datasourceUids"`
+	// IncludeShared includes the queries shared with the teams of the user
+	IncludeShared bool `json:"includeShared"`
+	Page          int  `json:"page"`
+	Limit         int  `json:"limit"`
+}
+
 type QueryHistoryDTO struct {
 	UID           string           `json:"uid" xorm:"uid"`
 	DatasourceUID string           `json:"datasourceUid" xorm:"datasource_uid"`
@@ -50,6 +84,8 @@ type QueryHistoryDTO struct {
 	Comment       string           `json:"comment"`
 	Queries       *simplejson.Json `json:"queries"`
 	Starred       bool             `json:"starred"`
+	// Teams the query is shared with, only set for the queries of the user
+	SharedWithTeams []int64 `json:"sharedWithTeams,omitempty" xorm:"-"`
 }
 
 // QueryHistoryResponse is a response struct for QueryHistoryDTO
@@ -91,3 +127,37 @@ type PatchQueryCommentInQueryHistoryCommand struct {
 	// Updated comment
 	Comment string `json:"comment"`
 }
+
+// ShareQueryInQueryHistoryCommand is the command for sharing a query in query history with a team
+// swagger:model
+type ShareQueryInQueryHistoryCommand struct {
+	// ID of the team the query is shared with, the user must be a member of the team
+	// required: true
+	TeamID int64 `json:"teamId"`
+}
+
+// QueryHistoryExport is the format of the exported and imported query history
+// swagger:model
+type QueryHistoryExport struct {
+	Version int                       `json:"version"`
+	Queries []QueryHistoryExportQuery `json:"queries"`
+}
+
+type QueryHistoryExportQuery struct {
+	DatasourceUID string           `json:"datasourceUid"`
+	Queries       *simplejson.Json `json:"queries"`
+	Comment       string           `json:"comment,omitempty"`
+	CreatedAt     int64            `json:"createdAt"`
+	Starred       bool             `json:"starred,omitempty"`
+}
+
+// QueryHistoryImportResponse is the response struct for importing queries into query history
+type QueryHistoryImportResponse struct {
+	Imported int    `json:"imported"`
+	Message  string `json:"message"`
+}
+
+// QueryHistoryShareResponse is the response struct for sharing and unsharing a query
+type QueryHistoryShareResponse struct {
+	Message string `json:"message"`
+}
@@ -11,6 +11,9 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
+// indexMaxAge is how long the full-text index of an org is used before it is synced with the database
+const indexMaxAge = 5 * time.Minute
+
 func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister) *QueryHistoryService {
 	s := &QueryHistoryService{
 		store:         sqlStore,
@@ -18,6 +21,7 @@ func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.Rout
 		RouteRegister: routeRegister,
 		log:           log.New("query-history"),
 		now:           time.Now,
+		index:         newFullTextIndex(sqlStore, indexMaxAge),
 	}
 
 	// Register routes only when query history is enabled
@@ -37,6 +41,12 @@ type Service interface {
 	UnstarQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error)
 	DeleteStaleQueriesInQueryHistory(ctx context.Context, olderThan int64) (int, error)
 	EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error)
+	ShareQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, cmd ShareQueryInQueryHistoryCommand) error
+	UnshareQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, teamID int64) error
+	SearchSharedInQueryHistory(ctx context.Context, user *user.SignedInUser, query SearchSharedInQueryHistoryQuery) (QueryHistorySearchResult, error)
+	FullTextSearchInQueryHistory(ctx context.Context, user *user.SignedInUser, query FullTextSearchInQueryHistoryQuery) (QueryHistorySearchResult, error)
+	ExportQueryHistory(ctx context.Context, user *user.SignedInUser, datasourceUIDs []string) (QueryHistoryExport, error)
+	ImportQueryHistory(ctx context.Context, user *user.SignedInUser, export QueryHistoryExport) (int, error)
 }
 
 type QueryHistoryService struct {
@@ -45,10 +55,15 @@ type QueryHistoryService struct {
 	RouteRegister routing.RouteRegister
 	log           log.Logger
 	now           func() time.Time
+	index         *fullTextIndex
 }
 
 func (s QueryHistoryService) CreateQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, cmd CreateQueryInQueryHistoryCommand) (QueryHistoryDTO, error) {
-	return s.createQuery(ctx, user, cmd)
+	query, err := s.createQuery(ctx, user, cmd)
+	if err == nil {
+		s.updateIndex(ctx, user.OrgID, query.UID)
+	}
+	return query, err
 }
 
 func (s QueryHistoryService) SearchInQueryHistory(ctx context.Context, user *user.SignedInUser, query SearchInQueryHistoryQuery) (QueryHistorySearchResult, error) {
@@ -56,11 +71,19 @@ func (s QueryHistoryService) SearchInQueryHistory(ctx context.Context, user *use
 }
 
 func (s QueryHistoryService) DeleteQueryFromQueryHistory(ctx context.Context, user *user.SignedInUser, UID string) (int64, error) {
-	return s.deleteQuery(ctx, user, UID)
+	id, err := s.deleteQuery(ctx, user, UID)
+	if err == nil {
+		s.updateIndex(ctx, user.OrgID, UID)
+	}
+	return id, err
 }
 
 func (s QueryHistoryService) PatchQueryCommentInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, cmd PatchQueryCommentInQueryHistoryCommand) (QueryHistoryDTO, error) {
-	return s.patchQueryComment(ctx, user, UID, cmd)
+	query, err := s.patchQueryComment(ctx, user, UID, cmd)
+	if err == nil {
+		s.updateIndex(ctx, user.OrgID, UID)
+	}
+	return query, err
 }
 
 func (s QueryHistoryService) StarQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error) {
@@ -78,3 +101,48 @@ func (s QueryHistoryService) DeleteStaleQueriesInQueryHistory(ctx context.Contex
 func (s QueryHistoryService) EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error) {
 	return s.enforceQueryHistoryRowLimit(ctx, limit, starredQueries)
 }
+
+func (s QueryHistoryService) ShareQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, cmd ShareQueryInQueryHistoryCommand) error {
+	err := s.shareQuery(ctx, user, UID, cmd)
+	if err == nil {
+		s.updateIndex(ctx, user.OrgID, UID)
+	}
+	return err
+}
+
+func (s QueryHistoryService) UnshareQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, teamID int64) error {
+	err := s.unshareQuery(ctx, user, UID, teamID)
+	if err == nil {
+		s.updateIndex(ctx, user.OrgID, UID)
+	}
+	return err
+}
+
+func (s QueryHistoryService) SearchSharedInQueryHistory(ctx context.Context, user *user.SignedInUser, query SearchSharedInQueryHistoryQuery) (QueryHistorySearchResult, error) {
+	return s.searchSharedQueries(ctx, user, query)
+}
+
+func (s QueryHistoryService) FullTextSearchInQueryHistory(ctx context.Context, user *user.SignedInUser, query FullTextSearchInQueryHistoryQuery) (QueryHistorySearchResult, error) {
+	return s.fullTextSearchQueries(ctx, user, query)
+}
+
+func (s QueryHistoryService) ExportQueryHistory(ctx context.Context, user *user.SignedInUser, datasourceUIDs []string) (QueryHistoryExport, error) {
+	return s.exportQueries(ctx, user, datasourceUIDs)
+}
+
+func (s QueryHistoryService) ImportQueryHistory(ctx context.Context, user *user.SignedInUser, export QueryHistoryExport) (int, error) {
+	imported, err := s.importQueries(ctx, user, export)
+	if err != nil {
+		return 0, err
+	}
+	for _, q := range imported {
+		s.updateIndex(ctx, user.OrgID, q.UID)
+	}
+	return len(imported), nil
+}
+
+func (s QueryHistoryService) updateIndex(ctx context.Context, orgID int64, UID string) {
+	if s.index != nil {
+		s.index.update(ctx, orgID, UID)
+	}
+}
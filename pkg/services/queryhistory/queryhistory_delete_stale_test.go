@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -33,6 +34,27 @@ func TestIntegrationDeleteStaleQueryFromQueryHistory(t *testing.T) {
 			require.Equal(t, 0, rowsDeleted)
 		})
 
+	testScenarioWithQueryInQueryHistory(t, "Stale shared query history is deleted with its shares",
+		func(t *testing.T, sc scenarioContext) {
+			addTeamMember(t, sc, 1, testUserID)
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: 1})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			olderThan := sc.service.now().Unix() + 60
+			rowsDeleted, err := sc.service.DeleteStaleQueriesInQueryHistory(context.Background(), olderThan)
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+
+			err = sc.sqlStore.WithDbSession(context.Background(), func(session *db.Session) error {
+				count, err := session.Table("query_history_share").Count()
+				require.Equal(t, int64(0), count)
+				return err
+			})
+			require.NoError(t, err)
+		})
+
 	testScenarioWithQueryInQueryHistory(t, "Not stale query history is not deleted",
 		func(t *testing.T, sc scenarioContext) {
 			olderThan := sc.service.now().Unix() - 60
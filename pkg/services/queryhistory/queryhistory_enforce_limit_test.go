@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func TestIntegrationEnforceRowLimitInQueryHistory(t *testing.T) {
@@ -28,6 +31,26 @@ func TestIntegrationEnforceRowLimitInQueryHistory(t *testing.T) {
 			require.Equal(t, 1, rowsDeleted)
 		})
 
+	testScenarioWithQueryInQueryHistory(t, "Enforce limit for shared queries in query_history and delete their shares",
+		func(t *testing.T, sc scenarioContext) {
+			addTeamMember(t, sc, 1, testUserID)
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: 1})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			rowsDeleted, err := sc.service.EnforceRowLimitInQueryHistory(context.Background(), 0, false)
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+
+			err = sc.sqlStore.WithDbSession(context.Background(), func(session *db.Session) error {
+				count, err := session.Table("query_history_share").Count()
+				require.Equal(t, int64(0), count)
+				return err
+			})
+			require.NoError(t, err)
+		})
+
 	// In this scenario we have 2 starred queries and 1 not starred query
 	testScenarioWithMultipleQueriesInQueryHistory(t, "Enforce limit for stars in query_history_star",
 		func(t *testing.T, sc scenarioContext) {
@@ -0,0 +1,82 @@
+package queryhistory
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestIntegrationExportImportQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users exports query history, it should return all their queries",
+		func(t *testing.T, sc scenarioContext) {
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var export QueryHistoryExport
+			err := json.Unmarshal(resp.Body(), &export)
+			require.NoError(t, err)
+			require.Equal(t, queryHistoryExportVersion, export.Version)
+			require.Len(t, export.Queries, 3)
+			require.Equal(t, "test comment 2", export.Queries[0].Comment)
+			require.False(t, export.Queries[0].Starred)
+			require.True(t, export.Queries[1].Starred)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users exports query history of a data source, it should return the queries of the data source",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("datasourceUid", testDsUID2)
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var export QueryHistoryExport
+			err := json.Unmarshal(resp.Body(), &export)
+			require.NoError(t, err)
+			require.Len(t, export.Queries, 1)
+		})
+
+	testScenario(t, "When users imports query history, it should add the queries",
+		func(t *testing.T, sc scenarioContext) {
+			export := QueryHistoryExport{
+				Version: queryHistoryExportVersion,
+				Queries: []QueryHistoryExportQuery{
+					{
+						DatasourceUID: testDsUID1,
+						Queries:       simplejson.NewFromAny(map[string]interface{}{"expr": "imported"}),
+						Comment:       "imported comment",
+						CreatedAt:     sc.service.now().Add(-time.Hour).Unix(),
+						Starred:       true,
+					},
+				},
+			}
+			sc.reqContext.Req.Body = mockRequestBody(export)
+			resp := sc.service.importHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var imported QueryHistoryImportResponse
+			err := json.Unmarshal(resp.Body(), &imported)
+			require.NoError(t, err)
+			require.Equal(t, 1, imported.Imported)
+
+			resp = sc.service.searchHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			err = json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 1, response.Result.TotalCount)
+			require.Equal(t, "imported comment", response.Result.QueryHistory[0].Comment)
+			require.True(t, response.Result.QueryHistory[0].Starred)
+		})
+
+	testScenario(t, "When users imports query history with an unknown version, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Body = mockRequestBody(QueryHistoryExport{Version: 2})
+			resp := sc.service.importHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+}
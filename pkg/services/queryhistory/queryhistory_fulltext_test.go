@@ -0,0 +1,146 @@
+package queryhistory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestIntegrationFullTextSearchInQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users searches for a term of the queries, it should return the matching queries",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("query", "test2")
+			resp := sc.service.fullTextSearchHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			err := json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, 2, response.Result.TotalCount)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users searches for a term of the comments, it should return the commented query",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("query", "comment")
+			resp := sc.service.fullTextSearchHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			err := json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, 1, response.Result.TotalCount)
+			require.Equal(t, sc.initialResult.Result.UID, response.Result.QueryHistory[0].UID)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users adds a query after the first search, it should be found",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("query", "rate")
+			resp := sc.service.fullTextSearchHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			err := json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 0, response.Result.TotalCount)
+
+			sc.reqContext.Req.Body = mockRequestBody(CreateQueryInQueryHistoryCommand{
+				DatasourceUID: testDsUID1,
+				Queries:       simplejson.NewFromAny(map[string]interface{}{"expr": "rate(http_requests_total[5m])"}),
+			})
+			sc.service.createHandler(sc.reqContext)
+
+			resp = sc.service.fullTextSearchHandler(sc.reqContext)
+			err = json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 1, response.Result.TotalCount)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When a member of the team searches the shared queries, it should return the shared query",
+		func(t *testing.T, sc scenarioContext) {
+			addTeamMember(t, sc, 1, testUserID)
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: 1})
+			sc.service.shareHandler(sc.reqContext)
+
+			otherUserID := int64(2)
+			addTeamMember(t, sc, 1, otherUserID)
+			sc.reqContext.SignedInUser = &user.SignedInUser{UserID: otherUserID, OrgID: testOrgID}
+			sc.reqContext.Req.Form.Add("query", "test")
+
+			resp := sc.service.fullTextSearchHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			err := json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 0, response.Result.TotalCount)
+
+			sc.reqContext.Req.Form.Add("includeShared", "true")
+			resp = sc.service.fullTextSearchHandler(sc.reqContext)
+			err = json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 1, response.Result.TotalCount)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When queries are changed by another instance, the index should be synced after its max age",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("query", "test2")
+			resp := sc.service.fullTextSearchHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			err := json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 2, response.Result.TotalCount)
+
+			// changes made without updating the index, like the ones of another instance or of the cleanup
+			err = sc.sqlStore.WithDbSession(context.Background(), func(session *db.Session) error {
+				if _, err := session.Exec("DELETE FROM query_history WHERE uid = ?", response.Result.QueryHistory[0].UID); err != nil {
+					return err
+				}
+				_, err := session.Exec("UPDATE query_history SET comment = ? WHERE uid = ?", "test2 comment", sc.initialResult.Result.UID)
+				return err
+			})
+			require.NoError(t, err)
+
+			resp = sc.service.fullTextSearchHandler(sc.reqContext)
+			err = json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 2, response.Result.TotalCount)
+
+			now := time.Now().Add(indexMaxAge)
+			sc.service.index.now = func() time.Time { return now }
+			resp = sc.service.fullTextSearchHandler(sc.reqContext)
+			err = json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 2, response.Result.TotalCount)
+			require.Equal(t, sc.initialResult.Result.UID, response.Result.QueryHistory[0].UID)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "The index of an org should be removed when it has not been searched for a while",
+		func(t *testing.T, sc scenarioContext) {
+			resp := sc.service.fullTextSearchHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			require.Len(t, sc.service.index.orgs, 1)
+
+			now := time.Now().Add(indexIdleTimeout)
+			sc.service.index.now = func() time.Time { return now }
+			sc.reqContext.SignedInUser.OrgID = 2
+			resp = sc.service.fullTextSearchHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			require.Len(t, sc.service.index.orgs, 1)
+			require.Contains(t, sc.service.index.orgs, int64(2))
+
+			// the index is built again by the next search of the org
+			sc.reqContext.SignedInUser.OrgID = testOrgID
+			resp = sc.service.fullTextSearchHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			err := json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 1, response.Result.TotalCount)
+			require.Len(t, sc.service.index.orgs, 2)
+		})
+}
@@ -0,0 +1,118 @@
+package queryhistory
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestIntegrationShareQueryInQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testTeamID := int64(1)
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to share query with a team they are not a member of, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: testTeamID})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 403, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to share query that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			addTeamMember(t, sc, testTeamID, testUserID)
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": "unknown"})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: testTeamID})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to share query with their team, it should succeed",
+		func(t *testing.T, sc scenarioContext) {
+			addTeamMember(t, sc, testTeamID, testUserID)
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: testTeamID})
+			resp := sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: testTeamID})
+			resp = sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 409, resp.Status())
+
+			resp = sc.service.searchHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			err := json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, []int64{testTeamID}, response.Result.QueryHistory[0].SharedWithTeams)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When another member of the team lists the shared queries, it should return the shared query",
+		func(t *testing.T, sc scenarioContext) {
+			memberID := int64(2)
+			addTeamMember(t, sc, testTeamID, testUserID)
+			addTeamMember(t, sc, testTeamID, memberID)
+			member := &user.SignedInUser{UserID: memberID, OrgID: testOrgID}
+
+			// the query of the other user is not listed before it is shared
+			owner := sc.reqContext.SignedInUser
+			sc.reqContext.SignedInUser = member
+			resp := sc.service.searchSharedHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			err := json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 0, response.Result.TotalCount)
+
+			sc.reqContext.SignedInUser = owner
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: testTeamID})
+			resp = sc.service.shareHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			sc.reqContext.SignedInUser = member
+			resp = sc.service.searchSharedHandler(sc.reqContext)
+			err = json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, 1, response.Result.TotalCount)
+			require.Equal(t, sc.initialResult.Result.UID, response.Result.QueryHistory[0].UID)
+			require.Equal(t, testUserID, response.Result.QueryHistory[0].CreatedBy)
+			require.NotEqual(t, memberID, response.Result.QueryHistory[0].CreatedBy)
+			require.False(t, response.Result.QueryHistory[0].Starred)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When a user who is not a member of the team lists the shared queries, it should return no queries",
+		func(t *testing.T, sc scenarioContext) {
+			addTeamMember(t, sc, testTeamID, testUserID)
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: testTeamID})
+			sc.service.shareHandler(sc.reqContext)
+
+			sc.reqContext.SignedInUser = &user.SignedInUser{UserID: 2, OrgID: testOrgID}
+			resp := sc.service.searchSharedHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			err := json.Unmarshal(resp.Body(), &response)
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.Status())
+			require.Equal(t, 0, response.Result.TotalCount)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users tries to unshare query, it should succeed once",
+		func(t *testing.T, sc scenarioContext) {
+			addTeamMember(t, sc, testTeamID, testUserID)
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(ShareQueryInQueryHistoryCommand{TeamID: testTeamID})
+			sc.service.shareHandler(sc.reqContext)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":teamId": "1"})
+			resp := sc.service.unshareHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			resp = sc.service.unshareHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org/orgimpl"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/supportbundles/supportbundlestest"
+	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/userimpl"
 	"github.com/grafana/grafana/pkg/setting"
@@ -55,6 +56,7 @@ func testScenario(t *testing.T, desc string, fn func(t *testing.T, sc scenarioCo
 			Cfg:   setting.NewCfg(),
 			store: sqlStore,
 			now:   time.Now,
+			index: newFullTextIndex(sqlStore, indexMaxAge),
 		}
 		service.Cfg.QueryHistoryEnabled = true
 		quotaService := quotatest.New(false, nil)
@@ -162,6 +164,22 @@ func testScenarioWithMultipleQueriesInQueryHistory(t *testing.T, desc string, fn
 	})
 }
 
+func addTeamMember(t *testing.T, sc scenarioContext, teamID int64, userID int64) {
+	t.Helper()
+
+	err := sc.sqlStore.WithDbSession(context.Background(), func(session *db.Session) error {
+		_, err := session.Insert(&team.TeamMember{
+			OrgID:   testOrgID,
+			TeamID:  teamID,
+			UserID:  userID,
+			Created: time.Now(),
+			Updated: time.Now(),
+		})
+		return err
+	})
+	require.NoError(t, err)
+}
+
 func mockRequestBody(v interface{}) io.ReadCloser {
 	b, _ := json.Marshal(v)
 	return io.NopCloser(bytes.NewReader(b))
@@ -51,3 +51,36 @@ func writeLimitSQL(query SearchInQueryHistoryQuery, sqlStore db.DB, builder *db.
 func writeOffsetSQL(query SearchInQueryHistoryQuery, sqlStore db.DB, builder *db.SQLBuilder) {
 	builder.Write(" OFFSET ? ", query.Limit*(query.Page-1))
 }
+
+// sharedWithUserSQL selects the UIDs of the queries shared with the teams of the user, the parameters are the org ID
+// and the user ID
+const sharedWithUserSQL = `SELECT query_history_share.query_uid FROM query_history_share
+	INNER JOIN team_member ON team_member.team_id = query_history_share.team_id
+	WHERE query_history_share.org_id = ? AND team_member.user_id = ?`
+
+func writeSharedStarredSQL(user *user.SignedInUser, sqlStore db.DB, builder *db.SQLBuilder) {
+	builder.Write(` CASE WHEN query_history_star.query_uid IS NULL THEN `+sqlStore.GetDialect().BooleanStr(false)+` ELSE `+sqlStore.GetDialect().BooleanStr(true)+` END AS starred
+				FROM query_history
+				LEFT JOIN query_history_star ON query_history_star.query_uid = query_history.uid AND query_history_star.user_id = ?
+				`, user.UserID)
+}
+
+func writeSharedFiltersSQL(query SearchSharedInQueryHistoryQuery, user *user.SignedInUser, sqlStore db.DB, builder *db.SQLBuilder) {
+	params := []interface{}{user.OrgID, user.OrgID, user.UserID}
+	var sql bytes.Buffer
+	sql.WriteString(" WHERE query_history.org_id = ? AND query_history.uid IN (" + sharedWithUserSQL)
+	if query.TeamID > 0 {
+		params = append(params, query.TeamID)
+		sql.WriteString(" AND query_history_share.team_id = ?")
+	}
+	params = append(params, "%"+query.SearchString+"%", "%"+query.SearchString+"%")
+	sql.WriteString(") AND (query_history.queries " + sqlStore.GetDialect().LikeStr() + " ? OR query_history.comment " + sqlStore.GetDialect().LikeStr() + " ?) ")
+
+	if len(query.DatasourceUIDs) > 0 {
+		for _, uid := range query.DatasourceUIDs {
+			params = append(params, uid)
+		}
+		sql.WriteString(" AND query_history.datasource_uid IN (? " + strings.Repeat(",?", len(query.DatasourceUIDs)-1) + ") ")
+	}
+	builder.Write(sql.String(), params...)
+}
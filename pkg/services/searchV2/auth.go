@@ -7,7 +7,9 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -30,6 +32,7 @@ type simpleAuthService struct {
 
 func (a *simpleAuthService) GetDashboardReadFilter(ctx context.Context, orgID int64, user *user.SignedInUser) (ResourceFilter, error) {
 	canReadDashboard, canReadFolder := accesscontrol.Checker(user, dashboards.ActionDashboardsRead), accesscontrol.Checker(user, dashboards.ActionFoldersRead)
+	canReadAlertRule, canReadDatasource := accesscontrol.Checker(user, accesscontrol.ActionAlertingRuleRead), accesscontrol.Checker(user, datasources.ActionRead)
 	return func(kind entityKind, uid, parent string) bool {
 		if kind == entityKindFolder {
 			scopes, err := dashboards.GetInheritedScopes(ctx, orgID, uid, a.folderService)
@@ -46,6 +49,29 @@ func (a *simpleAuthService) GetDashboardReadFilter(ctx context.Context, orgID in
 			scopes = append(scopes, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(uid))
 			scopes = append(scopes, dashboards.ScopeFoldersProvider.GetResourceScopeUID(parent))
 			return canReadDashboard(scopes...)
+		} else if kind == entityKindAlertRule {
+			scopes, err := dashboards.GetInheritedScopes(ctx, orgID, parent, a.folderService)
+			if err != nil {
+				a.logger.Debug("could not retrieve inherited folder scopes:", "err", err)
+			}
+			scopes = append(scopes, dashboards.ScopeFoldersProvider.GetResourceScopeUID(parent))
+			return canReadAlertRule(scopes...)
+		} else if kind == entityKindLibraryPanel {
+			// the same as the library panels API, viewers can see the library panels of the General folder
+			if parent == folder.GeneralFolderUID {
+				return user.HasRole(org.RoleViewer)
+			}
+			scopes, err := dashboards.GetInheritedScopes(ctx, orgID, parent, a.folderService)
+			if err != nil {
+				a.logger.Debug("could not retrieve inherited folder scopes:", "err", err)
+			}
+			scopes = append(scopes, dashboards.ScopeFoldersProvider.GetResourceScopeUID(parent))
+			return canReadFolder(scopes...)
+		} else if kind == entityKindDatasource {
+			return canReadDatasource(datasources.ScopeProvider.GetResourceScopeUID(uid))
+		} else if kind == entityKindPlaylist {
+			// playlists are visible to all the members of the organization
+			return true
 		}
 		return false
 	}, nil
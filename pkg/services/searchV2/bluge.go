@@ -33,11 +33,14 @@ const (
 	documentFieldTransformer = "transformer"
 	documentFieldDSUID       = "ds_uid"
 	documentFieldDSType      = "ds_type"
+	documentFieldDescription = "description"
+	documentFieldQuery       = "query"
+	documentFieldQuery_ngram = "query_ngram"
 	DocumentFieldCreatedAt   = "created_at"
 	DocumentFieldUpdatedAt   = "updated_at"
 )
 
-func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
+func initOrgIndex(dashboards []dashboard, entities []searchEntity, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
 	dashboardWriter, err := bluge.OpenWriter(bluge.InMemoryOnlyConfig())
 	if err != nil {
 		return nil, fmt.Errorf("error opening writer: %v", err)
@@ -120,6 +123,14 @@ func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDas
 		}
 	}
 
+	// Then the alert rules, library panels and other entities.
+	for _, e := range entities {
+		batch.Insert(getEntityDoc(e))
+		if err := flushIfRequired(false); err != nil {
+			return nil, err
+		}
+	}
+
 	// Flush docs in batch with force as we are in the end.
 	if err := flushIfRequired(true); err != nil {
 		return nil, err
@@ -207,6 +218,8 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 			AddField(bluge.NewKeywordField(documentFieldLocation, location).Aggregatable().StoreValue()).
 			AddField(bluge.NewKeywordField(documentFieldKind, string(entityKindPanel)).Aggregatable().StoreValue()) // likely want independent index for this
 
+		addQueryFields(doc, getPanelQueries(panel))
+
 		for _, ref := range panel.References {
 			switch ref.Family {
 			case entity.StandardKindDashboard:
@@ -238,6 +251,32 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 	return docs
 }
 
+// getPanelQueries returns the query text of the panel targets, like PromQL or SQL
+func getPanelQueries(panel *entity.EntitySummary) []string {
+	switch v := panel.Fields["queries"].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		queries := make([]string, 0, len(v))
+		for _, q := range v {
+			if str, ok := q.(string); ok {
+				queries = append(queries, str)
+			}
+		}
+		return queries
+	}
+	return nil
+}
+
+// Queries are indexed with the standard analyzer to find the exact metric names, and with
+// ngrams to find the metrics by their prefix
+func addQueryFields(doc *bluge.Document, queries []string) {
+	for _, q := range queries {
+		doc.AddField(bluge.NewTextField(documentFieldQuery, q))
+		doc.AddField(bluge.NewTextField(documentFieldQuery_ngram, q).WithAnalyzer(ngramIndexAnalyzer))
+	}
+}
+
 // Names need to be indexed a few ways to support key features
 func newSearchDocument(uid string, name string, descr string, url string) *bluge.Document {
 	doc := bluge.NewDocument(uid)
@@ -398,6 +437,11 @@ func doSearchQuery(
 		}
 		fullQuery.AddMust(bq)
 		hasConstraints = true
+	} else {
+		// The other entities are only returned when asked for explicitly
+		for _, k := range indexedEntityKinds {
+			fullQuery.AddMustNot(bluge.NewTermQuery(string(k)).SetField(documentFieldKind))
+		}
 	}
 
 	// Explicit UID lookup (stars etc)
@@ -435,6 +479,12 @@ func doSearchQuery(
 		hasConstraints = true
 	}
 
+	// Query text of the panels and alert rules
+	if q.QueryText != "" {
+		fullQuery.AddMust(newQueryTextQuery(q.QueryText, 1))
+		hasConstraints = true
+	}
+
 	// Folder
 	if q.Location != "" {
 		fullQuery.AddMust(bluge.NewTermQuery(q.Location).SetField(documentFieldLocation))
@@ -460,6 +510,15 @@ func doSearchQuery(
 				SetAnalyzer(ngramQueryAnalyzer).SetBoost(1))
 		}
 
+		// The alert rule annotations and the library panel descriptions
+		bq.AddShould(bluge.NewMatchQuery(q.Query).
+			SetField(documentFieldDescription).
+			SetOperator(bluge.MatchQueryOperatorAnd).
+			SetBoost(0.5))
+
+		// The queries of the panels and alert rules
+		bq.AddShould(newQueryTextQuery(q.Query, 2))
+
 		fullQuery.AddMust(bq)
 	}
 
@@ -579,6 +638,9 @@ func doSearchQuery(
 			response.Error = err
 			return response
 		}
+		if e := entityKind(kind); e.isIndexedEntity() {
+			uid = getEntityUIDFromDocID(e, uid)
+		}
 
 		fKind.Append(kind)
 		fUID.Append(uid)
@@ -674,6 +736,30 @@ func doSearchQuery(
 	return response
 }
 
+// newQueryTextQuery matches the exact tokens of the queries, like the metric names, and their prefixes
+func newQueryTextQuery(text string, boost float64) bluge.Query {
+	bq := bluge.NewBooleanQuery()
+	bq.AddShould(bluge.NewMatchQuery(text).
+		SetField(documentFieldQuery).
+		SetOperator(bluge.MatchQueryOperatorAnd). // all terms must match
+		SetBoost(boost))
+	// the ngram analyzer splits the metric names on the punctuation, like the underscores
+	useNgram := true
+	for _, k := range strings.Fields(punctuationReplacer.Replace(text)) {
+		if len(k) > ngramEdgeFilterMaxLength {
+			useNgram = false
+		}
+	}
+	if useNgram {
+		bq.AddShould(bluge.NewMatchQuery(text).
+			SetField(documentFieldQuery_ngram).
+			SetOperator(bluge.MatchQueryOperatorAnd).
+			SetAnalyzer(ngramQueryAnalyzer).
+			SetBoost(boost / 4))
+	}
+	return bq
+}
+
 func shouldUseNgram(q DashboardQuery) bool {
 	var tokens []string
 	if len(q.Query) > ngramEdgeFilterMaxLength {
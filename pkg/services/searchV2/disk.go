@@ -0,0 +1,226 @@
+package searchV2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/blugelabs/bluge"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// indexFormatVersion must be increased when the indexed documents change, the persisted
+// indexes of another version are built again from scratch.
+const indexFormatVersion = 1
+
+// The entity events are deleted after 24 hours, an older index can not be caught up.
+const maxCheckpointAge = 23 * time.Hour
+
+const (
+	checkpointFileName = "checkpoint.json"
+	snapshotDirName    = "snapshot"
+	liveDirName        = "live"
+)
+
+var errNoPersistedIndex = errors.New("no persisted index")
+
+// indexCheckpoint describes the persisted snapshot of an org index
+type indexCheckpoint struct {
+	Version     int       `json:"version"`
+	LastEventID int64     `json:"lastEventId"` // events after this one are not in the snapshot
+	Created     time.Time `json:"created"`
+}
+
+// diskIndexStore persists the org indexes, every org has a directory with:
+//   - snapshot: a backup of the index taken when the checkpoint was written
+//   - checkpoint.json: the checkpoint of the snapshot
+//   - live: a copy of the snapshot the loaded index writes to, it is discarded on the next startup
+type diskIndexStore struct {
+	path   string
+	logger log.Logger
+	now    func() time.Time
+}
+
+func newDiskIndexStore(path string, logger log.Logger) *diskIndexStore {
+	return &diskIndexStore{path: path, logger: logger, now: time.Now}
+}
+
+func (s *diskIndexStore) orgDir(orgID int64) string {
+	return filepath.Join(s.path, fmt.Sprintf("org_%d", orgID))
+}
+
+// load opens the persisted index of the org and returns the ID of the last event it contains.
+// The index is not loaded when its version does not match, it is too old to be caught up with
+// the entity events or it is ahead of them, like after a database restore.
+func (s *diskIndexStore) load(orgID int64, lastEventID int64) (*orgIndex, int64, error) {
+	dir := s.orgDir(orgID)
+
+	checkpoint, err := readCheckpoint(filepath.Join(dir, checkpointFileName))
+	if err != nil {
+		return nil, 0, err
+	}
+	if checkpoint.Version != indexFormatVersion {
+		return nil, 0, fmt.Errorf("index version %d does not match %d", checkpoint.Version, indexFormatVersion)
+	}
+	if age := s.now().Sub(checkpoint.Created); age > maxCheckpointAge {
+		return nil, 0, fmt.Errorf("index is too old: %s", age)
+	}
+	if checkpoint.LastEventID > lastEventID {
+		return nil, 0, fmt.Errorf("index is ahead of the entity events: %d > %d", checkpoint.LastEventID, lastEventID)
+	}
+
+	// The snapshot stays untouched, so that the checkpoint remains valid if we stop before the next one.
+	live := filepath.Join(dir, liveDirName)
+	if err := os.RemoveAll(live); err != nil {
+		return nil, 0, err
+	}
+	if err := copyDir(filepath.Join(dir, snapshotDirName), live); err != nil {
+		return nil, 0, fmt.Errorf("error copying snapshot: %w", err)
+	}
+
+	writer, err := bluge.OpenWriter(bluge.DefaultConfig(live))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error opening index: %w", err)
+	}
+	index := &orgIndex{
+		writers: map[indexType]*bluge.Writer{
+			indexTypeDashboard: writer,
+		},
+	}
+
+	// Reading the index detects most corruptions.
+	reader, cancel, err := index.readerForIndex(indexTypeDashboard)
+	if err != nil {
+		_ = writer.Close()
+		return nil, 0, fmt.Errorf("error reading index: %w", err)
+	}
+	defer cancel()
+	if _, err := reader.Count(); err != nil {
+		_ = writer.Close()
+		return nil, 0, fmt.Errorf("error reading index: %w", err)
+	}
+
+	return index, checkpoint.LastEventID, nil
+}
+
+// save replaces the snapshot of the org with the current state of the index, which must
+// contain all the events up to lastEventID.
+func (s *diskIndexStore) save(orgID int64, index *orgIndex, lastEventID int64) error {
+	dir := s.orgDir(orgID)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	reader, cancel, err := index.readerForIndex(indexTypeDashboard)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	tmp := filepath.Join(dir, snapshotDirName+".tmp")
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	// the backup writes the segment files to an existing directory
+	if err := os.MkdirAll(tmp, 0750); err != nil {
+		return err
+	}
+	if err := reader.Backup(tmp, make(chan struct{})); err != nil {
+		return fmt.Errorf("error taking index backup: %w", err)
+	}
+
+	// Remove the checkpoint first, a missing checkpoint rebuilds the index
+	// if we stop while the snapshot is replaced.
+	checkpointPath := filepath.Join(dir, checkpointFileName)
+	if err := os.Remove(checkpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	snapshot := filepath.Join(dir, snapshotDirName)
+	if err := os.RemoveAll(snapshot); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, snapshot); err != nil {
+		return err
+	}
+	return writeCheckpoint(checkpointPath, indexCheckpoint{
+		Version:     indexFormatVersion,
+		LastEventID: lastEventID,
+		Created:     s.now(),
+	})
+}
+
+// remove deletes the persisted index of the org, so that it is built from scratch on the next startup
+func (s *diskIndexStore) remove(orgID int64) error {
+	return os.RemoveAll(s.orgDir(orgID))
+}
+
+func readCheckpoint(path string) (indexCheckpoint, error) {
+	checkpoint := indexCheckpoint{}
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is built from the configuration
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return checkpoint, errNoPersistedIndex
+		}
+		return checkpoint, err
+	}
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return checkpoint, fmt.Errorf("error reading checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+func writeCheckpoint(path string, checkpoint indexCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func copyDir(src string, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0750)
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src string, dst string) error {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is built from the configuration
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,92 @@
+package searchV2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/store/entity"
+)
+
+func newTestDiskIndexStore(t *testing.T) *diskIndexStore {
+	t.Helper()
+	return newDiskIndexStore(t.TempDir(), testLogger)
+}
+
+func TestDiskIndexStore_SaveLoad(t *testing.T) {
+	disk := newTestDiskIndexStore(t)
+	index := initTestOrgIndexFromDashes(t, dashboardsWithTitles("Persisted dashboard", "Another one"))
+	require.NoError(t, disk.save(testOrgID, index, 10))
+
+	loaded, lastEventID, err := disk.load(testOrgID, 12)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), lastEventID)
+
+	frame := searchTestEntities(t, loaded, testAllowAllFilter, DashboardQuery{Query: "persisted"})
+	require.Equal(t, 1, frame.Rows())
+
+	// The loaded index is writable and does not change the snapshot.
+	doc := getNonFolderDashboardDoc(dashboard{id: 3, uid: "third", summary: &entity.EntitySummary{Name: "Third"}}, "general")
+	writer := loaded.writerForIndex(indexTypeDashboard)
+	require.NoError(t, writer.Update(doc.ID(), doc))
+	require.NoError(t, writer.Close())
+
+	reloaded, _, err := disk.load(testOrgID, 12)
+	require.NoError(t, err)
+	frame = searchTestEntities(t, reloaded, testAllowAllFilter, DashboardQuery{Query: "third"})
+	require.Equal(t, 0, frame.Rows())
+}
+
+func TestDiskIndexStore_LoadInvalid(t *testing.T) {
+	t.Run("not persisted", func(t *testing.T) {
+		disk := newTestDiskIndexStore(t)
+		_, _, err := disk.load(testOrgID, 1)
+		require.ErrorIs(t, err, errNoPersistedIndex)
+	})
+
+	t.Run("version mismatch", func(t *testing.T) {
+		disk := newTestDiskIndexStore(t)
+		require.NoError(t, disk.save(testOrgID, initTestOrgIndexFromDashes(t, dashboardsWithTitles("A")), 1))
+		require.NoError(t, writeCheckpoint(filepath.Join(disk.orgDir(testOrgID), checkpointFileName), indexCheckpoint{
+			Version:     indexFormatVersion + 1,
+			LastEventID: 1,
+			Created:     time.Now(),
+		}))
+		_, _, err := disk.load(testOrgID, 1)
+		require.ErrorContains(t, err, "version")
+	})
+
+	t.Run("checkpoint older than the entity events", func(t *testing.T) {
+		disk := newTestDiskIndexStore(t)
+		require.NoError(t, disk.save(testOrgID, initTestOrgIndexFromDashes(t, dashboardsWithTitles("A")), 1))
+		disk.now = func() time.Time { return time.Now().Add(maxCheckpointAge + time.Hour) }
+		_, _, err := disk.load(testOrgID, 1)
+		require.ErrorContains(t, err, "too old")
+	})
+
+	t.Run("checkpoint ahead of the entity events", func(t *testing.T) {
+		disk := newTestDiskIndexStore(t)
+		require.NoError(t, disk.save(testOrgID, initTestOrgIndexFromDashes(t, dashboardsWithTitles("A")), 5))
+		_, _, err := disk.load(testOrgID, 4)
+		require.ErrorContains(t, err, "ahead")
+	})
+
+	t.Run("missing snapshot", func(t *testing.T) {
+		disk := newTestDiskIndexStore(t)
+		require.NoError(t, disk.save(testOrgID, initTestOrgIndexFromDashes(t, dashboardsWithTitles("A")), 1))
+		require.NoError(t, os.RemoveAll(filepath.Join(disk.orgDir(testOrgID), snapshotDirName)))
+		_, _, err := disk.load(testOrgID, 1)
+		require.Error(t, err)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		disk := newTestDiskIndexStore(t)
+		require.NoError(t, disk.save(testOrgID, initTestOrgIndexFromDashes(t, dashboardsWithTitles("A")), 1))
+		require.NoError(t, disk.remove(testOrgID))
+		_, _, err := disk.load(testOrgID, 1)
+		require.ErrorIs(t, err, errNoPersistedIndex)
+	})
+}
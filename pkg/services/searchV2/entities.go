@@ -0,0 +1,339 @@
+package searchV2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blugelabs/bluge"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/store"
+	kdash "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
+)
+
+// The kinds indexed next to the dashboards, folders and panels
+var indexedEntityKinds = []entityKind{
+	entityKindAlertRule,
+	entityKindLibraryPanel,
+	entityKindDatasource,
+	entityKindPlaylist,
+}
+
+// The entity types of the entity events mapped to the indexed kinds
+var entityEventKinds = map[store.EntityType]entityKind{
+	store.EntityTypeAlertRule:    entityKindAlertRule,
+	store.EntityTypeLibraryPanel: entityKindLibraryPanel,
+	store.EntityTypeDatasource:   entityKindDatasource,
+	store.EntityTypePlaylist:     entityKindPlaylist,
+}
+
+type entityLoader interface {
+	// LoadEntities returns the entities of a kind. If uid is empty – then implementation
+	// must return all the entities of the kind in the organization. If uid is not empty –
+	// then only return the entity with specified UID or empty slice if not found.
+	LoadEntities(ctx context.Context, orgID int64, kind entityKind, uid string) ([]searchEntity, error)
+}
+
+// searchEntity is an entity stored outside the dashboard table
+type searchEntity struct {
+	kind        entityKind
+	uid         string
+	name        string
+	description string
+	url         string
+	location    string // the folder UID, empty for entities outside folders
+	tags        []string
+	panelType   string
+	dsUIDs      []string
+	dsType      string
+	queries     []string
+	created     time.Time
+	updated     time.Time
+}
+
+// UIDs are only unique per kind, so the entity documents are prefixed with their kind
+// to avoid replacing a dashboard with a datasource of the same UID.
+func getEntityDocID(kind entityKind, uid string) string {
+	return string(kind) + "/" + uid
+}
+
+func getEntityUIDFromDocID(kind entityKind, id string) string {
+	return strings.TrimPrefix(id, string(kind)+"/")
+}
+
+func (r entityKind) isIndexedEntity() bool {
+	for _, k := range indexedEntityKinds {
+		if r == k {
+			return true
+		}
+	}
+	return false
+}
+
+func getEntityDoc(e searchEntity) *bluge.Document {
+	doc := newSearchDocument(getEntityDocID(e.kind, e.uid), e.name, e.description, e.url).
+		AddField(bluge.NewKeywordField(documentFieldKind, string(e.kind)).Aggregatable().StoreValue())
+
+	if e.location != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldLocation, e.location).Aggregatable().StoreValue())
+	}
+	if e.description != "" {
+		doc.AddField(bluge.NewTextField(documentFieldDescription, e.description))
+	}
+	if !e.created.IsZero() {
+		doc.AddField(bluge.NewDateTimeField(DocumentFieldCreatedAt, e.created).Sortable().StoreValue())
+	}
+	if !e.updated.IsZero() {
+		doc.AddField(bluge.NewDateTimeField(DocumentFieldUpdatedAt, e.updated).Sortable().StoreValue())
+	}
+	for _, tag := range e.tags {
+		doc.AddField(bluge.NewKeywordField(documentFieldTag, tag).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	if e.panelType != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldPanelType, e.panelType).Aggregatable().StoreValue())
+	}
+	for _, uid := range e.dsUIDs {
+		doc.AddField(bluge.NewKeywordField(documentFieldDSUID, uid).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	if e.dsType != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldDSType, e.dsType).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	addQueryFields(doc, e.queries)
+	return doc
+}
+
+type sqlEntityLoader struct {
+	sql    db.DB
+	tracer tracing.Tracer
+}
+
+func newSQLEntityLoader(sql db.DB, tracer tracing.Tracer) *sqlEntityLoader {
+	return &sqlEntityLoader{sql: sql, tracer: tracer}
+}
+
+func (l sqlEntityLoader) LoadEntities(ctx context.Context, orgID int64, kind entityKind, uid string) ([]searchEntity, error) {
+	ctx, span := l.tracer.Start(ctx, "sqlEntityLoader LoadEntities")
+	span.SetAttributes("orgID", orgID, attribute.Key("orgID").Int64(orgID))
+	span.SetAttributes("kind", kind, attribute.Key("kind").String(string(kind)))
+	defer span.End()
+
+	var entities []searchEntity
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		switch kind {
+		case entityKindAlertRule:
+			entities, err = loadAlertRules(sess, orgID, uid)
+		case entityKindLibraryPanel:
+			entities, err = loadLibraryPanels(sess, orgID, uid)
+		case entityKindDatasource:
+			entities, err = loadDatasources(sess, orgID, uid)
+		case entityKindPlaylist:
+			entities, err = loadPlaylists(sess, orgID, uid)
+		default:
+			err = fmt.Errorf("unsupported entity kind: %s", kind)
+		}
+		return err
+	})
+	return entities, err
+}
+
+type alertRuleQueryResult struct {
+	UID          string `xorm:"uid"`
+	Title        string
+	NamespaceUID string `xorm:"namespace_uid"`
+	RuleGroup    string `xorm:"rule_group"`
+	Labels       string
+	Annotations  string
+	Data         string
+	Updated      time.Time
+}
+
+func loadAlertRules(sess *db.Session, orgID int64, uid string) ([]searchEntity, error) {
+	rows := make([]*alertRuleQueryResult, 0)
+	sess.Table("alert_rule").Where("org_id = ?", orgID)
+	if uid != "" {
+		sess.Where("uid = ?", uid)
+	}
+	err := sess.Cols("uid", "title", "namespace_uid", "rule_group", "labels", "annotations", "data", "updated").Find(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]searchEntity, 0, len(rows))
+	for _, row := range rows {
+		e := searchEntity{
+			kind:     entityKindAlertRule,
+			uid:      row.UID,
+			name:     row.Title,
+			url:      fmt.Sprintf("/alerting/grafana/%s/view", row.UID),
+			location: row.NamespaceUID,
+			created:  row.Updated, // the rules do not keep the creation time
+			updated:  row.Updated,
+		}
+
+		// labels are indexed as key=value tags, the annotations like the summary as the description
+		labels := map[string]string{}
+		if row.Labels != "" {
+			_ = json.Unmarshal([]byte(row.Labels), &labels)
+		}
+		for k, v := range labels {
+			e.tags = append(e.tags, k+"="+v)
+		}
+		sort.Strings(e.tags)
+
+		annotations := map[string]string{}
+		if row.Annotations != "" {
+			_ = json.Unmarshal([]byte(row.Annotations), &annotations)
+		}
+		keys := make([]string, 0, len(annotations))
+		for k := range annotations {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		descr := []string{row.RuleGroup}
+		for _, k := range keys {
+			descr = append(descr, annotations[k])
+		}
+		e.description = strings.Join(descr, " ")
+
+		var queries []struct {
+			DatasourceUID string                 `json:"datasourceUid"`
+			Model         map[string]interface{} `json:"model"`
+		}
+		if row.Data != "" {
+			_ = json.Unmarshal([]byte(row.Data), &queries)
+		}
+		for _, q := range queries {
+			// skip the expressions
+			if q.DatasourceUID != "" && q.DatasourceUID != "__expr__" && q.DatasourceUID != "-100" {
+				e.dsUIDs = append(e.dsUIDs, q.DatasourceUID)
+			}
+			e.queries = append(e.queries, kdash.GetQueryText(q.Model)...)
+		}
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+type libraryPanelQueryResult struct {
+	UID         string `xorm:"uid"`
+	Name        string
+	Description string
+	Type        string
+	FolderUID   string `xorm:"folder_uid"`
+	Created     time.Time
+	Updated     time.Time
+}
+
+func loadLibraryPanels(sess *db.Session, orgID int64, uid string) ([]searchEntity, error) {
+	rows := make([]*libraryPanelQueryResult, 0)
+	sql := `SELECT le.uid, le.name, le.description, le.type, COALESCE(f.uid, '') AS folder_uid, le.created, le.updated
+FROM library_element AS le
+LEFT JOIN dashboard AS f ON f.id = le.folder_id
+WHERE le.org_id = ? AND le.kind = ?`
+	args := []interface{}{orgID, model.PanelElement}
+	if uid != "" {
+		sql += " AND le.uid = ?"
+		args = append(args, uid)
+	}
+	if err := sess.SQL(sql, args...).Find(&rows); err != nil {
+		return nil, err
+	}
+
+	entities := make([]searchEntity, 0, len(rows))
+	for _, row := range rows {
+		location := row.FolderUID
+		if location == "" {
+			location = folder.GeneralFolderUID
+		}
+		entities = append(entities, searchEntity{
+			kind:        entityKindLibraryPanel,
+			uid:         row.UID,
+			name:        row.Name,
+			description: row.Description,
+			url:         "/library-panels",
+			location:    location,
+			panelType:   row.Type,
+			created:     row.Created,
+			updated:     row.Updated,
+		})
+	}
+	return entities, nil
+}
+
+type datasourceQueryResult struct {
+	UID     string `xorm:"uid"`
+	Name    string
+	Type    string
+	Created time.Time
+	Updated time.Time
+}
+
+func loadDatasources(sess *db.Session, orgID int64, uid string) ([]searchEntity, error) {
+	rows := make([]*datasourceQueryResult, 0)
+	sess.Table("data_source").Where("org_id = ?", orgID)
+	if uid != "" {
+		sess.Where("uid = ?", uid)
+	}
+	if err := sess.Cols("uid", "name", "type", "created", "updated").Find(&rows); err != nil {
+		return nil, err
+	}
+
+	entities := make([]searchEntity, 0, len(rows))
+	for _, row := range rows {
+		entities = append(entities, searchEntity{
+			kind:    entityKindDatasource,
+			uid:     row.UID,
+			name:    row.Name,
+			url:     fmt.Sprintf("/datasources/edit/%s", row.UID),
+			dsType:  row.Type,
+			created: row.Created,
+			updated: row.Updated,
+		})
+	}
+	return entities, nil
+}
+
+type playlistQueryResult struct {
+	UID  string `xorm:"uid"`
+	Name string
+}
+
+func loadPlaylists(sess *db.Session, orgID int64, uid string) ([]searchEntity, error) {
+	rows := make([]*playlistQueryResult, 0)
+	sess.Table("playlist").Where("org_id = ?", orgID)
+	if uid != "" {
+		sess.Where("uid = ?", uid)
+	}
+	if err := sess.Cols("uid", "name").Find(&rows); err != nil {
+		return nil, err
+	}
+
+	entities := make([]searchEntity, 0, len(rows))
+	for _, row := range rows {
+		entities = append(entities, searchEntity{
+			kind: entityKindPlaylist,
+			uid:  row.UID,
+			name: row.Name,
+			url:  fmt.Sprintf("/playlists/play/%s", row.UID),
+		})
+	}
+	return entities, nil
+}
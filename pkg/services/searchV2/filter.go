@@ -21,19 +21,22 @@ type PermissionFilter struct {
 type entityKind string
 
 const (
-	entityKindPanel      entityKind = entity.StandardKindPanel
-	entityKindDashboard  entityKind = entity.StandardKindDashboard
-	entityKindFolder     entityKind = entity.StandardKindFolder
-	entityKindDatasource entityKind = entity.StandardKindDataSource
-	entityKindQuery      entityKind = entity.StandardKindQuery
+	entityKindPanel        entityKind = entity.StandardKindPanel
+	entityKindDashboard    entityKind = entity.StandardKindDashboard
+	entityKindFolder       entityKind = entity.StandardKindFolder
+	entityKindDatasource   entityKind = entity.StandardKindDataSource
+	entityKindQuery        entityKind = entity.StandardKindQuery
+	entityKindAlertRule    entityKind = entity.StandardKindAlertRule
+	entityKindLibraryPanel entityKind = entity.StandardKindLibraryPanel
+	entityKindPlaylist     entityKind = entity.StandardKindPlaylist
 )
 
 func (r entityKind) IsValid() bool {
-	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder
+	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder || r.isIndexedEntity()
 }
 
 func (r entityKind) supportsAuthzCheck() bool {
-	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder
+	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder || r.isIndexedEntity()
 }
 
 var (
@@ -98,6 +101,11 @@ func (q *PermissionFilter) canAccess(kind entityKind, id, location string) bool
 		decision := q.filter(entityKindDashboard, dashboardUid, folderUid)
 		q.logAccessDecision(decision, kind, id, "resourceFilter", "folderUid", folderUid, "dashboardUid", dashboardUid, "panelId", matches[panelIdFieldPanelIdSubmatchIndex])
 		return decision
+	case entityKindAlertRule, entityKindLibraryPanel, entityKindDatasource, entityKindPlaylist:
+		// Location is the folder UID of the rules and library panels
+		decision := q.filter(kind, getEntityUIDFromDocID(kind, id), location)
+		q.logAccessDecision(decision, kind, id, "resourceFilter")
+		return decision
 	default:
 		q.logAccessDecision(false, kind, id, "reason", "unknownKind")
 		return false
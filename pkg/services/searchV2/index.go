@@ -90,6 +90,7 @@ func (i *orgIndex) readerForIndex(idxType indexType) (*bluge.Reader, func(), err
 type searchIndex struct {
 	mu                      sync.RWMutex
 	loader                  dashboardLoader
+	entityLoader            entityLoader
 	perOrgIndex             map[int64]*orgIndex
 	initializedOrgs         map[int64]bool
 	initialIndexingComplete bool
@@ -103,15 +104,22 @@ type searchIndex struct {
 	tracer                  tracing.Tracer
 	features                featuremgmt.FeatureToggles
 	settings                setting.SearchSettings
+	disk                    *diskIndexStore // nil when the index is only kept in memory
 }
 
-func newSearchIndex(dashLoader dashboardLoader, evStore eventStore, extender DocumentExtender, folderIDs folderUIDLookup, tracer tracing.Tracer, features featuremgmt.FeatureToggles, settings setting.SearchSettings) *searchIndex {
+func newSearchIndex(dashLoader dashboardLoader, entLoader entityLoader, evStore eventStore, extender DocumentExtender, folderIDs folderUIDLookup, tracer tracing.Tracer, features featuremgmt.FeatureToggles, settings setting.SearchSettings) *searchIndex {
+	logger := log.New("searchIndex")
+	var disk *diskIndexStore
+	if settings.IndexPath != "" {
+		disk = newDiskIndexStore(settings.IndexPath, logger)
+	}
 	return &searchIndex{
 		loader:          dashLoader,
+		entityLoader:    entLoader,
 		eventStore:      evStore,
 		perOrgIndex:     map[int64]*orgIndex{},
 		initializedOrgs: map[int64]bool{},
-		logger:          log.New("searchIndex"),
+		logger:          logger,
 		buildSignals:    make(chan buildSignal),
 		extender:        extender,
 		folderIdLookup:  folderIDs,
@@ -119,6 +127,7 @@ func newSearchIndex(dashLoader dashboardLoader, evStore eventStore, extender Doc
 		tracer:          tracer,
 		features:        features,
 		settings:        settings,
+		disk:            disk,
 	}
 }
 
@@ -182,7 +191,7 @@ func (i *searchIndex) sync(ctx context.Context) error {
 }
 
 func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh chan struct{}) error {
-	i.logger.Info("Initializing SearchV2", "dashboardLoadingBatchSize", i.settings.DashboardLoadingBatchSize, "fullReindexInterval", i.settings.FullReindexInterval, "indexUpdateInterval", i.settings.IndexUpdateInterval)
+	i.logger.Info("Initializing SearchV2", "dashboardLoadingBatchSize", i.settings.DashboardLoadingBatchSize, "fullReindexInterval", i.settings.FullReindexInterval, "indexUpdateInterval", i.settings.IndexUpdateInterval, "indexPath", i.settings.IndexPath)
 	initialSetupCtx, initialSetupSpan := i.tracer.Start(ctx, "searchV2 initialSetup")
 
 	reIndexInterval := i.settings.FullReindexInterval
@@ -203,11 +212,15 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 		lastEventID = lastEvent.Id
 	}
 
-	err = i.buildInitialIndexes(initialSetupCtx, orgIDs)
+	lastEventID, err = i.buildInitialIndexes(initialSetupCtx, orgIDs, lastEventID)
 	if err != nil {
 		initialSetupSpan.End()
 		return err
 	}
+	if lastEvent != nil && lastEventID < lastEvent.Id {
+		// The persisted indexes are behind the last event, catch them up before serving searches.
+		lastEventID = i.applyIndexUpdates(initialSetupCtx, lastEventID)
+	}
 
 	// This semaphore channel allows limiting concurrent async re-indexing routines to 1.
 	asyncReIndexSemaphore := make(chan struct{}, 1)
@@ -262,6 +275,9 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 				asyncReIndexSemaphore <- struct{}{}
 				defer func() { <-asyncReIndexSemaphore }()
 				_, err = i.buildOrgIndex(buildSignalCtx, signal.orgID)
+				if err == nil {
+					i.saveOrgIndex(signal.orgID, lastIndexedEventID)
+				}
 				signal.done <- err
 				reIndexDoneCh <- lastIndexedEventID
 			}()
@@ -287,6 +303,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 				i.logger.Info("Start re-indexing", i.withCtxData(fullReindexCtx)...)
 				i.reIndexFromScratch(fullReindexCtx)
 				i.logger.Info("Full re-indexing finished", i.withCtxData(fullReindexCtx, "fullReIndexElapsed", time.Since(started))...)
+				i.saveIndexes(lastIndexedEventID)
 				reIndexDoneCh <- lastIndexedEventID
 			}()
 		case lastIndexedEventID := <-reIndexDoneCh:
@@ -301,22 +318,105 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 			}
 			fullReIndexTimer.Reset(reIndexInterval)
 		case <-ctx.Done():
+			// Keep the events applied since the last full re-indexing for the next startup.
+			select {
+			case asyncReIndexSemaphore <- struct{}{}:
+				i.saveIndexes(lastEventID)
+			default:
+				// The indexes are being replaced, the previous snapshots are kept.
+			}
 			return ctx.Err()
 		}
 	}
 }
 
-func (i *searchIndex) buildInitialIndexes(ctx context.Context, orgIDs []int64) error {
+// buildInitialIndexes builds the indexes of the orgs, or loads them from the disk when persisted.
+// It returns the ID of the last event applied on every index.
+func (i *searchIndex) buildInitialIndexes(ctx context.Context, orgIDs []int64, lastEventID int64) (int64, error) {
 	started := time.Now()
 	i.logger.Info("Start building in-memory indexes")
+	appliedEventID := lastEventID
 	for _, orgID := range orgIDs {
+		if loadedEventID, ok := i.loadOrgIndex(orgID, lastEventID); ok {
+			if loadedEventID < appliedEventID {
+				appliedEventID = loadedEventID
+			}
+			continue
+		}
 		err := i.buildInitialIndex(ctx, orgID)
 		if err != nil {
-			return fmt.Errorf("can't build initial dashboard search index for org %d: %w", orgID, err)
+			return 0, fmt.Errorf("can't build initial dashboard search index for org %d: %w", orgID, err)
 		}
+		i.saveOrgIndex(orgID, lastEventID)
 	}
 	i.logger.Info("Finish building in-memory indexes", "elapsed", time.Since(started))
-	return nil
+	return appliedEventID, nil
+}
+
+// loadOrgIndex loads the persisted index of the org, it returns false when the index must be built from scratch
+func (i *searchIndex) loadOrgIndex(orgID int64, lastEventID int64) (int64, bool) {
+	if i.disk == nil {
+		return 0, false
+	}
+
+	started := time.Now()
+	index, loadedEventID, err := i.disk.load(orgID, lastEventID)
+	if err != nil {
+		if errors.Is(err, errNoPersistedIndex) {
+			i.logger.Info("No persisted index for org", "orgId", orgID)
+		} else {
+			i.logger.Warn("Can't load persisted index, building it from scratch", "orgId", orgID, "error", err)
+			if err := i.disk.remove(orgID); err != nil {
+				i.logger.Error("Can't remove persisted index", "orgId", orgID, "error", err)
+			}
+		}
+		return 0, false
+	}
+
+	i.mu.Lock()
+	i.perOrgIndex[orgID] = index
+	i.mu.Unlock()
+
+	i.initializationMutex.Lock()
+	i.initializedOrgs[orgID] = true
+	i.initializationMutex.Unlock()
+
+	i.logger.Info("Loaded persisted index for org", "orgId", orgID, "lastEventId", loadedEventID, "elapsed", time.Since(started))
+	return loadedEventID, true
+}
+
+// saveOrgIndex persists the index of the org, which must contain all the events up to lastEventID
+func (i *searchIndex) saveOrgIndex(orgID int64, lastEventID int64) {
+	if i.disk == nil {
+		return
+	}
+	index, ok := i.getOrgIndex(orgID)
+	if !ok {
+		return
+	}
+
+	started := time.Now()
+	if err := i.disk.save(orgID, index, lastEventID); err != nil {
+		i.logger.Error("Can't persist index", "orgId", orgID, "error", err)
+		return
+	}
+	i.logger.Debug("Persisted index", "orgId", orgID, "lastEventId", lastEventID, "elapsed", time.Since(started))
+}
+
+func (i *searchIndex) saveIndexes(lastEventID int64) {
+	if i.disk == nil {
+		return
+	}
+	i.mu.RLock()
+	orgIDs := make([]int64, 0, len(i.perOrgIndex))
+	for orgID := range i.perOrgIndex {
+		orgIDs = append(orgIDs, orgID)
+	}
+	i.mu.RUnlock()
+
+	for _, orgID := range orgIDs {
+		i.saveOrgIndex(orgID, lastEventID)
+	}
 }
 
 func (i *searchIndex) buildInitialIndex(ctx context.Context, orgID int64) error {
@@ -487,13 +587,24 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 	}
 	i.logger.Info("Finish loading org dashboards", "elapsed", orgSearchIndexLoadTime, "orgId", orgID)
 
+	var entities []searchEntity
+	for _, kind := range indexedEntityKinds {
+		kindEntities, err := i.entityLoader.LoadEntities(ctx, orgID, kind, "")
+		if err != nil {
+			return 0, fmt.Errorf("error loading %s entities: %w", kind, err)
+		}
+		entities = append(entities, kindEntities...)
+	}
+	orgSearchIndexLoadTime = time.Since(started)
+	i.logger.Info("Finish loading org entities", "elapsed", orgSearchIndexLoadTime, "orgId", orgID, "numEntities", len(entities))
+
 	dashboardExtender := i.extender.GetDashboardExtender(orgID)
 
 	_, initOrgIndexSpan := i.tracer.Start(ctx, "searchV2 buildOrgIndex init org index")
 	initOrgIndexSpan.SetAttributes("org_id", orgID, attribute.Key("org_id").Int64(orgID))
 	initOrgIndexSpan.SetAttributes("dashboardCount", len(dashboards), attribute.Key("dashboardCount").Int(len(dashboards)))
 
-	index, err := initOrgIndex(dashboards, i.logger, dashboardExtender)
+	index, err := initOrgIndex(dashboards, entities, i.logger, dashboardExtender)
 
 	initOrgIndexSpan.End()
 
@@ -508,7 +619,8 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 			"orgSearchIndexLoadTime", orgSearchIndexLoadTime,
 			"orgSearchIndexBuildTime", orgSearchIndexBuildTime,
 			"orgSearchIndexTotalTime", orgSearchIndexTotalTime,
-			"orgSearchDashboardCount", len(dashboards))...)
+			"orgSearchDashboardCount", len(dashboards),
+			"orgSearchEntityCount", len(entities))...)
 
 	i.mu.Lock()
 	if oldIndex, ok := i.perOrgIndex[orgID]; ok {
@@ -651,6 +763,10 @@ func (i *searchIndex) applyEvent(ctx context.Context, orgID int64, kind store.En
 	}
 	i.mu.Unlock()
 
+	if entKind, ok := entityEventKinds[kind]; ok {
+		return i.applyEntityEvent(ctx, orgID, entKind, uid)
+	}
+
 	// Both dashboard and folder share same DB table.
 	dbDashboards, err := i.loader.LoadDashboards(ctx, orgID, uid)
 	if err != nil {
@@ -685,6 +801,29 @@ func (i *searchIndex) applyEvent(ctx context.Context, orgID int64, kind store.En
 	return nil
 }
 
+func (i *searchIndex) applyEntityEvent(ctx context.Context, orgID int64, kind entityKind, uid string) error {
+	entities, err := i.entityLoader.LoadEntities(ctx, orgID, kind, uid)
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	index, ok := i.perOrgIndex[orgID]
+	if !ok {
+		// Skip event for org not yet fully indexed.
+		return nil
+	}
+
+	writer := index.writerForIndex(indexTypeDashboard)
+	if len(entities) == 0 {
+		return writer.Delete(bluge.NewDocument(getEntityDocID(kind, uid)).ID())
+	}
+	doc := getEntityDoc(entities[0])
+	return writer.Update(doc.ID(), doc)
+}
+
 func (i *searchIndex) removeDashboard(_ context.Context, index *orgIndex, dashboardUID string) error {
 	dashboardLocation, ok, err := getDashboardLocation(index, dashboardUID)
 	if err != nil {
@@ -28,6 +28,20 @@ func (t *testDashboardLoader) LoadDashboards(_ context.Context, _ int64, _ strin
 	return t.dashboards, nil
 }
 
+type testEntityLoader struct {
+	entities []searchEntity
+}
+
+func (t *testEntityLoader) LoadEntities(_ context.Context, _ int64, kind entityKind, uid string) ([]searchEntity, error) {
+	var entities []searchEntity
+	for _, e := range t.entities {
+		if e.kind == kind && (uid == "" || e.uid == uid) {
+			entities = append(entities, e)
+		}
+	}
+	return entities, nil
+}
+
 var testLogger = log.New("index-test-logger")
 
 var testAllowAllFilter = func(kind entityKind, uid, parent string) bool {
@@ -58,11 +72,16 @@ func initTestIndexFromDashes(t *testing.T, dashboards []dashboard) *searchIndex
 }
 
 func initTestIndexFromDashesExtended(t *testing.T, dashboards []dashboard, extender DocumentExtender) *searchIndex {
+	t.Helper()
+	return initTestIndexFromEntities(t, dashboards, &testEntityLoader{}, extender)
+}
+
+func initTestIndexFromEntities(t *testing.T, dashboards []dashboard, entityLoader *testEntityLoader, extender DocumentExtender) *searchIndex {
 	t.Helper()
 	dashboardLoader := &testDashboardLoader{
 		dashboards: dashboards,
 	}
-	index := newSearchIndex(dashboardLoader, &store.MockEntityEventsService{}, extender, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{})
+	index := newSearchIndex(dashboardLoader, entityLoader, &store.MockEntityEventsService{}, extender, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{})
 	require.NotNil(t, index)
 	numDashboards, err := index.buildOrgIndex(context.Background(), testOrgID)
 	require.NoError(t, err)
@@ -731,3 +750,191 @@ func TestDashboardIndex_MultiTermPrefixMatch(t *testing.T) {
 		})
 	}
 }
+
+var testEntities = []searchEntity{
+	{
+		kind:        entityKindAlertRule,
+		uid:         "1",
+		name:        "High CPU usage",
+		description: "cpu The CPU usage is above 90%",
+		location:    "1",
+		tags:        []string{"severity=critical"},
+		dsUIDs:      []string{"prom"},
+	},
+	{
+		kind:     entityKindLibraryPanel,
+		uid:      "1",
+		name:     "CPU panel",
+		location: "general",
+	},
+	{
+		kind:   entityKindDatasource,
+		uid:    "prom",
+		name:   "Prometheus",
+		dsType: "prometheus",
+	},
+	{
+		kind: entityKindPlaylist,
+		uid:  "1",
+		name: "TV",
+	},
+}
+
+func searchTestEntities(t *testing.T, index *orgIndex, filter ResourceFilter, query DashboardQuery) *data.Frame {
+	t.Helper()
+	resp := doSearchQuery(context.Background(), testLogger, index, filter, query, &NoopQueryExtender{}, "")
+	require.NoError(t, resp.Error)
+	return resp.Frames[0]
+}
+
+func TestDashboardIndex_Entities(t *testing.T) {
+	t.Run("entities-indexed", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, dashboardsWithFolders, &testEntityLoader{entities: testEntities}, &NoopDocumentExtender{})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		frame := searchTestEntities(t, orgIdx, testAllowAllFilter,
+			DashboardQuery{Query: "cpu", Kind: []string{string(entityKindAlertRule), string(entityKindLibraryPanel)}})
+		require.Equal(t, 2, frame.Rows())
+		kinds := []string{frame.Fields[0].At(0).(string), frame.Fields[0].At(1).(string)}
+		require.ElementsMatch(t, []string{string(entityKindAlertRule), string(entityKindLibraryPanel)}, kinds)
+		require.Equal(t, "1", frame.Fields[1].At(0))
+		require.Equal(t, "1", frame.Fields[1].At(1))
+	})
+
+	t.Run("entities-not-returned-without-kind", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, dashboardsWithFolders, &testEntityLoader{entities: testEntities}, &NoopDocumentExtender{})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		frame := searchTestEntities(t, orgIdx, testAllowAllFilter, DashboardQuery{Query: "Prometheus"})
+		require.Equal(t, 0, frame.Rows())
+	})
+
+	t.Run("alert-rule-by-label-and-annotation", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, dashboardsWithFolders, &testEntityLoader{entities: testEntities}, &NoopDocumentExtender{})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		frame := searchTestEntities(t, orgIdx, testAllowAllFilter,
+			DashboardQuery{Tags: []string{"severity=critical"}, Kind: []string{string(entityKindAlertRule)}})
+		require.Equal(t, 1, frame.Rows())
+
+		frame = searchTestEntities(t, orgIdx, testAllowAllFilter,
+			DashboardQuery{Query: "above", Kind: []string{string(entityKindAlertRule)}})
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, "High CPU usage", frame.Fields[2].At(0))
+	})
+
+	t.Run("entities-filtered-by-permissions", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, dashboardsWithFolders, &testEntityLoader{entities: testEntities}, &NoopDocumentExtender{})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		checked := map[string]bool{}
+		filter := func(kind entityKind, uid, parent string) bool {
+			if kind.isIndexedEntity() {
+				checked[fmt.Sprintf("%s/%s/%s", kind, uid, parent)] = true
+			}
+			return kind == entityKindDatasource
+		}
+		frame := searchTestEntities(t, orgIdx, filter,
+			DashboardQuery{Kind: []string{string(entityKindAlertRule), string(entityKindDatasource)}})
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, "prom", frame.Fields[1].At(0))
+		// the filter gets the UIDs of the entities, not the document IDs
+		require.True(t, checked["alertrule/1/1"])
+		require.True(t, checked["ds/prom/"])
+	})
+
+	t.Run("entity-updates", func(t *testing.T) {
+		loader := &testEntityLoader{entities: testEntities}
+		index := initTestIndexFromEntities(t, dashboardsWithFolders, loader, &NoopDocumentExtender{})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		loader.entities = append([]searchEntity{}, testEntities[1:]...)
+		loader.entities[0].name = "Memory panel"
+		require.NoError(t, index.applyEvent(context.Background(), testOrgID, store.EntityTypeAlertRule, "1", store.EntityEventTypeDelete))
+		require.NoError(t, index.applyEvent(context.Background(), testOrgID, store.EntityTypeLibraryPanel, "1", store.EntityEventTypeUpdate))
+
+		frame := searchTestEntities(t, orgIdx, testAllowAllFilter,
+			DashboardQuery{Query: "cpu", Kind: []string{string(entityKindAlertRule), string(entityKindLibraryPanel)}})
+		require.Equal(t, 0, frame.Rows())
+		frame = searchTestEntities(t, orgIdx, testAllowAllFilter,
+			DashboardQuery{Query: "memory", Kind: []string{string(entityKindLibraryPanel)}})
+		require.Equal(t, 1, frame.Rows())
+
+		// the folder with the same UID is still there
+		frame = searchTestEntities(t, orgIdx, testAllowAllFilter,
+			DashboardQuery{UIDs: []string{"1"}, Kind: []string{string(entityKindFolder)}})
+		require.Equal(t, 1, frame.Rows())
+	})
+}
+
+var dashboardsWithPanelQueries = []dashboard{
+	{
+		id:  1,
+		uid: "1",
+		summary: &entity.EntitySummary{
+			Name: "API",
+			Nested: []*entity.EntitySummary{
+				{
+					Kind:   "panel",
+					UID:    "1#1",
+					Name:   "Requests",
+					Fields: map[string]interface{}{"queries": []string{`sum(rate(http_requests_total{job="api"}[5m]))`}},
+				},
+				{
+					Kind:   "panel",
+					UID:    "1#2",
+					Name:   "Orders",
+					Fields: map[string]interface{}{"queries": []string{"SELECT count(*) FROM orders"}},
+				},
+			},
+		},
+	},
+}
+
+func TestDashboardIndex_PanelQueries(t *testing.T) {
+	entities := &testEntityLoader{entities: []searchEntity{
+		{
+			kind:     entityKindAlertRule,
+			uid:      "1",
+			name:     "Too many requests",
+			location: "general",
+			queries:  []string{`rate(http_requests_total[1m]) > 100`},
+		},
+	}}
+
+	t.Run("panel-query-exact-token", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, dashboardsWithPanelQueries, entities, &NoopDocumentExtender{})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		frame := searchTestEntities(t, orgIdx, testAllowAllFilter,
+			DashboardQuery{Query: "http_requests_total", Kind: []string{string(entityKindPanel), string(entityKindAlertRule)}})
+		require.Equal(t, 2, frame.Rows())
+		uids := []string{frame.Fields[1].At(0).(string), frame.Fields[1].At(1).(string)}
+		require.ElementsMatch(t, []string{"1#1", "1"}, uids)
+	})
+
+	t.Run("panel-query-prefix", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, dashboardsWithPanelQueries, entities, &NoopDocumentExtender{})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		frame := searchTestEntities(t, orgIdx, testAllowAllFilter, DashboardQuery{QueryText: "order"})
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, "1#2", frame.Fields[1].At(0))
+	})
+
+	t.Run("query-text-ignores-panel-names", func(t *testing.T) {
+		index := initTestIndexFromEntities(t, dashboardsWithPanelQueries, entities, &NoopDocumentExtender{})
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+
+		frame := searchTestEntities(t, orgIdx, testAllowAllFilter, DashboardQuery{QueryText: "requests"})
+		require.Equal(t, 0, frame.Rows())
+	})
+}
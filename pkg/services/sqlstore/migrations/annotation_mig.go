@@ -183,6 +183,17 @@ func addAnnotationMig(mg *Migrator) {
 	mg.AddMigration("Increase tags column to length 4096", NewRawSQLMigration("").
 		Postgres("ALTER TABLE annotation ALTER COLUMN tags TYPE VARCHAR(4096);").
 		Mysql("ALTER TABLE annotation MODIFY tags VARCHAR(4096);"))
+
+	//
+	// Optimize tag filtered annotation queries and the clean-up job
+	//
+	mg.AddMigration("Add index for tag_id_annotation_id on annotation_tag table", NewAddIndexMigration(annotationTagTableV3, &Index{
+		Cols: []string{"tag_id", "annotation_id"}, Type: IndexType,
+	}))
+
+	mg.AddMigration("Add index for created on annotation table", NewAddIndexMigration(table, &Index{
+		Cols: []string{"created"}, Type: IndexType,
+	}))
 }
 
 type AddMakeRegionSingleRowMigration struct {
@@ -96,4 +96,35 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add exposed_panels column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "exposed_panels",
+		Type:     DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add allowed_variables column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "allowed_variables",
+		Type:     DB_Text,
+		Nullable: true,
+	}))
+
+	// hourly views and queries of each public dashboard
+	dashboardPublicUsageV1 := Table{
+		Name: "dashboard_public_usage",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "bucket", Type: DB_BigInt, Nullable: false},
+			{Name: "views", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "queries", Type: DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*Index{
+			{Cols: []string{"public_dashboard_uid", "bucket"}, Type: UniqueIndex},
+			{Cols: []string{"bucket"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public usage table v1", NewAddTableMigration(dashboardPublicUsageV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicUsageV1)
 }
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addDashboardVersionRetentionMigrations(mg *Migrator) {
+	dashboardVersionRetentionV1 := Table{
+		Name: "dashboard_version_retention",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "folder_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "max_versions", Type: DB_Int, Nullable: false, Default: "0"},
+			{Name: "max_age", Type: DB_NVarchar, Length: 20, Nullable: false, Default: "''"},
+			{Name: "keep_tagged", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "folder_uid"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create dashboard_version_retention table v1", NewAddTableMigration(dashboardVersionRetentionV1))
+
+	mg.AddMigration("add unique index dashboard_version_retention.org_id-folder_uid", NewAddIndexMigration(dashboardVersionRetentionV1, dashboardVersionRetentionV1.Indices[0]))
+}
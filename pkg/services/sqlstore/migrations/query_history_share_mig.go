@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addQueryHistoryShareMigrations(mg *Migrator) {
+	queryHistoryShareV1 := Table{
+		Name: "query_history_share",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "query_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "team_id", Type: DB_BigInt, Nullable: false},
+			{Name: "shared_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"query_uid", "team_id"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "team_id"}},
+		},
+	}
+
+	mg.AddMigration("create query_history_share table v1", NewAddTableMigration(queryHistoryShareV1))
+
+	mg.AddMigration("add unique index query_history_share.query_uid-team_id", NewAddIndexMigration(queryHistoryShareV1, queryHistoryShareV1.Indices[0]))
+
+	mg.AddMigration("add index query_history_share.org_id-team_id", NewAddIndexMigration(queryHistoryShareV1, queryHistoryShareV1.Indices[1]))
+}
@@ -23,10 +23,14 @@ const (
 type EntityType string
 
 const (
-	EntityTypeDashboard EntityType = "dashboard"
-	EntityTypeFolder    EntityType = "folder"
-	EntityTypeImage     EntityType = "image"
-	EntityTypeJSON      EntityType = "json"
+	EntityTypeDashboard    EntityType = "dashboard"
+	EntityTypeFolder       EntityType = "folder"
+	EntityTypeImage        EntityType = "image"
+	EntityTypeJSON         EntityType = "json"
+	EntityTypeAlertRule    EntityType = "alert-rule"
+	EntityTypeLibraryPanel EntityType = "library-panel"
+	EntityTypeDatasource   EntityType = "datasource"
+	EntityTypePlaylist     EntityType = "playlist"
 )
 
 // CreateDatabaseEntityId creates entityId for entities stored in the existing SQL tables
@@ -42,6 +46,15 @@ func CreateDatabaseEntityId(internalId interface{}, orgId int64, entityType Enti
 	return fmt.Sprintf("database/%d/%s/%s", orgId, entityType, internalIdAsString)
 }
 
+// NewDatabaseEntityEvent creates an event for an entity stored in the existing SQL tables
+func NewDatabaseEntityEvent(internalId interface{}, orgId int64, entityType EntityType, eventType EntityEventType) *EntityEvent {
+	return &EntityEvent{
+		EventType: eventType,
+		EntityId:  CreateDatabaseEntityId(internalId, orgId, entityType),
+		Created:   time.Now().Unix(),
+	}
+}
+
 type EntityEvent struct {
 	Id        int64
 	EventType EntityEventType
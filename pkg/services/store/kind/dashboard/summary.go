@@ -76,6 +76,9 @@ func NewStaticDashboardSummaryBuilder(lookup DatasourceLookup, sanitize bool) en
 			p.Description = panel.Description
 			p.Fields = make(map[string]interface{}, 0)
 			p.Fields["type"] = panel.Type
+			if len(panel.Queries) > 0 {
+				p.Fields["queries"] = panel.Queries
+			}
 
 			if panel.Type != "row" {
 				panelRefs.Add(entity.ExternalEntityReferencePlugin, string(plugins.TypePanel), panel.Type)
@@ -1,12 +1,39 @@
 package dashboard
 
 import (
+	"sort"
+	"strings"
+
 	jsoniter "github.com/json-iterator/go"
 )
 
+// The keys of the targets holding the query text, like PromQL, LogQL or SQL
+var targetQueryKeys = map[string]bool{
+	"expr":       true, // prometheus, loki
+	"rawSql":     true, // sql datasources
+	"query":      true, // influxdb, elasticsearch, tempo and many others
+	"target":     true, // graphite
+	"expression": true, // server side expressions, cloudwatch
+}
+
+// GetQueryText returns the query text of a target, or of the model of an alert rule query
+func GetQueryText(target map[string]interface{}) []string {
+	var queries []string
+	for key, v := range target {
+		if str, ok := v.(string); ok && targetQueryKeys[key] {
+			if q := strings.TrimSpace(str); q != "" {
+				queries = append(queries, q)
+			}
+		}
+	}
+	sort.Strings(queries)
+	return queries
+}
+
 type targetInfo struct {
-	lookup DatasourceLookup
-	uids   map[string]*DataSourceRef
+	lookup  DatasourceLookup
+	uids    map[string]*DataSourceRef
+	queries []string
 }
 
 func newTargetInfo(lookup DatasourceLookup) targetInfo {
@@ -76,6 +103,12 @@ func (s *targetInfo) addTarget(iter *jsoniter.Iterator) {
 			iter.Skip()
 
 		default:
+			if targetQueryKeys[l1Field] && iter.WhatIsNext() == jsoniter.StringValue {
+				if q := strings.TrimSpace(iter.ReadString()); q != "" {
+					s.queries = append(s.queries, q)
+				}
+				continue
+			}
 			v := iter.Read()
 			logf("[Panel.TARGET] %s=%v\n", l1Field, v)
 		}
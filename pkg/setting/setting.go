@@ -374,6 +374,7 @@ type Cfg struct {
 	// Annotations
 	AnnotationCleanupJobBatchSize      int64
 	AnnotationMaximumTagsLength        int64
+	AnnotationBulkMaxSize              int64
 	AlertingAnnotationCleanupSetting   AnnotationCleanupSettings
 	DashboardAnnotationCleanupSettings AnnotationCleanupSettings
 	APIAnnotationCleanupSettings       AnnotationCleanupSettings
@@ -504,6 +505,9 @@ type Cfg struct {
 	// Unified Alerting
 	UnifiedAlerting UnifiedAlertingSettings
 
+	// Query
+	SplitConcurrentQueryLimit int
+
 	// Query history
 	QueryHistoryEnabled bool
 
@@ -511,6 +515,8 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	PublicDashboards PublicDashboardsSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -717,6 +723,7 @@ func (cfg *Cfg) readAnnotationSettings() error {
 		cfg.Logger.Warn("[annotations.tags_length] is too low; the minimum allowed (500) is enforced")
 		cfg.AnnotationMaximumTagsLength = 500
 	}
+	cfg.AnnotationBulkMaxSize = section.Key("bulk_max_size").MustInt64(5000)
 
 	dashboardAnnotation := cfg.Raw.Section("annotations.dashboard")
 	apiIAnnotation := cfg.Raw.Section("annotations.api")
@@ -1125,6 +1132,9 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	news := iniFile.Section("news")
 	NewsFeedEnabled = news.Key("news_feed_enabled").MustBool(true)
 
+	query := iniFile.Section("query")
+	cfg.SplitConcurrentQueryLimit = query.Key("split_concurrent_query_limit").MustInt(4)
+
 	queryHistory := iniFile.Section("query_history")
 	cfg.QueryHistoryEnabled = queryHistory.Key("enabled").MustBool(true)
 
@@ -1169,7 +1179,11 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	cfg.readSqlDataSourceSettings()
 
 	cfg.Storage = readStorageSettings(iniFile)
-	cfg.Search = readSearchSettings(iniFile)
+	cfg.Search = readSearchSettings(iniFile, cfg.DataPath)
+	cfg.PublicDashboards, err = readPublicDashboardsSettings(iniFile)
+	if err != nil {
+		return err
+	}
 
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
 	if err != nil {
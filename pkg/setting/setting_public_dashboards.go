@@ -0,0 +1,42 @@
+package setting
+
+import (
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"gopkg.in/ini.v1"
+)
+
+type PublicDashboardsSettings struct {
+	// MaxTimeRange caps the time range of public dashboard queries, 0 does not cap it
+	MaxTimeRange time.Duration
+	// MaxRows caps the number of rows of each data frame returned to public dashboards, 0 does not cap it
+	MaxRows int
+	// RateLimitPerToken is the number of requests per minute allowed for each public dashboard, 0 does not limit it
+	RateLimitPerToken int
+	// RateLimitPerIP is the number of requests per minute allowed for each client IP address, 0 does not limit it
+	RateLimitPerIP int
+	// QueryCacheTTL is how long the query results of public dashboards are cached, 0 disables the cache
+	QueryCacheTTL time.Duration
+}
+
+func readPublicDashboardsSettings(iniFile *ini.File) (PublicDashboardsSettings, error) {
+	s := PublicDashboardsSettings{}
+
+	section := iniFile.Section("public_dashboards")
+	maxTimeRange, err := gtime.ParseDuration(valueAsString(section, "max_time_range", "0"))
+	if err != nil {
+		return s, err
+	}
+	s.MaxTimeRange = maxTimeRange
+	s.MaxRows = section.Key("max_rows").MustInt(0)
+	s.RateLimitPerToken = section.Key("rate_limit_per_token").MustInt(0)
+	s.RateLimitPerIP = section.Key("rate_limit_per_ip").MustInt(0)
+
+	queryCacheTTL, err := gtime.ParseDuration(valueAsString(section, "query_cache_ttl", "0"))
+	if err != nil {
+		return s, err
+	}
+	s.QueryCacheTTL = queryCacheTTL
+	return s, nil
+}
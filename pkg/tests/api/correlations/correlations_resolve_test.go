@@ -0,0 +1,120 @@
+package correlations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestIntegrationResolveCorrelation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := NewTestEnv(t)
+
+	adminUser := ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleAdmin),
+		Password:       "admin",
+		Login:          "admin",
+	})
+
+	viewerUser := ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleViewer),
+		Password:       "viewer",
+		Login:          "viewer",
+		OrgID:          adminUser.User.OrgID,
+	})
+
+	createDsCommand := &datasources.AddDataSourceCommand{
+		Name:  "loki",
+		Type:  "loki",
+		OrgID: 1,
+	}
+	dataSource := ctx.createDs(createDsCommand)
+	correlation := ctx.createCorrelation(correlations.CreateCorrelationCommand{
+		SourceUID: dataSource.UID,
+		TargetUID: &dataSource.UID,
+		OrgId:     dataSource.OrgID,
+		Config: correlations.CorrelationConfig{
+			Type:   correlations.ConfigTypeQuery,
+			Field:  "message",
+			Target: map[string]interface{}{"expr": `{app="${app}"} |= "${traceID}"`},
+			Transformations: []correlations.Transformation{
+				{Type: "logfmt"},
+				{Type: "label", Expression: "app"},
+			},
+		},
+	})
+	url := fmt.Sprintf("/api/datasources/uid/%s/correlations/%s/resolve", dataSource.UID, correlation.UID)
+
+	t.Run("Unauthenticated users shouldn't be able to resolve correlations", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  url,
+			body: `{"fields": {"message": "traceID=abc123"}}`,
+		})
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Viewers should be able to resolve correlations", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  url,
+			body: `{"fields": {"message": "level=info traceID=abc123"}, "labels": {"app": "api"}}`,
+			user: viewerUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.ResolveCorrelationResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, dataSource.UID, *response.TargetUID)
+		require.Equal(t, `{app="api"} |= "abc123"`, response.Query["expr"])
+		require.Equal(t, "info", response.Variables["level"])
+
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("If a variable is missing from the source row it should return 400", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  url,
+			body: `{"fields": {"message": "no trace"}, "labels": {"app": "api"}}`,
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response errorResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "Failed to resolve correlation", response.Message)
+		require.Contains(t, response.Error, "traceID")
+
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("If the correlation does not exist it should return 404", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s/resolve", dataSource.UID, "some-correlation-uid"),
+			body: `{"fields": {"message": "traceID=abc123"}}`,
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
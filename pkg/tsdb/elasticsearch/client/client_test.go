@@ -3,6 +3,7 @@ package es
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -229,3 +230,85 @@ func createMultisearchForTest(t *testing.T, c Client) (*MultiSearchRequest, erro
 	})
 	return msb.Build()
 }
+
+func TestClient_ExecuteSQL(t *testing.T) {
+	var request *http.Request
+	var requestBody []byte
+	var response string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		request = r
+		buf, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requestBody = buf
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, err = rw.Write([]byte(response))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(ts.Close)
+
+	ds := DatasourceInfo{
+		URL:        ts.URL,
+		HTTPClient: ts.Client(),
+		Database:   "metrics",
+	}
+	c, err := NewClient(context.Background(), &ds, backend.TimeRange{From: time.Now().Add(-time.Hour), To: time.Now()})
+	require.NoError(t, err)
+
+	t.Run("SQL queries are sent to the _sql endpoint", func(t *testing.T) {
+		response = `{"columns": [{"name": "host", "type": "keyword"}, {"name": "count", "type": "long"}], "rows": [["a", 9007199254740993]], "cursor": "abc"}`
+
+		res, err := c.ExecuteSQL(&SQLRequest{Language: SQLLanguageSQL, Query: "SELECT host, COUNT(*) FROM metrics GROUP BY host", FetchSize: 100})
+		require.NoError(t, err)
+
+		require.Equal(t, "/_sql", request.URL.Path)
+		require.Equal(t, "format=json", request.URL.RawQuery)
+		require.Equal(t, "application/json", request.Header.Get("Content-Type"))
+
+		body, err := simplejson.NewJson(requestBody)
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT host, COUNT(*) FROM metrics GROUP BY host", body.Get("query").MustString())
+		assert.Equal(t, 100, body.Get("fetch_size").MustInt())
+		assert.Equal(t, "Z", body.Get("time_zone").MustString())
+
+		require.Len(t, res.Columns, 2)
+		require.Len(t, res.GetRows(), 1)
+		assert.Equal(t, "9007199254740993", res.GetRows()[0][1].(fmt.Stringer).String())
+		assert.Equal(t, "abc", res.Cursor)
+	})
+
+	t.Run("ES|QL queries are sent to the _query endpoint", func(t *testing.T) {
+		response = `{"columns": [{"name": "host", "type": "keyword"}], "values": [["a"], ["b"]]}`
+
+		res, err := c.ExecuteSQL(&SQLRequest{Language: SQLLanguageESQL, Query: "FROM metrics | KEEP host", FetchSize: 100})
+		require.NoError(t, err)
+
+		require.Equal(t, "/_query", request.URL.Path)
+		body, err := simplejson.NewJson(requestBody)
+		require.NoError(t, err)
+		assert.Equal(t, "FROM metrics | KEEP host", body.Get("query").MustString())
+		_, hasFetchSize := body.CheckGet("fetch_size")
+		assert.False(t, hasFetchSize)
+
+		require.Len(t, res.GetRows(), 2)
+	})
+
+	t.Run("Unsupported languages return an error", func(t *testing.T) {
+		_, err := c.ExecuteSQL(&SQLRequest{Language: "ppl", Query: "source=metrics"})
+		require.Error(t, err)
+	})
+
+	t.Run("SQL cursors are closed with the _sql/close endpoint", func(t *testing.T) {
+		response = `{"succeeded": true}`
+
+		err := c.CloseSQLCursor("abc")
+		require.NoError(t, err)
+
+		require.Equal(t, http.MethodPost, request.Method)
+		require.Equal(t, "/_sql/close", request.URL.Path)
+		body, err := simplejson.NewJson(requestBody)
+		require.NoError(t, err)
+		assert.Equal(t, "abc", body.Get("cursor").MustString())
+	})
+}
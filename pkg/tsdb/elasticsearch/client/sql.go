@@ -0,0 +1,134 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SQLLanguage represents the query language of a raw query
+type SQLLanguage string
+
+const (
+	// SQLLanguageSQL is Elasticsearch SQL, executed using the _sql endpoint
+	SQLLanguageSQL SQLLanguage = "sql"
+	// SQLLanguageESQL is the Elasticsearch Query Language (ES|QL), executed using the _query endpoint
+	SQLLanguageESQL SQLLanguage = "esql"
+)
+
+// SQLRequest represents a raw SQL or ES|QL query request
+type SQLRequest struct {
+	Language  SQLLanguage
+	Query     string
+	FetchSize int
+}
+
+// MarshalJSON returns the JSON encoding of the request.
+func (r *SQLRequest) MarshalJSON() ([]byte, error) {
+	root := map[string]interface{}{
+		"query": r.Query,
+	}
+	if r.Language == SQLLanguageSQL {
+		root["time_zone"] = "Z"
+		if r.FetchSize > 0 {
+			root["fetch_size"] = r.FetchSize
+		}
+	}
+	return json.Marshal(root)
+}
+
+// SQLColumn represents a column of a raw query response
+type SQLColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// SQLResponse represents the columnar response of a raw SQL or ES|QL query
+type SQLResponse struct {
+	Status  int                    `json:"-"`
+	Error   map[string]interface{} `json:"error"`
+	Columns []SQLColumn            `json:"columns"`
+	// Rows are returned by the _sql endpoint
+	Rows [][]interface{} `json:"rows"`
+	// Values are returned by the _query endpoint
+	Values [][]interface{} `json:"values"`
+	// Cursor is set by the _sql endpoint when there are more rows than the fetch size
+	Cursor string `json:"cursor"`
+}
+
+// GetRows returns the rows of the response, whatever endpoint returned it
+func (r *SQLResponse) GetRows() [][]interface{} {
+	if r.Values != nil {
+		return r.Values
+	}
+	return r.Rows
+}
+
+func (c *baseClientImpl) ExecuteSQL(r *SQLRequest) (*SQLResponse, error) {
+	var uriPath, uriQuery string
+	switch r.Language {
+	case SQLLanguageSQL:
+		uriPath, uriQuery = "_sql", "format=json"
+	case SQLLanguageESQL:
+		uriPath = "_query"
+	default:
+		return nil, fmt.Errorf("unsupported query language %q", r.Language)
+	}
+
+	body, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("Executing raw query", "language", r.Language)
+	res, err := c.executeRequest(http.MethodPost, uriPath, uriQuery, "application/json", body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	c.logger.Debug("Received raw query response", "code", res.StatusCode, "status", res.Status, "content-length", res.ContentLength)
+
+	start := time.Now()
+	var sr SQLResponse
+	dec := json.NewDecoder(res.Body)
+	// numbers are kept as json.Number to not lose the precision of long values
+	dec.UseNumber()
+	if err := dec.Decode(&sr); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response with status %d: %w", uriPath, res.StatusCode, err)
+	}
+	c.logger.Debug("Decoded raw query response", "took", time.Since(start))
+
+	sr.Status = res.StatusCode
+	return &sr, nil
+}
+
+// CloseSQLCursor closes the cursor of a SQL response, to release the search context it keeps open
+// on the Elasticsearch nodes when not all the rows are read.
+func (c *baseClientImpl) CloseSQLCursor(cursor string) error {
+	body, err := json.Marshal(map[string]string{"cursor": cursor})
+	if err != nil {
+		return err
+	}
+
+	c.logger.Debug("Closing raw query cursor")
+	res, err := c.executeRequest(http.MethodPost, "_sql/close", "", "application/json", body)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to close cursor, status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -29,11 +29,38 @@ var newElasticsearchDataQuery = func(client es.Client, dataQuery []backend.DataQ
 }
 
 func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
-	queries, err := parseQuery(e.dataQueries)
+	parsedQueries, err := parseQuery(e.dataQueries)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
 	}
 
+	// raw SQL and ES|QL queries don't use the multisearch api
+	queries := make([]*Query, 0, len(parsedQueries))
+	sqlQueries := make([]*Query, 0)
+	for _, q := range parsedQueries {
+		if isRawSQLQuery(q) {
+			sqlQueries = append(sqlQueries, q)
+		} else {
+			queries = append(queries, q)
+		}
+	}
+
+	result := &backend.QueryDataResponse{Responses: backend.Responses{}}
+	if len(queries) > 0 {
+		result, err = e.executeMultisearch(queries)
+		if err != nil {
+			return &backend.QueryDataResponse{}, err
+		}
+	}
+
+	for _, q := range sqlQueries {
+		result.Responses[q.RefID] = e.executeSQLQuery(q, e.dataQueries[0].TimeRange)
+	}
+
+	return result, nil
+}
+
+func (e *elasticsearchDataQuery) executeMultisearch(queries []*Query) (*backend.QueryDataResponse, error) {
 	ms := e.client.MultiSearch()
 
 	from := e.dataQueries[0].TimeRange.From.UnixNano() / int64(time.Millisecond)
@@ -54,7 +81,14 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 		return &backend.QueryDataResponse{}, err
 	}
 
-	return parseResponse(res.Responses, queries, e.client.GetConfiguredFields())
+	result, err := parseResponse(res.Responses, queries, e.client.GetConfiguredFields())
+	if err != nil {
+		return &backend.QueryDataResponse{}, err
+	}
+	if result.Responses == nil {
+		result.Responses = backend.Responses{}
+	}
+	return result, nil
 }
 
 func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilder, from, to int64) error {
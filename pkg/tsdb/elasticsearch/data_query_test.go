@@ -1711,6 +1711,10 @@ type fakeClient struct {
 	multiSearchError    error
 	builder             *es.MultiSearchRequestBuilder
 	multisearchRequests []*es.MultiSearchRequest
+	sqlResponse         *es.SQLResponse
+	sqlError            error
+	sqlRequests         []*es.SQLRequest
+	closedSQLCursors    []string
 }
 
 func newFakeClient() *fakeClient {
@@ -1724,6 +1728,7 @@ func newFakeClient() *fakeClient {
 		configuredFields:    configuredFields,
 		multisearchRequests: make([]*es.MultiSearchRequest, 0),
 		multiSearchResponse: &es.MultiSearchResponse{},
+		sqlResponse:         &es.SQLResponse{},
 	}
 }
 
@@ -1736,6 +1741,16 @@ func (c *fakeClient) ExecuteMultisearch(r *es.MultiSearchRequest) (*es.MultiSear
 	return c.multiSearchResponse, c.multiSearchError
 }
 
+func (c *fakeClient) ExecuteSQL(r *es.SQLRequest) (*es.SQLResponse, error) {
+	c.sqlRequests = append(c.sqlRequests, r)
+	return c.sqlResponse, c.sqlError
+}
+
+func (c *fakeClient) CloseSQLCursor(cursor string) error {
+	c.closedSQLCursors = append(c.closedSQLCursors, cursor)
+	return nil
+}
+
 func (c *fakeClient) MultiSearch() *es.MultiSearchRequestBuilder {
 	c.builder = es.NewMultiSearchRequestBuilder()
 	return c.builder
@@ -16,6 +16,11 @@ type Query struct {
 	IntervalMs    int64
 	RefID         string
 	MaxDataPoints int64
+
+	// QueryType is "sql" or "esql" for raw queries, RawQuery is then the SQL or ES|QL query
+	QueryType string
+	// Format is the format of the raw query results, "table" or "time_series"
+	Format string
 }
 
 // BucketAgg represents a bucket aggregation of the time series query model of the datasource
@@ -29,6 +29,8 @@ func parseQuery(tsdbQuery []backend.DataQuery) ([]*Query, error) {
 		alias := model.Get("alias").MustString("")
 		intervalMs := model.Get("intervalMs").MustInt64(0)
 		interval := q.Interval
+		queryType := model.Get("queryType").MustString(q.QueryType)
+		format := model.Get("format").MustString(formatTable)
 
 		queries = append(queries, &Query{
 			RawQuery:      rawQuery,
@@ -39,6 +41,8 @@ func parseQuery(tsdbQuery []backend.DataQuery) ([]*Query, error) {
 			IntervalMs:    intervalMs,
 			RefID:         q.RefID,
 			MaxDataPoints: q.MaxDataPoints,
+			QueryType:     queryType,
+			Format:        format,
 		})
 	}
 
@@ -0,0 +1,343 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
+)
+
+const (
+	// Raw query types
+	sqlQueryType  = "sql"
+	esqlQueryType = "esql"
+
+	// Raw query formats
+	formatTable      = "table"
+	formatTimeSeries = "time_series"
+
+	// sqlFetchSize is the maximum number of rows returned by a SQL query
+	sqlFetchSize = 10000
+)
+
+var sqlMacroRegex = regexp.MustCompile(`\$([_a-zA-Z0-9]+)\(([^\)]*)\)`)
+
+func isRawSQLQuery(q *Query) bool {
+	return q.QueryType == sqlQueryType || q.QueryType == esqlQueryType
+}
+
+func (e *elasticsearchDataQuery) executeSQLQuery(q *Query, timeRange backend.TimeRange) backend.DataResponse {
+	language := es.SQLLanguageSQL
+	if q.QueryType == esqlQueryType {
+		language = es.SQLLanguageESQL
+	}
+
+	query, err := interpolateSQL(q, timeRange, language)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	res, err := e.client.ExecuteSQL(&es.SQLRequest{
+		Language:  language,
+		Query:     query,
+		FetchSize: sqlFetchSize,
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	if res.Cursor != "" {
+		// only the first page is displayed, the cursor to the next pages is closed right away
+		if err := e.client.CloseSQLCursor(res.Cursor); err != nil {
+			eslog.Warn("Failed to close SQL cursor", "err", err)
+		}
+	}
+	if res.Error != nil {
+		return backend.DataResponse{Error: errors.New(getErrorFromElasticResponse(&es.SearchResponse{Error: res.Error}))}
+	}
+
+	frame, err := sqlResponseToFrame(res, q.Format)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	frame.RefID = q.RefID
+	if frame.Meta == nil {
+		frame.Meta = &data.FrameMeta{}
+	}
+	frame.Meta.ExecutedQueryString = query
+	if res.Cursor != "" {
+		frame.AppendNotices(data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("The query returned more than %d rows, only the first %d rows are displayed.", sqlFetchSize, sqlFetchSize),
+		})
+	}
+
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// interpolateSQL replaces the time range and interval macros of a raw SQL or ES|QL query
+func interpolateSQL(q *Query, timeRange backend.TimeRange, language es.SQLLanguage) (string, error) {
+	query := q.RawQuery
+	query = strings.ReplaceAll(query, "$__interval_ms", strconv.FormatInt(q.Interval.Milliseconds(), 10))
+	query = strings.ReplaceAll(query, "$__interval", intervalv2.FormatDuration(q.Interval))
+
+	var macroError error
+	query = sqlMacroRegex.ReplaceAllStringFunc(query, func(match string) string {
+		groups := sqlMacroRegex.FindStringSubmatch(match)
+		args := strings.Split(groups[2], ",")
+		for i, arg := range args {
+			args[i] = strings.TrimSpace(arg)
+		}
+		res, err := evaluateSQLMacro(groups[1], args, timeRange, language)
+		if err != nil {
+			if macroError == nil {
+				macroError = err
+			}
+			return match
+		}
+		return res
+	})
+	if macroError != nil {
+		return "", macroError
+	}
+	return query, nil
+}
+
+func evaluateSQLMacro(name string, args []string, timeRange backend.TimeRange, language es.SQLLanguage) (string, error) {
+	formatTime := func(t time.Time) string {
+		ts := t.UTC().Format("2006-01-02T15:04:05.000Z")
+		if language == es.SQLLanguageESQL {
+			return fmt.Sprintf(`TO_DATETIME("%s")`, ts)
+		}
+		return fmt.Sprintf(`CAST('%s' AS DATETIME)`, ts)
+	}
+
+	switch name {
+	case "__timeFilter":
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %s AND %s <= %s", args[0], formatTime(timeRange.From), args[0], formatTime(timeRange.To)), nil
+	case "__timeFrom":
+		return formatTime(timeRange.From), nil
+	case "__timeTo":
+		return formatTime(timeRange.To), nil
+	case "__timeGroup", "__timeGroupAlias":
+		if len(args) != 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'"`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		seconds := int64(interval.Seconds())
+		if seconds < 1 {
+			seconds = 1
+		}
+
+		if language == es.SQLLanguageESQL {
+			group := fmt.Sprintf("DATE_TRUNC(%d seconds, %s)", seconds, args[0])
+			if name == "__timeGroupAlias" {
+				return "time = " + group, nil
+			}
+			return group, nil
+		}
+
+		group := fmt.Sprintf("HISTOGRAM(%s, INTERVAL %d SECONDS)", args[0], seconds)
+		if name == "__timeGroupAlias" {
+			return group + ` AS "time"`, nil
+		}
+		return group, nil
+	default:
+		return "", fmt.Errorf("unknown macro %q", name)
+	}
+}
+
+// sqlResponseToFrame converts the columnar response of a raw query to a frame. Time series are
+// converted to the wide format, using the string columns as labels, like the SQL datasources do.
+func sqlResponseToFrame(res *es.SQLResponse, format string) (*data.Frame, error) {
+	rows := res.GetRows()
+	converters := make([]sqlColumnConverter, len(res.Columns))
+	fields := make([]*data.Field, len(res.Columns))
+	timeIndex := -1
+	for i, col := range res.Columns {
+		converters[i] = sqlConverterForType(col.Type)
+		fields[i] = data.NewFieldFromFieldType(converters[i].fieldType, len(rows))
+		fields[i].Name = col.Name
+		if timeIndex == -1 && converters[i].fieldType == data.FieldTypeNullableTime {
+			timeIndex = i
+		}
+	}
+
+	for rowIdx, row := range rows {
+		if len(row) != len(res.Columns) {
+			return nil, fmt.Errorf("row %d has %d values, expected %d", rowIdx, len(row), len(res.Columns))
+		}
+		for colIdx, value := range row {
+			v, err := converters[colIdx].convert(value)
+			if err != nil {
+				return nil, fmt.Errorf("column %q: %w", res.Columns[colIdx].Name, err)
+			}
+			fields[colIdx].Set(rowIdx, v)
+		}
+	}
+
+	frame := data.NewFrame("", fields...)
+	if format != formatTimeSeries {
+		return frame, nil
+	}
+
+	if timeIndex == -1 {
+		return nil, fmt.Errorf("found no column of type date or datetime, a time series query must return a time column")
+	}
+	sortFrameByTime(frame, timeIndex)
+
+	if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeLong {
+		return data.LongToWide(frame, nil)
+	}
+	return frame, nil
+}
+
+// sortFrameByTime sorts the rows of the frame by ascending time, as required to convert it to the wide format
+func sortFrameByTime(frame *data.Frame, timeIndex int) {
+	timeField := frame.Fields[timeIndex]
+	order := make([]int, timeField.Len())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, aOk := timeField.ConcreteAt(order[i])
+		b, bOk := timeField.ConcreteAt(order[j])
+		if !aOk || !bOk {
+			return aOk
+		}
+		return a.(time.Time).Before(b.(time.Time))
+	})
+
+	for _, field := range frame.Fields {
+		values := make([]interface{}, field.Len())
+		for i, idx := range order {
+			values[i] = field.At(idx)
+		}
+		for i, v := range values {
+			field.Set(i, v)
+		}
+	}
+}
+
+type sqlColumnConverter struct {
+	fieldType data.FieldType
+	convert   func(value interface{}) (interface{}, error)
+}
+
+func sqlConverterForType(columnType string) sqlColumnConverter {
+	switch columnType {
+	case "date", "datetime", "date_nanos":
+		return sqlColumnConverter{fieldType: data.FieldTypeNullableTime, convert: convertSQLTime}
+	case "long", "integer", "short", "byte", "counter_integer", "counter_long":
+		return sqlColumnConverter{fieldType: data.FieldTypeNullableInt64, convert: convertSQLInt}
+	case "double", "float", "half_float", "scaled_float", "unsigned_long", "counter_double":
+		return sqlColumnConverter{fieldType: data.FieldTypeNullableFloat64, convert: convertSQLFloat}
+	case "boolean":
+		return sqlColumnConverter{fieldType: data.FieldTypeNullableBool, convert: convertSQLBool}
+	default:
+		return sqlColumnConverter{fieldType: data.FieldTypeNullableString, convert: convertSQLString}
+	}
+}
+
+func convertSQLTime(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return (*time.Time)(nil), nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
+	case json.Number:
+		ms, err := v.Int64()
+		if err != nil {
+			return nil, err
+		}
+		t := time.UnixMilli(ms).UTC()
+		return &t, nil
+	default:
+		return nil, fmt.Errorf("unexpected time value %v", value)
+	}
+}
+
+func convertSQLInt(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return (*int64)(nil), nil
+	case json.Number:
+		i, err := v.Int64()
+		if err != nil {
+			return nil, err
+		}
+		return &i, nil
+	default:
+		return nil, fmt.Errorf("unexpected integer value %v", value)
+	}
+}
+
+func convertSQLFloat(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return (*float64)(nil), nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return &f, nil
+	case string:
+		// NaN and infinite values are returned as strings
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &f, nil
+	default:
+		return nil, fmt.Errorf("unexpected number value %v", value)
+	}
+}
+
+func convertSQLBool(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return (*bool)(nil), nil
+	case bool:
+		return &v, nil
+	default:
+		return nil, fmt.Errorf("unexpected boolean value %v", value)
+	}
+}
+
+func convertSQLString(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return (*string)(nil), nil
+	case string:
+		return &v, nil
+	default:
+		// multi-valued fields and objects are displayed as JSON
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		s := string(b)
+		return &s, nil
+	}
+}
@@ -0,0 +1,161 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+func TestInterpolateSQL(t *testing.T) {
+	timeRange := backend.TimeRange{
+		From: time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC),
+		To:   time.Date(2023, 7, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		language es.SQLLanguage
+		before   string
+		after    string
+	}{
+		{
+			name:     "SQL time filter",
+			language: es.SQLLanguageSQL,
+			before:   `SELECT * FROM logs WHERE $__timeFilter("@timestamp")`,
+			after:    `SELECT * FROM logs WHERE "@timestamp" >= CAST('2023-07-01T10:00:00.000Z' AS DATETIME) AND "@timestamp" <= CAST('2023-07-01T11:00:00.000Z' AS DATETIME)`,
+		},
+		{
+			name:     "SQL time group with interval",
+			language: es.SQLLanguageSQL,
+			before:   `SELECT $__timeGroupAlias("@timestamp", $__interval), COUNT(*) FROM logs GROUP BY 1`,
+			after:    `SELECT HISTOGRAM("@timestamp", INTERVAL 60 SECONDS) AS "time", COUNT(*) FROM logs GROUP BY 1`,
+		},
+		{
+			name:     "ES|QL time filter and group",
+			language: es.SQLLanguageESQL,
+			before:   `FROM logs | WHERE $__timeFilter(@timestamp) | STATS c = COUNT(*) BY $__timeGroupAlias(@timestamp, 5m)`,
+			after:    `FROM logs | WHERE @timestamp >= TO_DATETIME("2023-07-01T10:00:00.000Z") AND @timestamp <= TO_DATETIME("2023-07-01T11:00:00.000Z") | STATS c = COUNT(*) BY time = DATE_TRUNC(300 seconds, @timestamp)`,
+		},
+		{
+			name:     "time from, time to and interval in milliseconds",
+			language: es.SQLLanguageESQL,
+			before:   `$__timeFrom() $__timeTo() $__interval_ms`,
+			after:    `TO_DATETIME("2023-07-01T10:00:00.000Z") TO_DATETIME("2023-07-01T11:00:00.000Z") 60000`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := interpolateSQL(&Query{RawQuery: tt.before, Interval: time.Minute}, timeRange, tt.language)
+			require.NoError(t, err)
+			require.Equal(t, tt.after, query)
+		})
+	}
+
+	t.Run("unknown macros return an error", func(t *testing.T) {
+		_, err := interpolateSQL(&Query{RawQuery: `SELECT $__unknown(a)`}, timeRange, es.SQLLanguageSQL)
+		require.Error(t, err)
+	})
+}
+
+func TestSQLResponseToFrame(t *testing.T) {
+	res := &es.SQLResponse{
+		Columns: []es.SQLColumn{
+			{Name: "time", Type: "datetime"},
+			{Name: "host", Type: "keyword"},
+			{Name: "count", Type: "long"},
+			{Name: "avg", Type: "double"},
+		},
+		Rows: [][]interface{}{
+			{"2023-07-01T10:01:00.000Z", "b", json.Number("3"), json.Number("1.5")},
+			{"2023-07-01T10:00:00.000Z", "a", json.Number("1"), nil},
+			{"2023-07-01T10:00:00.000Z", "b", json.Number("2"), json.Number("2.5")},
+		},
+	}
+
+	t.Run("table format keeps the rows", func(t *testing.T) {
+		frame, err := sqlResponseToFrame(res, formatTable)
+		require.NoError(t, err)
+		require.Equal(t, 3, frame.Rows())
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		require.Equal(t, data.FieldTypeNullableString, frame.Fields[1].Type())
+		require.Equal(t, data.FieldTypeNullableInt64, frame.Fields[2].Type())
+		require.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[3].Type())
+		require.Equal(t, "b", *frame.Fields[1].At(0).(*string))
+		require.Nil(t, frame.Fields[3].At(1))
+	})
+
+	t.Run("time series format converts the rows to one series per group", func(t *testing.T) {
+		frame, err := sqlResponseToFrame(res, formatTimeSeries)
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+		// time, then count and avg for each host
+		require.Len(t, frame.Fields, 5)
+		require.Equal(t, data.Labels{"host": "a"}, frame.Fields[1].Labels)
+		require.Equal(t, data.Labels{"host": "b"}, frame.Fields[2].Labels)
+	})
+
+	t.Run("time series format requires a time column", func(t *testing.T) {
+		_, err := sqlResponseToFrame(&es.SQLResponse{
+			Columns: []es.SQLColumn{{Name: "count", Type: "long"}},
+			Values:  [][]interface{}{{json.Number("1")}},
+		}, formatTimeSeries)
+		require.Error(t, err)
+	})
+}
+
+func TestExecuteSQLQuery(t *testing.T) {
+	from := time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	c := newFakeClient()
+	c.sqlResponse = &es.SQLResponse{
+		Columns: []es.SQLColumn{{Name: "host", Type: "keyword"}},
+		Values:  [][]interface{}{{"a"}},
+	}
+
+	result, err := executeElasticsearchDataQuery(c, `{
+		"queryType": "esql",
+		"query": "FROM logs | WHERE $__timeFilter(@timestamp) | KEEP host"
+	}`, from, to)
+	require.NoError(t, err)
+	require.Empty(t, c.multisearchRequests)
+	require.Len(t, c.sqlRequests, 1)
+	require.Equal(t, es.SQLLanguageESQL, c.sqlRequests[0].Language)
+
+	res := result.Responses[""]
+	require.NoError(t, res.Error)
+	require.Len(t, res.Frames, 1)
+	require.Equal(t, `FROM logs | WHERE @timestamp >= TO_DATETIME("2023-07-01T10:00:00.000Z") AND @timestamp <= TO_DATETIME("2023-07-01T11:00:00.000Z") | KEEP host`, res.Frames[0].Meta.ExecutedQueryString)
+
+	require.Empty(t, c.closedSQLCursors)
+
+	t.Run("the cursor of truncated results is closed", func(t *testing.T) {
+		c := newFakeClient()
+		c.sqlResponse = &es.SQLResponse{
+			Columns: []es.SQLColumn{{Name: "host", Type: "keyword"}},
+			Rows:    [][]interface{}{{"a"}},
+			Cursor:  "abc",
+		}
+
+		result, err := executeElasticsearchDataQuery(c, `{"queryType": "sql", "query": "SELECT host FROM logs"}`, from, to)
+		require.NoError(t, err)
+		require.NoError(t, result.Responses[""].Error)
+		require.Equal(t, []string{"abc"}, c.closedSQLCursors)
+		require.Len(t, result.Responses[""].Frames[0].Meta.Notices, 1)
+	})
+
+	t.Run("errors returned by elasticsearch are returned for the query", func(t *testing.T) {
+		c := newFakeClient()
+		c.sqlResponse = &es.SQLResponse{Error: map[string]interface{}{"reason": "Unknown index [logs]"}}
+
+		result, err := executeElasticsearchDataQuery(c, `{"queryType": "sql", "query": "SELECT * FROM logs"}`, from, to)
+		require.NoError(t, err)
+		require.EqualError(t, result.Responses[""].Error, "Unknown index [logs]")
+	})
+}
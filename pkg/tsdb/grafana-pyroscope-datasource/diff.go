@@ -0,0 +1,160 @@
+package phlare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/errgroup"
+)
+
+// DiffTree is a node of a flame graph that merges a baseline (left) and a comparison (right) profile. Nodes of
+// the two profiles are matched by their name and the names of their ancestors.
+type DiffTree struct {
+	Level      int
+	Name       string
+	Value      int64
+	Self       int64
+	ValueRight int64
+	SelfRight  int64
+	Nodes      []*DiffTree
+
+	nodesByName map[string]*DiffTree
+}
+
+// mergeTrees merges the baseline and the comparison profile trees, either of them can be nil if the profile
+// was empty.
+func mergeTrees(left, right *ProfileTree) *DiffTree {
+	if left == nil && right == nil {
+		return nil
+	}
+	root := &DiffTree{Name: "total"}
+	if left != nil {
+		root.Name = left.Name
+		root.add(left, false)
+	}
+	if right != nil {
+		root.Name = right.Name
+		root.add(right, true)
+	}
+	return root
+}
+
+func (dt *DiffTree) add(tree *ProfileTree, right bool) {
+	if right {
+		dt.ValueRight += tree.Value
+		dt.SelfRight += tree.Self
+	} else {
+		dt.Value += tree.Value
+		dt.Self += tree.Self
+	}
+	for _, node := range tree.Nodes {
+		dt.child(node.Name).add(node, right)
+	}
+}
+
+// child returns the child node with the given name, new nodes are added after the existing ones so the order
+// of the baseline profile is kept.
+func (dt *DiffTree) child(name string) *DiffTree {
+	if dt.nodesByName == nil {
+		dt.nodesByName = map[string]*DiffTree{}
+	}
+	if node, ok := dt.nodesByName[name]; ok {
+		return node
+	}
+	node := &DiffTree{Level: dt.Level + 1, Name: name}
+	dt.nodesByName[name] = node
+	dt.Nodes = append(dt.Nodes, node)
+	return node
+}
+
+// diffTreeToNestedSetDataFrame converts the merged tree to the same nested set format as
+// treeToNestedSetDataFrame. The value and self fields hold the sum of both profiles, the valueRight and selfRight
+// fields hold the values of the comparison profile, so the flame graph can color each node by its change.
+func diffTreeToNestedSetDataFrame(tree *DiffTree, unit string) *data.Frame {
+	frame := data.NewFrame("response")
+	frame.Meta = &data.FrameMeta{PreferredVisualization: "flamegraph"}
+
+	levelField := data.NewField("level", nil, []int64{})
+	valueField := data.NewField("value", nil, []int64{})
+	selfField := data.NewField("self", nil, []int64{})
+	valueRightField := data.NewField("valueRight", nil, []int64{})
+	selfRightField := data.NewField("selfRight", nil, []int64{})
+
+	for _, f := range []*data.Field{valueField, selfField, valueRightField, selfRightField} {
+		f.Config = &data.FieldConfig{Unit: unit}
+	}
+
+	labelField := NewEnumField("label", nil)
+
+	if tree != nil {
+		stack := []*DiffTree{tree}
+		for len(stack) > 0 {
+			node := stack[0]
+			levelField.Append(int64(node.Level))
+			valueField.Append(node.Value + node.ValueRight)
+			selfField.Append(node.Self + node.SelfRight)
+			valueRightField.Append(node.ValueRight)
+			selfRightField.Append(node.SelfRight)
+			labelField.Append(node.Name)
+			// Put the children first so we do depth first traversal
+			stack = append(append([]*DiffTree{}, node.Nodes...), stack[1:]...)
+		}
+	}
+
+	frame.Fields = data.Fields{levelField, valueField, selfField, labelField.GetField(), valueRightField, selfRightField}
+	return frame
+}
+
+// diffProfiles fetches the baseline and the comparison profile of a diff query in parallel and merges them. The
+// comparison is the profile of the label selector in the query time range, the baseline uses the baseline label
+// selector and the time range shifted back by the baseline time shift.
+func (d *PhlareDatasource) diffProfiles(ctx context.Context, qm queryModel, timeRange backend.TimeRange) (*data.Frame, error) {
+	baselineSelector := qm.LabelSelector
+	if qm.BaselineLabelSelector != nil && *qm.BaselineLabelSelector != "" {
+		baselineSelector = *qm.BaselineLabelSelector
+	}
+
+	var shift time.Duration
+	if qm.BaselineTimeShift != nil && *qm.BaselineTimeShift != "" {
+		var err error
+		shift, err = gtime.ParseDuration(*qm.BaselineTimeShift)
+		if err != nil {
+			return nil, fmt.Errorf("invalid baseline time shift %q: %w", *qm.BaselineTimeShift, err)
+		}
+	}
+
+	if baselineSelector == qm.LabelSelector && shift == 0 {
+		return nil, errors.New("a diff query needs a baseline label selector or a baseline time shift")
+	}
+
+	var left, right *ProfileResponse
+	g, gCtx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		left, err = d.client.GetProfile(gCtx, qm.ProfileTypeId, baselineSelector, timeRange.From.Add(-shift).UnixMilli(), timeRange.To.Add(-shift).UnixMilli(), qm.MaxNodes)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		right, err = d.client.GetProfile(gCtx, qm.ProfileTypeId, qm.LabelSelector, timeRange.From.UnixMilli(), timeRange.To.UnixMilli(), qm.MaxNodes)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	tree := mergeTrees(profileToTree(left), profileToTree(right))
+	return diffTreeToNestedSetDataFrame(tree, right.Units), nil
+}
+
+func profileToTree(resp *ProfileResponse) *ProfileTree {
+	if resp == nil || resp.Flamebearer == nil {
+		return nil
+	}
+	return levelsToTree(resp.Flamebearer.Levels, resp.Flamebearer.Names)
+}
@@ -0,0 +1,81 @@
+package phlare
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mergeTrees(t *testing.T) {
+	left := &ProfileTree{
+		Level: 0, Value: 10, Self: 0, Name: "total",
+		Nodes: []*ProfileTree{
+			{Level: 1, Value: 6, Self: 6, Name: "foo"},
+			{Level: 1, Value: 4, Self: 4, Name: "bar"},
+		},
+	}
+	right := &ProfileTree{
+		Level: 0, Value: 20, Self: 0, Name: "total",
+		Nodes: []*ProfileTree{
+			{Level: 1, Value: 5, Self: 5, Name: "bar"},
+			{Level: 1, Value: 15, Self: 5, Name: "baz", Nodes: []*ProfileTree{
+				{Level: 2, Value: 10, Self: 10, Name: "foo"},
+			}},
+		},
+	}
+
+	frame := diffTreeToNestedSetDataFrame(mergeTrees(left, right), "short")
+	require.Equal(t, []int64{0, 1, 1, 1, 2}, fieldValues[int64](frame.Fields[0]))
+	require.Equal(t, []int64{30, 6, 9, 15, 10}, fieldValues[int64](frame.Fields[1]))
+	require.Equal(t, []int64{0, 6, 9, 5, 10}, fieldValues[int64](frame.Fields[2]))
+	require.Equal(t, "label", frame.Fields[3].Name)
+	require.Equal(t, []string{"total", "foo", "bar", "baz"}, frame.Fields[3].Config.TypeConfig.Enum.Text)
+	require.Equal(t, []int64{20, 0, 5, 15, 10}, fieldValues[int64](frame.Fields[4]))
+	require.Equal(t, []int64{0, 0, 5, 5, 10}, fieldValues[int64](frame.Fields[5]))
+
+	t.Run("empty profiles", func(t *testing.T) {
+		require.Nil(t, mergeTrees(nil, nil))
+		frame := diffTreeToNestedSetDataFrame(mergeTrees(nil, right), "short")
+		require.Equal(t, []int64{20, 5, 15, 10}, fieldValues[int64](frame.Fields[1]))
+		require.Equal(t, []int64{20, 5, 15, 10}, fieldValues[int64](frame.Fields[4]))
+	})
+}
+
+func Test_queryDiff(t *testing.T) {
+	ds := &PhlareDatasource{
+		client: &FakeClient{},
+	}
+
+	t.Run("query diff", func(t *testing.T) {
+		dataQuery := makeDataQuery()
+		dataQuery.QueryType = queryTypeDiff
+		dataQuery.JSON = []byte(`{"profileTypeId":"memory:alloc_objects:count:space:bytes","labelSelector":"{app=\"baz\"}","baselineTimeShift":"1h"}`)
+		resp := ds.query(context.Background(), backend.PluginContext{}, *dataQuery)
+		require.Nil(t, resp.Error)
+		require.Equal(t, 1, len(resp.Frames))
+		frame := resp.Frames[0]
+		require.Equal(t, data.NewField("level", nil, []int64{0, 1, 2}), frame.Fields[0])
+		require.Equal(t, []int64{20, 18, 16}, fieldValues[int64](frame.Fields[1]))
+		require.Equal(t, "valueRight", frame.Fields[4].Name)
+		require.Equal(t, []int64{10, 9, 8}, fieldValues[int64](frame.Fields[4]))
+		require.Equal(t, []int64{0, 0, 8}, fieldValues[int64](frame.Fields[5]))
+	})
+
+	t.Run("query diff without baseline", func(t *testing.T) {
+		dataQuery := makeDataQuery()
+		dataQuery.QueryType = queryTypeDiff
+		resp := ds.query(context.Background(), backend.PluginContext{}, *dataQuery)
+		require.Error(t, resp.Error)
+	})
+
+	t.Run("query diff with invalid time shift", func(t *testing.T) {
+		dataQuery := makeDataQuery()
+		dataQuery.QueryType = queryTypeDiff
+		dataQuery.JSON = []byte(`{"profileTypeId":"memory:alloc_objects:count:space:bytes","labelSelector":"{}","baselineTimeShift":"yesterday"}`)
+		resp := ds.query(context.Background(), backend.PluginContext{}, *dataQuery)
+		require.ErrorContains(t, resp.Error, "invalid baseline time shift")
+	})
+}
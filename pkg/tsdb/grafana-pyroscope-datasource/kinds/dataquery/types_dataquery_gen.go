@@ -12,6 +12,7 @@ package dataquery
 // Defines values for PhlareQueryType.
 const (
 	PhlareQueryTypeBoth    PhlareQueryType = "both"
+	PhlareQueryTypeDiff    PhlareQueryType = "diff"
 	PhlareQueryTypeMetrics PhlareQueryType = "metrics"
 	PhlareQueryTypeProfile PhlareQueryType = "profile"
 )
@@ -48,6 +49,12 @@ type GrafanaPyroscopeDataQuery struct {
 	// properties for the given context.
 	DataQuery
 
+	// Specifies the label selectors of the baseline profile of a diff query. Defaults to the label selectors of the query.
+	BaselineLabelSelector *string `json:"baselineLabelSelector,omitempty"`
+
+	// Shifts the time range of the baseline profile of a diff query back by the given duration, e.g. 1d.
+	BaselineTimeShift *string `json:"baselineTimeShift,omitempty"`
+
 	// For mixed data sources the selected datasource is on the query level.
 	// For non mixed scenarios this is undefined.
 	// TODO find a better way to do this ^ that's friendly to schema
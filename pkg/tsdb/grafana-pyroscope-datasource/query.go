@@ -30,6 +30,7 @@ const (
 	queryTypeProfile = string(dataquery.PhlareQueryTypeProfile)
 	queryTypeMetrics = string(dataquery.PhlareQueryTypeMetrics)
 	queryTypeBoth    = string(dataquery.PhlareQueryTypeBoth)
+	queryTypeDiff    = string(dataquery.PhlareQueryTypeDiff)
 )
 
 // query processes single Phlare query transforming the response to data.Frame packaged in DataResponse
@@ -111,6 +112,21 @@ func (d *PhlareDatasource) query(ctx context.Context, pCtx backend.PluginContext
 		})
 	}
 
+	if query.QueryType == queryTypeDiff {
+		g.Go(func() error {
+			logger.Debug("Calling GetProfile for diff", "queryModel", qm)
+			frame, err := d.diffProfiles(gCtx, qm, query.TimeRange)
+			if err != nil {
+				logger.Error("Error diffing profiles", "err", err)
+				return err
+			}
+			responseMutex.Lock()
+			response.Frames = append(response.Frames, frame)
+			responseMutex.Unlock()
+			return nil
+		})
+	}
+
 	if err := g.Wait(); err != nil {
 		response.Error = g.Wait()
 	}
@@ -0,0 +1,140 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// AlertStore is the part of the alerting store used by the alert states query
+type AlertStore interface {
+	GetUserVisibleNamespaces(ctx context.Context, orgID int64, user *user.SignedInUser) (map[string]*folder.Folder, error)
+	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
+	ListAlertInstances(ctx context.Context, query *ngmodels.ListAlertInstancesQuery) ([]*ngmodels.AlertInstance, error)
+}
+
+func (s *Service) doAlertStatesQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	if s.alerts == nil || s.ac == nil {
+		return backend.ErrDataResponse(backend.StatusInternal, "alert states are not available")
+	}
+
+	r := requestModel{}
+	if err := json.Unmarshal(query.JSON, &r); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse query json: %v", err))
+	}
+	m := r.AlertStates
+
+	user, err := appcontext.User(ctx)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusUnauthorized, "alert states query requires a signed in user")
+	}
+	orgID := req.PluginContext.OrgID
+
+	// Only the rules in the folders the user can see and read the rules of are returned
+	namespaces, err := s.alerts.GetUserVisibleNamespaces(ctx, orgID, user)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	namespaceUIDs := make([]string, 0, len(namespaces))
+	for uid := range namespaces {
+		evaluator := accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleRead, dashboards.ScopeFoldersProvider.GetResourceScopeUID(uid))
+		canRead, err := s.ac.Evaluate(ctx, user, evaluator)
+		if err != nil {
+			return backend.DataResponse{Error: err}
+		}
+		if canRead {
+			namespaceUIDs = append(namespaceUIDs, uid)
+		}
+	}
+	if len(namespaceUIDs) == 0 {
+		return backend.DataResponse{Frames: data.Frames{newAlertStatesFrame(0)}}
+	}
+
+	rules, err := s.alerts.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: namespaceUIDs,
+		DashboardUID:  m.DashboardUID,
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	if len(rules) == 0 {
+		return backend.DataResponse{Frames: data.Frames{newAlertStatesFrame(0)}}
+	}
+	rulesByUID := make(map[string]*ngmodels.AlertRule, len(rules))
+	ruleUIDs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		rulesByUID[rule.UID] = rule
+		ruleUIDs = append(ruleUIDs, rule.UID)
+	}
+
+	states := make([]ngmodels.InstanceStateType, 0, len(m.States))
+	for _, state := range m.States {
+		states = append(states, ngmodels.InstanceStateType(state))
+	}
+
+	instances, err := s.alerts.ListAlertInstances(ctx, &ngmodels.ListAlertInstancesQuery{
+		RuleOrgID: orgID,
+		RuleUIDs:  ruleUIDs,
+		States:    states,
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	sort.SliceStable(instances, func(i, j int) bool {
+		if instances[i].RuleUID != instances[j].RuleUID {
+			return instances[i].RuleUID < instances[j].RuleUID
+		}
+		return instances[i].LabelsHash < instances[j].LabelsHash
+	})
+
+	frame := newAlertStatesFrame(len(instances))
+	for i, instance := range instances {
+		rule := rulesByUID[instance.RuleUID]
+		folderTitle := ""
+		if f, ok := namespaces[rule.NamespaceUID]; ok && f != nil {
+			folderTitle = f.Title
+		}
+		labels, err := json.Marshal(instance.Labels)
+		if err != nil {
+			return backend.DataResponse{Error: err}
+		}
+
+		frame.Fields[0].Set(i, rule.UID)
+		frame.Fields[1].Set(i, rule.Title)
+		frame.Fields[2].Set(i, folderTitle)
+		frame.Fields[3].Set(i, string(instance.CurrentState))
+		frame.Fields[4].Set(i, instance.CurrentReason)
+		frame.Fields[5].Set(i, json.RawMessage(labels))
+		frame.Fields[6].Set(i, instance.CurrentStateSince)
+		frame.Fields[7].Set(i, instance.LastEvalTime)
+	}
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+func newAlertStatesFrame(size int) *data.Frame {
+	frame := data.NewFrame("alertStates",
+		data.NewField("ruleUID", nil, make([]string, size)),
+		data.NewField("title", nil, make([]string, size)),
+		data.NewField("folder", nil, make([]string, size)),
+		data.NewField("state", nil, make([]string, size)),
+		data.NewField("reason", nil, make([]string, size)),
+		data.NewField("labels", nil, make([]json.RawMessage, size)),
+		data.NewField("since", nil, make([]time.Time, size)),
+		data.NewField("lastEvaluation", nil, make([]time.Time, size)),
+	)
+	frame.SetMeta(&data.FrameMeta{Type: data.FrameTypeTable})
+	return frame
+}
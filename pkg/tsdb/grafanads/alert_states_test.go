@@ -0,0 +1,120 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeAlertStore struct {
+	namespaces     map[string]*folder.Folder
+	rules          ngmodels.RulesGroup
+	instances      []*ngmodels.AlertInstance
+	rulesQuery     *ngmodels.ListAlertRulesQuery
+	instancesQuery *ngmodels.ListAlertInstancesQuery
+}
+
+func (f *fakeAlertStore) GetUserVisibleNamespaces(_ context.Context, _ int64, _ *user.SignedInUser) (map[string]*folder.Folder, error) {
+	return f.namespaces, nil
+}
+
+func (f *fakeAlertStore) ListAlertRules(_ context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error) {
+	f.rulesQuery = query
+	return f.rules, nil
+}
+
+func (f *fakeAlertStore) ListAlertInstances(_ context.Context, query *ngmodels.ListAlertInstancesQuery) ([]*ngmodels.AlertInstance, error) {
+	f.instancesQuery = query
+	return f.instances, nil
+}
+
+func TestAlertStatesQuery(t *testing.T) {
+	ac := acimpl.ProvideAccessControl(setting.NewCfg())
+	ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{
+		1: {accesscontrol.ActionAlertingRuleRead: {dashboards.ScopeFoldersProvider.GetResourceScopeUID("folder")}},
+	}})
+	req := &backend.QueryDataRequest{PluginContext: backend.PluginContext{OrgID: 1}}
+	query := func(t *testing.T, m alertStatesQueryModel) backend.DataQuery {
+		t.Helper()
+		raw, err := json.Marshal(requestModel{QueryType: queryTypeAlertStates, AlertStates: m})
+		require.NoError(t, err)
+		return backend.DataQuery{RefID: "A", QueryType: queryTypeAlertStates, JSON: raw}
+	}
+
+	t.Run("filters are passed to the store", func(t *testing.T) {
+		store := &fakeAlertStore{
+			namespaces: map[string]*folder.Folder{"folder": {UID: "folder", Title: "Folder"}},
+			rules:      ngmodels.RulesGroup{{UID: "rule", Title: "Rule", NamespaceUID: "folder"}},
+			instances: []*ngmodels.AlertInstance{{
+				AlertInstanceKey: ngmodels.AlertInstanceKey{RuleOrgID: 1, RuleUID: "rule", LabelsHash: "hash"},
+				Labels:           ngmodels.InstanceLabels{"a": "b"},
+				CurrentState:     ngmodels.InstanceStateFiring,
+			}},
+		}
+		s := newService(nil, nil, nil, nil, store, ac)
+
+		rsp := s.doAlertStatesQuery(ctx, req, query(t, alertStatesQueryModel{DashboardUID: "dash", States: []string{"Alerting"}}))
+		require.NoError(t, rsp.Error)
+
+		require.Equal(t, []string{"folder"}, store.rulesQuery.NamespaceUIDs)
+		require.Equal(t, "dash", store.rulesQuery.DashboardUID)
+		require.Equal(t, []string{"rule"}, store.instancesQuery.RuleUIDs)
+		require.Equal(t, []ngmodels.InstanceStateType{ngmodels.InstanceStateFiring}, store.instancesQuery.States)
+
+		require.Len(t, rsp.Frames, 1)
+		frame := rsp.Frames[0]
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, "Rule", frame.Fields[1].At(0))
+		require.Equal(t, "Folder", frame.Fields[2].At(0))
+		require.Equal(t, "Alerting", frame.Fields[3].At(0))
+		require.JSONEq(t, `{"a":"b"}`, string(frame.Fields[5].At(0).(json.RawMessage)))
+	})
+
+	t.Run("no rules return an empty frame without listing the instances", func(t *testing.T) {
+		store := &fakeAlertStore{namespaces: map[string]*folder.Folder{"folder": {UID: "folder"}}}
+		s := newService(nil, nil, nil, nil, store, ac)
+
+		rsp := s.doAlertStatesQuery(ctx, req, query(t, alertStatesQueryModel{}))
+		require.NoError(t, rsp.Error)
+		require.Equal(t, 0, rsp.Frames[0].Rows())
+		require.Nil(t, store.instancesQuery)
+	})
+
+	t.Run("rules are not returned without the permission to read the rules of their folder", func(t *testing.T) {
+		store := &fakeAlertStore{
+			namespaces: map[string]*folder.Folder{"folder": {UID: "folder"}, "other": {UID: "other"}},
+			rules:      ngmodels.RulesGroup{{UID: "rule", NamespaceUID: "folder"}},
+		}
+		s := newService(nil, nil, nil, nil, store, ac)
+
+		rsp := s.doAlertStatesQuery(ctx, req, query(t, alertStatesQueryModel{}))
+		require.NoError(t, rsp.Error)
+		require.Equal(t, []string{"folder"}, store.rulesQuery.NamespaceUIDs)
+
+		store.rulesQuery = nil
+		viewer := appcontext.WithUser(context.Background(), &user.SignedInUser{OrgID: 1})
+		rsp = s.doAlertStatesQuery(viewer, req, query(t, alertStatesQueryModel{}))
+		require.NoError(t, rsp.Error)
+		require.Equal(t, 0, rsp.Frames[0].Rows())
+		require.Nil(t, store.rulesQuery)
+	})
+
+	t.Run("requires a signed in user", func(t *testing.T) {
+		s := newService(nil, nil, nil, nil, &fakeAlertStore{}, ac)
+		rsp := s.doAlertStatesQuery(context.Background(), req, query(t, alertStatesQueryModel{}))
+		require.Error(t, rsp.Error)
+		require.Equal(t, backend.StatusUnauthorized, rsp.Status)
+	})
+}
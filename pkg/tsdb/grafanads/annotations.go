@@ -0,0 +1,107 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+// defaultAnnotationsLimit matches the default limit of the annotations HTTP API
+const defaultAnnotationsLimit = 100
+
+func (s *Service) doAnnotationsQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	if s.annotations == nil {
+		return backend.ErrDataResponse(backend.StatusInternal, "annotations are not available")
+	}
+
+	r := requestModel{}
+	if err := json.Unmarshal(query.JSON, &r); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse query json: %v", err))
+	}
+	m := r.AnnotationsFrame
+
+	user, err := appcontext.User(ctx)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusUnauthorized, "annotations query requires a signed in user")
+	}
+
+	limit := m.Limit
+	if limit <= 0 {
+		limit = defaultAnnotationsLimit
+	}
+
+	items, err := s.annotations.Find(ctx, &annotations.ItemQuery{
+		OrgID:        req.PluginContext.OrgID,
+		From:         query.TimeRange.From.UnixMilli(),
+		To:           query.TimeRange.To.UnixMilli(),
+		DashboardUID: m.DashboardUID,
+		Tags:         m.Tags,
+		MatchAny:     m.MatchAny,
+		Type:         m.Type,
+		Limit:        limit,
+		SignedInUser: user,
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	frame, err := annotationsToFrame(items)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+func annotationsToFrame(items []*annotations.ItemDTO) (*data.Frame, error) {
+	count := len(items)
+	times := make([]time.Time, count)
+	timeEnds := make([]time.Time, count)
+	texts := make([]string, count)
+	tags := make([]json.RawMessage, count)
+	dashboardUIDs := make([]string, count)
+	panelIDs := make([]int64, count)
+	states := make([]string, count)
+	logins := make([]string, count)
+
+	for i, item := range items {
+		times[i] = time.UnixMilli(item.Time)
+		timeEnds[i] = time.UnixMilli(item.TimeEnd)
+		texts[i] = item.Text
+		if item.DashboardUID != nil {
+			dashboardUIDs[i] = *item.DashboardUID
+		}
+		panelIDs[i] = item.PanelID
+		states[i] = item.NewState
+		logins[i] = item.Login
+
+		itemTags := item.Tags
+		if itemTags == nil {
+			itemTags = []string{}
+		}
+		raw, err := json.Marshal(itemTags)
+		if err != nil {
+			return nil, err
+		}
+		tags[i] = raw
+	}
+
+	frame := data.NewFrame("annotations",
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+		data.NewField("tags", nil, tags),
+		data.NewField("dashboardUID", nil, dashboardUIDs),
+		data.NewField("panelId", nil, panelIDs),
+		data.NewField("newState", nil, states),
+		data.NewField("login", nil, logins),
+	)
+	frame.SetMeta(&data.FrameMeta{Type: data.FrameTypeTable})
+	return frame, nil
+}
@@ -0,0 +1,73 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeAnnotationsRepo struct {
+	annotations.Repository
+	items []*annotations.ItemDTO
+	query *annotations.ItemQuery
+}
+
+func (f *fakeAnnotationsRepo) Find(_ context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	f.query = query
+	return f.items, nil
+}
+
+func TestAnnotationsQuery(t *testing.T) {
+	ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{OrgID: 1})
+	req := &backend.QueryDataRequest{PluginContext: backend.PluginContext{OrgID: 1}}
+	from := time.UnixMilli(1000)
+	to := time.UnixMilli(2000)
+
+	dashboardUID := "dash"
+	repo := &fakeAnnotationsRepo{items: []*annotations.ItemDTO{{
+		Time:         1500,
+		TimeEnd:      1600,
+		Text:         "deploy",
+		DashboardUID: &dashboardUID,
+		PanelID:      2,
+		Login:        "admin",
+	}}}
+	s := newService(nil, nil, repo, nil, nil, nil)
+
+	raw, err := json.Marshal(requestModel{
+		QueryType:        queryTypeAnnotationsFrame,
+		AnnotationsFrame: annotationsQueryModel{Tags: []string{"deploy"}, Type: "annotation"},
+	})
+	require.NoError(t, err)
+
+	rsp := s.doAnnotationsQuery(ctx, req, backend.DataQuery{
+		RefID:     "A",
+		TimeRange: backend.TimeRange{From: from, To: to},
+		JSON:      raw,
+	})
+	require.NoError(t, rsp.Error)
+
+	require.Equal(t, int64(1), repo.query.OrgID)
+	require.Equal(t, int64(1000), repo.query.From)
+	require.Equal(t, int64(2000), repo.query.To)
+	require.Equal(t, []string{"deploy"}, repo.query.Tags)
+	require.Equal(t, "annotation", repo.query.Type)
+	require.Equal(t, int64(defaultAnnotationsLimit), repo.query.Limit)
+
+	require.Len(t, rsp.Frames, 1)
+	frame := rsp.Frames[0]
+	require.Equal(t, 1, frame.Rows())
+	require.Equal(t, time.UnixMilli(1500), frame.Fields[0].At(0))
+	require.Equal(t, "deploy", frame.Fields[2].At(0))
+	require.Equal(t, json.RawMessage(`[]`), frame.Fields[3].At(0))
+	require.Equal(t, "dash", frame.Fields[4].At(0))
+	require.Equal(t, int64(2), frame.Fields[5].At(0))
+}
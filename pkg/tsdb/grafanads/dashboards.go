@@ -0,0 +1,134 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/search/model"
+)
+
+// defaultDashboardsLimit matches the default limit of the search HTTP API
+const defaultDashboardsLimit = 1000
+
+func (s *Service) doDashboardsQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	if s.dashboards == nil {
+		return backend.ErrDataResponse(backend.StatusInternal, "dashboards are not available")
+	}
+
+	r := requestModel{}
+	if err := json.Unmarshal(query.JSON, &r); err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse query json: %v", err))
+	}
+	m := r.Dashboards
+
+	user, err := appcontext.User(ctx)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusUnauthorized, "dashboards query requires a signed in user")
+	}
+	orgID := req.PluginContext.OrgID
+
+	limit := m.Limit
+	if limit <= 0 {
+		limit = defaultDashboardsLimit
+	}
+
+	// The search checks the permissions of the user, the dashboards are only fetched for the metadata
+	hits, err := s.dashboards.SearchDashboards(ctx, &dashboards.FindPersistedDashboardsQuery{
+		Title:        m.Query,
+		Tags:         m.Tags,
+		Limit:        limit,
+		OrgId:        orgID,
+		SignedInUser: user,
+		Type:         string(model.DashHitDB),
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	byUID := make(map[string]*dashboards.Dashboard, len(hits))
+	if len(hits) > 0 {
+		uids := make([]string, 0, len(hits))
+		for _, hit := range hits {
+			uids = append(uids, hit.UID)
+		}
+		dashes, err := s.dashboards.GetDashboards(ctx, &dashboards.GetDashboardsQuery{
+			DashboardUIDs: uids,
+			OrgID:         orgID,
+		})
+		if err != nil {
+			return backend.DataResponse{Error: err}
+		}
+		for _, dash := range dashes {
+			byUID[dash.UID] = dash
+		}
+	}
+
+	frame, err := dashboardsToFrame(hits, byUID)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+func dashboardsToFrame(hits model.HitList, byUID map[string]*dashboards.Dashboard) (*data.Frame, error) {
+	count := len(hits)
+	uids := make([]string, count)
+	titles := make([]string, count)
+	urls := make([]string, count)
+	folderUIDs := make([]string, count)
+	folderTitles := make([]string, count)
+	tags := make([]json.RawMessage, count)
+	starred := make([]bool, count)
+	versions := make([]*int64, count)
+	created := make([]*time.Time, count)
+	updated := make([]*time.Time, count)
+
+	for i, hit := range hits {
+		uids[i] = hit.UID
+		titles[i] = hit.Title
+		urls[i] = hit.URL
+		folderUIDs[i] = hit.FolderUID
+		folderTitles[i] = hit.FolderTitle
+		starred[i] = hit.IsStarred
+
+		hitTags := hit.Tags
+		if hitTags == nil {
+			hitTags = []string{}
+		}
+		raw, err := json.Marshal(hitTags)
+		if err != nil {
+			return nil, err
+		}
+		tags[i] = raw
+
+		if dash, ok := byUID[hit.UID]; ok {
+			version := int64(dash.Version)
+			versions[i] = &version
+			createdAt, updatedAt := dash.Created, dash.Updated
+			created[i] = &createdAt
+			updated[i] = &updatedAt
+		}
+	}
+
+	frame := data.NewFrame("dashboards",
+		data.NewField("uid", nil, uids),
+		data.NewField("title", nil, titles),
+		data.NewField("url", nil, urls),
+		data.NewField("folderUID", nil, folderUIDs),
+		data.NewField("folderTitle", nil, folderTitles),
+		data.NewField("tags", nil, tags),
+		data.NewField("starred", nil, starred),
+		data.NewField("version", nil, versions),
+		data.NewField("created", nil, created),
+		data.NewField("updated", nil, updated),
+	)
+	frame.SetMeta(&data.FrameMeta{Type: data.FrameTypeTable})
+	return frame, nil
+}
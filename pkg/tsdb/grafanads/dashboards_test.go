@@ -0,0 +1,53 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestDashboardsQuery(t *testing.T) {
+	ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{OrgID: 1})
+	req := &backend.QueryDataRequest{PluginContext: backend.PluginContext{OrgID: 1}}
+	updated := time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("SearchDashboards", mock.Anything, mock.MatchedBy(func(q *dashboards.FindPersistedDashboardsQuery) bool {
+		return q.Title == "prod" && q.Limit == defaultDashboardsLimit && q.OrgId == 1 && q.Type == string(model.DashHitDB)
+	})).Return(model.HitList{
+		{UID: "a", Title: "Prod A", Tags: []string{"prod"}, FolderTitle: "Team"},
+		{UID: "b", Title: "Prod B"},
+	}, nil)
+	dashboardService.On("GetDashboards", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardsQuery) bool {
+		return len(q.DashboardUIDs) == 2 && q.OrgID == 1
+	})).Return([]*dashboards.Dashboard{{UID: "a", Version: 3, Created: updated, Updated: updated}}, nil)
+
+	s := newService(nil, nil, nil, dashboardService, nil, nil)
+
+	raw, err := json.Marshal(requestModel{QueryType: queryTypeDashboards, Dashboards: dashboardsQueryModel{Query: "prod"}})
+	require.NoError(t, err)
+
+	rsp := s.doDashboardsQuery(ctx, req, backend.DataQuery{RefID: "A", JSON: raw})
+	require.NoError(t, rsp.Error)
+
+	require.Len(t, rsp.Frames, 1)
+	frame := rsp.Frames[0]
+	require.Equal(t, 2, frame.Rows())
+	require.Equal(t, "Prod A", frame.Fields[1].At(0))
+	require.Equal(t, "Team", frame.Fields[4].At(0))
+	require.Equal(t, json.RawMessage(`["prod"]`), frame.Fields[5].At(0))
+	require.Equal(t, int64(3), *frame.Fields[7].At(0).(*int64))
+	require.Equal(t, updated, *frame.Fields[9].At(0).(*time.Time))
+	// the version is unknown for dashboards that are not returned by the store
+	require.Nil(t, frame.Fields[7].At(1))
+}
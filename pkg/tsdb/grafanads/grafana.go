@@ -14,6 +14,9 @@ import (
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/store"
@@ -51,15 +54,21 @@ var (
 	)
 )
 
-func ProvideService(search searchV2.SearchService, store store.StorageService) *Service {
-	return newService(search, store)
+func ProvideService(search searchV2.SearchService, store store.StorageService, annotations annotations.Repository,
+	dashboards dashboards.DashboardService, alerts AlertStore, ac accesscontrol.AccessControl) *Service {
+	return newService(search, store, annotations, dashboards, alerts, ac)
 }
 
-func newService(search searchV2.SearchService, store store.StorageService) *Service {
+func newService(search searchV2.SearchService, store store.StorageService, annotations annotations.Repository,
+	dashboards dashboards.DashboardService, alerts AlertStore, ac accesscontrol.AccessControl) *Service {
 	s := &Service{
-		search: search,
-		store:  store,
-		log:    log.New("grafanads"),
+		search:      search,
+		store:       store,
+		annotations: annotations,
+		dashboards:  dashboards,
+		alerts:      alerts,
+		ac:          ac,
+		log:         log.New("grafanads"),
 	}
 
 	return s
@@ -67,9 +76,13 @@ func newService(search searchV2.SearchService, store store.StorageService) *Serv
 
 // Service exists regardless of user settings
 type Service struct {
-	search searchV2.SearchService
-	store  store.StorageService
-	log    log.Logger
+	search      searchV2.SearchService
+	store       store.StorageService
+	annotations annotations.Repository
+	dashboards  dashboards.DashboardService
+	alerts      AlertStore
+	ac          accesscontrol.AccessControl
+	log         log.Logger
 }
 
 func DataSourceModel(orgId int64) *datasources.DataSource {
@@ -97,6 +110,12 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 			response.Responses[q.RefID] = s.doReadQuery(ctx, q)
 		case queryTypeSearch:
 			response.Responses[q.RefID] = s.doSearchQuery(ctx, req, q)
+		case queryTypeAnnotationsFrame:
+			response.Responses[q.RefID] = s.doAnnotationsQuery(ctx, req, q)
+		case queryTypeAlertStates:
+			response.Responses[q.RefID] = s.doAlertStatesQuery(ctx, req, q)
+		case queryTypeDashboards:
+			response.Responses[q.RefID] = s.doDashboardsQuery(ctx, req, q)
 		default:
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
@@ -198,6 +217,9 @@ func (s *Service) doSearchQuery(ctx context.Context, req *backend.QueryDataReque
 }
 
 type requestModel struct {
-	QueryType string                  `json:"queryType"`
-	Search    searchV2.DashboardQuery `json:"search,omitempty"`
+	QueryType        string                  `json:"queryType"`
+	Search           searchV2.DashboardQuery `json:"search,omitempty"`
+	AnnotationsFrame annotationsQueryModel   `json:"annotationsFrame,omitempty"`
+	AlertStates      alertStatesQueryModel   `json:"alertStates,omitempty"`
+	Dashboards       dashboardsQueryModel    `json:"dashboards,omitempty"`
 }
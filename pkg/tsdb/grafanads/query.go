@@ -22,3 +22,35 @@ type listQueryModel struct {
 type readQueryModel struct {
 	Path string `json:"path"`
 }
+
+const (
+	// QueryTypeAnnotationsFrame returns the annotations of the time range as a data frame
+	queryTypeAnnotationsFrame = "annotationsFrame"
+
+	// QueryTypeAlertStates returns the state of the alert rule instances as a table
+	queryTypeAlertStates = "alertStates"
+
+	// QueryTypeDashboards returns the dashboards with their metadata as a table
+	queryTypeDashboards = "dashboards"
+)
+
+type annotationsQueryModel struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	MatchAny     bool     `json:"matchAny,omitempty"`
+	// Type is either "annotation" or "alert", empty returns both
+	Type  string `json:"type,omitempty"`
+	Limit int64  `json:"limit,omitempty"`
+}
+
+type alertStatesQueryModel struct {
+	DashboardUID string `json:"dashboardUID,omitempty"`
+	// States filters the instances by state (Normal, Alerting, Pending, NoData, Error), empty returns all
+	States []string `json:"states,omitempty"`
+}
+
+type dashboardsQueryModel struct {
+	Query string   `json:"query,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	Limit int64    `json:"limit,omitempty"`
+}
@@ -17,7 +17,9 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/patrickmn/go-cache"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 
@@ -32,8 +34,9 @@ import (
 var logger = log.New("tsdb.graphite")
 
 type Service struct {
-	im     instancemgmt.InstanceManager
-	tracer tracing.Tracer
+	im              instancemgmt.InstanceManager
+	tracer          tracing.Tracer
+	resourceHandler backend.CallResourceHandler
 }
 
 const (
@@ -42,16 +45,21 @@ const (
 )
 
 func ProvideService(httpClientProvider httpclient.Provider, tracer tracing.Tracer) *Service {
-	return &Service{
+	s := &Service{
 		im:     datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
 		tracer: tracer,
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
 type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
 	Id         int64
+
+	// resourceCache holds the responses of resource calls and the list of Graphite functions
+	resourceCache *cache.Cache
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -67,9 +75,10 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 		}
 
 		model := datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
-			Id:         settings.ID,
+			HTTPClient:    client,
+			URL:           settings.URL,
+			Id:            settings.ID,
+			resourceCache: newResourceCache(),
 		}
 
 		return model, nil
@@ -85,6 +94,10 @@ func (s *Service) getDSInfo(ctx context.Context, pluginCtx backend.PluginContext
 	return &instance, nil
 }
 
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	if len(req.Queries) == 0 {
 		return nil, fmt.Errorf("query contains no queries")
@@ -114,8 +127,17 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		"target":        []string{},
 	}
 
+	// Validate targets up front so invalid queries fail with a clear error instead of an opaque Graphite error
+	queries, invalidResponses, err := s.validateQueries(dsInfo, req.Queries)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) == 0 {
+		return &backend.QueryDataResponse{Responses: invalidResponses}, nil
+	}
+
 	// Convert datasource query to graphite target request
-	targetList, emptyQueries, origRefIds, err := s.processQueries(logger, req.Queries)
+	targetList, emptyQueries, origRefIds, err := s.processQueries(logger, queries)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +146,7 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	if len(emptyQueries) != 0 {
 		logger.Warn("Found query models without targets", "models without targets", strings.Join(emptyQueries, "\n"))
 		// If no queries had a valid target, return an error; otherwise, attempt with the targets we have
-		if len(emptyQueries) == len(req.Queries) {
+		if len(emptyQueries) == len(queries) {
 			return &result, errors.New("no query target found for the alert rule")
 		}
 	}
@@ -176,7 +198,7 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	}
 
 	result = backend.QueryDataResponse{
-		Responses: make(backend.Responses),
+		Responses: invalidResponses,
 	}
 
 	for _, f := range frames {
@@ -193,6 +215,35 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	return &result, nil
 }
 
+// validateQueries checks the syntax of each query target and returns the valid queries, along with
+// error responses for the invalid ones. Function names are only checked when the list of functions
+// supported by the Graphite server has been loaded by the functions resource.
+func (s *Service) validateQueries(dsInfo *datasourceInfo, queries []backend.DataQuery) ([]backend.DataQuery, backend.Responses, error) {
+	valid := make([]backend.DataQuery, 0, len(queries))
+	invalid := make(backend.Responses)
+	functions := cachedFunctions(dsInfo)
+
+	for _, query := range queries {
+		model, err := simplejson.NewJson(query.JSON)
+		if err != nil {
+			return nil, nil, err
+		}
+		target := model.Get(TargetFullModelField).MustString(model.Get(TargetModelField).MustString())
+		if target == "" {
+			valid = append(valid, query)
+			continue
+		}
+
+		if err := validateTarget(target, functions); err != nil {
+			invalid[query.RefID] = backend.DataResponse{Error: err}
+			continue
+		}
+		valid = append(valid, query)
+	}
+
+	return valid, invalid, nil
+}
+
 // processQueries converts each datasource query to a graphite query target. It returns the list of
 // targets, a list of invalid queries, and a mapping of formatted refIds (used in the target query)
 // to original query refIds, later used to associate ressponses with the original queries
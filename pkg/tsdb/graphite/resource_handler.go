@@ -0,0 +1,225 @@
+package graphite
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/patrickmn/go-cache"
+)
+
+const (
+	// functionsCacheTTL is how long the list of Graphite functions is cached, it only changes when Graphite is upgraded
+	functionsCacheTTL = time.Hour
+	// resourceCacheTTL is how long tag and metric autocomplete responses are cached
+	resourceCacheTTL = time.Minute
+
+	functionsCacheKey = "functions"
+)
+
+// forwardedHeaders are the headers of the resource requests identifying the user, set when the data source forwards
+// the OAuth identity, the allowed cookies or the user name. They are sent to Graphite like for the queries, and the
+// responses are cached for each identity.
+var forwardedHeaders = []string{"Authorization", "X-ID-Token", "Cookie", "X-Grafana-User"}
+
+// infinityDefault matches function parameter defaults Graphite serializes as the invalid JSON literal Infinity
+var infinityDefault = regexp.MustCompile(`"default":\s*Infinity`)
+
+type cachedResourceResponse struct {
+	status  int
+	headers http.Header
+	body    []byte
+}
+
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/functions", s.handleResourceReq("functions", functionsCacheTTL))
+	mux.HandleFunc("/tags/autoComplete/tags", s.handleResourceReq("tags/autoComplete/tags", resourceCacheTTL))
+	mux.HandleFunc("/tags/autoComplete/values", s.handleResourceReq("tags/autoComplete/values", resourceCacheTTL))
+	mux.HandleFunc("/metrics/find", s.handleResourceReq("metrics/find", resourceCacheTTL))
+	return mux
+}
+
+// handleResourceReq proxies the request to the given Graphite endpoint and caches successful responses
+// for the given duration, keyed by the endpoint, query string, request body and forwarded identity.
+// The function list returned by the functions endpoint is also kept to validate the query targets.
+func (s *Service) handleResourceReq(graphitePath string, ttl time.Duration) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodPost {
+			writeResponse(rw, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method %s", req.Method))
+			return
+		}
+
+		pluginCtx := httpadapter.PluginConfigFromContext(req.Context())
+		dsInfo, err := s.getDSInfo(req.Context(), pluginCtx)
+		if err != nil {
+			writeResponse(rw, http.StatusInternalServerError, fmt.Sprintf("unexpected error %v", err))
+			return
+		}
+
+		var body []byte
+		if req.Body != nil {
+			body, err = io.ReadAll(req.Body)
+			if err != nil {
+				writeResponse(rw, http.StatusBadRequest, fmt.Sprintf("failed to read request body %v", err))
+				return
+			}
+		}
+
+		headers := http.Header{}
+		for _, name := range forwardedHeaders {
+			if values := req.Header.Values(name); len(values) > 0 {
+				headers[name] = values
+			}
+		}
+		if ct := req.Header.Get("Content-Type"); ct != "" {
+			headers.Set("Content-Type", ct)
+		}
+
+		cacheKey := fmt.Sprintf("%s?%s|%s|%s", graphitePath, req.URL.RawQuery, body, identityHash(headers))
+		if dsInfo.resourceCache != nil {
+			if cached, found := dsInfo.resourceCache.Get(cacheKey); found {
+				writeCachedResponse(rw, cached.(*cachedResourceResponse))
+				return
+			}
+		}
+
+		resp, err := s.doResourceRequest(req.Context(), dsInfo, req.Method, graphitePath, req.URL.Query(), body, headers)
+		if err != nil {
+			writeResponse(rw, http.StatusBadGateway, fmt.Sprintf("failed to query graphite %v", err))
+			return
+		}
+
+		if resp.status/100 == 2 && dsInfo.resourceCache != nil {
+			dsInfo.resourceCache.Set(cacheKey, resp, ttl)
+			if graphitePath == "functions" {
+				cacheFunctions(dsInfo, resp.body)
+			}
+		}
+		writeCachedResponse(rw, resp)
+	}
+}
+
+// identityHash returns a hash of the forwarded identity headers, so that the cache keys don't contain credentials
+func identityHash(headers http.Header) string {
+	h := sha256.New()
+	for _, name := range forwardedHeaders {
+		for _, value := range headers.Values(name) {
+			_, _ = fmt.Fprintf(h, "%s=%s\n", name, value)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *Service) doResourceRequest(ctx context.Context, dsInfo *datasourceInfo, method string, graphitePath string, params url.Values, body []byte, headers http.Header) (*cachedResourceResponse, error) {
+	if dsInfo.HTTPClient == nil {
+		return nil, fmt.Errorf("no http client configured for data source")
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, graphitePath)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resHeaders := http.Header{}
+	if ct := res.Header.Get("Content-Type"); ct != "" {
+		resHeaders.Set("Content-Type", ct)
+	}
+
+	return &cachedResourceResponse{status: res.StatusCode, headers: resHeaders, body: resBody}, nil
+}
+
+// cachedFunctions returns the names of the functions supported by the Graphite server, as last returned by the
+// functions resource. It returns nil when the list hasn't been loaded, in which case the function names of the
+// query targets are not validated.
+func cachedFunctions(dsInfo *datasourceInfo) map[string]struct{} {
+	if dsInfo.resourceCache == nil {
+		return nil
+	}
+	if cached, found := dsInfo.resourceCache.Get(functionsCacheKey); found {
+		return cached.(map[string]struct{})
+	}
+	return nil
+}
+
+func cacheFunctions(dsInfo *datasourceInfo, body []byte) {
+	functions, err := parseFunctions(body)
+	if err != nil {
+		logger.Debug("Unable to parse graphite functions, skipping function validation", "error", err)
+		return
+	}
+	dsInfo.resourceCache.Set(functionsCacheKey, functions, functionsCacheTTL)
+}
+
+func parseFunctions(body []byte) (map[string]struct{}, error) {
+	body = infinityDefault.ReplaceAll(body, []byte(`"default": "Infinity"`))
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse graphite functions: %w", err)
+	}
+
+	functions := make(map[string]struct{}, len(raw))
+	for name := range raw {
+		functions[name] = struct{}{}
+	}
+	return functions, nil
+}
+
+func writeCachedResponse(rw http.ResponseWriter, resp *cachedResourceResponse) {
+	for k, v := range resp.headers {
+		rw.Header()[k] = v
+	}
+	writeResponseBytes(rw, resp.status, resp.body)
+}
+
+func writeResponse(rw http.ResponseWriter, code int, msg string) {
+	writeResponseBytes(rw, code, []byte(msg))
+}
+
+func writeResponseBytes(rw http.ResponseWriter, code int, msg []byte) {
+	rw.WriteHeader(code)
+	_, err := rw.Write(msg)
+	if err != nil {
+		logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
+
+func newResourceCache() *cache.Cache {
+	return cache.New(resourceCacheTTL, 5*time.Minute)
+}
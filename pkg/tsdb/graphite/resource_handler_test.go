@@ -0,0 +1,141 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceHandler(t *testing.T) {
+	requests := map[string]int{}
+	var authorizations []string
+	graphite := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/functions":
+			_, _ = w.Write([]byte(`{"sumSeries": {"name": "sumSeries"}}`))
+		case "/tags/autoComplete/values":
+			_, _ = w.Write([]byte(`["app"]`))
+		case "/tags/autoComplete/tags":
+			assert.Equal(t, "na", r.URL.Query().Get("tagPrefix"))
+			_, _ = w.Write([]byte(`["name"]`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(graphite.Close)
+
+	dsInfo := datasourceInfo{
+		HTTPClient:    graphite.Client(),
+		URL:           graphite.URL,
+		resourceCache: newResourceCache(),
+	}
+	s := &Service{im: staticInstanceManager{dsInfo}}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+
+	callResourceWithHeaders := func(path string, query string, headers map[string][]string) *backend.CallResourceResponse {
+		sender := &fakeSender{}
+		err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+			Method:  http.MethodGet,
+			Path:    path,
+			URL:     path + "?" + query,
+			Headers: headers,
+		}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+	callResource := func(path string, query string) *backend.CallResourceResponse {
+		return callResourceWithHeaders(path, query, nil)
+	}
+
+	t.Run("Proxies and caches tag autocomplete requests", func(t *testing.T) {
+		resp := callResource("tags/autoComplete/tags", "tagPrefix=na")
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `["name"]`, string(resp.Body))
+
+		resp = callResource("tags/autoComplete/tags", "tagPrefix=na")
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.Equal(t, 1, requests["/tags/autoComplete/tags"])
+	})
+
+	t.Run("Does not cache failed requests", func(t *testing.T) {
+		resp := callResource("metrics/find", "query=*")
+		assert.Equal(t, http.StatusInternalServerError, resp.Status)
+
+		_ = callResource("metrics/find", "query=*")
+		assert.Equal(t, 2, requests["/metrics/find"])
+	})
+
+	t.Run("Forwards the user identity and caches the responses for each identity", func(t *testing.T) {
+		authorizations = nil
+		alice := map[string][]string{"Authorization": {"Bearer alice"}}
+		bob := map[string][]string{"Authorization": {"Bearer bob"}}
+
+		_ = callResourceWithHeaders("tags/autoComplete/values", "tag=name", alice)
+		_ = callResourceWithHeaders("tags/autoComplete/values", "tag=name", bob)
+		_ = callResourceWithHeaders("tags/autoComplete/values", "tag=name", alice)
+		assert.Equal(t, 2, requests["/tags/autoComplete/values"])
+		assert.Equal(t, []string{"Bearer alice", "Bearer bob"}, authorizations)
+	})
+
+	t.Run("Only checks the syntax of query targets until the function list is loaded", func(t *testing.T) {
+		resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", JSON: []byte(`{"target": "sumSeries(app.*.count"}`)},
+			},
+		})
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+		assert.Contains(t, resp.Responses["A"].Error.Error(), "unbalanced parentheses")
+		assert.Equal(t, 0, requests["/functions"])
+		assert.Nil(t, cachedFunctions(&dsInfo))
+	})
+
+	t.Run("Validates query targets against the cached function list", func(t *testing.T) {
+		functionsResp := callResource("functions", "")
+		require.Equal(t, http.StatusOK, functionsResp.Status)
+
+		resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", JSON: []byte(`{"target": "sumSeriez(app.*.count)"}`)},
+				{RefID: "B", JSON: []byte(`{"target": "sumSeries(app.*.count"}`)},
+			},
+		})
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+		assert.Contains(t, resp.Responses["A"].Error.Error(), `unknown function "sumSeriez"`)
+		require.Error(t, resp.Responses["B"].Error)
+		assert.Contains(t, resp.Responses["B"].Error.Error(), "unbalanced parentheses")
+		assert.Equal(t, 0, requests["/render"])
+	})
+}
+
+type fakeSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (sender *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	sender.response = resp
+	return nil
+}
+
+type staticInstanceManager struct {
+	dsInfo datasourceInfo
+}
+
+func (m staticInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return m.dsInfo, nil
+}
+
+func (m staticInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
@@ -0,0 +1,81 @@
+package graphite
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// validateTarget performs a basic syntax check of a Graphite target so that obviously invalid
+// queries fail with a clear error instead of an opaque error from the Graphite server.
+// It verifies that parentheses are balanced and, if functions is not nil, that every
+// function called in the target is supported by the Graphite server.
+func validateTarget(target string, functions map[string]struct{}) error {
+	depth := 0
+	var quote rune
+	// identStart and identEnd track the position of the name preceding a '(', allowing whitespace in between
+	identStart, identEnd := -1, -1
+
+	for i, r := range target {
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case r == '\'' || r == '"':
+			quote = r
+			identStart, identEnd = -1, -1
+		case r == '(':
+			if identStart == -1 {
+				return fmt.Errorf("invalid target %q: unexpected '(' at position %d", target, i)
+			}
+			end := i
+			if identEnd != -1 {
+				end = identEnd
+			}
+			name := target[identStart:end]
+			if functions != nil {
+				if _, ok := functions[name]; !ok {
+					return fmt.Errorf("invalid target %q: unknown function %q", target, name)
+				}
+			}
+			depth++
+			identStart, identEnd = -1, -1
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("invalid target %q: unexpected ')' at position %d", target, i)
+			}
+			identStart, identEnd = -1, -1
+		case unicode.IsSpace(r):
+			if identStart != -1 && identEnd == -1 {
+				identEnd = i
+			}
+		case isFunctionNameRune(r, identStart == -1 || identEnd != -1):
+			if identStart == -1 || identEnd != -1 {
+				identStart, identEnd = i, -1
+			}
+		default:
+			identStart, identEnd = -1, -1
+		}
+	}
+
+	if quote != 0 {
+		return fmt.Errorf("invalid target %q: unterminated string", target)
+	}
+	if depth != 0 {
+		return fmt.Errorf("invalid target %q: unbalanced parentheses", target)
+	}
+	return nil
+}
+
+// isFunctionNameRune reports whether r can be part of a function name. Function names
+// start with a letter, and metric path characters such as '.' or '*' reset the name.
+func isFunctionNameRune(r rune, first bool) bool {
+	if first {
+		return unicode.IsLetter(r)
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
@@ -0,0 +1,68 @@
+package graphite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTarget(t *testing.T) {
+	functions := map[string]struct{}{
+		"aliasByNode":   {},
+		"averageSeries": {},
+		"hitcount":      {},
+		"seriesByTag":   {},
+	}
+
+	t.Run("Accepts valid targets", func(t *testing.T) {
+		targets := []string{
+			"app.grafana.*.dashboards.views.1M.count",
+			"aliasByNode(hitcount(averageSeries(app.grafana.*.dashboards.views.count), '1min'), 4)",
+			"seriesByTag('name=cpu', 'host=~web(01|02)')",
+			"aliasByNode (app.grafana.*.count, 2)",
+		}
+		for _, target := range targets {
+			assert.NoError(t, validateTarget(target, functions), target)
+		}
+	})
+
+	t.Run("Rejects unbalanced parentheses", func(t *testing.T) {
+		err := validateTarget("aliasByNode(averageSeries(app.grafana.*.count), 4", functions)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unbalanced parentheses")
+
+		err = validateTarget("averageSeries(app.grafana.*.count))", functions)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected ')'")
+	})
+
+	t.Run("Rejects unterminated strings", func(t *testing.T) {
+		err := validateTarget("hitcount(app.grafana.*.count, '1min)", functions)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unterminated string")
+	})
+
+	t.Run("Rejects unknown functions", func(t *testing.T) {
+		err := validateTarget("aliasByNode(sumSeriez(app.grafana.*.count), 4)", functions)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown function "sumSeriez"`)
+	})
+
+	t.Run("Skips function name validation without a function list", func(t *testing.T) {
+		assert.NoError(t, validateTarget("sumSeriez(app.grafana.*.count)", nil))
+	})
+}
+
+func TestParseFunctions(t *testing.T) {
+	body := `{
+		"aliasByNode": {"name": "aliasByNode", "params": []},
+		"limit": {"name": "limit", "params": [{"name": "n", "type": "integer", "default": Infinity}]}
+	}`
+
+	functions, err := parseFunctions([]byte(body))
+	require.NoError(t, err)
+	assert.Len(t, functions, 2)
+	assert.Contains(t, functions, "aliasByNode")
+	assert.Contains(t, functions, "limit")
+}
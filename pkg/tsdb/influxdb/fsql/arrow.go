@@ -0,0 +1,170 @@
+package fsql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// columnAppender appends the value at row i of an arrow column to a frame field
+type columnAppender func(field *data.Field, col arrow.Array, i int)
+
+// frameConverter accumulates arrow records sharing the same schema into a data frame
+type frameConverter struct {
+	frame     *data.Frame
+	appenders []columnAppender
+}
+
+func newFrameConverter(schema *arrow.Schema) (*frameConverter, error) {
+	c := &frameConverter{
+		frame:     data.NewFrame(""),
+		appenders: make([]columnAppender, 0, len(schema.Fields())),
+	}
+
+	for _, f := range schema.Fields() {
+		fieldType, appender, err := fieldTypeFor(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", f.Name, err)
+		}
+		if f.Nullable {
+			fieldType = fieldType.NullableType()
+		}
+
+		c.frame.Fields = append(c.frame.Fields, data.NewFieldFromFieldType(fieldType, 0))
+		c.frame.Fields[len(c.frame.Fields)-1].Name = f.Name
+		c.appenders = append(c.appenders, appender)
+	}
+	return c, nil
+}
+
+// append adds the rows of the record to the frame
+func (c *frameConverter) append(record arrow.Record) error {
+	if int(record.NumCols()) != len(c.appenders) {
+		return fmt.Errorf("record has %d columns, expected %d", record.NumCols(), len(c.appenders))
+	}
+
+	for i, col := range record.Columns() {
+		field := c.frame.Fields[i]
+		appender := c.appenders[i]
+		for row := 0; row < col.Len(); row++ {
+			if col.IsNull(row) {
+				if !field.Nullable() {
+					return fmt.Errorf("column %q contains null values but is not nullable", field.Name)
+				}
+				field.Append(nil)
+				continue
+			}
+			appender(field, col, row)
+		}
+	}
+	return nil
+}
+
+// fieldTypeFor returns the non-nullable frame field type matching the arrow type, and the
+// function used to copy values from arrow columns of that type.
+func fieldTypeFor(t arrow.DataType) (data.FieldType, columnAppender, error) {
+	switch t.ID() {
+	case arrow.BOOL:
+		return data.FieldTypeBool, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Boolean).Value(i))
+		}, nil
+	case arrow.INT8:
+		return data.FieldTypeInt8, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Int8).Value(i))
+		}, nil
+	case arrow.INT16:
+		return data.FieldTypeInt16, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Int16).Value(i))
+		}, nil
+	case arrow.INT32:
+		return data.FieldTypeInt32, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Int32).Value(i))
+		}, nil
+	case arrow.INT64:
+		return data.FieldTypeInt64, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Int64).Value(i))
+		}, nil
+	case arrow.UINT8:
+		return data.FieldTypeUint8, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Uint8).Value(i))
+		}, nil
+	case arrow.UINT16:
+		return data.FieldTypeUint16, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Uint16).Value(i))
+		}, nil
+	case arrow.UINT32:
+		return data.FieldTypeUint32, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Uint32).Value(i))
+		}, nil
+	case arrow.UINT64:
+		return data.FieldTypeUint64, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Uint64).Value(i))
+		}, nil
+	case arrow.FLOAT32:
+		return data.FieldTypeFloat32, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Float32).Value(i))
+		}, nil
+	case arrow.FLOAT64:
+		return data.FieldTypeFloat64, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.Float64).Value(i))
+		}, nil
+	case arrow.STRING:
+		return data.FieldTypeString, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, col.(*array.String).Value(i))
+		}, nil
+	case arrow.BINARY:
+		return data.FieldTypeString, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, string(col.(*array.Binary).Value(i)))
+		}, nil
+	case arrow.TIMESTAMP:
+		unit := t.(*arrow.TimestampType).Unit
+		return data.FieldTypeTime, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, timestampToTime(int64(col.(*array.Timestamp).Value(i)), unit))
+		}, nil
+	case arrow.DATE32:
+		return data.FieldTypeTime, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, time.Unix(int64(col.(*array.Date32).Value(i))*86400, 0).UTC())
+		}, nil
+	case arrow.DATE64:
+		return data.FieldTypeTime, func(f *data.Field, col arrow.Array, i int) {
+			appendValue(f, time.UnixMilli(int64(col.(*array.Date64).Value(i))).UTC())
+		}, nil
+	case arrow.DICTIONARY:
+		// InfluxDB returns the tags as dictionary encoded strings
+		fieldType, appender, err := fieldTypeFor(t.(*arrow.DictionaryType).ValueType)
+		if err != nil {
+			return data.FieldTypeUnknown, nil, err
+		}
+		return fieldType, func(f *data.Field, col arrow.Array, i int) {
+			dict := col.(*array.Dictionary)
+			appender(f, dict.Dictionary(), dict.GetValueIndex(i))
+		}, nil
+	default:
+		return data.FieldTypeUnknown, nil, fmt.Errorf("unsupported arrow type %s", t)
+	}
+}
+
+// appendValue appends v to the field, as a pointer if the field is nullable
+func appendValue[T any](f *data.Field, v T) {
+	if f.Nullable() {
+		f.Append(&v)
+		return
+	}
+	f.Append(v)
+}
+
+func timestampToTime(v int64, unit arrow.TimeUnit) time.Time {
+	switch unit {
+	case arrow.Second:
+		return time.Unix(v, 0).UTC()
+	case arrow.Millisecond:
+		return time.UnixMilli(v).UTC()
+	case arrow.Microsecond:
+		return time.UnixMicro(v).UTC()
+	default:
+		return time.Unix(0, v).UTC()
+	}
+}
@@ -0,0 +1,119 @@
+package fsql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameConverter(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "time", Type: &arrow.TimestampType{Unit: arrow.Nanosecond}},
+		{Name: "host", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "usage", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "count", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "up", Type: arrow.FixedWidthTypes.Boolean},
+	}, nil)
+
+	now := time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC)
+	newRecord := func() arrow.Record {
+		b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+		defer b.Release()
+
+		b.Field(0).(*array.TimestampBuilder).AppendValues([]arrow.Timestamp{arrow.Timestamp(now.UnixNano()), arrow.Timestamp(now.Add(time.Minute).UnixNano())}, nil)
+		b.Field(1).(*array.StringBuilder).AppendValues([]string{"a", ""}, []bool{true, false})
+		b.Field(2).(*array.Float64Builder).AppendValues([]float64{1.5, 0}, []bool{true, false})
+		b.Field(3).(*array.Int64Builder).AppendValues([]int64{1, 2}, nil)
+		b.Field(4).(*array.BooleanBuilder).AppendValues([]bool{true, false}, nil)
+		return b.NewRecord()
+	}
+
+	c, err := newFrameConverter(schema)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		rec := newRecord()
+		require.NoError(t, c.append(rec))
+		rec.Release()
+	}
+
+	frame := c.frame
+	require.Equal(t, 4, frame.Rows())
+	require.Equal(t, data.FieldTypeTime, frame.Fields[0].Type())
+	require.Equal(t, data.FieldTypeNullableString, frame.Fields[1].Type())
+	require.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[2].Type())
+	require.Equal(t, data.FieldTypeInt64, frame.Fields[3].Type())
+	require.Equal(t, data.FieldTypeBool, frame.Fields[4].Type())
+
+	require.Equal(t, now, frame.Fields[0].At(0))
+	require.Equal(t, now.Add(time.Minute), frame.Fields[0].At(1))
+	require.Equal(t, "a", *frame.Fields[1].At(0).(*string))
+	require.Nil(t, frame.Fields[1].At(1))
+	require.Equal(t, 1.5, *frame.Fields[2].At(0).(*float64))
+	require.Nil(t, frame.Fields[2].At(1))
+	require.Equal(t, int64(2), frame.Fields[3].At(3))
+}
+
+func TestFrameConverterDictionaryColumns(t *testing.T) {
+	dictType := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int32, ValueType: arrow.BinaryTypes.String}
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "host", Type: dictType, Nullable: true},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	hosts := b.Field(0).(*array.BinaryDictionaryBuilder)
+	require.NoError(t, hosts.AppendString("a"))
+	require.NoError(t, hosts.AppendString("b"))
+	require.NoError(t, hosts.AppendString("a"))
+	hosts.AppendNull()
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	c, err := newFrameConverter(schema)
+	require.NoError(t, err)
+	require.NoError(t, c.append(rec))
+
+	field := c.frame.Fields[0]
+	require.Equal(t, data.FieldTypeNullableString, field.Type())
+	require.Equal(t, "a", *field.At(0).(*string))
+	require.Equal(t, "b", *field.At(1).(*string))
+	require.Equal(t, "a", *field.At(2).(*string))
+	require.Nil(t, field.At(3))
+}
+
+func TestFrameConverterUnsupportedTypes(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "values", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64)},
+	}, nil)
+
+	_, err := newFrameConverter(schema)
+	require.ErrorContains(t, err, `column "values"`)
+}
+
+func TestToTimeSeries(t *testing.T) {
+	now := time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("long frames are converted to wide frames", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{now, now}),
+			data.NewField("host", nil, []string{"a", "b"}),
+			data.NewField("usage", nil, []float64{1, 2}),
+		)
+		wide, err := toTimeSeries(frame, nil)
+		require.NoError(t, err)
+		require.Len(t, wide.Fields, 3)
+		require.Equal(t, data.Labels{"host": "a"}, wide.Fields[1].Labels)
+	})
+
+	t.Run("frames without a time column return an error", func(t *testing.T) {
+		frame := data.NewFrame("", data.NewField("usage", nil, []float64{1}))
+		_, err := toTimeSeries(frame, nil)
+		require.Error(t, err)
+	})
+}
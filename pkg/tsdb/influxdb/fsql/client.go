@@ -0,0 +1,184 @@
+package fsql
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/apache/arrow/go/v12/arrow/flight"
+	"github.com/apache/arrow/go/v12/arrow/flight/flightsql"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	sdkproxy "github.com/grafana/grafana-plugin-sdk-go/backend/proxy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/net/proxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
+)
+
+type queryRunner interface {
+	runQuery(ctx context.Context, sql string) (*data.Frame, error)
+}
+
+// Client executes SQL queries with Flight SQL. The gRPC connection of the client is shared by all the
+// queries of a data source instance, and closed when the instance is disposed.
+type Client struct {
+	client *flightsql.Client
+	md     metadata.MD
+}
+
+// NewClient creates the Flight SQL client of the data source. The connection is established on the
+// first query. The TLS settings and the secure socks proxy of the HTTP client options of the data
+// source are used by the gRPC connection too.
+func NewClient(dsInfo *models.DatasourceInfo, opts httpclient.Options) (*Client, error) {
+	if dsInfo.URL == "" {
+		return nil, fmt.Errorf("missing URL from datasource configuration")
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("bad URL %q: %w", dsInfo.URL, err)
+	}
+
+	secure := u.Scheme != "http"
+	addr := u.Host
+	if u.Port() == "" {
+		if secure {
+			addr += ":443"
+		} else {
+			addr += ":80"
+		}
+	}
+
+	dialOpts, err := grpcDialOptions(secure && !dsInfo.InsecureGrpc, opts)
+	if err != nil {
+		return nil, err
+	}
+	client, err := flightsql.NewClient(addr, nil, nil, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create flight sql client: %w", err)
+	}
+
+	return &Client{client: client, md: requestMetadata(dsInfo)}, nil
+}
+
+// grpcDialOptions returns the transport credentials built from the TLS settings of the data source, its custom
+// CA, client certificate and skip verification, and the dialer of the secure socks proxy when it is enabled.
+func grpcDialOptions(secure bool, opts httpclient.Options) ([]grpc.DialOption, error) {
+	var dialOpts []grpc.DialOption
+	if !secure {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		tlsConfig, err := httpclient.GetTLSConfig(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TLS config: %w", err)
+		}
+		if tlsConfig.RootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load system certificates: %w", err)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	if sdkproxy.Cli.SecureSocksProxyEnabled(opts.ProxyOptions) {
+		dialer, err := sdkproxy.Cli.NewSecureSocksProxyContextDialer(opts.ProxyOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create secure socks proxy dialer: %w", err)
+		}
+		contextDialer, ok := dialer.(proxy.ContextDialer)
+		if !ok {
+			return nil, errors.New("secure socks proxy dialer does not support contexts")
+		}
+		dialOpts = append(dialOpts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return contextDialer.DialContext(ctx, "tcp", addr)
+		}))
+	}
+	return dialOpts, nil
+}
+
+// requestMetadata returns the gRPC metadata sent with every query: the configured metadata, the
+// database and the token of the data source.
+func requestMetadata(dsInfo *models.DatasourceInfo) metadata.MD {
+	md := metadata.MD{}
+	for _, m := range dsInfo.Metadata {
+		for k, v := range m {
+			if k != "" && v != "" {
+				md.Set(strings.ToLower(k), v)
+			}
+		}
+	}
+	if dsInfo.DbName != "" && len(md.Get("database")) == 0 {
+		md.Set("database", dsInfo.DbName)
+	}
+	if dsInfo.Token != "" {
+		md.Set("authorization", fmt.Sprintf("Bearer %s", dsInfo.Token))
+	}
+	return md
+}
+
+// Close closes the connection of the client.
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// runQuery executes the SQL statement and reads the records of every endpoint returned
+// by the server into a single frame.
+func (c *Client) runQuery(ctx context.Context, sql string) (*data.Frame, error) {
+	ctx = metadata.NewOutgoingContext(ctx, c.md)
+	info, err := c.client.Execute(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	var converter *frameConverter
+	for _, endpoint := range info.Endpoint {
+		if err := c.readEndpoint(ctx, endpoint, &converter); err != nil {
+			return nil, err
+		}
+	}
+
+	if converter == nil {
+		return data.NewFrame(""), nil
+	}
+	return converter.frame, nil
+}
+
+func (c *Client) readEndpoint(ctx context.Context, endpoint *flight.FlightEndpoint, converter **frameConverter) error {
+	reader, err := c.client.DoGet(ctx, endpoint.Ticket)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("failed to read query results: %w", err)
+	}
+	defer reader.Release()
+
+	if *converter == nil {
+		fc, err := newFrameConverter(reader.Schema())
+		if err != nil {
+			return err
+		}
+		*converter = fc
+	}
+
+	for reader.Next() {
+		if err := (*converter).append(reader.Record()); err != nil {
+			return err
+		}
+	}
+	if err := reader.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read query results: %w", err)
+	}
+	return nil
+}
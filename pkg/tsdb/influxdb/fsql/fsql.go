@@ -0,0 +1,94 @@
+package fsql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
+)
+
+var (
+	glog = log.New("tsdb.influx_flightsql")
+)
+
+// Query executes SQL queries against InfluxDB using the Flight SQL client of the data source instance
+// and returns the results as data frames.
+func Query(ctx context.Context, dsInfo *models.DatasourceInfo, client *Client, req backend.QueryDataRequest) (
+	*backend.QueryDataResponse, error) {
+	logger := glog.FromContext(ctx)
+	tRes := backend.NewQueryDataResponse()
+
+	for _, query := range req.Queries {
+		qm, err := getQueryModel(query, dsInfo)
+		if err != nil {
+			tRes.Responses[query.RefID] = backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("bad request: %s", err.Error()))
+			continue
+		}
+
+		tRes.Responses[query.RefID] = executeQuery(ctx, logger, qm, client)
+	}
+	return tRes, nil
+}
+
+func executeQuery(ctx context.Context, logger log.Logger, qm *queryModel, r queryRunner) backend.DataResponse {
+	engine := newMacroEngine()
+	sql, err := engine.interpolate(qm)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("interpolation failed: %s", err.Error()))
+	}
+
+	logger.Debug("Executing Flight SQL query", "refId", qm.RefID, "query", sql)
+	frame, err := r.runQuery(ctx, sql)
+	if err != nil {
+		return backend.DataResponse{
+			Error:  fmt.Errorf("flight sql query error: %w", err),
+			Frames: data.Frames{errorFrame(sql)},
+		}
+	}
+
+	frame.RefID = qm.RefID
+	if frame.Meta == nil {
+		frame.Meta = &data.FrameMeta{}
+	}
+	frame.Meta.ExecutedQueryString = sql
+
+	if qm.Format == formatTimeSeries {
+		frame, err = toTimeSeries(frame, engine.fillMissing)
+		if err != nil {
+			return backend.DataResponse{
+				Error:  err,
+				Frames: data.Frames{errorFrame(sql)},
+			}
+		}
+	}
+
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// toTimeSeries converts a frame in the long format, with a time column and optional string
+// columns used as labels, to the wide format expected by time series visualizations.
+func toTimeSeries(frame *data.Frame, fillMissing *data.FillMissing) (*data.Frame, error) {
+	if frame.Rows() == 0 {
+		return frame, nil
+	}
+
+	schema := frame.TimeSeriesSchema()
+	switch schema.Type {
+	case data.TimeSeriesTypeNot:
+		return nil, fmt.Errorf("no time column found, a time series query must return a time column")
+	case data.TimeSeriesTypeLong:
+		return data.LongToWide(frame, fillMissing)
+	default:
+		return frame, nil
+	}
+}
+
+func errorFrame(sql string) *data.Frame {
+	frame := data.NewFrame("")
+	frame.SetMeta(&data.FrameMeta{ExecutedQueryString: sql})
+	return frame
+}
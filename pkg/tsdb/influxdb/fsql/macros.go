@@ -0,0 +1,159 @@
+package fsql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+const rsIdentifier = `([_a-zA-Z0-9]+)`
+const sExpr = `\$` + rsIdentifier + `\(([^\)]*)\)`
+
+var macroRegexp = regexp.MustCompile(sExpr)
+
+// macroEngine interpolates macros in SQL queries written for the InfluxDB SQL dialect, based on Apache DataFusion
+type macroEngine struct {
+	*sqleng.SQLMacroEngineBase
+
+	// fillMissing is set when a time grouping macro is called with a fill argument
+	fillMissing *data.FillMissing
+}
+
+func newMacroEngine() *macroEngine {
+	return &macroEngine{
+		SQLMacroEngineBase: sqleng.NewSQLMacroEngineBase(),
+	}
+}
+
+func (m *macroEngine) interpolate(qm *queryModel) (string, error) {
+	sql, err := sqleng.Interpolate(qm.Query, qm.Query.TimeRange, qm.TimeInterval, qm.RawSQL)
+	if err != nil {
+		return "", err
+	}
+
+	var macroError error
+	sql = m.ReplaceAllStringSubmatchFunc(macroRegexp, sql, func(groups []string) string {
+		args := strings.Split(groups[2], ",")
+		for i, arg := range args {
+			args[i] = strings.Trim(arg, " ")
+		}
+		res, err := m.evaluateMacro(qm, groups[1], args)
+		if err != nil && macroError == nil {
+			macroError = err
+			return "macro_error()"
+		}
+		return res
+	})
+
+	if macroError != nil {
+		return "", macroError
+	}
+
+	return sql, nil
+}
+
+//nolint:gocyclo
+func (m *macroEngine) evaluateMacro(qm *queryModel, name string, args []string) (string, error) {
+	timeRange := qm.Query.TimeRange
+	switch name {
+	case "__time":
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s AS \"time\"", args[0]), nil
+	case "__timeEpoch":
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("extract(epoch from %s) AS \"time\"", args[0]), nil
+	case "__timeFilter":
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= '%s' AND %s <= '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339Nano), args[0], timeRange.To.UTC().Format(time.RFC3339Nano)), nil
+	case "__timeFrom":
+		return fmt.Sprintf("'%s'", timeRange.From.UTC().Format(time.RFC3339Nano)), nil
+	case "__timeTo":
+		return fmt.Sprintf("'%s'", timeRange.To.UTC().Format(time.RFC3339Nano)), nil
+	case "__timeGroup":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		if len(args) == 3 {
+			if err := m.setupFillmode(args[2]); err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("date_bin(interval '%d nanoseconds', %s, timestamp '1970-01-01T00:00:00Z')", interval.Nanoseconds(), args[0]), nil
+	case "__timeGroupAlias":
+		tg, err := m.evaluateMacro(qm, "__timeGroup", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
+	case "__unixEpochFilter":
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().Unix(), args[0], timeRange.To.UTC().Unix()), nil
+	case "__unixEpochNanoFilter":
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().UnixNano(), args[0], timeRange.To.UTC().UnixNano()), nil
+	case "__unixEpochNanoFrom":
+		return fmt.Sprintf("%d", timeRange.From.UTC().UnixNano()), nil
+	case "__unixEpochNanoTo":
+		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
+	case "__unixEpochGroup":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		if len(args) == 3 {
+			if err := m.setupFillmode(args[2]); err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("floor((%s)/%v)*%v", args[0], interval.Seconds(), interval.Seconds()), nil
+	case "__unixEpochGroupAlias":
+		tg, err := m.evaluateMacro(qm, "__unixEpochGroup", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
+	default:
+		return "", fmt.Errorf("unknown macro %q", name)
+	}
+}
+
+// setupFillmode configures how missing points are filled when the result is converted to a time series
+func (m *macroEngine) setupFillmode(fillmode string) error {
+	switch fillmode {
+	case "NULL":
+		m.fillMissing = &data.FillMissing{Mode: data.FillModeNull}
+	case "previous":
+		m.fillMissing = &data.FillMissing{Mode: data.FillModePrevious}
+	default:
+		floatVal, err := strconv.ParseFloat(fillmode, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing fill value %v", fillmode)
+		}
+		m.fillMissing = &data.FillMissing{Mode: data.FillModeValue, Value: floatVal}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package fsql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolate(t *testing.T) {
+	timeRange := backend.TimeRange{
+		From: time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC),
+		To:   time.Date(2023, 7, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+	}{
+		{
+			name:   "time and epoch macros",
+			before: `SELECT $__time(time), $__timeEpoch(time) FROM cpu`,
+			after:  `SELECT time AS "time", extract(epoch from time) AS "time" FROM cpu`,
+		},
+		{
+			name:   "time filter",
+			before: `SELECT * FROM cpu WHERE $__timeFilter(time)`,
+			after:  `SELECT * FROM cpu WHERE time >= '2023-07-01T10:00:00Z' AND time <= '2023-07-01T11:00:00Z'`,
+		},
+		{
+			name:   "time from and to",
+			before: `SELECT * FROM cpu WHERE time > $__timeFrom() AND time < $__timeTo()`,
+			after:  `SELECT * FROM cpu WHERE time > '2023-07-01T10:00:00Z' AND time < '2023-07-01T11:00:00Z'`,
+		},
+		{
+			name:   "time group with interval variable",
+			before: `SELECT $__timeGroupAlias(time, $__interval), avg(usage) FROM cpu GROUP BY 1`,
+			after:  `SELECT date_bin(interval '60000000000 nanoseconds', time, timestamp '1970-01-01T00:00:00Z') AS "time", avg(usage) FROM cpu GROUP BY 1`,
+		},
+		{
+			name:   "unix epoch macros",
+			before: `SELECT $__unixEpochGroupAlias(ts, 5m) FROM t WHERE $__unixEpochFilter(ts) AND $__unixEpochNanoFilter(ns)`,
+			after:  `SELECT floor((ts)/300)*300 AS "time" FROM t WHERE ts >= 1688205600 AND ts <= 1688209200 AND ns >= 1688205600000000000 AND ns <= 1688209200000000000`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qm := &queryModel{
+				RawSQL: tt.before,
+				Query:  backend.DataQuery{TimeRange: timeRange, Interval: time.Minute},
+			}
+			sql, err := newMacroEngine().interpolate(qm)
+			require.NoError(t, err)
+			assert.Equal(t, tt.after, sql)
+		})
+	}
+
+	t.Run("fill argument sets up fill mode", func(t *testing.T) {
+		engine := newMacroEngine()
+		_, err := engine.interpolate(&queryModel{
+			RawSQL: `SELECT $__timeGroup(time, 1m, previous) FROM cpu`,
+			Query:  backend.DataQuery{TimeRange: timeRange},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &data.FillMissing{Mode: data.FillModePrevious}, engine.fillMissing)
+	})
+
+	t.Run("missing arguments return an error", func(t *testing.T) {
+		_, err := newMacroEngine().interpolate(&queryModel{
+			RawSQL: `SELECT * FROM cpu WHERE $__timeFilter()`,
+			Query:  backend.DataQuery{TimeRange: timeRange},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("unknown macros return an error", func(t *testing.T) {
+		_, err := newMacroEngine().interpolate(&queryModel{
+			RawSQL: `SELECT $__unknown(time) FROM cpu`,
+			Query:  backend.DataQuery{TimeRange: timeRange},
+		})
+		require.Error(t, err)
+	})
+}
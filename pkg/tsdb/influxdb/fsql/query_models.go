@@ -0,0 +1,49 @@
+package fsql
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
+)
+
+const (
+	formatTable      = "table"
+	formatTimeSeries = "time_series"
+)
+
+// queryModel represents a query.
+type queryModel struct {
+	RawSQL string `json:"rawSql"`
+	Format string `json:"format"`
+
+	// Not from JSON
+	RefID        string            `json:"-"`
+	Query        backend.DataQuery `json:"-"`
+	TimeInterval string            `json:"-"`
+}
+
+func getQueryModel(query backend.DataQuery, dsInfo *models.DatasourceInfo) (*queryModel, error) {
+	model := &queryModel{}
+	if err := json.Unmarshal(query.JSON, model); err != nil {
+		return nil, fmt.Errorf("error reading query: %w", err)
+	}
+	if model.RawSQL == "" {
+		return nil, fmt.Errorf("query is empty")
+	}
+
+	switch model.Format {
+	case "":
+		model.Format = formatTable
+	case formatTable, formatTimeSeries:
+	default:
+		return nil, fmt.Errorf("unsupported format %q", model.Format)
+	}
+
+	model.RefID = query.RefID
+	model.Query = query
+	model.TimeInterval = dsInfo.TimeInterval
+	return model, nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/influxdb/flux"
+	"github.com/grafana/grafana/pkg/tsdb/influxdb/fsql"
 	"github.com/grafana/grafana/pkg/tsdb/influxdb/models"
 )
 
@@ -32,6 +33,22 @@ type Service struct {
 
 var ErrInvalidHttpMode = errors.New("'httpMode' should be either 'GET' or 'POST'")
 
+// instance is the data source instance. The Flight SQL client of the instance is shared by its SQL queries.
+type instance struct {
+	dsInfo    *models.DatasourceInfo
+	sqlClient *fsql.Client
+}
+
+// Dispose closes the Flight SQL connection when the instance is replaced or removed.
+func (i *instance) Dispose() {
+	if i.sqlClient == nil {
+		return
+	}
+	if err := i.sqlClient.Close(); err != nil {
+		logger.Warn("Failed to close Flight SQL client", "error", err)
+	}
+}
+
 func ProvideService(httpClient httpclient.Provider) *Service {
 	return &Service{
 		queryParser:    &InfluxdbQueryParser{},
@@ -83,9 +100,19 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			DefaultBucket: jsonData.DefaultBucket,
 			Organization:  jsonData.Organization,
 			MaxSeries:     maxSeries,
+			Metadata:      jsonData.Metadata,
+			InsecureGrpc:  jsonData.InsecureGrpc,
 			Token:         settings.DecryptedSecureJSONData["token"],
 		}
-		return model, nil
+
+		inst := &instance{dsInfo: model}
+		if version == influxVersionSQL {
+			inst.sqlClient, err = fsql.NewClient(model, opts)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return inst, nil
 	}
 }
 
@@ -93,16 +120,20 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	logger := logger.FromContext(ctx)
 	logger.Debug("Received a query request", "numQueries", len(req.Queries))
 
-	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	inst, err := s.getInstance(ctx, req.PluginContext)
 	if err != nil {
 		return nil, err
 	}
+	dsInfo := inst.dsInfo
 	version := dsInfo.Version
-	if version == "Flux" {
+	switch version {
+	case influxVersionFlux:
 		return flux.Query(ctx, dsInfo, *req)
+	case influxVersionSQL:
+		return fsql.Query(ctx, dsInfo, inst.sqlClient, *req)
 	}
 
-	logger.Debug("Making a non-Flux type query")
+	logger.Debug("Making an InfluxQL query")
 
 	var allRawQueries string
 	queries := make([]Query, 0, len(req.Queries))
@@ -192,16 +223,16 @@ func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *
 	return req, nil
 }
 
-func (s *Service) getDSInfo(ctx context.Context, pluginCtx backend.PluginContext) (*models.DatasourceInfo, error) {
+func (s *Service) getInstance(ctx context.Context, pluginCtx backend.PluginContext) (*instance, error) {
 	i, err := s.im.Get(ctx, pluginCtx)
 	if err != nil {
 		return nil, err
 	}
 
-	instance, ok := i.(*models.DatasourceInfo)
+	inst, ok := i.(*instance)
 	if !ok {
 		return nil, fmt.Errorf("failed to cast datsource info")
 	}
 
-	return instance, nil
+	return inst, nil
 }
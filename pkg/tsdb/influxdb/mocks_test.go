@@ -65,17 +65,19 @@ func (f *fakeInstance) Get(_ context.Context, _ backend.PluginContext) (instance
 		return nil, err
 	}
 
-	return &models.DatasourceInfo{
-		HTTPClient:    client,
-		Token:         "sometoken",
-		URL:           "https://awesome-influx.com",
-		DbName:        "testdb",
-		Version:       f.version,
-		HTTPMode:      "GET",
-		TimeInterval:  "10s",
-		DefaultBucket: "testbucket",
-		Organization:  "testorg",
-		MaxSeries:     2,
+	return &instance{
+		dsInfo: &models.DatasourceInfo{
+			HTTPClient:    client,
+			Token:         "sometoken",
+			URL:           "https://awesome-influx.com",
+			DbName:        "testdb",
+			Version:       f.version,
+			HTTPMode:      "GET",
+			TimeInterval:  "10s",
+			DefaultBucket: "testbucket",
+			Organization:  "testorg",
+			MaxSeries:     2,
+		},
 	}, nil
 }
 
@@ -16,4 +16,8 @@ type DatasourceInfo struct {
 	DefaultBucket string `json:"defaultBucket"`
 	Organization  string `json:"organization"`
 	MaxSeries     int    `json:"maxSeries"`
+
+	// Flight SQL settings, used when Version is SQL
+	Metadata     []map[string]string `json:"metadata"`
+	InsecureGrpc bool                `json:"insecureGrpc"`
 }
@@ -55,7 +55,7 @@ func makeDataRequest(ctx context.Context, lokiDsUrl string, query lokiQuery) (*h
 	}
 
 	switch query.QueryType {
-	case QueryTypeRange:
+	case QueryTypeRange, QueryTypeVolume, QueryTypePatterns:
 		{
 			qs.Set("start", strconv.FormatInt(query.Start.UnixNano(), 10))
 			qs.Set("end", strconv.FormatInt(query.End.UnixNano(), 10))
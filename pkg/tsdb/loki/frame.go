@@ -47,7 +47,7 @@ func adjustMetricFrame(frame *data.Frame, query *lokiQuery, setFrameName bool) e
 
 	labels := getFrameLabels(frame)
 
-	isMetricRange := query.QueryType == QueryTypeRange
+	isMetricRange := query.QueryType == QueryTypeRange || query.QueryType == QueryTypeVolume
 
 	name := formatName(labels, query)
 	if setFrameName {
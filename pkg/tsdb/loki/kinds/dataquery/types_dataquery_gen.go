@@ -17,9 +17,11 @@ const (
 
 // Defines values for LokiQueryType.
 const (
-	LokiQueryTypeInstant LokiQueryType = "instant"
-	LokiQueryTypeRange   LokiQueryType = "range"
-	LokiQueryTypeStream  LokiQueryType = "stream"
+	LokiQueryTypeInstant  LokiQueryType = "instant"
+	LokiQueryTypePatterns LokiQueryType = "patterns"
+	LokiQueryTypeRange    LokiQueryType = "range"
+	LokiQueryTypeStream   LokiQueryType = "stream"
+	LokiQueryTypeVolume   LokiQueryType = "volume"
 )
 
 // Defines values for QueryEditorMode.
@@ -212,6 +212,13 @@ func runQuery(ctx context.Context, api *LokiAPI, query *lokiQuery, responseOpts
 		}
 	}
 
+	switch query.QueryType {
+	case QueryTypeVolume:
+		adjustVolumeFrames(frames, query)
+	case QueryTypePatterns:
+		return data.Frames{makePatternsFrame(frames, query)}, nil
+	}
+
 	return frames, nil
 }
 
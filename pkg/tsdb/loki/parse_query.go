@@ -65,6 +65,10 @@ func parseQueryType(jsonPointerValue *string) (QueryType, error) {
 			return QueryTypeInstant, nil
 		case "range":
 			return QueryTypeRange, nil
+		case "volume":
+			return QueryTypeVolume, nil
+		case "patterns":
+			return QueryTypePatterns, nil
 		default:
 			return QueryTypeRange, fmt.Errorf("invalid queryType: %s", jsonValue)
 		}
@@ -158,6 +162,24 @@ func parseQuery(queryContext *backend.QueryDataRequest) ([]*lokiQuery, error) {
 			return nil, err
 		}
 
+		switch queryType {
+		case QueryTypeVolume:
+			expr, err = makeVolumeExpr(expr, step)
+			if err != nil {
+				return nil, err
+			}
+			if supportingQueryType == SupportingQueryNone {
+				supportingQueryType = SupportingQueryLogsVolume
+			}
+		case QueryTypePatterns:
+			if !isLogsQuery(expr) {
+				return nil, fmt.Errorf("patterns can only be computed for log queries")
+			}
+			if maxLines <= 0 {
+				maxLines = defaultPatternsMaxLines
+			}
+		}
+
 		qs = append(qs, &lokiQuery{
 			Expr:                expr,
 			QueryType:           queryType,
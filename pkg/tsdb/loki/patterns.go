@@ -0,0 +1,159 @@
+package loki
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	// defaultPatternsMaxLines is the number of log lines clustered when the query doesn't set a line limit
+	defaultPatternsMaxLines = 1000
+
+	// patternWildcard replaces the tokens that vary between the log lines of a pattern
+	patternWildcard = "<_>"
+
+	// drainDepth is the number of leading tokens used to group log lines before comparing them
+	drainDepth = 2
+	// drainSimilarityThreshold is the minimal ratio of matching tokens for a log line to join a pattern
+	drainSimilarityThreshold = 0.5
+	// drainMaxClustersPerGroup limits the number of patterns compared with every log line
+	drainMaxClustersPerGroup = 100
+)
+
+type logCluster struct {
+	tokens []string
+	count  int64
+	sample string
+}
+
+// drain clusters log lines into patterns using a simplified version of the Drain algorithm
+// (He et al., "Drain: An Online Log Parsing Approach with Fixed Depth Tree"). Log lines are grouped
+// by their number of tokens and their leading tokens, and every line is then merged into the most
+// similar pattern of its group, the tokens that differ being replaced by a wildcard.
+type drain struct {
+	groups   map[string][]*logCluster
+	clusters []*logCluster
+}
+
+func newDrain() *drain {
+	return &drain{
+		groups: make(map[string][]*logCluster),
+	}
+}
+
+func (d *drain) add(line string) {
+	tokens := strings.Fields(line)
+	if len(tokens) == 0 {
+		return
+	}
+
+	key := groupKey(tokens)
+	group := d.groups[key]
+
+	var best *logCluster
+	bestSimilarity := -1.0
+	for _, c := range group {
+		similarity := tokensSimilarity(c.tokens, tokens)
+		if similarity > bestSimilarity {
+			best, bestSimilarity = c, similarity
+		}
+	}
+
+	if best != nil && (bestSimilarity >= drainSimilarityThreshold || len(group) >= drainMaxClustersPerGroup) {
+		for i, token := range tokens {
+			if best.tokens[i] != token {
+				best.tokens[i] = patternWildcard
+			}
+		}
+		best.count++
+		return
+	}
+
+	c := &logCluster{
+		tokens: append([]string(nil), tokens...),
+		count:  1,
+		sample: line,
+	}
+	d.groups[key] = append(group, c)
+	d.clusters = append(d.clusters, c)
+}
+
+// groupKey returns the key of the group of the log line, based on the number of tokens and the
+// leading tokens. Tokens containing digits are likely variables and don't take part in the key.
+func groupKey(tokens []string) string {
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(len(tokens)))
+	for i := 0; i < drainDepth && i < len(tokens); i++ {
+		sb.WriteString(" ")
+		if strings.IndexFunc(tokens[i], unicode.IsDigit) >= 0 {
+			sb.WriteString(patternWildcard)
+		} else {
+			sb.WriteString(tokens[i])
+		}
+	}
+	return sb.String()
+}
+
+// tokensSimilarity returns the ratio of the tokens of the pattern matching the tokens of the log line
+func tokensSimilarity(pattern []string, tokens []string) float64 {
+	if len(pattern) != len(tokens) {
+		return 0
+	}
+	matching := 0
+	for i, token := range pattern {
+		if token == tokens[i] {
+			matching++
+		}
+	}
+	return float64(matching) / float64(len(tokens))
+}
+
+// patterns returns the clusters sorted by decreasing number of log lines
+func (d *drain) patterns() []*logCluster {
+	clusters := append([]*logCluster(nil), d.clusters...)
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].count > clusters[j].count
+	})
+	return clusters
+}
+
+// makePatternsFrame clusters the log lines of the logs frames into patterns and returns
+// a frame with the pattern, the number of log lines and an example log line for every pattern.
+func makePatternsFrame(frames data.Frames, query *lokiQuery) *data.Frame {
+	d := newDrain()
+	for _, frame := range frames {
+		// logs frames are "labels, time, line, ..." after adjustment
+		if len(frame.Fields) < 3 || frame.Fields[2].Type() != data.FieldTypeString {
+			continue
+		}
+		lineField := frame.Fields[2]
+		for i := 0; i < lineField.Len(); i++ {
+			d.add(lineField.At(i).(string))
+		}
+	}
+
+	clusters := d.patterns()
+	patterns := make([]string, 0, len(clusters))
+	counts := make([]int64, 0, len(clusters))
+	samples := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		patterns = append(patterns, strings.Join(c.tokens, " "))
+		counts = append(counts, c.count)
+		samples = append(samples, c.sample)
+	}
+
+	frame := data.NewFrame("patterns",
+		data.NewField("pattern", nil, patterns),
+		data.NewField("count", nil, counts),
+		data.NewField("sample", nil, samples),
+	)
+	frame.SetMeta(&data.FrameMeta{
+		ExecutedQueryString:    "Expr: " + query.Expr,
+		PreferredVisualization: data.VisTypeTable,
+	})
+	return frame
+}
@@ -0,0 +1,62 @@
+package loki
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrain(t *testing.T) {
+	t.Run("similar log lines are clustered into one pattern", func(t *testing.T) {
+		d := newDrain()
+		d.add("user 1 logged in from 10.0.0.1")
+		d.add("user 2 logged in from 10.0.0.2")
+		d.add("user 3 logged in from 10.0.0.1")
+		d.add("connection closed")
+
+		patterns := d.patterns()
+		require.Len(t, patterns, 2)
+		require.Equal(t, "user <_> logged in from <_>", strings.Join(patterns[0].tokens, " "))
+		require.Equal(t, int64(3), patterns[0].count)
+		require.Equal(t, "user 1 logged in from 10.0.0.1", patterns[0].sample)
+		require.Equal(t, "connection closed", strings.Join(patterns[1].tokens, " "))
+		require.Equal(t, int64(1), patterns[1].count)
+	})
+
+	t.Run("log lines with different leading tokens are not clustered", func(t *testing.T) {
+		d := newDrain()
+		d.add("GET /api/health 200")
+		d.add("POST /api/health 200")
+		require.Len(t, d.patterns(), 2)
+	})
+
+	t.Run("dissimilar log lines of the same length are not clustered", func(t *testing.T) {
+		d := newDrain()
+		d.add("level=info msg=starting component=a")
+		d.add("level=info msg=stopping reason=b")
+		require.Len(t, d.patterns(), 2)
+	})
+
+	t.Run("empty log lines are ignored", func(t *testing.T) {
+		d := newDrain()
+		d.add("  ")
+		require.Empty(t, d.patterns())
+	})
+}
+
+func TestMakePatternsFrame(t *testing.T) {
+	frame := data.NewFrame("",
+		data.NewField("labels", nil, []string{"", "", ""}),
+		data.NewField("time", nil, []int64{1, 2, 3}),
+		data.NewField("line", nil, []string{"request took 10ms", "request took 12ms", "cache miss"}),
+	)
+
+	result := makePatternsFrame(data.Frames{frame}, &lokiQuery{Expr: `{job="app"}`})
+	require.Equal(t, 2, result.Rows())
+	require.Equal(t, "request took <_>", result.Fields[0].At(0))
+	require.Equal(t, int64(2), result.Fields[1].At(0))
+	require.Equal(t, "cache miss", result.Fields[0].At(1))
+	require.Equal(t, data.VisType(data.VisTypeTable), result.Meta.PreferredVisualization)
+}
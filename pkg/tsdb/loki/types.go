@@ -11,8 +11,10 @@ type SupportingQueryType = dataquery.SupportingQueryType
 type Direction = dataquery.LokiQueryDirection
 
 const (
-	QueryTypeRange   = dataquery.LokiQueryTypeRange
-	QueryTypeInstant = dataquery.LokiQueryTypeInstant
+	QueryTypeRange    = dataquery.LokiQueryTypeRange
+	QueryTypeInstant  = dataquery.LokiQueryTypeInstant
+	QueryTypeVolume   = dataquery.LokiQueryTypeVolume
+	QueryTypePatterns = dataquery.LokiQueryTypePatterns
 )
 
 const (
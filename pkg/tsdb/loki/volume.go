@@ -0,0 +1,61 @@
+package loki
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	volumeLevelLabel   = "level"
+	volumeUnknownLevel = "unknown"
+)
+
+// isLogsQuery reports whether expr is a log query, as opposed to a metric query.
+// Log queries always start with a stream selector.
+func isLogsQuery(expr string) bool {
+	return strings.HasPrefix(strings.TrimSpace(expr), "{")
+}
+
+// makeVolumeExpr converts a log query to a metric query counting the log lines per level
+// in every step, which is used to draw the log volume histogram.
+func makeVolumeExpr(expr string, step time.Duration) (string, error) {
+	if !isLogsQuery(expr) {
+		return "", fmt.Errorf("log volume can only be computed for log queries")
+	}
+	// we use milliseconds for the same reason as the step parameter of range queries,
+	// Loki does not support durations with a fractional part like "1.5s"
+	return fmt.Sprintf("sum by (%s) (count_over_time(%s[%dms]))", volumeLevelLabel, strings.TrimSpace(expr), step.Milliseconds()), nil
+}
+
+// adjustVolumeFrames names every log volume series by its level, unless a legend format is
+// used. Log lines without a level label are reported with the "unknown" level.
+func adjustVolumeFrames(frames data.Frames, query *lokiQuery) {
+	for _, frame := range frames {
+		if len(frame.Fields) != 2 {
+			continue
+		}
+		valueField := frame.Fields[1]
+		if valueField.Labels == nil {
+			valueField.Labels = data.Labels{}
+		}
+		level, ok := valueField.Labels[volumeLevelLabel]
+		if !ok || level == "" {
+			level = volumeUnknownLevel
+			valueField.Labels[volumeLevelLabel] = level
+		}
+
+		if query.LegendFormat != "" {
+			continue
+		}
+		if frame.Name != "" {
+			frame.Name = level
+		}
+		if valueField.Config == nil {
+			valueField.Config = &data.FieldConfig{}
+		}
+		valueField.Config.DisplayNameFromDS = level
+	}
+}
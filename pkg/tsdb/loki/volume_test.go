@@ -0,0 +1,79 @@
+package loki
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeVolumeExpr(t *testing.T) {
+	t.Run("log queries are converted to a count per level", func(t *testing.T) {
+		expr, err := makeVolumeExpr(` {job="app"} |= "error" `, 1500*time.Millisecond)
+		require.NoError(t, err)
+		require.Equal(t, `sum by (level) (count_over_time({job="app"} |= "error"[1500ms]))`, expr)
+	})
+
+	t.Run("metric queries return an error", func(t *testing.T) {
+		_, err := makeVolumeExpr(`rate({job="app"}[1m])`, time.Minute)
+		require.Error(t, err)
+	})
+}
+
+func TestParseVolumeAndPatternsQueries(t *testing.T) {
+	makeRequest := func(json string) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					JSON: []byte(json),
+					TimeRange: backend.TimeRange{
+						From: time.Now().Add(-1 * time.Hour),
+						To:   time.Now(),
+					},
+					Interval: time.Minute,
+				},
+			},
+		}
+	}
+
+	t.Run("volume queries are converted to metric queries", func(t *testing.T) {
+		queries, err := parseQuery(makeRequest(`{"expr": "{job=\"app\"}", "queryType": "volume", "refId": "A"}`))
+		require.NoError(t, err)
+		require.Equal(t, QueryTypeVolume, queries[0].QueryType)
+		require.Equal(t, `sum by (level) (count_over_time({job="app"}[60000ms]))`, queries[0].Expr)
+		require.Equal(t, SupportingQueryLogsVolume, queries[0].SupportingQueryType)
+	})
+
+	t.Run("patterns queries use a default line limit", func(t *testing.T) {
+		queries, err := parseQuery(makeRequest(`{"expr": "{job=\"app\"}", "queryType": "patterns", "refId": "A"}`))
+		require.NoError(t, err)
+		require.Equal(t, QueryTypePatterns, queries[0].QueryType)
+		require.Equal(t, defaultPatternsMaxLines, queries[0].MaxLines)
+	})
+
+	t.Run("patterns queries must be log queries", func(t *testing.T) {
+		_, err := parseQuery(makeRequest(`{"expr": "count_over_time({job=\"app\"}[1m])", "queryType": "patterns", "refId": "A"}`))
+		require.Error(t, err)
+	})
+}
+
+func TestAdjustVolumeFrames(t *testing.T) {
+	frames := data.Frames{
+		data.NewFrame("{level=\"error\"}",
+			data.NewField("time", nil, []time.Time{time.Unix(0, 0)}),
+			data.NewField("value", data.Labels{"level": "error"}, []float64{1}),
+		),
+		data.NewFrame("{}",
+			data.NewField("time", nil, []time.Time{time.Unix(0, 0)}),
+			data.NewField("value", nil, []float64{2}),
+		),
+	}
+
+	adjustVolumeFrames(frames, &lokiQuery{})
+	require.Equal(t, "error", frames[0].Name)
+	require.Equal(t, "error", frames[0].Fields[1].Config.DisplayNameFromDS)
+	require.Equal(t, "unknown", frames[1].Name)
+	require.Equal(t, data.Labels{"level": "unknown"}, frames[1].Fields[1].Labels)
+}
@@ -0,0 +1,112 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// annotationQueryModel is the model of the annotation queries of the OpenTSDB annotation editor
+type annotationQueryModel struct {
+	FromAnnotations bool   `json:"fromAnnotations"`
+	Target          string `json:"target"`
+	IsGlobal        bool   `json:"isGlobal"`
+}
+
+func parseAnnotationQuery(query backend.DataQuery) (*annotationQueryModel, bool) {
+	model := &annotationQueryModel{}
+	if err := json.Unmarshal(query.JSON, model); err != nil || !model.FromAnnotations {
+		return nil, false
+	}
+	return model, true
+}
+
+// queryAnnotations returns the annotations of the metric of the query, or the global annotations, in the time range
+// of the query. OpenTSDB can only list the annotations of a time range through the query endpoint, the annotation
+// endpoint only returns single annotations.
+func (s *Service) queryAnnotations(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery, model *annotationQueryModel) backend.DataResponse {
+	tsdbQuery := OpenTsdbQuery{
+		Start:             query.TimeRange.From.UnixMilli(),
+		End:               query.TimeRange.To.UnixMilli(),
+		Queries:           []map[string]interface{}{{"aggregator": "sum", "metric": model.Target}},
+		GlobalAnnotations: model.IsGlobal,
+	}
+
+	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "status", res.Status, "body", string(body))
+		return backend.DataResponse{Error: fmt.Errorf("request failed, status: %s", res.Status)}
+	}
+
+	var responseData []OpenTsdbResponse
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		return backend.DataResponse{Error: fmt.Errorf("failed to parse opentsdb response: %w", err)}
+	}
+
+	var annotations []OpenTsdbAnnotation
+	if len(responseData) > 0 {
+		annotations = responseData[0].Annotations
+		if model.IsGlobal {
+			annotations = responseData[0].GlobalAnnotations
+		}
+	}
+
+	frame := annotationsToFrame(annotations)
+	frame.RefID = query.RefID
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// annotationsToFrame returns a frame with the time, end time and text of the annotations, the times of OpenTSDB
+// annotations are in seconds
+func annotationsToFrame(annotations []OpenTsdbAnnotation) *data.Frame {
+	times := make([]time.Time, 0, len(annotations))
+	timeEnds := make([]*time.Time, 0, len(annotations))
+	texts := make([]string, 0, len(annotations))
+
+	for _, a := range annotations {
+		times = append(times, secondsToTime(a.StartTime))
+		if a.EndTime > 0 {
+			end := secondsToTime(a.EndTime)
+			timeEnds = append(timeEnds, &end)
+		} else {
+			timeEnds = append(timeEnds, nil)
+		}
+		texts = append(texts, a.Description)
+	}
+
+	return data.NewFrame("annotations",
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+	)
+}
+
+func secondsToTime(seconds float64) time.Time {
+	return time.Unix(int64(math.Floor(seconds)), 0).UTC()
+}
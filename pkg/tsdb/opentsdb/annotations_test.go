@@ -0,0 +1,78 @@
+package opentsdb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
+)
+
+func TestAnnotationQuery(t *testing.T) {
+	var requestBody string
+	tsdb := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/query", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requestBody = string(body)
+		_, _ = w.Write([]byte(`[{
+			"metric": "deploys",
+			"dps": {},
+			"annotations": [{"description": "deploy v1", "startTime": 1405544146}],
+			"globalAnnotations": [{"description": "outage", "startTime": 1405544100, "endTime": 1405544200}]
+		}]`))
+	}))
+	t.Cleanup(tsdb.Close)
+
+	s := &Service{
+		im:                 staticInstanceManager{&datasourceInfo{HTTPClient: tsdb.Client(), URL: tsdb.URL}},
+		intervalCalculator: intervalv2.NewCalculator(),
+	}
+
+	query := func(json string) backend.DataResponse {
+		resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID: "Anno",
+				TimeRange: backend.TimeRange{
+					From: time.Unix(1405544000, 0),
+					To:   time.Unix(1405545000, 0),
+				},
+				JSON: []byte(json),
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["Anno"]
+	}
+
+	t.Run("Returns the annotations of the metric", func(t *testing.T) {
+		resp := query(`{"fromAnnotations": true, "target": "deploys"}`)
+		require.NoError(t, resp.Error)
+		assert.JSONEq(t, `{"start":1405544000000,"end":1405545000000,"queries":[{"aggregator":"sum","metric":"deploys"}]}`, requestBody)
+
+		require.Len(t, resp.Frames, 1)
+		frame := resp.Frames[0]
+		require.Equal(t, 1, frame.Rows())
+		assert.Equal(t, time.Unix(1405544146, 0).UTC(), frame.Fields[0].At(0))
+		assert.Nil(t, frame.Fields[1].At(0))
+		assert.Equal(t, "deploy v1", frame.Fields[2].At(0))
+	})
+
+	t.Run("Returns the global annotations", func(t *testing.T) {
+		resp := query(`{"fromAnnotations": true, "target": "deploys", "isGlobal": true}`)
+		require.NoError(t, resp.Error)
+		assert.Contains(t, requestBody, `"globalAnnotations":true`)
+
+		frame := resp.Frames[0]
+		require.Equal(t, 1, frame.Rows())
+		assert.Equal(t, "outage", frame.Fields[2].At(0))
+		end := time.Unix(1405544200, 0).UTC()
+		assert.Equal(t, &end, frame.Fields[1].At(0))
+	})
+}
@@ -15,24 +15,38 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 )
 
 var logger = log.New("tsdb.opentsdb")
 
+const (
+	// defaultDownsampleInterval is the minimum downsample interval of queries using the interval variable
+	defaultDownsampleInterval = time.Minute
+	// safeResolution is the maximum number of points per series of a query downsampled by the interval variable
+	safeResolution = 11000
+)
+
 type Service struct {
-	im instancemgmt.InstanceManager
+	im                 instancemgmt.InstanceManager
+	intervalCalculator intervalv2.Calculator
+	resourceHandler    backend.CallResourceHandler
 }
 
 func ProvideService(httpClientProvider httpclient.Provider) *Service {
-	return &Service{
-		im: datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
+	s := &Service{
+		im:                 datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
+		intervalCalculator: intervalv2.NewCalculator(),
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
 type datasourceInfo struct {
@@ -68,12 +82,33 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 
 	logger := logger.FromContext(ctx)
 
-	q := req.Queries[0]
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	result := backend.NewQueryDataResponse()
+
+	// Annotation queries are executed one by one, the metric queries are sent in a single request
+	metricQueries := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		if model, ok := parseAnnotationQuery(query); ok {
+			result.Responses[query.RefID] = s.queryAnnotations(ctx, logger, dsInfo, query, model)
+			continue
+		}
+		metricQueries = append(metricQueries, query)
+	}
+
+	if len(metricQueries) == 0 {
+		return result, nil
+	}
+
+	q := metricQueries[0]
 
 	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
 
-	for _, query := range req.Queries {
+	for _, query := range metricQueries {
 		metric := s.buildMetric(query)
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
 	}
@@ -83,11 +118,6 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
-	if err != nil {
-		return nil, err
-	}
-
 	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
@@ -105,11 +135,15 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		}
 	}()
 
-	result, err := s.parseResponse(logger, res)
+	metricResult, err := s.parseResponse(logger, res)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
 	}
 
+	for refID, response := range metricResult.Responses {
+		result.Responses[refID] = response
+	}
+
 	return result, nil
 }
 
@@ -205,6 +239,8 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 		downsampleInterval := model.Get("downsampleInterval").MustString()
 		if downsampleInterval == "" {
 			downsampleInterval = "1m" // default value for blank
+		} else if isIntervalVariable(downsampleInterval) {
+			downsampleInterval = s.calculateDownsampleInterval(query)
 		}
 		downsample := downsampleInterval + "-" + model.Get("downsampleAggregator").MustString()
 		if model.Get("downsampleFillPolicy").MustString() != "none" {
@@ -252,6 +288,29 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 	return metric
 }
 
+// calculateDownsampleInterval returns the interval of queries downsampled by the interval variable, calculated from
+// the time range, interval and max data points of the query like the interval of a panel. It keeps the number of
+// points of alert queries within their max data points instead of returning raw data.
+func (s *Service) calculateDownsampleInterval(query backend.DataQuery) string {
+	minInterval, err := intervalv2.GetIntervalFrom("", "", query.Interval.Milliseconds(), defaultDownsampleInterval)
+	if err != nil {
+		minInterval = defaultDownsampleInterval
+	}
+
+	calculated := s.intervalCalculator.Calculate(query.TimeRange, minInterval, query.MaxDataPoints)
+	safe := s.intervalCalculator.CalculateSafeInterval(query.TimeRange, safeResolution)
+
+	interval := calculated.Value
+	if safe.Value > interval {
+		interval = safe.Value
+	}
+	return intervalv2.FormatDuration(interval)
+}
+
+func isIntervalVariable(interval string) bool {
+	return interval == "$__interval" || interval == "${__interval}"
+}
+
 func (s *Service) getDSInfo(ctx context.Context, pluginCtx backend.PluginContext) (*datasourceInfo, error) {
 	i, err := s.im.Get(ctx, pluginCtx)
 	if err != nil {
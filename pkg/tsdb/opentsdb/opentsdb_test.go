@@ -13,6 +13,8 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 )
 
 func TestOpenTsdbExecutor(t *testing.T) {
@@ -138,6 +140,42 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, "5m-sum-null", metric["downsample"])
 	})
 
+	t.Run("Build metric with downsampling interval calculated from the query", func(t *testing.T) {
+		service := &Service{intervalCalculator: intervalv2.NewCalculator()}
+		from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		for _, tc := range []struct {
+			name          string
+			interval      string
+			timeRange     time.Duration
+			queryInterval time.Duration
+			maxDataPoints int64
+			expected      string
+		}{
+			{name: "blank interval", interval: "", timeRange: 6 * time.Hour, queryInterval: 15 * time.Second, maxDataPoints: 1000, expected: "1m-avg"},
+			{name: "interval variable", interval: "$__interval", timeRange: 6 * time.Hour, queryInterval: 15 * time.Second, maxDataPoints: 1000, expected: "20s-avg"},
+			{name: "braced interval variable", interval: "${__interval}", timeRange: 6 * time.Hour, queryInterval: 15 * time.Second, maxDataPoints: 1000, expected: "20s-avg"},
+			{name: "alert query", interval: "$__interval", timeRange: 24 * time.Hour, queryInterval: time.Second, maxDataPoints: 43200, expected: "10s-avg"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				query := backend.DataQuery{
+					TimeRange:     backend.TimeRange{From: from, To: from.Add(tc.timeRange)},
+					Interval:      tc.queryInterval,
+					MaxDataPoints: tc.maxDataPoints,
+					JSON: []byte(`{
+						"metric": "cpu.average.percent",
+						"aggregator": "avg",
+						"downsampleInterval": "` + tc.interval + `",
+						"downsampleAggregator": "avg",
+						"downsampleFillPolicy": "none"
+					}`),
+				}
+
+				metric := service.buildMetric(query)
+				require.Equal(t, tc.expected, metric["downsample"])
+			})
+		}
+	})
+
 	t.Run("Build metric with tags with downsampling disabled", func(t *testing.T) {
 		query := backend.DataQuery{
 			JSON: []byte(`
@@ -0,0 +1,89 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+)
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
+// newResourceMux returns the handler of the OpenTSDB endpoints used by the query editors and template variables,
+// so they can be queried through the backend with the credentials of the data source.
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/suggest", s.handleResourceReq("api/suggest"))
+	mux.HandleFunc("/api/search/lookup", s.handleResourceReq("api/search/lookup"))
+	mux.HandleFunc("/api/aggregators", s.handleResourceReq("api/aggregators"))
+	mux.HandleFunc("/api/config/filters", s.handleResourceReq("api/config/filters"))
+	return mux
+}
+
+// handleResourceReq proxies GET requests to the given OpenTSDB endpoint with the query string of the request.
+func (s *Service) handleResourceReq(tsdbPath string) func(rw http.ResponseWriter, req *http.Request) {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			writeResponse(rw, http.StatusMethodNotAllowed, []byte(fmt.Sprintf("unsupported method %s", req.Method)))
+			return
+		}
+
+		pluginCtx := httpadapter.PluginConfigFromContext(req.Context())
+		dsInfo, err := s.getDSInfo(req.Context(), pluginCtx)
+		if err != nil {
+			writeResponse(rw, http.StatusInternalServerError, []byte(fmt.Sprintf("unexpected error %v", err)))
+			return
+		}
+
+		res, err := s.doResourceRequest(req.Context(), dsInfo, tsdbPath, req.URL.Query())
+		if err != nil {
+			writeResponse(rw, http.StatusBadGateway, []byte(fmt.Sprintf("failed to query opentsdb %v", err)))
+			return
+		}
+		defer func() {
+			if err := res.Body.Close(); err != nil {
+				logger.Warn("Failed to close response body", "error", err)
+			}
+		}()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			writeResponse(rw, http.StatusBadGateway, []byte(fmt.Sprintf("failed to read opentsdb response %v", err)))
+			return
+		}
+
+		if ct := res.Header.Get("Content-Type"); ct != "" {
+			rw.Header().Set("Content-Type", ct)
+		}
+		writeResponse(rw, res.StatusCode, body)
+	}
+}
+
+func (s *Service) doResourceRequest(ctx context.Context, dsInfo *datasourceInfo, tsdbPath string, params url.Values) (*http.Response, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, tsdbPath)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return dsInfo.HTTPClient.Do(req)
+}
+
+func writeResponse(rw http.ResponseWriter, code int, body []byte) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write(body); err != nil {
+		logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
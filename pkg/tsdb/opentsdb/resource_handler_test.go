@@ -0,0 +1,86 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceHandler(t *testing.T) {
+	tsdb := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/suggest":
+			assert.Equal(t, "metrics", r.URL.Query().Get("type"))
+			assert.Equal(t, "cpu", r.URL.Query().Get("q"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`["cpu.system","cpu.user"]`))
+		case "/api/search/lookup":
+			assert.Equal(t, "cpu{host=*}", r.URL.Query().Get("m"))
+			_, _ = w.Write([]byte(`{"results":[{"tags":{"host":"a"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(tsdb.Close)
+
+	s := &Service{im: staticInstanceManager{&datasourceInfo{HTTPClient: tsdb.Client(), URL: tsdb.URL}}}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+
+	callResource := func(method, path, query string) *backend.CallResourceResponse {
+		sender := &fakeSender{}
+		err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+			Method: method,
+			Path:   path,
+			URL:    path + "?" + query,
+		}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+
+	t.Run("Proxies suggest requests", func(t *testing.T) {
+		resp := callResource(http.MethodGet, "api/suggest", "type=metrics&q=cpu")
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `["cpu.system","cpu.user"]`, string(resp.Body))
+		assert.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"])
+	})
+
+	t.Run("Proxies lookup requests", func(t *testing.T) {
+		resp := callResource(http.MethodGet, "api/search/lookup", "m=cpu%7Bhost%3D%2A%7D")
+		assert.Equal(t, http.StatusOK, resp.Status)
+		assert.JSONEq(t, `{"results":[{"tags":{"host":"a"}}]}`, string(resp.Body))
+	})
+
+	t.Run("Rejects other methods", func(t *testing.T) {
+		resp := callResource(http.MethodPost, "api/suggest", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Status)
+	})
+}
+
+type fakeSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (sender *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	sender.response = resp
+	return nil
+}
+
+type staticInstanceManager struct {
+	dsInfo *datasourceInfo
+}
+
+func (m staticInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return m.dsInfo, nil
+}
+
+func (m staticInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
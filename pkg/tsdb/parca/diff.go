@@ -0,0 +1,179 @@
+package parca
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	v1alpha1 "buf.build/gen/go/parca-dev/parca/protocolbuffers/go/parca/query/v1alpha1"
+	"github.com/bufbuild/connect-go"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/errgroup"
+)
+
+// diffNode is a node of a flame graph that merges a baseline (left) and a comparison (right) profile. Nodes of
+// the two profiles are matched by their name and the names of their ancestors.
+type diffNode struct {
+	level      int64
+	name       string
+	value      int64
+	self       int64
+	valueRight int64
+	selfRight  int64
+	children   []*diffNode
+
+	childrenByName map[string]*diffNode
+}
+
+// mergeFlamegraphs merges the baseline and the comparison flame graphs, either of them can be nil.
+func mergeFlamegraphs(left, right *v1alpha1.Flamegraph) *diffNode {
+	root := &diffNode{name: "total"}
+	for _, fg := range []struct {
+		graph *v1alpha1.Flamegraph
+		right bool
+	}{{left, false}, {right, true}} {
+		if fg.graph == nil || fg.graph.Root == nil {
+			continue
+		}
+		var childrenValue int64
+		for _, child := range fg.graph.Root.Children {
+			childrenValue += child.Cumulative
+			root.child(nodeName(child)).add(child, fg.right)
+		}
+		root.addValue(fg.graph.Root.Cumulative, fg.graph.Root.Cumulative-childrenValue, fg.right)
+	}
+	return root
+}
+
+func (n *diffNode) add(node *v1alpha1.FlamegraphNode, right bool) {
+	var childrenValue int64
+	for _, child := range node.Children {
+		childrenValue += child.Cumulative
+		n.child(nodeName(child)).add(child, right)
+	}
+	n.addValue(node.Cumulative, node.Cumulative-childrenValue, right)
+}
+
+func (n *diffNode) addValue(value, self int64, right bool) {
+	if right {
+		n.valueRight += value
+		n.selfRight += self
+	} else {
+		n.value += value
+		n.self += self
+	}
+}
+
+// child returns the child node with the given name, new nodes are added after the existing ones so the order
+// of the baseline profile is kept.
+func (n *diffNode) child(name string) *diffNode {
+	if n.childrenByName == nil {
+		n.childrenByName = map[string]*diffNode{}
+	}
+	if c, ok := n.childrenByName[name]; ok {
+		return c
+	}
+	c := &diffNode{level: n.level + 1, name: name}
+	n.childrenByName[name] = c
+	n.children = append(n.children, c)
+	return c
+}
+
+// diffToNestedSetDataFrame converts the merged tree to the same nested set format as treeToNestedSetDataFrame.
+// The value and self fields hold the sum of both profiles, the valueRight and selfRight fields hold the values of
+// the comparison profile, so the flame graph can color each node by its change.
+func diffToNestedSetDataFrame(root *diffNode, unit string) *data.Frame {
+	frame := data.NewFrame("response")
+	frame.Meta = &data.FrameMeta{PreferredVisualization: "flamegraph"}
+
+	levelField := data.NewField("level", nil, []int64{})
+	valueField := data.NewField("value", nil, []int64{})
+	selfField := data.NewField("self", nil, []int64{})
+	labelField := data.NewField("label", nil, []string{})
+	valueRightField := data.NewField("valueRight", nil, []int64{})
+	selfRightField := data.NewField("selfRight", nil, []int64{})
+	for _, f := range []*data.Field{valueField, selfField, valueRightField, selfRightField} {
+		f.Config = &data.FieldConfig{Unit: normalizeUnit(unit)}
+	}
+	frame.Fields = data.Fields{levelField, valueField, selfField, labelField, valueRightField, selfRightField}
+
+	stack := []*diffNode{root}
+	for len(stack) > 0 {
+		node := stack[0]
+		levelField.Append(node.level)
+		valueField.Append(node.value + node.valueRight)
+		selfField.Append(node.self + node.selfRight)
+		labelField.Append(node.name)
+		valueRightField.Append(node.valueRight)
+		selfRightField.Append(node.selfRight)
+		// Put the children first so we do depth first traversal
+		stack = append(append([]*diffNode{}, node.children...), stack[1:]...)
+	}
+	return frame
+}
+
+// diffProfiles fetches the baseline and the comparison profile of a diff query in parallel and merges them. The
+// comparison is the profile of the label selector in the query time range, the baseline uses the baseline label
+// selector and the time range shifted back by the baseline time shift.
+func (d *ParcaDatasource) diffProfiles(ctx context.Context, qm queryModel, query backend.DataQuery) (*data.Frame, error) {
+	baselineQuery := qm
+	if qm.BaselineLabelSelector != nil && *qm.BaselineLabelSelector != "" {
+		baselineQuery.LabelSelector = *qm.BaselineLabelSelector
+	}
+
+	var shift time.Duration
+	if qm.BaselineTimeShift != nil && *qm.BaselineTimeShift != "" {
+		var err error
+		shift, err = gtime.ParseDuration(*qm.BaselineTimeShift)
+		if err != nil {
+			return nil, fmt.Errorf("invalid baseline time shift %q: %w", *qm.BaselineTimeShift, err)
+		}
+	}
+
+	if baselineQuery.LabelSelector == qm.LabelSelector && shift == 0 {
+		return nil, errors.New("a diff query needs a baseline label selector or a baseline time shift")
+	}
+
+	baselineDataQuery := query
+	baselineDataQuery.TimeRange = backend.TimeRange{
+		From: query.TimeRange.From.Add(-shift),
+		To:   query.TimeRange.To.Add(-shift),
+	}
+
+	var left, right *connect.Response[v1alpha1.QueryResponse]
+	g, gCtx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		left, err = d.client.Query(gCtx, makeProfileRequest(baselineQuery, baselineDataQuery))
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		right, err = d.client.Query(gCtx, makeProfileRequest(qm, query))
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	leftGraph, err := flamegraphOf(left)
+	if err != nil {
+		return nil, err
+	}
+	rightGraph, err := flamegraphOf(right)
+	if err != nil {
+		return nil, err
+	}
+	return diffToNestedSetDataFrame(mergeFlamegraphs(leftGraph, rightGraph), rightGraph.Unit), nil
+}
+
+func flamegraphOf(resp *connect.Response[v1alpha1.QueryResponse]) (*v1alpha1.Flamegraph, error) {
+	flameResponse, ok := resp.Msg.Report.(*v1alpha1.QueryResponse_Flamegraph)
+	if !ok {
+		return nil, errors.New("unknown report type returned from query")
+	}
+	return flameResponse.Flamegraph, nil
+}
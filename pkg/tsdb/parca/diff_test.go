@@ -0,0 +1,85 @@
+package parca
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1alpha11 "buf.build/gen/go/parca-dev/parca/protocolbuffers/go/parca/metastore/v1alpha1"
+	v1alpha1 "buf.build/gen/go/parca-dev/parca/protocolbuffers/go/parca/query/v1alpha1"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_queryDiff(t *testing.T) {
+	client := &FakeClient{}
+	ds := &ParcaDatasource{
+		client: client,
+	}
+
+	dataQuery := backend.DataQuery{
+		RefID:     "A",
+		QueryType: queryTypeDiff,
+		TimeRange: backend.TimeRange{
+			From: time.Unix(10000, 0),
+			To:   time.Unix(20000, 0),
+		},
+		JSON: []byte(`{"profileTypeId":"foo:bar","labelSelector":"{app=\"baz\"}","baselineTimeShift":"1h"}`),
+	}
+
+	t.Run("query diff", func(t *testing.T) {
+		resp := ds.query(context.Background(), backend.PluginContext{}, dataQuery)
+		require.Nil(t, resp.Error)
+		require.Equal(t, 1, len(resp.Frames))
+		frame := resp.Frames[0]
+		require.Equal(t, data.NewField("level", nil, []int64{0, 1, 2, 3}), frame.Fields[0])
+		require.Equal(t, []int64{200, 20, 18, 16}, fieldValues[int64](frame.Fields[1]))
+		require.Equal(t, []int64{180, 2, 2, 16}, fieldValues[int64](frame.Fields[2]))
+		require.Equal(t, []string{"total", "foo", "bar", "baz"}, fieldValues[string](frame.Fields[3]))
+		require.Equal(t, []int64{100, 10, 9, 8}, fieldValues[int64](frame.Fields[4]))
+		require.Equal(t, []int64{90, 1, 1, 8}, fieldValues[int64](frame.Fields[5]))
+	})
+
+	t.Run("query diff without baseline", func(t *testing.T) {
+		q := dataQuery
+		q.JSON = []byte(`{"profileTypeId":"foo:bar","labelSelector":"{app=\"baz\"}"}`)
+		resp := ds.query(context.Background(), backend.PluginContext{}, q)
+		require.Error(t, resp.Error)
+	})
+}
+
+func Test_mergeFlamegraphs(t *testing.T) {
+	node := func(name string, cumulative int64, children ...*v1alpha1.FlamegraphNode) *v1alpha1.FlamegraphNode {
+		return &v1alpha1.FlamegraphNode{
+			Meta:       &v1alpha1.FlamegraphNodeMeta{Function: &v1alpha11.Function{Name: name}},
+			Cumulative: cumulative,
+			Children:   children,
+		}
+	}
+	left := &v1alpha1.Flamegraph{Root: &v1alpha1.FlamegraphRootNode{
+		Cumulative: 10,
+		Children:   []*v1alpha1.FlamegraphNode{node("foo", 6), node("bar", 4)},
+	}}
+	right := &v1alpha1.Flamegraph{Root: &v1alpha1.FlamegraphRootNode{
+		Cumulative: 20,
+		Children:   []*v1alpha1.FlamegraphNode{node("bar", 5), node("baz", 15, node("foo", 10))},
+	}}
+
+	frame := diffToNestedSetDataFrame(mergeFlamegraphs(left, right), "count")
+	require.Equal(t, []int64{0, 1, 1, 1, 2}, fieldValues[int64](frame.Fields[0]))
+	require.Equal(t, []int64{30, 6, 9, 15, 10}, fieldValues[int64](frame.Fields[1]))
+	require.Equal(t, []int64{0, 6, 9, 5, 10}, fieldValues[int64](frame.Fields[2]))
+	require.Equal(t, []string{"total", "foo", "bar", "baz", "foo"}, fieldValues[string](frame.Fields[3]))
+	require.Equal(t, []int64{20, 0, 5, 15, 10}, fieldValues[int64](frame.Fields[4]))
+	require.Equal(t, []int64{0, 0, 5, 5, 10}, fieldValues[int64](frame.Fields[5]))
+	require.Equal(t, "short", frame.Fields[4].Config.Unit)
+}
+
+func fieldValues[T any](field *data.Field) []T {
+	values := make([]T, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		values[i] = field.At(i).(T)
+	}
+	return values
+}
@@ -23,6 +23,7 @@ const (
 	queryTypeProfile = string(dataquery.ParcaQueryTypeProfile)
 	queryTypeMetrics = string(dataquery.ParcaQueryTypeMetrics)
 	queryTypeBoth    = string(dataquery.ParcaQueryTypeBoth)
+	queryTypeDiff    = string(dataquery.ParcaQueryTypeDiff)
 )
 
 // query processes single Parca query transforming the response to data.Frame packaged in DataResponse
@@ -56,6 +57,16 @@ func (d *ParcaDatasource) query(ctx context.Context, pCtx backend.PluginContext,
 		response.Frames = append(response.Frames, frame)
 	}
 
+	if query.QueryType == queryTypeDiff {
+		logger.Debug("Querying the baseline and comparison profiles for diff", "queryModel", qm)
+		frame, err := d.diffProfiles(ctx, qm, query)
+		if err != nil {
+			response.Error = err
+			return response
+		}
+		response.Frames = append(response.Frames, frame)
+	}
+
 	return response
 }
 
@@ -0,0 +1,86 @@
+package querydata
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/util/converter"
+)
+
+// Settings in the data source JSON data that limit the size of query results
+const (
+	// maxQueryPointsKey is the maximum number of points (series × samples) of a range query. The step of a
+	// query that exceeds it is widened, and the query is executed again.
+	maxQueryPointsKey = "maxQueryPoints"
+	// maxResponseBytesKey is the maximum size of a response body, larger responses are rejected.
+	maxResponseBytesKey = "maxResponseBytes"
+)
+
+// getInt64Optional returns the value of an optional integer setting, which can either be a JSON number or a
+// numeric string when it was set from an input in the configuration page.
+func getInt64Optional(obj map[string]interface{}, key string) (int64, error) {
+	switch v := obj[key].(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return int64(v), nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("the field '%s' should be a number", key)
+		}
+		return i, nil
+	default:
+		return 0, fmt.Errorf("the field '%s' should be a number", key)
+	}
+}
+
+// countPoints returns the number of samples in the frames of a query result, exemplars are not counted
+func countPoints(frames data.Frames) int64 {
+	var points int64
+	for _, frame := range frames {
+		if isExemplarFrame(frame) {
+			continue
+		}
+		// every row of a heatmap is a bucket of a native histogram sample
+		if converter.IsHeatmapCellsFrame(frame) {
+			points += int64(frame.Rows())
+			continue
+		}
+		for _, field := range frame.Fields {
+			if field.Type().Numeric() {
+				points += int64(field.Len())
+			}
+		}
+	}
+	return points
+}
+
+// widenStep returns the step that brings a result of the given number of points within maxPoints. The step
+// is a multiple of the current step so the results stay aligned, and is never larger than the query range.
+func widenStep(step, queryRange time.Duration, points, maxPoints int64) time.Duration {
+	if maxPoints <= 0 || points <= maxPoints || step <= 0 {
+		return step
+	}
+	factor := int64(math.Ceil(float64(points) / float64(maxPoints)))
+	widened := step * time.Duration(factor)
+	if queryRange > step && widened > queryRange {
+		widened = queryRange.Truncate(step)
+	}
+	return widened
+}
+
+func widenedStepNotice(points, maxPoints int64, step, widened time.Duration) data.Notice {
+	return data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text: fmt.Sprintf("The query returned %d points, more than the limit of %d points of the data source. The step was widened from %s to %s.",
+			points, maxPoints, step, widened),
+	}
+}
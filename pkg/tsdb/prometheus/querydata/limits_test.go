@@ -0,0 +1,100 @@
+package querydata
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata/exemplar"
+)
+
+func TestGetInt64Optional(t *testing.T) {
+	jsonData := map[string]interface{}{"number": float64(100), "string": "200", "empty": "", "invalid": "abc"}
+
+	v, err := getInt64Optional(jsonData, "number")
+	require.NoError(t, err)
+	require.Equal(t, int64(100), v)
+
+	v, err = getInt64Optional(jsonData, "string")
+	require.NoError(t, err)
+	require.Equal(t, int64(200), v)
+
+	v, err = getInt64Optional(jsonData, "empty")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), v)
+
+	v, err = getInt64Optional(jsonData, "missing")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), v)
+
+	_, err = getInt64Optional(jsonData, "invalid")
+	require.Error(t, err)
+}
+
+func TestCountPoints(t *testing.T) {
+	series := data.NewFrame("",
+		data.NewField("Time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+		data.NewField("Value", nil, []float64{1, 2}),
+		data.NewField("Value", nil, []*float64{nil, nil}),
+	)
+	heatmap := data.NewFrame("",
+		data.NewField("xMax", nil, []time.Time{time.Unix(1, 0), time.Unix(1, 0), time.Unix(2, 0)}),
+		data.NewField("yMin", nil, []float64{0, 1, 0}),
+		data.NewField("yMax", nil, []float64{1, 2, 1}),
+		data.NewField("count", nil, []float64{3, 4, 5}),
+	).SetMeta(&data.FrameMeta{Type: "heatmap-cells"})
+	exemplars := data.NewFrame("",
+		data.NewField("Time", nil, []time.Time{time.Unix(1, 0)}),
+		data.NewField("Value", nil, []float64{1}),
+	).SetMeta(&data.FrameMeta{Custom: map[string]string{"resultType": "exemplar"}})
+
+	require.Equal(t, int64(7), countPoints(data.Frames{series, heatmap, exemplars}))
+}
+
+func TestWidenStep(t *testing.T) {
+	t.Run("step is kept within the limit", func(t *testing.T) {
+		require.Equal(t, 15*time.Second, widenStep(15*time.Second, time.Hour, 1000, 1000))
+		require.Equal(t, 15*time.Second, widenStep(15*time.Second, time.Hour, 1000, 0))
+	})
+
+	t.Run("step is widened by a multiple of the step", func(t *testing.T) {
+		require.Equal(t, 30*time.Second, widenStep(15*time.Second, time.Hour, 2000, 1000))
+		require.Equal(t, 45*time.Second, widenStep(15*time.Second, time.Hour, 2001, 1000))
+	})
+
+	t.Run("step is not wider than the range", func(t *testing.T) {
+		require.Equal(t, time.Hour, widenStep(15*time.Second, time.Hour, 1000000, 10))
+	})
+}
+
+func TestParseResponseMaxBytes(t *testing.T) {
+	body := `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up"},"values":[[1,"1"],[2,"1"],[3,"1"]]}]}}`
+	s := &QueryData{
+		tracer:          tracing.InitializeTracerForTest(),
+		log:             &logtest.Fake{},
+		exemplarSampler: exemplar.NewNoOpSampler,
+	}
+	q := &models.Query{Expr: "up", Step: time.Second, RangeQuery: true}
+
+	t.Run("response within the limit is parsed", func(t *testing.T) {
+		s.maxResponseBytes = int64(len(body))
+		res := s.parseResponse(context.Background(), q, &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(body)))})
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+	})
+
+	t.Run("response exceeding the limit is rejected", func(t *testing.T) {
+		s.maxResponseBytes = 50
+		res := s.parseResponse(context.Background(), q, &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(body)))})
+		require.ErrorContains(t, res.Error, "exceeds the limit of 50 bytes")
+	})
+}
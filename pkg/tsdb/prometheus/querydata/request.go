@@ -45,6 +45,8 @@ type QueryData struct {
 	enableWideSeries   bool
 	enableDataplane    bool
 	exemplarSampler    func() exemplar.Sampler
+	maxQueryPoints     int64
+	maxResponseBytes   int64
 }
 
 func New(
@@ -65,6 +67,16 @@ func New(
 		return nil, err
 	}
 
+	maxQueryPoints, err := getInt64Optional(jsonData, maxQueryPointsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	maxResponseBytes, err := getInt64Optional(jsonData, maxResponseBytesKey)
+	if err != nil {
+		return nil, err
+	}
+
 	promClient := client.NewClient(httpClient, httpMethod, settings.URL)
 
 	// standard deviation sampler is the default for backwards compatibility
@@ -85,6 +97,8 @@ func New(
 		enableWideSeries:   features.IsEnabled(featuremgmt.FlagPrometheusWideSeries),
 		enableDataplane:    features.IsEnabled(featuremgmt.FlagPrometheusDataplane),
 		exemplarSampler:    exemplarSampler,
+		maxQueryPoints:     maxQueryPoints,
+		maxResponseBytes:   maxResponseBytes,
 	}, nil
 }
 
@@ -153,7 +167,31 @@ func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.
 	return dr
 }
 
+// rangeQuery executes a range query. When the result has more points than the limit of the data source, the query
+// is executed again with a wider step.
 func (s *QueryData) rangeQuery(ctx context.Context, c *client.Client, q *models.Query, headers map[string]string) backend.DataResponse {
+	res := s.queryRange(ctx, c, q)
+	if res.Error != nil || s.maxQueryPoints <= 0 {
+		return res
+	}
+
+	points := countPoints(res.Frames)
+	step := widenStep(q.Step, q.End.Sub(q.Start), points, s.maxQueryPoints)
+	if step == q.Step {
+		return res
+	}
+
+	s.log.FromContext(ctx).Debug("Widening step of query exceeding the points limit", "points", points, "limit", s.maxQueryPoints, "step", q.Step, "widenedStep", step)
+	widened := *q
+	widened.Step = step
+	res = s.queryRange(ctx, c, &widened)
+	if len(res.Frames) > 0 {
+		res.Frames[0].AppendNotices(widenedStepNotice(points, s.maxQueryPoints, q.Step, step))
+	}
+	return res
+}
+
+func (s *QueryData) queryRange(ctx context.Context, c *client.Client, q *models.Query) backend.DataResponse {
 	res, err := c.QueryRange(ctx, q)
 	if err != nil {
 		return backend.DataResponse{
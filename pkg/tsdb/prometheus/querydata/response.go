@@ -2,6 +2,7 @@ package querydata
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
@@ -12,6 +13,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	jsoniter "github.com/json-iterator/go"
 
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata/exemplar"
 	"github.com/grafana/grafana/pkg/tsdb/prometheus/utils"
@@ -28,13 +30,25 @@ func (s *QueryData) parseResponse(ctx context.Context, q *models.Query, res *htt
 	ctx, endSpan := utils.StartTrace(ctx, s.tracer, "datasource.prometheus.parseResponse", []utils.Attribute{})
 	defer endSpan()
 
-	iter := jsoniter.Parse(jsoniter.ConfigDefault, res.Body, 1024)
+	body := res.Body
+	if s.maxResponseBytes > 0 {
+		body = httpclient.MaxBytesReader(res.Body, s.maxResponseBytes)
+	}
+
+	iter := jsoniter.Parse(jsoniter.ConfigDefault, body, 1024)
 	r := converter.ReadPrometheusStyleResult(iter, converter.Options{
 		MatrixWideSeries: s.enableWideSeries,
 		VectorWideSeries: s.enableWideSeries,
 		Dataplane:        s.enableDataplane,
 	})
 
+	// the reader error is the first error of the iterator when the response is truncated
+	if errors.Is(iter.Error, httpclient.ErrResponseBodyTooLarge) {
+		return backend.DataResponse{
+			Error: fmt.Errorf("query response exceeds the limit of %d bytes, reduce the time range or the number of series", s.maxResponseBytes),
+		}
+	}
+
 	// Add frame to attach metadata
 	if len(r.Frames) == 0 && !q.ExemplarQuery {
 		r.Frames = append(r.Frames, data.NewFrame(""))
@@ -118,6 +132,11 @@ func addMetadataToMultiFrame(q *models.Query, frame *data.Frame, enableDataplane
 	}
 	frame.Fields[0].Config = &data.FieldConfig{Interval: float64(q.Step.Milliseconds())}
 
+	if converter.IsHeatmapCellsFrame(frame) {
+		frame.Name = getName(q, frame.Fields[1])
+		return
+	}
+
 	customName := getName(q, frame.Fields[1])
 	if customName != "" {
 		frame.Fields[1].Config = &data.FieldConfig{DisplayNameFromDS: customName}
@@ -142,6 +161,13 @@ func addMetadataToWideFrame(q *models.Query, frame *data.Frame) {
 		return
 	}
 	frame.Fields[0].Config = &data.FieldConfig{Interval: float64(q.Step.Milliseconds())}
+
+	// the fields of native histograms are the bucket bounds and counts, they must keep their names
+	if converter.IsHeatmapCellsFrame(frame) {
+		frame.Name = getName(q, frame.Fields[1])
+		return
+	}
+
 	for _, f := range frame.Fields {
 		if f.Type() == data.FieldTypeFloat64 || f.Type() == data.FieldTypeNullableFloat64 {
 			f.Name = getName(q, f)
@@ -210,6 +236,9 @@ func getName(q *models.Query, field *data.Field) string {
 }
 
 func isExemplarFrame(frame *data.Frame) bool {
+	if frame.Meta == nil {
+		return false
+	}
 	rt := models.ResultTypeFromFrame(frame)
 	return rt == models.ResultTypeExemplar
 }
@@ -19,14 +19,16 @@ const (
 
 // Defines values for TempoQueryType.
 const (
-	TempoQueryTypeClear         TempoQueryType = "clear"
-	TempoQueryTypeNativeSearch  TempoQueryType = "nativeSearch"
-	TempoQueryTypeSearch        TempoQueryType = "search"
-	TempoQueryTypeServiceMap    TempoQueryType = "serviceMap"
-	TempoQueryTypeTraceId       TempoQueryType = "traceId"
-	TempoQueryTypeTraceql       TempoQueryType = "traceql"
-	TempoQueryTypeTraceqlSearch TempoQueryType = "traceqlSearch"
-	TempoQueryTypeUpload        TempoQueryType = "upload"
+	TempoQueryTypeClear                TempoQueryType = "clear"
+	TempoQueryTypeNativeSearch         TempoQueryType = "nativeSearch"
+	TempoQueryTypeSearch               TempoQueryType = "search"
+	TempoQueryTypeServiceMap           TempoQueryType = "serviceMap"
+	TempoQueryTypeServiceMapFromTraces TempoQueryType = "serviceMapFromTraces"
+	TempoQueryTypeTraceId              TempoQueryType = "traceId"
+	TempoQueryTypeTraceql              TempoQueryType = "traceql"
+	TempoQueryTypeTraceqlMetrics       TempoQueryType = "traceqlMetrics"
+	TempoQueryTypeTraceqlSearch        TempoQueryType = "traceqlSearch"
+	TempoQueryTypeUpload               TempoQueryType = "upload"
 )
 
 // Defines values for TraceqlSearchScope.
@@ -121,6 +123,9 @@ type TempoQuery struct {
 
 	// @deprecated Query traces by span name
 	SpanName *string `json:"spanName,omitempty"`
+
+	// For TraceQL metrics queries, the step of the returned time series. Use duration format, for example: 30s, 1m
+	Step *string `json:"step,omitempty"`
 }
 
 // TempoQueryType search = Loki search, nativeSearch = Tempo search for backwards compatibility
@@ -0,0 +1,400 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+)
+
+const (
+	// serviceGraphDefaultTraceLimit is the number of traces used to compute the service graph when the query has no limit
+	serviceGraphDefaultTraceLimit = 100
+	// serviceGraphMaxTraceLimit bounds the number of traces fetched for a single service graph query
+	serviceGraphMaxTraceLimit = 500
+	// serviceGraphConcurrency is the number of traces fetched in parallel
+	serviceGraphConcurrency = 10
+)
+
+type searchResponse struct {
+	Traces []struct {
+		TraceID string `json:"traceID"`
+	} `json:"traces"`
+}
+
+// graphSpan holds the fields of a span, as returned by TraceToFrame, that are needed to build a service graph
+type graphSpan struct {
+	spanID       string
+	parentSpanID string
+	serviceName  string
+	statusCode   int64
+	duration     float64
+}
+
+// graphStats are the requests of a node or an edge of the service graph
+type graphStats struct {
+	requests  int64
+	failed    int64
+	durations []float64
+}
+
+func (g *graphStats) add(span graphSpan) {
+	g.requests++
+	if span.statusCode == int64(ptrace.StatusCodeError) {
+		g.failed++
+	}
+	g.durations = append(g.durations, span.duration)
+}
+
+// p95 returns the 95th percentile of the durations, using the nearest rank
+func (g *graphStats) p95() float64 {
+	if len(g.durations) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, g.durations...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func (g *graphStats) errorRate() float64 {
+	if g.requests == 0 {
+		return 0
+	}
+	return float64(g.failed) / float64(g.requests)
+}
+
+type serviceGraph struct {
+	nodes map[string]*graphStats
+	edges map[[2]string]*graphStats
+}
+
+func newServiceGraph() *serviceGraph {
+	return &serviceGraph{
+		nodes: map[string]*graphStats{},
+		edges: map[[2]string]*graphStats{},
+	}
+}
+
+// addTrace adds the spans of a trace to the graph. A span is a request to its service when it has no parent or
+// when its parent belongs to another service, in which case it is also a request on the edge between both services.
+func (g *serviceGraph) addTrace(spans []graphSpan) {
+	byID := make(map[string]graphSpan, len(spans))
+	for _, span := range spans {
+		byID[span.spanID] = span
+	}
+
+	for _, span := range spans {
+		parent, hasParent := byID[span.parentSpanID]
+		if hasParent && parent.serviceName == span.serviceName {
+			continue
+		}
+
+		node, ok := g.nodes[span.serviceName]
+		if !ok {
+			node = &graphStats{}
+			g.nodes[span.serviceName] = node
+		}
+		node.add(span)
+
+		if !hasParent {
+			continue
+		}
+		if _, ok := g.nodes[parent.serviceName]; !ok {
+			g.nodes[parent.serviceName] = &graphStats{}
+		}
+		key := [2]string{parent.serviceName, span.serviceName}
+		edge, ok := g.edges[key]
+		if !ok {
+			edge = &graphStats{}
+			g.edges[key] = edge
+		}
+		edge.add(span)
+	}
+}
+
+// frames returns the nodes and edges frames of the graph, rates are calculated over the given duration in seconds
+func (g *serviceGraph) frames(rangeSeconds float64) data.Frames {
+	if rangeSeconds <= 0 {
+		rangeSeconds = 1
+	}
+
+	nodes := data.NewFrame("Nodes",
+		data.NewField("id", nil, []string{}),
+		data.NewField("title", nil, []string{}).SetConfig(&data.FieldConfig{DisplayName: "Service name"}),
+		data.NewField("mainstat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "p95 latency", Unit: "ms"}),
+		data.NewField("secondarystat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests per second", Unit: "r/sec"}),
+		data.NewField("arc__success", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Success", Color: map[string]interface{}{"mode": "fixed", "fixedColor": "green"}}),
+		data.NewField("arc__failed", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Failed", Color: map[string]interface{}{"mode": "fixed", "fixedColor": "red"}}),
+		data.NewField("detail__errorrate", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Error rate", Unit: "percentunit"}),
+		data.NewField("detail__requests", nil, []int64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests"}),
+	)
+	nodes.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeNodeGraph}
+
+	names := make([]string, 0, len(g.nodes))
+	for name := range g.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		stats := g.nodes[name]
+		success := 1.0
+		if stats.requests > 0 {
+			success = 1 - stats.errorRate()
+		}
+		nodes.AppendRow(name, name, stats.p95(), float64(stats.requests)/rangeSeconds, success, 1-success, stats.errorRate(), stats.requests)
+	}
+
+	edges := data.NewFrame("Edges",
+		data.NewField("id", nil, []string{}),
+		data.NewField("source", nil, []string{}),
+		data.NewField("target", nil, []string{}),
+		data.NewField("mainstat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "p95 latency", Unit: "ms"}),
+		data.NewField("secondarystat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests per second", Unit: "r/sec"}),
+		data.NewField("detail__errorrate", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Error rate", Unit: "percentunit"}),
+		data.NewField("detail__requests", nil, []int64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests"}),
+	)
+	edges.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeNodeGraph}
+
+	keys := make([][2]string, 0, len(g.edges))
+	for key := range g.edges {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, key := range keys {
+		stats := g.edges[key]
+		edges.AppendRow(key[0]+"_"+key[1], key[0], key[1], stats.p95(), float64(stats.requests)/rangeSeconds, stats.errorRate(), stats.requests)
+	}
+
+	return data.Frames{nodes, edges}
+}
+
+// spansFromTraceFrame reads the spans of a frame created by TraceToFrame
+func spansFromTraceFrame(frame *data.Frame) ([]graphSpan, error) {
+	if frame == nil {
+		return nil, nil
+	}
+	fields := map[string]*data.Field{}
+	for _, name := range []string{"spanID", "parentSpanID", "serviceName", "statusCode", "duration"} {
+		field, _ := frame.FieldByName(name)
+		if field == nil {
+			return nil, fmt.Errorf("trace frame is missing the %s field", name)
+		}
+		fields[name] = field
+	}
+
+	spans := make([]graphSpan, 0, frame.Rows())
+	for i := 0; i < frame.Rows(); i++ {
+		spans = append(spans, graphSpan{
+			spanID:       fields["spanID"].At(i).(string),
+			parentSpanID: fields["parentSpanID"].At(i).(string),
+			serviceName:  fields["serviceName"].At(i).(string),
+			statusCode:   fields["statusCode"].At(i).(int64),
+			duration:     fields["duration"].At(i).(float64),
+		})
+	}
+	return spans, nil
+}
+
+// runServiceMapFromTraces computes a service graph from the traces matching a TraceQL query, for data sources
+// without the Tempo metrics generator
+func (s *Service) runServiceMapFromTraces(ctx context.Context, pCtx backend.PluginContext, query backend.DataQuery) (*backend.DataResponse, error) {
+	result := &backend.DataResponse{}
+
+	model := &dataquery.TempoQuery{}
+	err := json.Unmarshal(query.JSON, model)
+	if err != nil {
+		return result, err
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, pCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	traceQL := strings.TrimSpace(model.Query)
+	if traceQL == "" {
+		traceQL = "{}"
+	}
+	limit := int64(serviceGraphDefaultTraceLimit)
+	if model.Limit != nil && *model.Limit > 0 {
+		limit = *model.Limit
+	}
+	if limit > serviceGraphMaxTraceLimit {
+		limit = serviceGraphMaxTraceLimit
+	}
+
+	start, end := query.TimeRange.From.Unix(), query.TimeRange.To.Unix()
+	traceIDs, err := s.searchTraceIDs(ctx, dsInfo, traceQL, start, end, limit)
+	if err != nil {
+		result.Error = err
+		return result, nil
+	}
+
+	graph, failed, err := s.buildServiceGraph(ctx, dsInfo, traceIDs, start, end)
+	if err != nil {
+		result.Error = err
+		return result, nil
+	}
+
+	frames := graph.frames(query.TimeRange.Duration().Seconds())
+	for _, frame := range frames {
+		frame.RefID = query.RefID
+		frame.Meta.ExecutedQueryString = traceQL
+	}
+	frames[0].AppendNotices(data.Notice{
+		Severity: data.NoticeSeverityInfo,
+		Text:     fmt.Sprintf("The service graph is computed from %d traces matching the query, rates are estimated from these traces only.", len(traceIDs)),
+	})
+	if failed > 0 {
+		frames[0].AppendNotices(data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("%d of %d traces could not be fetched and are not included in the service graph.", failed, len(traceIDs)),
+		})
+	}
+	result.Frames = frames
+	return result, nil
+}
+
+// buildServiceGraph fetches the traces and adds their spans to a service graph. Traces that cannot be fetched
+// are skipped and counted, an error is only returned when the context is done or when no trace could be fetched.
+func (s *Service) buildServiceGraph(ctx context.Context, dsInfo *Datasource, traceIDs []string, start, end int64) (*serviceGraph, int, error) {
+	graph := newServiceGraph()
+	var mu sync.Mutex
+	var failed int
+	var firstErr error
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(serviceGraphConcurrency)
+	for _, traceID := range traceIDs {
+		traceID := traceID
+		g.Go(func() error {
+			spans, err := s.fetchTraceSpans(gctx, dsInfo, traceID, start, end)
+			if err != nil {
+				if gctx.Err() != nil {
+					return gctx.Err()
+				}
+				s.logger.FromContext(ctx).Warn("Failed to fetch trace for the service graph", "traceId", traceID, "err", err)
+				mu.Lock()
+				defer mu.Unlock()
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+				return nil
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			graph.addTrace(spans)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, 0, err
+	}
+	if len(traceIDs) > 0 && failed == len(traceIDs) {
+		return nil, 0, firstErr
+	}
+	return graph, failed, nil
+}
+
+func (s *Service) searchTraceIDs(ctx context.Context, dsInfo *Datasource, traceQL string, start, end, limit int64) ([]string, error) {
+	params := url.Values{}
+	params.Set("q", traceQL)
+	params.Set("start", strconv.FormatInt(start, 10))
+	params.Set("end", strconv.FormatInt(end, 10))
+	params.Set("limit", strconv.FormatInt(limit, 10))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/search?%s", dsInfo.URL, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed get to tempo: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.logger.FromContext(ctx).Warn("failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to search traces: %s Status: %s Body: %s", traceQL, resp.Status, string(body))
+	}
+
+	sr := &searchResponse{}
+	if err := json.Unmarshal(body, sr); err != nil {
+		return nil, fmt.Errorf("failed to parse search response: %w", err)
+	}
+
+	traceIDs := make([]string, 0, len(sr.Traces))
+	for _, t := range sr.Traces {
+		traceIDs = append(traceIDs, t.TraceID)
+	}
+	return traceIDs, nil
+}
+
+func (s *Service) fetchTraceSpans(ctx context.Context, dsInfo *Datasource, traceID string, start, end int64) ([]graphSpan, error) {
+	req, err := s.createRequest(ctx, dsInfo, traceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed get to tempo: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.logger.FromContext(ctx).Warn("failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get trace with id: %s Status: %s Body: %s", traceID, resp.Status, string(body))
+	}
+
+	pbUnmarshaler := ptrace.ProtoUnmarshaler{}
+	otTrace, err := pbUnmarshaler.UnmarshalTraces(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert tempo response to Otlp: %w", err)
+	}
+
+	frame, err := TraceToFrame(otTrace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform trace %v to data frame: %w", traceID, err)
+	}
+	return spansFromTraceFrame(frame)
+}
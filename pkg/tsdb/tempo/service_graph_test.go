@@ -0,0 +1,114 @@
+package tempo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestServiceGraph(t *testing.T) {
+	t.Run("spans entering a service are requests of the service and of the edge from the calling service", func(t *testing.T) {
+		graph := newServiceGraph()
+		graph.addTrace([]graphSpan{
+			{spanID: "1", parentSpanID: "0000000000000000", serviceName: "frontend", duration: 100},
+			{spanID: "2", parentSpanID: "1", serviceName: "frontend", duration: 90},
+			{spanID: "3", parentSpanID: "2", serviceName: "api", duration: 80},
+			{spanID: "4", parentSpanID: "3", serviceName: "db", duration: 20, statusCode: 2},
+			{spanID: "5", parentSpanID: "3", serviceName: "db", duration: 40},
+		})
+
+		require.Len(t, graph.nodes, 3)
+		assert.Equal(t, int64(1), graph.nodes["frontend"].requests)
+		assert.Equal(t, int64(1), graph.nodes["api"].requests)
+		assert.Equal(t, int64(2), graph.nodes["db"].requests)
+		assert.Equal(t, int64(1), graph.nodes["db"].failed)
+
+		require.Len(t, graph.edges, 2)
+		assert.Equal(t, int64(1), graph.edges[[2]string{"frontend", "api"}].requests)
+		assert.Equal(t, int64(2), graph.edges[[2]string{"api", "db"}].requests)
+		assert.Equal(t, 0.5, graph.edges[[2]string{"api", "db"}].errorRate())
+		assert.Equal(t, float64(40), graph.edges[[2]string{"api", "db"}].p95())
+	})
+
+	t.Run("frames returns the nodes and edges of the node graph", func(t *testing.T) {
+		graph := newServiceGraph()
+		graph.addTrace([]graphSpan{
+			{spanID: "1", serviceName: "api", duration: 10},
+			{spanID: "2", parentSpanID: "1", serviceName: "db", duration: 5, statusCode: 2},
+		})
+
+		frames := graph.frames(10)
+		require.Len(t, frames, 2)
+
+		nodes, edges := frames[0], frames[1]
+		assert.Equal(t, data.VisType(data.VisTypeNodeGraph), nodes.Meta.PreferredVisualization)
+		require.Equal(t, 2, nodes.Rows())
+		assert.Equal(t, []interface{}{"api", "api", float64(10), 0.1, float64(1), float64(0), float64(0), int64(1)}, nodes.RowCopy(0))
+		assert.Equal(t, []interface{}{"db", "db", float64(5), 0.1, float64(0), float64(1), float64(1), int64(1)}, nodes.RowCopy(1))
+
+		require.Equal(t, 1, edges.Rows())
+		assert.Equal(t, []interface{}{"api_db", "api", "db", float64(5), 0.1, float64(1), int64(1)}, edges.RowCopy(0))
+	})
+
+	t.Run("p95 uses the nearest rank", func(t *testing.T) {
+		stats := &graphStats{}
+		for i := 1; i <= 100; i++ {
+			stats.durations = append(stats.durations, float64(i))
+		}
+		assert.Equal(t, float64(95), stats.p95())
+		assert.Equal(t, float64(0), (&graphStats{}).p95())
+	})
+
+	t.Run("spansFromTraceFrame reads the span model of a trace frame", func(t *testing.T) {
+		frame := data.NewFrame("Trace",
+			data.NewField("traceID", nil, []string{"abc"}),
+			data.NewField("spanID", nil, []string{"1"}),
+			data.NewField("parentSpanID", nil, []string{"0"}),
+			data.NewField("serviceName", nil, []string{"api"}),
+			data.NewField("statusCode", nil, []int64{2}),
+			data.NewField("startTime", nil, []float64{float64(time.Now().UnixMilli())}),
+			data.NewField("duration", nil, []float64{12.5}),
+		)
+		spans, err := spansFromTraceFrame(frame)
+		require.NoError(t, err)
+		assert.Equal(t, []graphSpan{{spanID: "1", parentSpanID: "0", serviceName: "api", statusCode: 2, duration: 12.5}}, spans)
+
+		_, err = spansFromTraceFrame(data.NewFrame("Trace"))
+		require.Error(t, err)
+	})
+
+	t.Run("buildServiceGraph skips the traces that cannot be fetched", func(t *testing.T) {
+		proto, err := os.ReadFile("testData/tempo_proto_response")
+		require.NoError(t, err)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/missing") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(proto)
+		}))
+		t.Cleanup(srv.Close)
+
+		service := &Service{logger: log.New("tempo-test")}
+		dsInfo := &Datasource{URL: srv.URL, HTTPClient: srv.Client()}
+
+		graph, failed, err := service.buildServiceGraph(context.Background(), dsInfo, []string{"found", "missing"}, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, failed)
+		assert.NotEmpty(t, graph.nodes)
+
+		_, _, err = service.buildServiceGraph(context.Background(), dsInfo, []string{"missing"}, 0, 0)
+		require.Error(t, err)
+	})
+}
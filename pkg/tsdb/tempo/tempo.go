@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/log"
+	ngalertmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
 	"github.com/grafana/tempo/pkg/tempopb"
 )
@@ -62,8 +63,16 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	// create response struct
 	response := backend.NewQueryDataResponse()
 
+	_, fromAlert := req.Headers[ngalertmodels.FromAlertHeaderName]
+
 	// loop over queries and execute them individually.
 	for _, q := range req.Queries {
+		// only the metrics queries return time series that alert rules can be evaluated on
+		if fromAlert && q.QueryType != string(dataquery.TempoQueryTypeTraceqlMetrics) {
+			response.Responses[q.RefID] = backend.ErrDataResponse(backend.StatusBadRequest,
+				fmt.Sprintf("query type '%s' is not supported in alerting, use a TraceQL metrics query", q.QueryType))
+			continue
+		}
 		if res, err := s.query(ctx, req.PluginContext, q); err != nil {
 			return response, err
 		} else {
@@ -77,8 +86,13 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 }
 
 func (s *Service) query(ctx context.Context, pCtx backend.PluginContext, query backend.DataQuery) (*backend.DataResponse, error) {
-	if query.QueryType == string(dataquery.TempoQueryTypeTraceId) {
+	switch query.QueryType {
+	case string(dataquery.TempoQueryTypeTraceId):
 		return s.getTrace(ctx, pCtx, query)
+	case string(dataquery.TempoQueryTypeTraceqlMetrics):
+		return s.runTraceQLMetricsQuery(ctx, pCtx, query)
+	case string(dataquery.TempoQueryTypeServiceMapFromTraces):
+		return s.runServiceMapFromTraces(ctx, pCtx, query)
 	}
 
 	return nil, fmt.Errorf("unsupported query type: '%s' for query with refID '%s'", query.QueryType, query.RefID)
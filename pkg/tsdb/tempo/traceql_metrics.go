@@ -0,0 +1,237 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+)
+
+// metricsQueryRangeResponse is the JSON representation of the response of the Tempo metrics query range API
+type metricsQueryRangeResponse struct {
+	Series []metricsSeries `json:"series"`
+}
+
+type metricsSeries struct {
+	Labels  []metricsLabel  `json:"labels"`
+	Samples []metricsSample `json:"samples"`
+}
+
+type metricsLabel struct {
+	Key   string          `json:"key"`
+	Value metricsAnyValue `json:"value"`
+}
+
+// metricsAnyValue is an OTLP AnyValue, int64 values are encoded as strings in the protobuf JSON mapping
+type metricsAnyValue struct {
+	StringValue *string      `json:"stringValue,omitempty"`
+	IntValue    *json.Number `json:"intValue,omitempty"`
+	DoubleValue *float64     `json:"doubleValue,omitempty"`
+	BoolValue   *bool        `json:"boolValue,omitempty"`
+}
+
+// metricsSample is a sample of a series, the timestamp is a JSON number or a string
+type metricsSample struct {
+	TimestampMs json.Number `json:"timestampMs"`
+	Value       float64     `json:"value"`
+}
+
+func (v metricsAnyValue) String() string {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.IntValue != nil:
+		return v.IntValue.String()
+	case v.DoubleValue != nil:
+		return strconv.FormatFloat(*v.DoubleValue, 'f', -1, 64)
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue)
+	default:
+		return ""
+	}
+}
+
+func (s *Service) runTraceQLMetricsQuery(ctx context.Context, pCtx backend.PluginContext, query backend.DataQuery) (*backend.DataResponse, error) {
+	result := &backend.DataResponse{}
+
+	model := &dataquery.TempoQuery{}
+	err := json.Unmarshal(query.JSON, model)
+	if err != nil {
+		return result, err
+	}
+
+	if strings.TrimSpace(model.Query) == "" {
+		return result, nil
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, pCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	step, err := metricsStep(model, query)
+	if err != nil {
+		result.Error = err
+		return result, nil
+	}
+
+	request, err := s.createMetricsRequest(ctx, dsInfo, model.Query, query.TimeRange, step)
+	if err != nil {
+		return result, err
+	}
+
+	resp, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return result, fmt.Errorf("failed get to tempo: %w", err)
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.logger.FromContext(ctx).Warn("failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &backend.DataResponse{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Errorf("failed to run TraceQL metrics query: %s Status: %s Body: %s", model.Query, resp.Status, string(body))
+		return result, nil
+	}
+
+	metricsResponse := &metricsQueryRangeResponse{}
+	if err := json.Unmarshal(body, metricsResponse); err != nil {
+		return &backend.DataResponse{}, fmt.Errorf("failed to parse TraceQL metrics response: %w", err)
+	}
+
+	frames, err := metricsResponseToFrames(metricsResponse, query.RefID, model.Query, step)
+	if err != nil {
+		return &backend.DataResponse{}, err
+	}
+	result.Frames = frames
+	return result, nil
+}
+
+// metricsStep returns the step of the query, which is either set in the query or is the interval of the request
+func metricsStep(model *dataquery.TempoQuery, query backend.DataQuery) (time.Duration, error) {
+	if model.Step != nil && *model.Step != "" {
+		step, err := time.ParseDuration(*model.Step)
+		if err != nil {
+			return 0, fmt.Errorf("invalid step %q: %w", *model.Step, err)
+		}
+		return step, nil
+	}
+	return query.Interval, nil
+}
+
+func (s *Service) createMetricsRequest(ctx context.Context, dsInfo *Datasource, traceQL string, timeRange backend.TimeRange, step time.Duration) (*http.Request, error) {
+	params := url.Values{}
+	params.Set("q", traceQL)
+	params.Set("start", strconv.FormatInt(timeRange.From.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(timeRange.To.UnixNano(), 10))
+	if step > 0 {
+		params.Set("step", formatStep(step))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/metrics/query_range?%s", dsInfo.URL, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	s.logger.FromContext(ctx).Debug("Tempo metrics request", "url", req.URL.String())
+	return req, nil
+}
+
+// formatStep returns the step as a Prometheus style duration, in seconds or in milliseconds for sub-second
+// steps. The Go format of durations (1m0s) is not accepted by the API.
+func formatStep(step time.Duration) string {
+	if step%time.Second == 0 {
+		return strconv.FormatInt(int64(step/time.Second), 10) + "s"
+	}
+	return strconv.FormatInt(step.Milliseconds(), 10) + "ms"
+}
+
+// metricsResponseToFrames returns a time series frame for each series of the response, the labels of the
+// series are the attributes of the "by" clause of the query
+func metricsResponseToFrames(rsp *metricsQueryRangeResponse, refID, traceQL string, step time.Duration) (data.Frames, error) {
+	frames := make(data.Frames, 0, len(rsp.Series))
+	for _, series := range rsp.Series {
+		labels := data.Labels{}
+		for _, l := range series.Labels {
+			labels[l.Key] = l.Value.String()
+		}
+
+		type point struct {
+			t time.Time
+			v float64
+		}
+		points := make([]point, 0, len(series.Samples))
+		for _, sample := range series.Samples {
+			ms, err := sample.TimestampMs.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("invalid sample timestamp %q: %w", sample.TimestampMs, err)
+			}
+			points = append(points, point{t: time.UnixMilli(ms).UTC(), v: sample.Value})
+		}
+		sort.Slice(points, func(i, j int) bool { return points[i].t.Before(points[j].t) })
+
+		times := make([]time.Time, len(points))
+		values := make([]float64, len(points))
+		for i, p := range points {
+			times[i] = p.t
+			values[i] = p.v
+		}
+
+		timeField := data.NewField(data.TimeSeriesTimeFieldName, nil, times)
+		if step > 0 {
+			timeField.Config = &data.FieldConfig{Interval: float64(step.Milliseconds())}
+		}
+		valueField := data.NewField(data.TimeSeriesValueFieldName, labels, values)
+		valueField.Config = &data.FieldConfig{DisplayNameFromDS: metricsSeriesName(labels, traceQL)}
+
+		frame := data.NewFrame("", timeField, valueField)
+		frame.RefID = refID
+		frame.Meta = &data.FrameMeta{
+			Type:                data.FrameTypeTimeSeriesMulti,
+			TypeVersion:         data.FrameTypeVersion{0, 1},
+			ExecutedQueryString: traceQL,
+		}
+		frames = append(frames, frame)
+	}
+
+	// an empty typed frame makes the response readable as "no data" by expressions and alerting
+	if len(frames) == 0 {
+		frame := data.NewFrame("")
+		frame.RefID = refID
+		frame.Meta = &data.FrameMeta{
+			Type:                data.FrameTypeTimeSeriesMulti,
+			TypeVersion:         data.FrameTypeVersion{0, 1},
+			ExecutedQueryString: traceQL,
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// metricsSeriesName returns the labels formatted like Prometheus series, or the query when there are no labels
+func metricsSeriesName(labels data.Labels, traceQL string) string {
+	if len(labels) == 0 {
+		return traceQL
+	}
+	return labels.String()
+}
@@ -0,0 +1,96 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+)
+
+func TestTraceQLMetrics(t *testing.T) {
+	t.Run("createMetricsRequest sets query, time range and step", func(t *testing.T) {
+		service := &Service{logger: log.New("tempo-test")}
+		timeRange := backend.TimeRange{From: time.Unix(1, 0), To: time.Unix(2, 0)}
+		req, err := service.createMetricsRequest(context.Background(), &Datasource{URL: "http://tempo"}, "{} | rate()", timeRange, 30*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, "/api/metrics/query_range", req.URL.Path)
+		assert.Equal(t, "{} | rate()", req.URL.Query().Get("q"))
+		assert.Equal(t, "1000000000", req.URL.Query().Get("start"))
+		assert.Equal(t, "2000000000", req.URL.Query().Get("end"))
+		assert.Equal(t, "30s", req.URL.Query().Get("step"))
+	})
+
+	t.Run("createMetricsRequest sends the step in seconds or milliseconds", func(t *testing.T) {
+		service := &Service{logger: log.New("tempo-test")}
+		timeRange := backend.TimeRange{From: time.Unix(1, 0), To: time.Unix(2, 0)}
+		req, err := service.createMetricsRequest(context.Background(), &Datasource{URL: "http://tempo"}, "{} | rate()", timeRange, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, "60s", req.URL.Query().Get("step"))
+
+		req, err = service.createMetricsRequest(context.Background(), &Datasource{URL: "http://tempo"}, "{} | rate()", timeRange, 1500*time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, "1500ms", req.URL.Query().Get("step"))
+	})
+
+	t.Run("alerting only supports metrics queries", func(t *testing.T) {
+		service := &Service{logger: log.New("tempo-test")}
+		rsp, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Headers: map[string]string{"FromAlert": "true"},
+			Queries: []backend.DataQuery{{RefID: "A", QueryType: string(dataquery.TempoQueryTypeTraceId)}},
+		})
+		require.NoError(t, err)
+		require.Error(t, rsp.Responses["A"].Error)
+		assert.Equal(t, backend.StatusBadRequest, rsp.Responses["A"].Status)
+	})
+
+	t.Run("metricsStep uses the step of the query or the interval", func(t *testing.T) {
+		step := "1m"
+		s, err := metricsStep(&dataquery.TempoQuery{Step: &step}, backend.DataQuery{Interval: time.Second})
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, s)
+
+		s, err = metricsStep(&dataquery.TempoQuery{}, backend.DataQuery{Interval: time.Second})
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, s)
+
+		invalid := "abc"
+		_, err = metricsStep(&dataquery.TempoQuery{Step: &invalid}, backend.DataQuery{})
+		require.Error(t, err)
+	})
+
+	t.Run("metricsResponseToFrames returns a time series per series", func(t *testing.T) {
+		body := `{"series":[{"labels":[{"key":"span.http.method","value":{"stringValue":"GET"}},{"key":"span.http.status_code","value":{"intValue":"200"}}],
+			"samples":[{"timestampMs":"2000","value":2},{"timestampMs":"1000","value":1.5}]}]}`
+		rsp := &metricsQueryRangeResponse{}
+		require.NoError(t, json.Unmarshal([]byte(body), rsp))
+
+		frames, err := metricsResponseToFrames(rsp, "A", "{} | rate() by (span.http.method)", time.Second)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+
+		frame := frames[0]
+		assert.Equal(t, "A", frame.RefID)
+		assert.Equal(t, data.FrameTypeTimeSeriesMulti, frame.Meta.Type)
+		assert.Equal(t, "{} | rate() by (span.http.method)", frame.Meta.ExecutedQueryString)
+		assert.Equal(t, []time.Time{time.UnixMilli(1000).UTC(), time.UnixMilli(2000).UTC()}, []time.Time{frame.Fields[0].At(0).(time.Time), frame.Fields[0].At(1).(time.Time)})
+		assert.Equal(t, 1.5, frame.Fields[1].At(0))
+		assert.Equal(t, data.Labels{"span.http.method": "GET", "span.http.status_code": "200"}, frame.Fields[1].Labels)
+		assert.Equal(t, float64(1000), frame.Fields[0].Config.Interval)
+	})
+
+	t.Run("metricsResponseToFrames returns an empty frame without series", func(t *testing.T) {
+		frames, err := metricsResponseToFrames(&metricsQueryRangeResponse{}, "A", "{} | rate()", 0)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		assert.Len(t, frames[0].Fields, 0)
+		assert.Equal(t, data.FrameTypeTimeSeriesMulti, frames[0].Meta.Type)
+	})
+}
@@ -40,7 +40,7 @@ func (s *Service) handleCsvContentScenario(ctx context.Context, req *backend.Que
 
 		dropPercent := model.DropPercent
 		if dropPercent > 0 {
-			frame, err = dropValues(frame, dropPercent)
+			frame, err = dropValues(frame, dropPercent, newRand(model, 0))
 			if err != nil {
 				return nil, err
 			}
@@ -77,7 +77,7 @@ func (s *Service) handleCsvFileScenario(ctx context.Context, req *backend.QueryD
 
 		dropPercent := model.DropPercent
 		if dropPercent > 0 {
-			frame, err = dropValues(frame, dropPercent)
+			frame, err = dropValues(frame, dropPercent, newRand(model, 0))
 			if err != nil {
 				return nil, err
 			}
@@ -43,6 +43,7 @@ const (
 	TestDataQueryTypeGrafanaApi                   TestDataQueryType = "grafana_api"
 	TestDataQueryTypeLinearHeatmapBucketData      TestDataQueryType = "linear_heatmap_bucket_data"
 	TestDataQueryTypeLive                         TestDataQueryType = "live"
+	TestDataQueryTypeLoad                         TestDataQueryType = "load"
 	TestDataQueryTypeLogs                         TestDataQueryType = "logs"
 	TestDataQueryTypeManualEntry                  TestDataQueryType = "manual_entry"
 	TestDataQueryTypeNoDataPoints                 TestDataQueryType = "no_data_points"
@@ -96,6 +97,17 @@ type DataQuery struct {
 	RefId string `json:"refId"`
 }
 
+// LoadQuery defines model for LoadQuery.
+type LoadQuery struct {
+	// Error rate percentage (the chance the query fails 0-100)
+	ErrorRate   *float64 `json:"errorRate,omitempty"`
+	FieldCount  *int32   `json:"fieldCount,omitempty"`
+	LabelCount  *int32   `json:"labelCount,omitempty"`
+	LatencyMs   *int64   `json:"latencyMs,omitempty"`
+	PointCount  *int32   `json:"pointCount,omitempty"`
+	SeriesCount *int32   `json:"seriesCount,omitempty"`
+}
+
 // NodesQuery defines model for NodesQuery.
 type NodesQuery struct {
 	Count *int64          `json:"count,omitempty"`
@@ -166,17 +178,21 @@ type TestDataDataQuery struct {
 	Labels          *string            `json:"labels,omitempty"`
 	LevelColumn     *bool              `json:"levelColumn,omitempty"`
 	Lines           *int64             `json:"lines,omitempty"`
+	Load            *LoadQuery         `json:"load,omitempty"`
 	Nodes           *NodesQuery        `json:"nodes,omitempty"`
 	Points          [][]any            `json:"points,omitempty"`
 	PulseWave       *PulseWaveQuery    `json:"pulseWave,omitempty"`
 	RawFrameContent *string            `json:"rawFrameContent,omitempty"`
 	ScenarioId      *TestDataQueryType `json:"scenarioId,omitempty"`
-	SeriesCount     *int32             `json:"seriesCount,omitempty"`
-	Sim             *SimulationQuery   `json:"sim,omitempty"`
-	SpanCount       *int32             `json:"spanCount,omitempty"`
-	Stream          *StreamingQuery    `json:"stream,omitempty"`
-	StringInput     *string            `json:"stringInput,omitempty"`
-	Usa             *USAQuery          `json:"usa,omitempty"`
+
+	// Seed of the random scenarios, the same seed returns the same values
+	Seed        *int64           `json:"seed,omitempty"`
+	SeriesCount *int32           `json:"seriesCount,omitempty"`
+	Sim         *SimulationQuery `json:"sim,omitempty"`
+	SpanCount   *int32           `json:"spanCount,omitempty"`
+	Stream      *StreamingQuery  `json:"stream,omitempty"`
+	StringInput *string          `json:"stringInput,omitempty"`
+	Usa         *USAQuery        `json:"usa,omitempty"`
 }
 
 // ErrorType defines model for TestDataDataQuery.ErrorType.
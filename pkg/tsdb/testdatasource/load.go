@@ -0,0 +1,116 @@
+package testdatasource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// maxLoadValues limits the number of values a single load query can return
+const maxLoadValues = 10_000_000
+
+type loadQuery struct {
+	SeriesCount int   `json:"seriesCount"`
+	FieldCount  int   `json:"fieldCount"`
+	PointCount  int   `json:"pointCount"`
+	LabelCount  int   `json:"labelCount"`
+	LatencyMs   int64 `json:"latencyMs"`
+	// Error rate percentage (the chance the query fails 0-100)
+	ErrorRate float64 `json:"errorRate"`
+}
+
+func (s *Service) handleLoadScenario(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		model, err := getModel(q.JSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse query json: %v", err)
+		}
+
+		if model.Load.LatencyMs > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(model.Load.LatencyMs) * time.Millisecond):
+			}
+		}
+
+		resp.Responses[q.RefID] = doLoadQuery(q, model)
+	}
+
+	return resp, nil
+}
+
+func doLoadQuery(query backend.DataQuery, model JSONModel) backend.DataResponse {
+	load := model.Load
+	if load.SeriesCount <= 0 {
+		load.SeriesCount = 1
+	}
+	if load.FieldCount <= 0 {
+		load.FieldCount = 1
+	}
+	if load.PointCount <= 0 {
+		load.PointCount = int(query.MaxDataPoints)
+		if load.PointCount <= 0 {
+			load.PointCount = 100
+		}
+	}
+
+	// The counts are checked one at a time so that their product can't overflow
+	if load.SeriesCount > maxLoadValues/load.FieldCount || load.SeriesCount*load.FieldCount > maxLoadValues/load.PointCount {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("load query would return %d series of %d fields of %d points, the limit is %d values", load.SeriesCount, load.FieldCount, load.PointCount, maxLoadValues))
+	}
+
+	// The errors are seeded with the start of the time range too, so a seeded query fails for some time ranges
+	// and not for all of them
+	if load.ErrorRate > 0 && newRand(model, int(query.TimeRange.From.Unix())).Float64()*100 < load.ErrorRate {
+		return backend.ErrDataResponse(backend.StatusInternal, fmt.Sprintf("load query failed (error rate %v%%)", load.ErrorRate))
+	}
+
+	from := query.TimeRange.From.UnixMilli()
+	step := query.TimeRange.Duration().Milliseconds() / int64(load.PointCount)
+	times := make([]time.Time, load.PointCount)
+	for i := range times {
+		times[i] = time.UnixMilli(from + int64(i)*step)
+	}
+
+	frames := make(data.Frames, 0, load.SeriesCount)
+	for i := 0; i < load.SeriesCount; i++ {
+		rand := newRand(model, i)
+		labels := loadLabels(model, load.LabelCount, i)
+
+		frame := newSeriesForQuery(query, model, i)
+		frame.Fields = append(frame.Fields, data.NewField(data.TimeSeriesTimeFieldName, nil, times))
+		for f := 0; f < load.FieldCount; f++ {
+			name := data.TimeSeriesValueFieldName
+			if load.FieldCount > 1 {
+				name = fmt.Sprintf("%s%d", name, f)
+			}
+
+			walker := rand.Float64() * 100
+			values := make([]float64, load.PointCount)
+			for p := range values {
+				values[p] = walker
+				walker += rand.Float64() - 0.5
+			}
+			frame.Fields = append(frame.Fields, data.NewField(name, labels, values))
+		}
+		frames = append(frames, frame)
+	}
+
+	return backend.DataResponse{Frames: frames}
+}
+
+// loadLabels returns the labels of the query with labelCount generated labels. The first label is unique for each
+// series, the next ones have half the values of the previous one, like the hosts, clusters and regions of real series.
+func loadLabels(model JSONModel, labelCount int, seriesIndex int) data.Labels {
+	labels := parseLabels(model, seriesIndex)
+	for l := 0; l < labelCount; l++ {
+		labels[fmt.Sprintf("label%d", l)] = fmt.Sprintf("value%d", seriesIndex>>l)
+	}
+	return labels
+}
@@ -0,0 +1,101 @@
+package testdatasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadScenario(t *testing.T) {
+	s := &Service{}
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	loadRequest := func(json string) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:         "A",
+				TimeRange:     backend.TimeRange{From: from, To: from.Add(time.Hour)},
+				Interval:      time.Minute,
+				MaxDataPoints: 60,
+				JSON:          []byte(json),
+			}},
+		}
+	}
+
+	t.Run("Should return the configured series, fields, points and labels", func(t *testing.T) {
+		resp, err := s.handleLoadScenario(context.Background(), loadRequest(`{"load": {"seriesCount": 4, "fieldCount": 3, "pointCount": 120, "labelCount": 2}}`))
+		require.NoError(t, err)
+
+		dResp := resp.Responses["A"]
+		require.NoError(t, dResp.Error)
+		require.Len(t, dResp.Frames, 4)
+		for i, frame := range dResp.Frames {
+			require.Len(t, frame.Fields, 4)
+			require.Equal(t, 120, frame.Rows())
+			require.True(t, from.Equal(frame.Fields[0].At(0).(time.Time)))
+			require.Equal(t, "Value1", frame.Fields[2].Name)
+			require.Equal(t, data.Labels{"label0": []string{"value0", "value1", "value2", "value3"}[i], "label1": []string{"value0", "value0", "value1", "value1"}[i]}, frame.Fields[1].Labels)
+		}
+	})
+
+	t.Run("Should default to a single series with the max data points", func(t *testing.T) {
+		resp, err := s.handleLoadScenario(context.Background(), loadRequest(`{}`))
+		require.NoError(t, err)
+
+		dResp := resp.Responses["A"]
+		require.Len(t, dResp.Frames, 1)
+		require.Len(t, dResp.Frames[0].Fields, 2)
+		require.Equal(t, 60, dResp.Frames[0].Rows())
+	})
+
+	t.Run("Should return the same values for the same seed", func(t *testing.T) {
+		first, err := s.handleLoadScenario(context.Background(), loadRequest(`{"seed": 1, "load": {"seriesCount": 2}}`))
+		require.NoError(t, err)
+		second, err := s.handleLoadScenario(context.Background(), loadRequest(`{"seed": 1, "load": {"seriesCount": 2}}`))
+		require.NoError(t, err)
+		require.Equal(t, first.Responses["A"].Frames, second.Responses["A"].Frames)
+	})
+
+	t.Run("Should fail with the error rate", func(t *testing.T) {
+		resp, err := s.handleLoadScenario(context.Background(), loadRequest(`{"load": {"errorRate": 100}}`))
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+		require.Equal(t, backend.StatusInternal, resp.Responses["A"].Status)
+	})
+
+	t.Run("Should reject queries over the value limit", func(t *testing.T) {
+		resp, err := s.handleLoadScenario(context.Background(), loadRequest(`{"load": {"seriesCount": 1000, "fieldCount": 100, "pointCount": 1000}}`))
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+		require.Equal(t, backend.StatusBadRequest, resp.Responses["A"].Status)
+	})
+
+	t.Run("Should reject queries whose value count overflows", func(t *testing.T) {
+		for _, json := range []string{
+			// the product of the counts wraps around to 0
+			`{"load": {"seriesCount": 4294967296, "fieldCount": 4294967296, "pointCount": 1}}`,
+			`{"load": {"seriesCount": 1, "fieldCount": 1, "pointCount": 9223372036854775807}}`,
+		} {
+			resp, err := s.handleLoadScenario(context.Background(), loadRequest(json))
+			require.NoError(t, err)
+			require.Error(t, resp.Responses["A"].Error)
+			require.Equal(t, backend.StatusBadRequest, resp.Responses["A"].Status)
+		}
+	})
+
+	t.Run("Should wait for the latency", func(t *testing.T) {
+		start := time.Now()
+		_, err := s.handleLoadScenario(context.Background(), loadRequest(`{"load": {"latencyMs": 50}}`))
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = s.handleLoadScenario(ctx, loadRequest(`{"load": {"latencyMs": 10000}}`))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
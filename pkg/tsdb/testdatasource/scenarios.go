@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"math/rand"
 	"strconv"
 	"strings"
 	"time"
@@ -43,6 +42,7 @@ const (
 	csvFileQueryType                  queryType = "csv_file"
 	csvContentQueryType               queryType = "csv_content"
 	traceType                         queryType = "trace"
+	loadScenarioQuery                 queryType = "load"
 )
 
 type queryType string
@@ -223,6 +223,14 @@ Timestamps will line up evenly on timeStepSeconds (For example, 60 seconds means
 		Name: "Trace",
 	})
 
+	s.registerScenario(&Scenario{
+		ID:      string(loadScenarioQuery),
+		Name:    "Load",
+		handler: s.handleLoadScenario,
+		Description: `Load returns a configurable number of series, fields, points and labels, after an optional latency,
+and fails with the given error rate. Set a seed to get the same values for every request.`,
+	})
+
 	s.queryMux.HandleFunc("", s.handleFallbackScenario)
 }
 
@@ -253,6 +261,9 @@ type JSONModel struct {
 	CSVContent  string      `json:"csvContent"`
 	CSVFileName string      `json:"csvFileName"`
 	DropPercent float64     `json:"dropPercent"`
+	// Seed makes the random scenarios return the same values for every request
+	Seed *int64    `json:"seed,omitempty"`
+	Load loadQuery `json:"load"`
 }
 
 type pulseWave struct {
@@ -269,13 +280,17 @@ func getModel(j json.RawMessage) (JSONModel, error) {
 		ScenarioID:  string(randomWalkQuery),
 		SeriesCount: 1,
 		Lines:       10,
-		StartValue:  rand.Float64() * 100,
+		StartValue:  math.NaN(),
 		Spread:      1,
 	}
 	err := json.Unmarshal(j, &model)
 	if err != nil {
 		return JSONModel{}, err
 	}
+	// The default start value depends on the seed of the query
+	if math.IsNaN(model.StartValue) {
+		model.StartValue = newRand(model, 0).Float64() * 100
+	}
 	return model, nil
 }
 
@@ -563,8 +578,13 @@ func (s *Service) handleExponentialHeatmapBucketDataScenario(ctx context.Context
 	resp := backend.NewQueryDataResponse()
 
 	for _, q := range req.Queries {
+		model, err := getModel(q.JSON)
+		if err != nil {
+			continue
+		}
+
 		respD := resp.Responses[q.RefID]
-		frame := randomHeatmapData(q, func(index int) float64 {
+		frame := randomHeatmapData(q, model, func(index int) float64 {
 			return math.Exp2(float64(index))
 		})
 		respD.Frames = append(respD.Frames, frame)
@@ -578,8 +598,13 @@ func (s *Service) handleLinearHeatmapBucketDataScenario(ctx context.Context, req
 	resp := backend.NewQueryDataResponse()
 
 	for _, q := range req.Queries {
+		model, err := getModel(q.JSON)
+		if err != nil {
+			continue
+		}
+
 		respD := resp.Responses[q.RefID]
-		frame := randomHeatmapData(q, func(index int) float64 {
+		frame := randomHeatmapData(q, model, func(index int) float64 {
 			return float64(index * 10)
 		})
 		respD.Frames = append(respD.Frames, frame)
@@ -633,7 +658,8 @@ func (s *Service) handleLogsScenario(ctx context.Context, req *backend.QueryData
 		lines := model.Lines
 		includeLevelColumn := model.IncludeLevelColumn
 
-		logLevelGenerator := newRandomStringProvider([]string{
+		r := newRand(model, 0)
+		logLevelGenerator := newRandomStringProvider(r, []string{
 			"emerg",
 			"alert",
 			"crit",
@@ -650,11 +676,11 @@ func (s *Service) handleLogsScenario(ctx context.Context, req *backend.QueryData
 			"trace",
 			"",
 		})
-		containerIDGenerator := newRandomStringProvider([]string{
+		containerIDGenerator := newRandomStringProvider(r, []string{
 			"f36a9eaa6d34310686f2b851655212023a216de955cbcc764210cefa71179b1a",
 			"5a354a630364f3742c602f315132e16def594fe68b1e4a195b2fce628e24c97a",
 		})
-		hostnameGenerator := newRandomStringProvider([]string{
+		hostnameGenerator := newRandomStringProvider(r, []string{
 			"srv-001",
 			"srv-002",
 		})
@@ -704,7 +730,7 @@ func (s *Service) handleLogsScenario(ctx context.Context, req *backend.QueryData
 }
 
 func RandomWalk(query backend.DataQuery, model JSONModel, index int) *data.Frame {
-	rand := rand.New(rand.NewSource(time.Now().UnixNano() + int64(index)))
+	rand := newRand(model, index)
 	timeWalkerMs := query.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	to := query.TimeRange.To.UnixNano() / int64(time.Millisecond)
 	startValue := model.StartValue
@@ -765,7 +791,7 @@ func RandomWalk(query backend.DataQuery, model JSONModel, index int) *data.Frame
 }
 
 func randomWalkTable(query backend.DataQuery, model JSONModel) *data.Frame {
-	rand := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rand := newRand(model, 0)
 	timeWalkerMs := query.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	to := query.TimeRange.To.UnixNano() / int64(time.Millisecond)
 	withNil := model.WithNil
@@ -987,8 +1013,8 @@ func predictablePulse(query backend.DataQuery, model JSONModel) (*data.Frame, er
 	return frame, nil
 }
 
-func randomHeatmapData(query backend.DataQuery, fnBucketGen func(index int) float64) *data.Frame {
-	rand := rand.New(rand.NewSource(time.Now().UnixNano()))
+func randomHeatmapData(query backend.DataQuery, model JSONModel, fnBucketGen func(index int) float64) *data.Frame {
+	rand := newRand(model, 0)
 	frame := data.NewFrame("data", data.NewField("time", nil, []*time.Time{}))
 	for i := 0; i < 10; i++ {
 		frame.Fields = append(frame.Fields, data.NewField(strconv.FormatInt(int64(fnBucketGen(i)), 10), nil, []*float64{}))
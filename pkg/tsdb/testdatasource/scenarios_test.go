@@ -58,6 +58,46 @@ func TestTestdataScenarios(t *testing.T) {
 		})
 	})
 
+	t.Run("seed", func(t *testing.T) {
+		timeRange := legacydata.DataTimeRange{From: "5m", To: "now", Now: time.Now()}
+		query := backend.DataQuery{
+			RefID: "A",
+			TimeRange: backend.TimeRange{
+				From: timeRange.MustGetFrom(),
+				To:   timeRange.MustGetTo(),
+			},
+			Interval:      100 * time.Millisecond,
+			MaxDataPoints: 100,
+		}
+
+		queryWithSeed := func(seed int) *backend.QueryDataRequest {
+			q := query
+			q.JSON = []byte(fmt.Sprintf(`{"seed": %d, "seriesCount": 2, "withNil": true, "noise": 1}`, seed))
+			return &backend.QueryDataRequest{Queries: []backend.DataQuery{q}}
+		}
+
+		handlers := map[string]backend.QueryDataHandlerFunc{
+			"random walk":        s.handleRandomWalkScenario,
+			"random walk table":  s.handleRandomWalkTableScenario,
+			"exponential bucket": s.handleExponentialHeatmapBucketDataScenario,
+			"logs":               s.handleLogsScenario,
+		}
+		for name, handler := range handlers {
+			t.Run(name+" returns the same frames for the same seed", func(t *testing.T) {
+				first, err := handler(context.Background(), queryWithSeed(42))
+				require.NoError(t, err)
+				second, err := handler(context.Background(), queryWithSeed(42))
+				require.NoError(t, err)
+				other, err := handler(context.Background(), queryWithSeed(7))
+				require.NoError(t, err)
+
+				require.NotEmpty(t, first.Responses["A"].Frames)
+				require.Equal(t, first.Responses["A"].Frames, second.Responses["A"].Frames)
+				require.NotEqual(t, first.Responses["A"].Frames, other.Responses["A"].Frames)
+			})
+		}
+	})
+
 	t.Run("random walk table", func(t *testing.T) {
 		t.Run("Should return a table that looks like value/min/max", func(t *testing.T) {
 			timeRange := legacydata.DataTimeRange{From: "5m", To: "now", Now: time.Now()}
@@ -63,6 +63,7 @@ func NewSimulationEngine() (*SimulationEngine, error) {
 	}
 	// Initialize each type
 	initializers := []simulationInitializer{
+		newExpressionSimInfo,
 		newFlightSimInfo,
 		newSinewaveInfo,
 		newTankSimInfo,
@@ -0,0 +1,346 @@
+package sims
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	// nolint:depguard // The parser of the math expressions only depends on the standard library
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+type expressionSim struct {
+	key   simulationKey
+	cfg   expressionConfig
+	trees []*parse.Tree // parsed expressions of the fields
+	state expressionState
+
+	// GetValues is called from queries and streams
+	mutex sync.Mutex
+}
+
+var (
+	_ Simulation = (*expressionSim)(nil)
+)
+
+type expressionConfig struct {
+	Fields []expressionField `json:"fields"`
+}
+
+type expressionField struct {
+	Name       string  `json:"name"`
+	Expression string  `json:"expression"`
+	Initial    float64 `json:"initial,omitempty"` // value of $prev for the first point
+	Unit       string  `json:"unit,omitempty"`
+}
+
+type expressionState struct {
+	Time   time.Time
+	Values map[string]float64
+}
+
+var expressionFieldName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reserved variables of the expressions, the fields can also be used as variables
+var expressionVars = map[string]bool{
+	"t":    true, // seconds since the epoch
+	"dt":   true, // seconds since the previous point
+	"prev": true, // previous value of the field
+}
+
+// expressionFunc is the implementation of the functions of the expressions, the generator is seeded with the time
+// of the point so random values are consistent for the value
+type expressionFunc func(gen *rand.Rand, args []float64) float64
+
+var expressionFuncs = map[string]parse.Func{
+	"abs":   mathFunc(math.Abs),
+	"sqrt":  mathFunc(math.Sqrt),
+	"exp":   mathFunc(math.Exp),
+	"log":   mathFunc(math.Log),
+	"sin":   mathFunc(math.Sin),
+	"cos":   mathFunc(math.Cos),
+	"tan":   mathFunc(math.Tan),
+	"floor": mathFunc(math.Floor),
+	"ceil":  mathFunc(math.Ceil),
+	"round": mathFunc(math.Round),
+	"min": newExpressionFunc(2, func(_ *rand.Rand, args []float64) float64 {
+		return math.Min(args[0], args[1])
+	}),
+	"max": newExpressionFunc(2, func(_ *rand.Rand, args []float64) float64 {
+		return math.Max(args[0], args[1])
+	}),
+	"clamp": newExpressionFunc(3, func(_ *rand.Rand, args []float64) float64 {
+		return math.Min(math.Max(args[0], args[1]), args[2])
+	}),
+	"pi": newExpressionFunc(0, func(_ *rand.Rand, _ []float64) float64 {
+		return math.Pi
+	}),
+	"random": newExpressionFunc(0, func(gen *rand.Rand, _ []float64) float64 {
+		return gen.Float64()
+	}),
+}
+
+func mathFunc(f func(float64) float64) parse.Func {
+	return newExpressionFunc(1, func(_ *rand.Rand, args []float64) float64 {
+		return f(args[0])
+	})
+}
+
+func newExpressionFunc(argCount int, f expressionFunc) parse.Func {
+	args := make([]parse.ReturnType, argCount)
+	for i := range args {
+		args[i] = parse.TypeVariantSet
+	}
+	return parse.Func{
+		Args:   args,
+		Return: parse.TypeScalar,
+		F:      f,
+	}
+}
+
+// parseExpressionConfig validates the fields of the config and returns the parsed expressions
+func parseExpressionConfig(cfg expressionConfig) ([]*parse.Tree, error) {
+	names := make(map[string]bool, len(cfg.Fields))
+	for _, f := range cfg.Fields {
+		if !expressionFieldName.MatchString(f.Name) {
+			return nil, fmt.Errorf("invalid field name %q", f.Name)
+		}
+		if expressionVars[f.Name] || f.Name == data.TimeSeriesTimeFieldName {
+			return nil, fmt.Errorf("reserved field name %q", f.Name)
+		}
+		if names[f.Name] {
+			return nil, fmt.Errorf("duplicate field name %q", f.Name)
+		}
+		names[f.Name] = true
+	}
+
+	trees := make([]*parse.Tree, 0, len(cfg.Fields))
+	for _, f := range cfg.Fields {
+		tree, err := parse.Parse(f.Expression, expressionFuncs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression of field %q: %w", f.Name, err)
+		}
+		for _, name := range tree.VarNames {
+			if !expressionVars[name] && !names[name] {
+				return nil, fmt.Errorf("unknown variable $%s in the expression of field %q", name, f.Name)
+			}
+		}
+		trees = append(trees, tree)
+	}
+	return trees, nil
+}
+
+func evalExpression(node parse.Node, vars map[string]float64, gen *rand.Rand) float64 {
+	switch n := node.(type) {
+	case *parse.ScalarNode:
+		return n.Float64
+	case *parse.VarNode:
+		return vars[n.Name]
+	case *parse.UnaryNode:
+		v := evalExpression(n.Arg, vars, gen)
+		if n.OpStr == "!" {
+			return boolToFloat(v == 0)
+		}
+		return -v
+	case *parse.BinaryNode:
+		a := evalExpression(n.Args[0], vars, gen)
+		b := evalExpression(n.Args[1], vars, gen)
+		switch n.OpStr {
+		case "+":
+			return a + b
+		case "-":
+			return a - b
+		case "*":
+			return a * b
+		case "/":
+			return a / b
+		case "%":
+			return math.Mod(a, b)
+		case "**":
+			return math.Pow(a, b)
+		case "==":
+			return boolToFloat(a == b)
+		case "!=":
+			return boolToFloat(a != b)
+		case ">":
+			return boolToFloat(a > b)
+		case ">=":
+			return boolToFloat(a >= b)
+		case "<":
+			return boolToFloat(a < b)
+		case "<=":
+			return boolToFloat(a <= b)
+		case "&&":
+			return boolToFloat(a != 0 && b != 0)
+		case "||":
+			return boolToFloat(a != 0 || b != 0)
+		}
+	case *parse.FuncNode:
+		f, ok := n.F.F.(expressionFunc)
+		if !ok {
+			return math.NaN()
+		}
+		args := make([]float64, len(n.Args))
+		for i, arg := range n.Args {
+			args[i] = evalExpression(arg, vars, gen)
+		}
+		return f(gen, args)
+	}
+	return math.NaN()
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (s *expressionSim) GetState() simulationState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return simulationState{
+		Key:    s.key,
+		Config: s.cfg,
+	}
+}
+
+func (s *expressionSim) SetConfig(vals map[string]interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.applyConfig(vals)
+}
+
+// applyConfig updates the config and keeps the values of the fields that are still there
+func (s *expressionSim) applyConfig(input interface{}) error {
+	current, err := asStringMap(s.cfg)
+	if err != nil {
+		return err
+	}
+	next, err := asStringMap(input)
+	if err != nil {
+		return err
+	}
+	for k, v := range next {
+		current[k] = v
+	}
+
+	// Decode into an empty config, decoding into the current fields would merge the old and new fields
+	cfg := expressionConfig{}
+	if err := updateConfigObjectFromJSON(&cfg, current); err != nil {
+		return err
+	}
+	trees, err := parseExpressionConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]float64, len(cfg.Fields))
+	for _, f := range cfg.Fields {
+		v, ok := s.state.Values[f.Name]
+		if !ok {
+			v = f.Initial
+		}
+		values[f.Name] = v
+	}
+
+	s.cfg = cfg
+	s.trees = trees
+	s.state.Values = values
+	return nil
+}
+
+func (s *expressionSim) NewFrame(size int) *data.Frame {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	frame := data.NewFrame("", data.NewField(data.TimeSeriesTimeFieldName, nil, make([]time.Time, size)))
+	for _, f := range s.cfg.Fields {
+		field := data.NewField(f.Name, nil, make([]float64, size))
+		if f.Unit != "" {
+			field.Config = &data.FieldConfig{Unit: f.Unit}
+		}
+		frame.Fields = append(frame.Fields, field)
+	}
+	return frame
+}
+
+func (s *expressionSim) GetValues(t time.Time) map[string]interface{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	started := !s.state.Time.IsZero()
+	if started && t.Before(s.state.Time) {
+		return nil // can not look backwards!
+	}
+
+	values := map[string]interface{}{
+		data.TimeSeriesTimeFieldName: t,
+	}
+	if started && t.Equal(s.state.Time) {
+		for name, v := range s.state.Values {
+			values[name] = v
+		}
+		return values
+	}
+
+	vars := make(map[string]float64, len(s.state.Values)+len(expressionVars))
+	for name, v := range s.state.Values {
+		vars[name] = v
+	}
+	vars["t"] = float64(t.UnixMilli()) / 1000
+	if started {
+		vars["dt"] = t.Sub(s.state.Time).Seconds()
+	}
+
+	// The fields are evaluated in order, so a field sees the new values of the fields above it
+	gen := rand.New(rand.NewSource(t.UnixMilli())) // consistent for the value
+	for i, f := range s.cfg.Fields {
+		vars["prev"] = s.state.Values[f.Name]
+		v := evalExpression(s.trees[i].Root, vars, gen)
+		vars[f.Name] = v
+		values[f.Name] = v
+	}
+
+	for _, f := range s.cfg.Fields {
+		s.state.Values[f.Name] = vars[f.Name]
+	}
+	s.state.Time = t
+	return values
+}
+
+func (s *expressionSim) Close() error {
+	return nil
+}
+
+func newExpressionSimInfo() simulationInfo {
+	ec := expressionConfig{
+		Fields: []expressionField{
+			{Name: "value", Expression: "sin($t * 2 * pi() / 60) * 10 + 50"},
+			{Name: "total", Expression: "$prev + $value * $dt"},
+		},
+	}
+
+	return simulationInfo{
+		Type:         "expression",
+		Name:         "Expression",
+		Description:  "Fields defined by math expressions of the time and the previous values",
+		ConfigFields: data.NewFrame(""), // the fields are edited as JSON
+		OnlyForward:  true,
+		create: func(cfg simulationState) (Simulation, error) {
+			s := &expressionSim{
+				key: cfg.Key,
+				cfg: ec, // default value
+			}
+			err := s.applyConfig(cfg.Config) // override any fields
+			return s, err
+		},
+	}
+}
@@ -0,0 +1,149 @@
+package sims
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionQuery(t *testing.T) {
+	s, err := NewSimulationEngine()
+	require.NoError(t, err)
+
+	start := time.Date(2020, time.January, 10, 23, 0, 0, 0, time.UTC)
+	query := func(uid string, config interface{}) backend.DataResponse {
+		sq := &simulationQuery{}
+		sq.Key = simulationKey{
+			Type:   "expression",
+			TickHZ: 1,
+			UID:    uid,
+		}
+		sq.Config = config
+		sb, err := json.Marshal(map[string]interface{}{
+			"sim": sq,
+		})
+		require.NoError(t, err)
+
+		rsp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					RefID: "A",
+					TimeRange: backend.TimeRange{
+						From: start,
+						To:   start.Add(time.Second * 5),
+					},
+					Interval:      time.Second,
+					MaxDataPoints: 10,
+					JSON:          sb,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return rsp.Responses["A"]
+	}
+
+	t.Run("fields use the time and the previous values", func(t *testing.T) {
+		dr := query("counter", map[string]interface{}{
+			"fields": []map[string]interface{}{
+				{"name": "count", "expression": "$prev + 1", "initial": 10},
+				{"name": "double", "expression": "$count * 2", "unit": "short"},
+				{"name": "elapsed", "expression": "$prev + $dt"},
+				{"name": "even", "expression": "$t % 2 == 0"},
+				{"name": "bounded", "expression": "clamp(random() * 10, 2, 3)"},
+			},
+		})
+		require.Len(t, dr.Frames, 1)
+
+		frame := dr.Frames[0]
+		require.Len(t, frame.Fields, 6)
+		require.Equal(t, 5, frame.Rows())
+		require.Equal(t, "short", frame.Fields[2].Config.Unit)
+		for i := 0; i < frame.Rows(); i++ {
+			require.Equal(t, float64(11+i), frame.Fields[1].At(i))
+			require.Equal(t, float64(22+2*i), frame.Fields[2].At(i))
+			require.Equal(t, float64(i), frame.Fields[3].At(i))
+			require.Equal(t, float64(1-i%2), frame.Fields[4].At(i))
+			bounded := frame.Fields[5].At(i).(float64)
+			require.True(t, bounded >= 2 && bounded <= 3)
+		}
+	})
+
+	t.Run("default fields", func(t *testing.T) {
+		dr := query("", nil)
+		require.Len(t, dr.Frames, 1)
+
+		frame := dr.Frames[0]
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, "value", frame.Fields[1].Name)
+		require.InDelta(t, math.Sin(float64(start.Unix())*2*math.Pi/60)*10+50, frame.Fields[1].At(0), 1e-9)
+	})
+
+	t.Run("invalid configs", func(t *testing.T) {
+		for name, fields := range map[string][]map[string]interface{}{
+			"syntax error":     {{"name": "a", "expression": "1 +"}},
+			"unknown function": {{"name": "a", "expression": "foo(1)"}},
+			"unknown variable": {{"name": "a", "expression": "$b + 1"}},
+			"reserved name":    {{"name": "prev", "expression": "1"}},
+			"duplicate name":   {{"name": "a", "expression": "1"}, {"name": "a", "expression": "2"}},
+			"invalid name":     {{"name": "a-b", "expression": "1"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := s.Lookup(simulationState{
+					Key:    simulationKey{Type: "expression", TickHZ: 1, UID: "invalid"},
+					Config: map[string]interface{}{"fields": fields},
+				})
+				require.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestExpressionSetConfig(t *testing.T) {
+	s, err := NewSimulationEngine()
+	require.NoError(t, err)
+
+	sim, err := s.Lookup(simulationState{
+		Key: simulationKey{Type: "expression", TickHZ: 1},
+		Config: map[string]interface{}{
+			"fields": []map[string]interface{}{
+				{"name": "a", "expression": "$prev + 1", "unit": "short"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	start := time.Date(2020, time.January, 10, 23, 0, 0, 0, time.UTC)
+	require.Equal(t, 1.0, sim.GetValues(start)["a"])
+	require.Equal(t, 1.0, sim.GetValues(start)["a"], "the same time returns the same values")
+	require.Nil(t, sim.GetValues(start.Add(-time.Second)), "can not look backwards")
+
+	// The values of the fields that are still there are kept
+	err = sim.SetConfig(map[string]interface{}{
+		"fields": []interface{}{
+			map[string]interface{}{"name": "a", "expression": "$prev + 10"},
+			map[string]interface{}{"name": "b", "expression": "$prev - 1", "initial": 5},
+		},
+	})
+	require.NoError(t, err)
+
+	values := sim.GetValues(start.Add(time.Second))
+	require.Equal(t, 11.0, values["a"])
+	require.Equal(t, 4.0, values["b"])
+
+	frame := sim.NewFrame(0)
+	require.Len(t, frame.Fields, 3)
+	require.Nil(t, frame.Fields[1].Config, "the unit of the old field is not kept")
+
+	err = sim.SetConfig(map[string]interface{}{
+		"fields": []interface{}{
+			map[string]interface{}{"name": "a", "expression": "$c"},
+		},
+	})
+	require.Error(t, err)
+	require.Len(t, sim.NewFrame(0).Fields, 3, "an invalid config is not applied")
+}
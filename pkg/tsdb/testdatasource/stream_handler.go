@@ -2,6 +2,7 @@ package testdatasource
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"regexp"
@@ -82,7 +83,15 @@ func (s *Service) RunStream(ctx context.Context, request *backend.RunStreamReque
 	default:
 		return fmt.Errorf("testdata plugin does not support path: %s", request.Path)
 	}
-	return s.runTestStream(ctx, request.Path, conf, sender)
+
+	// The seed of the query is passed with the channel data, like for the other random scenarios
+	var model JSONModel
+	if len(request.Data) > 0 {
+		if err := json.Unmarshal(request.Data, &model); err != nil {
+			return fmt.Errorf("failed to parse stream data: %w", err)
+		}
+	}
+	return s.runTestStream(ctx, request.Path, conf, newRand(model, 0), sender)
 }
 
 type testStreamConfig struct {
@@ -91,7 +100,7 @@ type testStreamConfig struct {
 	Labeled  bool
 }
 
-func (s *Service) runTestStream(ctx context.Context, path string, conf testStreamConfig, sender *backend.StreamSender) error {
+func (s *Service) runTestStream(ctx context.Context, path string, conf testStreamConfig, rand *rand.Rand, sender *backend.StreamSender) error {
 	spread := 50.0
 	walker := rand.Float64() * 100
 
@@ -12,9 +12,9 @@ type randomStringProvider struct {
 	data []string
 }
 
-func newRandomStringProvider(data []string) *randomStringProvider {
+func newRandomStringProvider(r *rand.Rand, data []string) *randomStringProvider {
 	return &randomStringProvider{
-		r:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		r:    r,
 		data: data,
 	}
 }
@@ -23,7 +23,17 @@ func (p *randomStringProvider) Next() string {
 	return p.data[p.r.Int31n(int32(len(p.data)))]
 }
 
-func dropValues(frame *data.Frame, percent float64) (*data.Frame, error) {
+// newRand returns the random number generator of the series with the given index. Queries with a seed get the same
+// values for every request, the other ones are seeded with the current time.
+func newRand(model JSONModel, index int) *rand.Rand {
+	seed := time.Now().UnixNano()
+	if model.Seed != nil {
+		seed = *model.Seed
+	}
+	return rand.New(rand.NewSource(seed + int64(index)))
+}
+
+func dropValues(frame *data.Frame, percent float64, r *rand.Rand) (*data.Frame, error) {
 	if frame == nil || percent <= 0 || percent >= 100 {
 		return frame, nil
 	}
@@ -31,8 +41,6 @@ func dropValues(frame *data.Frame, percent float64) (*data.Frame, error) {
 	copy := frame.EmptyCopy()
 
 	percentage := percent / 100.0
-	seed := time.Now().UnixMilli()
-	r := rand.New(rand.NewSource(seed))
 	for i := 0; i < rows; i++ {
 		if r.Float64() < percentage { // .2 == 20
 			continue
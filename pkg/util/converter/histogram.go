@@ -0,0 +1,15 @@
+package converter
+
+import "github.com/grafana/grafana-plugin-sdk-go/data"
+
+// BucketLabel is the label that holds the upper bound of a cumulative histogram bucket, as used by Prometheus
+const BucketLabel = "le"
+
+// heatmapCellsFrameType is the frame type of native histograms, see ReadPrometheusStyleResult
+const heatmapCellsFrameType = "heatmap-cells"
+
+// IsHeatmapCellsFrame returns true for the frames of native histograms, the series labels
+// are then stored on the yMin field (index 1)
+func IsHeatmapCellsFrame(frame *data.Frame) bool {
+	return frame != nil && frame.Meta != nil && frame.Meta.Type == heatmapCellsFrameType
+}
@@ -436,10 +436,15 @@ func readMatrixOrVectorWide(iter *jsoniter.Iterator, resultType string, opt Opti
 		}
 
 		if histogram != nil {
+			// the series is returned as a heatmap, so it has no values in the wide frame
+			if isEmptyField(valueField) {
+				frame.Fields = frame.Fields[:len(frame.Fields)-1]
+			}
+
 			histogram.yMin.Labels = valueField.Labels
 			frame := data.NewFrame(valueField.Name, histogram.time, histogram.yMin, histogram.yMax, histogram.count, histogram.yLayout)
 			frame.Meta = &data.FrameMeta{
-				Type: "heatmap-cells",
+				Type: heatmapCellsFrameType,
 			}
 			if frame.Name == data.TimeSeriesValueFieldName {
 				frame.Name = "" // only set the name if useful
@@ -448,15 +453,26 @@ func readMatrixOrVectorWide(iter *jsoniter.Iterator, resultType string, opt Opti
 		}
 	}
 
-	if len(rsp.Frames) == 0 {
+	// keep the float series when the response also contains native histograms
+	if len(rsp.Frames) == 0 || len(frame.Fields) > 1 {
 		sorter := experimental.NewFrameSorter(frame, frame.Fields[0])
 		sort.Sort(sorter)
-		rsp.Frames = append(rsp.Frames, frame)
+		rsp.Frames = append([]*data.Frame{frame}, rsp.Frames...)
 	}
 
 	return rsp
 }
 
+// isEmptyField returns true if the field has no non-null values
+func isEmptyField(field *data.Field) bool {
+	for i := 0; i < field.Len(); i++ {
+		if v, ok := field.ConcreteAt(i); ok && v != nil {
+			return false
+		}
+	}
+	return true
+}
+
 func addValuePairToFrame(frame *data.Frame, timeMap map[int64]int, rowIdx int, iter *jsoniter.Iterator) (map[int64]int, int) {
 	timeField := frame.Fields[0]
 	valueField := frame.Fields[len(frame.Fields)-1]
@@ -545,7 +561,7 @@ func readMatrixOrVectorMulti(iter *jsoniter.Iterator, resultType string, opt Opt
 			histogram.yMin.Labels = valueField.Labels
 			frame := data.NewFrame(valueField.Name, histogram.time, histogram.yMin, histogram.yMax, histogram.count, histogram.yLayout)
 			frame.Meta = &data.FrameMeta{
-				Type: "heatmap-cells",
+				Type: heatmapCellsFrameType,
 			}
 			if frame.Name == data.TimeSeriesValueFieldName {
 				frame.Name = "" // only set the name if useful
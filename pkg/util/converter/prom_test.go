@@ -22,6 +22,7 @@ func TestReadPromFrames(t *testing.T) {
 		"prom-matrix-with-nans",
 		"prom-matrix-histogram-no-labels",
 		"prom-matrix-histogram-partitioned",
+		"prom-matrix-histogram-mixed",
 		"prom-vector-histogram-no-labels",
 		"prom-vector",
 		"prom-string",
@@ -33,6 +33,10 @@ const (
 	// checks.
 	// HTTP status code 400.
 	StatusValidationFailed CoreStatus = "Validation failed"
+	// StatusRequestEntityTooLarge means that the payload of the request
+	// is larger than the server accepts.
+	// HTTP status code 413.
+	StatusRequestEntityTooLarge CoreStatus = "Request entity too large"
 	// StatusInternal means that the server acknowledges that there's
 	// an error, but that there is nothing the client can do to fix it.
 	// HTTP status code 500.
@@ -75,6 +79,8 @@ func (s CoreStatus) HTTPStatus() int {
 		return http.StatusTooManyRequests
 	case StatusBadRequest, StatusValidationFailed:
 		return http.StatusBadRequest
+	case StatusRequestEntityTooLarge:
+		return http.StatusRequestEntityTooLarge
 	case StatusNotImplemented:
 		return http.StatusNotImplemented
 	case StatusUnknown, StatusInternal:
@@ -101,6 +107,8 @@ func (s CoreStatus) LogLevel() LogLevel {
 		return LevelInfo
 	case StatusValidationFailed:
 		return LevelInfo
+	case StatusRequestEntityTooLarge:
+		return LevelInfo
 	case StatusNotImplemented:
 		return LevelDebug
 	case StatusUnknown, StatusInternal:
@@ -52,6 +52,10 @@ export const Math = ({ labelWidth, onChange, query, onRunQuery }: Props) => {
                       name="abs"
                       description="returns the absolute value of its argument which can be a number or a series"
                     />
+                    <DocumentedFunction
+                      name="histogram_quantile"
+                      description="calculates a quantile of cumulative histogram buckets, identified by their le label. For example histogram_quantile(0.95, $A)."
+                    />
                     <DocumentedFunction
                       name="is_inf"
                       description="returns 1 for Inf values (negative or positive) and 0 for other values. It's able to operate on series or scalar values."
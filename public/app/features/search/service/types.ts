@@ -14,8 +14,9 @@ export interface SearchQuery {
   ds_type?: string;
   saved_query_uid?: string; // TODO: not implemented yet
   tags?: string[];
-  kind?: string[];
+  kind?: string[]; // alertrule, librarypanel, ds and playlist are only returned when listed
   panel_type?: string;
+  query_text?: string; // matches the queries of the panels and alert rules, like PromQL or SQL
   uid?: string[];
   facet?: FacetField[];
   explain?: boolean;
@@ -28,7 +29,7 @@ export interface SearchQuery {
 }
 
 export interface DashboardQueryResult {
-  kind: string; // panel, dashboard, folder
+  kind: string; // panel, dashboard, folder, alertrule, librarypanel, ds, playlist
   name: string;
   uid: string;
   url: string; // link to value (unique)
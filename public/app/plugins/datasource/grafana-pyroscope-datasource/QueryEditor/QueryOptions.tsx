@@ -21,6 +21,7 @@ const typeOptions: Array<{ value: Query['queryType']; label: string; description
   { value: 'metrics', label: 'Metric', description: 'Return aggregated metrics' },
   { value: 'profile', label: 'Profile', description: 'Return profile' },
   { value: 'both', label: 'Both', description: 'Return both metric and profile data' },
+  { value: 'diff', label: 'Diff', description: 'Compare the profile to a baseline profile' },
 ];
 
 function getTypeOptions(app?: CoreApp) {
@@ -71,6 +72,34 @@ export function QueryOptions({ query, onQueryChange, app, labels }: Props) {
               onChange={(value) => onQueryChange({ ...query, queryType: value })}
             />
           </EditorField>
+          {query.queryType === 'diff' && (
+            <>
+              <EditorField
+                label={'Baseline label selector'}
+                tooltip={<>Label selector of the baseline profile. Defaults to the label selector of the query.</>}
+              >
+                <Input
+                  value={query.baselineLabelSelector || ''}
+                  placeholder={query.labelSelector}
+                  onChange={(event: React.SyntheticEvent<HTMLInputElement>) => {
+                    onQueryChange({ ...query, baselineLabelSelector: event.currentTarget.value });
+                  }}
+                />
+              </EditorField>
+              <EditorField
+                label={'Baseline time shift'}
+                tooltip={<>Shifts the time range of the baseline profile back, for example 1h or 1d.</>}
+              >
+                <Input
+                  value={query.baselineTimeShift || ''}
+                  placeholder="1d"
+                  onChange={(event: React.SyntheticEvent<HTMLInputElement>) => {
+                    onQueryChange({ ...query, baselineTimeShift: event.currentTarget.value });
+                  }}
+                />
+              </EditorField>
+            </>
+          )}
           <EditorField
             label={'Group by'}
             tooltip={
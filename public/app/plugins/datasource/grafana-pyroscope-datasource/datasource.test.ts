@@ -77,6 +77,23 @@ describe('Phlare data source', () => {
       expect(templateSrv.replace).toBeCalledTimes(1);
       expect(query.labelSelector).toBe(`{${interpolationText}="${interpolationText}"}`);
     });
+
+    it('should update baselineLabelSelector of diff queries if there are template variables', () => {
+      const templateSrv = new TemplateSrv();
+      templateSrv.replace = jest.fn((query: string): string => {
+        return query.replace(/\$interpolationVar/g, interpolationText);
+      });
+      ds = new PhlareDataSource(defaultSettings, templateSrv);
+      const query = ds.applyTemplateVariables(
+        {
+          ...defaultQuery(`{${noInterpolation}}`),
+          queryType: 'diff',
+          baselineLabelSelector: `{app="${interpolationVar}"}`,
+        },
+        { interpolationVar: { text: interpolationText, value: interpolationText } }
+      );
+      expect(query.baselineLabelSelector).toBe(`{app="${interpolationText}"}`);
+    });
   });
 });
 
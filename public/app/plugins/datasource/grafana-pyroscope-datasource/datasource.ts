@@ -70,6 +70,9 @@ export class PhlareDataSource extends DataSourceWithBackend<Query, PhlareDataSou
     return {
       ...query,
       labelSelector: this.templateSrv.replace(query.labelSelector ?? '', scopedVars),
+      baselineLabelSelector: query.baselineLabelSelector
+        ? this.templateSrv.replace(query.baselineLabelSelector, scopedVars)
+        : query.baselineLabelSelector,
     };
   }
 
@@ -0,0 +1,128 @@
+import React from 'react';
+
+import { SelectableValue } from '@grafana/data';
+import { InlineField, InlineFieldRow, InlineSwitch, Input, MultiSelect, Select, TagsInput } from '@grafana/ui';
+
+import { GrafanaAlertStatesQuery, GrafanaAnnotationsFrameQuery, GrafanaDashboardsQuery } from '../types';
+
+const labelWidth = 12;
+
+const annotationTypes: Array<SelectableValue<GrafanaAnnotationsFrameQuery['type']>> = [
+  { label: 'All', value: undefined },
+  { label: 'Annotations', value: 'annotation' },
+  { label: 'Alerts', value: 'alert' },
+];
+
+const alertStates: Array<SelectableValue<string>> = ['Normal', 'Alerting', 'Pending', 'NoData', 'Error'].map((v) => ({
+  label: v,
+  value: v,
+}));
+
+function parseLimit(value: string): number | undefined {
+  const limit = parseInt(value, 10);
+  return isNaN(limit) || limit <= 0 ? undefined : limit;
+}
+
+interface Props<T> {
+  value: T;
+  onChange: (value: T) => void;
+}
+
+export function AnnotationsFrameEditor({ value, onChange }: Props<GrafanaAnnotationsFrameQuery>) {
+  return (
+    <>
+      <InlineFieldRow>
+        <InlineField label="Dashboard" labelWidth={labelWidth} tooltip="UID of the dashboard, empty for all dashboards">
+          <Input
+            width={20}
+            defaultValue={value.dashboardUID}
+            placeholder="All dashboards"
+            onBlur={(e) => onChange({ ...value, dashboardUID: e.currentTarget.value || undefined })}
+          />
+        </InlineField>
+        <InlineField label="Type" labelWidth={labelWidth}>
+          <Select
+            width={16}
+            options={annotationTypes}
+            value={annotationTypes.find((v) => v.value === value.type) ?? annotationTypes[0]}
+            onChange={(v) => onChange({ ...value, type: v.value })}
+          />
+        </InlineField>
+        <InlineField label="Limit" labelWidth={labelWidth}>
+          <Input
+            width={10}
+            type="number"
+            defaultValue={value.limit}
+            placeholder="100"
+            onBlur={(e) => onChange({ ...value, limit: parseLimit(e.currentTarget.value) })}
+          />
+        </InlineField>
+      </InlineFieldRow>
+      <InlineFieldRow>
+        <InlineField label="Tags" labelWidth={labelWidth}>
+          <TagsInput tags={value.tags} onChange={(tags) => onChange({ ...value, tags })} />
+        </InlineField>
+        <InlineField
+          label="Match any"
+          labelWidth={labelWidth}
+          tooltip="By default only the annotations that match all the tags are returned"
+        >
+          <InlineSwitch
+            value={value.matchAny ?? false}
+            onChange={(e) => onChange({ ...value, matchAny: e.currentTarget.checked })}
+          />
+        </InlineField>
+      </InlineFieldRow>
+    </>
+  );
+}
+
+export function AlertStatesEditor({ value, onChange }: Props<GrafanaAlertStatesQuery>) {
+  return (
+    <InlineFieldRow>
+      <InlineField label="Dashboard" labelWidth={labelWidth} tooltip="UID of the dashboard, empty for all the rules">
+        <Input
+          width={20}
+          defaultValue={value.dashboardUID}
+          placeholder="All rules"
+          onBlur={(e) => onChange({ ...value, dashboardUID: e.currentTarget.value || undefined })}
+        />
+      </InlineField>
+      <InlineField label="States" labelWidth={labelWidth} grow={true}>
+        <MultiSelect
+          options={alertStates}
+          value={value.states ?? []}
+          placeholder="All states"
+          onChange={(v) => onChange({ ...value, states: v.length ? v.map((s) => s.value!) : undefined })}
+        />
+      </InlineField>
+    </InlineFieldRow>
+  );
+}
+
+export function DashboardsEditor({ value, onChange }: Props<GrafanaDashboardsQuery>) {
+  return (
+    <InlineFieldRow>
+      <InlineField label="Title" labelWidth={labelWidth}>
+        <Input
+          width={20}
+          defaultValue={value.query}
+          placeholder="All dashboards"
+          onBlur={(e) => onChange({ ...value, query: e.currentTarget.value || undefined })}
+        />
+      </InlineField>
+      <InlineField label="Tags" labelWidth={labelWidth}>
+        <TagsInput tags={value.tags} onChange={(tags) => onChange({ ...value, tags })} />
+      </InlineField>
+      <InlineField label="Limit" labelWidth={labelWidth}>
+        <Input
+          width={10}
+          type="number"
+          defaultValue={value.limit}
+          placeholder="1000"
+          onBlur={(e) => onChange({ ...value, limit: parseLimit(e.currentTarget.value) })}
+        />
+      </InlineField>
+    </InlineFieldRow>
+  );
+}
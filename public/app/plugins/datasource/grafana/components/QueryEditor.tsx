@@ -35,8 +35,16 @@ import * as DFImport from 'app/features/dataframe-import';
 import { SearchQuery } from 'app/features/search/service';
 
 import { GrafanaDatasource } from '../datasource';
-import { defaultQuery, GrafanaQuery, GrafanaQueryType } from '../types';
-
+import {
+  defaultQuery,
+  GrafanaAlertStatesQuery,
+  GrafanaAnnotationsFrameQuery,
+  GrafanaDashboardsQuery,
+  GrafanaQuery,
+  GrafanaQueryType,
+} from '../types';
+
+import { AlertStatesEditor, AnnotationsFrameEditor, DashboardsEditor } from './GrafanaObjectsEditor';
 import SearchEditor from './SearchEditor';
 
 interface Props extends QueryEditorProps<GrafanaDatasource, GrafanaQuery>, Themeable2 {}
@@ -68,6 +76,21 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
       value: GrafanaQueryType.List,
       description: 'Show directory listings for public resources',
     },
+    {
+      label: 'Annotations',
+      value: GrafanaQueryType.AnnotationsFrame,
+      description: 'Annotations of the time range as a table',
+    },
+    {
+      label: 'Alert states',
+      value: GrafanaQueryType.AlertStates,
+      description: 'Current state of the alert rule instances',
+    },
+    {
+      label: 'Dashboards',
+      value: GrafanaQueryType.Dashboards,
+      description: 'Dashboards with their folder, tags and versions',
+    },
   ];
 
   constructor(props: Props) {
@@ -457,6 +480,24 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
     onRunQuery();
   };
 
+  onAnnotationsFrameChange = (annotationsFrame: GrafanaAnnotationsFrameQuery) => {
+    const { query, onChange, onRunQuery } = this.props;
+    onChange({ ...query, annotationsFrame });
+    onRunQuery();
+  };
+
+  onAlertStatesChange = (alertStates: GrafanaAlertStatesQuery) => {
+    const { query, onChange, onRunQuery } = this.props;
+    onChange({ ...query, alertStates });
+    onRunQuery();
+  };
+
+  onDashboardsChange = (dashboards: GrafanaDashboardsQuery) => {
+    const { query, onChange, onRunQuery } = this.props;
+    onChange({ ...query, dashboards });
+    onRunQuery();
+  };
+
   render() {
     const query = {
       ...defaultQuery,
@@ -500,6 +541,15 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
         {queryType === GrafanaQueryType.Search && (
           <SearchEditor value={query.search ?? {}} onChange={this.onSearchChange} />
         )}
+        {queryType === GrafanaQueryType.AnnotationsFrame && (
+          <AnnotationsFrameEditor value={query.annotationsFrame ?? {}} onChange={this.onAnnotationsFrameChange} />
+        )}
+        {queryType === GrafanaQueryType.AlertStates && (
+          <AlertStatesEditor value={query.alertStates ?? {}} onChange={this.onAlertStatesChange} />
+        )}
+        {queryType === GrafanaQueryType.Dashboards && (
+          <DashboardsEditor value={query.dashboards ?? {}} onChange={this.onDashboardsChange} />
+        )}
       </>
     );
   }
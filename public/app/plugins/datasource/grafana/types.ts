@@ -19,6 +19,9 @@ export enum GrafanaQueryType {
   List = 'list',
   Read = 'read',
   Search = 'search',
+  AnnotationsFrame = 'annotationsFrame',
+  AlertStates = 'alertStates',
+  Dashboards = 'dashboards',
 }
 
 export interface GrafanaQuery extends DataQuery {
@@ -31,6 +34,28 @@ export interface GrafanaQuery extends DataQuery {
   snapshot?: DataFrameJSON[];
   timeRegion?: TimeRegionConfig;
   file?: GrafanaQueryFile;
+  annotationsFrame?: GrafanaAnnotationsFrameQuery;
+  alertStates?: GrafanaAlertStatesQuery;
+  dashboards?: GrafanaDashboardsQuery;
+}
+
+export interface GrafanaAnnotationsFrameQuery {
+  dashboardUID?: string;
+  tags?: string[];
+  matchAny?: boolean;
+  type?: 'annotation' | 'alert';
+  limit?: number; // 100
+}
+
+export interface GrafanaAlertStatesQuery {
+  dashboardUID?: string;
+  states?: string[]; // Normal, Alerting, Pending, NoData, Error
+}
+
+export interface GrafanaDashboardsQuery {
+  query?: string;
+  tags?: string[];
+  limit?: number; // 1000
 }
 
 export interface GrafanaQueryFile {
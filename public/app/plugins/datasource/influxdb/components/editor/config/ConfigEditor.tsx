@@ -14,10 +14,13 @@ import {
 } from '@grafana/data/src';
 import {
   Alert,
+  Button,
   DataSourceHttpSettings,
+  IconButton,
   InfoBox,
   InlineField,
   InlineFormLabel,
+  InlineSwitch,
   LegacyForms,
   Select,
 } from '@grafana/ui/src';
@@ -44,6 +47,11 @@ const versions: Array<SelectableValue<InfluxVersion>> = [
     value: InfluxVersion.Flux,
     description: 'Advanced data scripting and query language.  Supported in InfluxDB 2.x and 1.8+',
   },
+  {
+    label: 'SQL',
+    value: InfluxVersion.SQL,
+    description: 'Native SQL language queried with Flight SQL. Supported in InfluxDB 3.0',
+  },
 ];
 
 export type Props = DataSourcePluginOptionsEditorProps<InfluxOptions>;
@@ -92,6 +100,13 @@ export class ConfigEditor extends PureComponent<Props, State> {
       // Remove old 1x configs
       const { user, database, ...rest } = copy;
 
+      onOptionsChange(rest as DataSourceSettings<InfluxOptions, {}>);
+    } else if (selected.value === InfluxVersion.SQL) {
+      copy.access = 'proxy';
+
+      // SQL queries are authenticated with a token
+      const { user, database, ...rest } = copy;
+
       onOptionsChange(rest as DataSourceSettings<InfluxOptions, {}>);
     } else {
       onOptionsChange(copy);
@@ -172,6 +187,113 @@ export class ConfigEditor extends PureComponent<Props, State> {
     );
   }
 
+  onMetadataChange = (metadata: Array<{ key: string; value: string }>) => {
+    updateDatasourcePluginJsonDataOption(
+      this.props,
+      'metadata',
+      metadata.map(({ key, value }) => ({ [key]: value }))
+    );
+  };
+
+  renderInfluxSQL() {
+    const { options } = this.props;
+    const { secureJsonFields } = options;
+    const secureJsonData = (options.secureJsonData || {}) as InfluxSecureJsonData;
+    const { htmlPrefix } = this;
+    const metadata = (options.jsonData.metadata ?? []).map((m) => {
+      const [key = '', value = ''] = Object.entries(m)[0] ?? [];
+      return { key, value };
+    });
+
+    return (
+      <>
+        <div className="gf-form-inline">
+          <div className="gf-form">
+            <InlineFormLabel htmlFor={`${htmlPrefix}-sql-db`} className="width-10">
+              Database
+            </InlineFormLabel>
+            <div className="width-20">
+              <Input
+                id={`${htmlPrefix}-sql-db`}
+                className="width-20"
+                value={options.jsonData.dbName || ''}
+                onChange={onUpdateDatasourceJsonDataOption(this.props, 'dbName')}
+              />
+            </div>
+          </div>
+        </div>
+        <div className="gf-form-inline">
+          <div className="gf-form">
+            <SecretFormField
+              isConfigured={Boolean(secureJsonFields && secureJsonFields.token)}
+              value={secureJsonData.token || ''}
+              label="Token"
+              aria-label="Token"
+              labelWidth={10}
+              inputWidth={20}
+              onReset={this.onResetToken}
+              onChange={onUpdateDatasourceSecureJsonDataOption(this.props, 'token')}
+            />
+          </div>
+        </div>
+        <div className="gf-form-inline">
+          <InlineField
+            labelWidth={20}
+            label="Insecure connection"
+            tooltip="Connect to the Flight SQL endpoint without TLS, even when the URL is https."
+          >
+            <InlineSwitch
+              value={options.jsonData.insecureGrpc ?? false}
+              onChange={(event) =>
+                updateDatasourcePluginJsonDataOption(this.props, 'insecureGrpc', event.currentTarget.checked)
+              }
+            />
+          </InlineField>
+        </div>
+        <h6>MetaData</h6>
+        {metadata.map((m, i) => (
+          <div className="gf-form-inline" key={i}>
+            <InlineField labelWidth={10} label="Key">
+              <Input
+                className="width-15"
+                value={m.key}
+                onChange={(event) =>
+                  this.onMetadataChange(
+                    metadata.map((v, j) => (j === i ? { ...v, key: event.currentTarget.value } : v))
+                  )
+                }
+              />
+            </InlineField>
+            <InlineField labelWidth={10} label="Value">
+              <Input
+                className="width-15"
+                value={m.value}
+                onChange={(event) =>
+                  this.onMetadataChange(
+                    metadata.map((v, j) => (j === i ? { ...v, value: event.currentTarget.value } : v))
+                  )
+                }
+              />
+            </InlineField>
+            <IconButton
+              name="trash-alt"
+              tooltip="Remove metadata"
+              onClick={() => this.onMetadataChange(metadata.filter((_, j) => j !== i))}
+            />
+          </div>
+        ))}
+        <Button
+          variant="secondary"
+          icon="plus"
+          size="sm"
+          onClick={() => this.onMetadataChange([...metadata, { key: '', value: '' }])}
+        >
+          Add metadata
+        </Button>
+      </>
+    );
+  }
+
   renderInflux1x() {
     const { options } = this.props;
     const { secureJsonFields } = options;
@@ -303,7 +425,7 @@ export class ConfigEditor extends PureComponent<Props, State> {
               <Select
                 aria-label="Query language"
                 className="width-30"
-                value={options.jsonData.version === InfluxVersion.Flux ? versions[1] : versions[0]}
+                value={versions.find((version) => version.value === options.jsonData.version) ?? versions[0]}
                 options={versions}
                 defaultValue={versions[0]}
                 onChange={this.onVersionChanged}
@@ -342,7 +464,11 @@ export class ConfigEditor extends PureComponent<Props, State> {
           <div>
             <h3 className="page-heading">InfluxDB Details</h3>
           </div>
-          {options.jsonData.version === InfluxVersion.Flux ? this.renderInflux2x() : this.renderInflux1x()}
+          {options.jsonData.version === InfluxVersion.Flux && this.renderInflux2x()}
+          {options.jsonData.version === InfluxVersion.SQL && this.renderInfluxSQL()}
+          {options.jsonData.version !== InfluxVersion.Flux &&
+            options.jsonData.version !== InfluxVersion.SQL &&
+            this.renderInflux1x()}
           <div className="gf-form-inline">
             <InlineField
               labelWidth={20}
@@ -8,6 +8,7 @@ import { buildRawQuery } from '../../../queryUtils';
 import { InfluxOptions, InfluxQuery } from '../../../types';
 
 import { FluxQueryEditor } from './flux/FluxQueryEditor';
+import { FSQLEditor } from './fsql/FSQLEditor';
 import { QueryEditorModeSwitcher } from './influxql/QueryEditorModeSwitcher';
 import { RawInfluxQLEditor } from './influxql/code/RawInfluxQLEditor';
 import { VisualInfluxQLEditor as VisualInfluxQLEditor } from './influxql/visual/VisualInfluxQLEditor';
@@ -15,6 +16,14 @@ import { VisualInfluxQLEditor as VisualInfluxQLEditor } from './influxql/visual/
 type Props = QueryEditorProps<InfluxDatasource, InfluxQuery, InfluxOptions>;
 
 export const QueryEditor = ({ query, onChange, onRunQuery, datasource }: Props) => {
+  if (datasource.isSql) {
+    return (
+      <div className="gf-form-query-content">
+        <FSQLEditor query={query} onChange={onChange} onRunQuery={onRunQuery} datasource={datasource} />
+      </div>
+    );
+  }
+
   if (datasource.isFlux) {
     return (
       <div className="gf-form-query-content">
@@ -0,0 +1,163 @@
+import { css, cx } from '@emotion/css';
+import React, { PureComponent } from 'react';
+
+import { GrafanaTheme2, SelectableValue } from '@grafana/data/src';
+import { getTemplateSrv } from '@grafana/runtime/src';
+import {
+  CodeEditor,
+  CodeEditorSuggestionItem,
+  CodeEditorSuggestionItemKind,
+  InlineFormLabel,
+  LinkButton,
+  MonacoEditor,
+  Select,
+  Themeable2,
+  withTheme2,
+} from '@grafana/ui/src';
+
+import InfluxDatasource from '../../../../datasource';
+import { InfluxQuery, SQLFormat } from '../../../../types';
+
+interface Props extends Themeable2 {
+  onChange: (query: InfluxQuery) => void;
+  onRunQuery: () => void;
+  query: InfluxQuery;
+  datasource: InfluxDatasource;
+}
+
+const formats: Array<SelectableValue<SQLFormat>> = [
+  { label: 'Table', value: 'table' },
+  { label: 'Time series', value: 'time_series' },
+];
+
+const macros: CodeEditorSuggestionItem[] = [
+  {
+    label: '$__timeFilter(time)',
+    kind: CodeEditorSuggestionItemKind.Method,
+    detail: 'Time column within the dashboard time range',
+  },
+  { label: '$__timeFrom()', kind: CodeEditorSuggestionItemKind.Method, detail: 'Start of the dashboard time range' },
+  { label: '$__timeTo()', kind: CodeEditorSuggestionItemKind.Method, detail: 'End of the dashboard time range' },
+  {
+    label: '$__timeGroup(time, $__interval)',
+    kind: CodeEditorSuggestionItemKind.Method,
+    detail: 'Time column grouped by the interval',
+  },
+  {
+    label: '$__timeGroupAlias(time, $__interval)',
+    kind: CodeEditorSuggestionItemKind.Method,
+    detail: 'Time group with a time alias',
+  },
+  { label: '$__interval', kind: CodeEditorSuggestionItemKind.Property, detail: 'based on max data points' },
+];
+
+class UnthemedFSQLEditor extends PureComponent<Props> {
+  onSQLChange = (rawSql: string) => {
+    this.props.onChange({ ...this.props.query, rawSql });
+    this.props.onRunQuery();
+  };
+
+  onFormatChange = (format: SelectableValue<SQLFormat>) => {
+    this.props.onChange({ ...this.props.query, format: format.value });
+    this.props.onRunQuery();
+  };
+
+  getSuggestions = (): CodeEditorSuggestionItem[] => {
+    const sugs: CodeEditorSuggestionItem[] = [...macros];
+
+    const templateSrv = getTemplateSrv();
+    templateSrv.getVariables().forEach((variable) => {
+      const label = '${' + variable.name + '}';
+      let val = templateSrv.replace(label);
+      if (val === label) {
+        val = '';
+      }
+      sugs.push({
+        label,
+        kind: CodeEditorSuggestionItemKind.Text,
+        detail: `(Template Variable) ${val}`,
+      });
+    });
+
+    return sugs;
+  };
+
+  // Forces the layout shortly after mount, so that the editor gets its width when re-mounted in angular
+  editorDidMountCallbackHack = (editor: MonacoEditor) => {
+    setTimeout(() => editor.layout(), 100);
+  };
+
+  render() {
+    const { query, theme } = this.props;
+    const styles = getStyles(theme);
+
+    const helpTooltip = (
+      <div>
+        Type: <i>ctrl+space</i> to show macro and template variable suggestions <br />
+        Use the time series format for queries returning a time column, the string columns are used as labels
+      </div>
+    );
+
+    return (
+      <>
+        <CodeEditor
+          height={'100%'}
+          containerStyles={styles.editorContainerStyles}
+          language="sql"
+          value={query.rawSql || ''}
+          onBlur={this.onSQLChange}
+          onSave={this.onSQLChange}
+          showMiniMap={false}
+          showLineNumbers={true}
+          getSuggestions={this.getSuggestions}
+          onEditorDidMount={this.editorDidMountCallbackHack}
+        />
+        <div className={cx('gf-form-inline', styles.editorActions)}>
+          <LinkButton
+            icon="external-link-alt"
+            variant="secondary"
+            target="blank"
+            href="https://docs.influxdata.com/influxdb/cloud-serverless/query-data/sql/"
+          >
+            SQL language syntax
+          </LinkButton>
+          <InlineFormLabel width={6} className={styles.formatLabel}>
+            Format as
+          </InlineFormLabel>
+          <Select
+            aria-label="Format as"
+            width={16}
+            options={formats}
+            value={query.format ?? 'table'}
+            onChange={this.onFormatChange}
+          />
+          <div className="gf-form gf-form--grow">
+            <div className="gf-form-label gf-form-label--grow"></div>
+          </div>
+          <InlineFormLabel width={5} tooltip={helpTooltip}>
+            Help
+          </InlineFormLabel>
+        </div>
+      </>
+    );
+  }
+}
+
+const getStyles = (theme: GrafanaTheme2) => ({
+  editorContainerStyles: css`
+    height: 200px;
+    max-width: 100%;
+    resize: vertical;
+    overflow: auto;
+    background-color: ${theme.isDark ? theme.colors.background.canvas : theme.colors.background.primary};
+    padding-bottom: ${theme.spacing(1)};
+  `,
+  editorActions: css`
+    margin-top: 6px;
+  `,
+  formatLabel: css`
+    margin-left: ${theme.spacing(0.5)};
+  `,
+});
+
+export const FSQLEditor = withTheme2(UnthemedFSQLEditor);
@@ -30,9 +30,11 @@ import {
 } from '@grafana/runtime';
 import config from 'app/core/config';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
+import { VariableWithMultiSupport } from 'app/features/variables/types';
 
 import { AnnotationEditor } from './components/editor/annotation/AnnotationEditor';
 import { FluxQueryEditor } from './components/editor/query/flux/FluxQueryEditor';
+import { FSQLEditor } from './components/editor/query/fsql/FSQLEditor';
 import { BROWSER_MODE_DISABLED_MESSAGE } from './constants';
 import InfluxQueryModel from './influx_query_model';
 import InfluxSeries from './influx_series';
@@ -56,6 +58,7 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
   responseParser: ResponseParser;
   httpMode: string;
   isFlux: boolean;
+  isSql: boolean;
   isProxyAccess: boolean;
   retentionPolicies: string[];
 
@@ -82,6 +85,7 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
     this.httpMode = settingsData.httpMode || 'GET';
     this.responseParser = new ResponseParser();
     this.isFlux = settingsData.version === InfluxVersion.Flux;
+    this.isSql = settingsData.version === InfluxVersion.SQL;
     this.isProxyAccess = instanceSettings.access === 'proxy';
     this.retentionPolicies = [];
 
@@ -90,6 +94,10 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
       this.annotations = {
         QueryEditor: FluxQueryEditor,
       };
+    } else if (this.isSql) {
+      this.annotations = {
+        QueryEditor: FSQLEditor,
+      };
     } else {
       this.annotations = {
         QueryEditor: AnnotationEditor,
@@ -100,7 +108,7 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
 
   async getRetentionPolicies(): Promise<string[]> {
     // Only For InfluxQL Mode
-    if (this.isFlux || this.retentionPolicies.length) {
+    if (this.isFlux || this.isSql || this.retentionPolicies.length) {
       return Promise.resolve(this.retentionPolicies);
     } else {
       return getAllPolicies(this).catch((err) => {
@@ -171,7 +179,7 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
       return merge(...streams);
     }
 
-    if (this.isFlux) {
+    if (this.isFlux || this.isSql) {
       return super.query(filteredRequest);
     }
 
@@ -224,6 +232,9 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
     if (this.isFlux) {
       return query.query;
     }
+    if (this.isSql) {
+      return query.rawSql;
+    }
     return new InfluxQueryModel(query).render(false);
   }
 
@@ -234,6 +245,9 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
     if (this.isFlux) {
       return !!query.query;
     }
+    if (this.isSql) {
+      return !!query.rawSql;
+    }
     return true;
   }
 
@@ -248,6 +262,13 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
       };
     }
 
+    if (this.isSql) {
+      return {
+        ...query,
+        rawSql: this.templateSrv.replace(query.rawSql ?? '', rest, this.interpolateSQLVariable),
+      };
+    }
+
     if (this.isMigrationToggleOnAndIsAccessProxy()) {
       query = this.applyVariables(query, scopedVars, rest);
     }
@@ -255,10 +276,22 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
     return query;
   }
 
+  // Quotes the values of multi-value variables for SQL queries, so that they can be used in IN clauses
+  interpolateSQLVariable = (value: string | string[] | number, variable: VariableWithMultiSupport) => {
+    const quoteLiteral = (v: string) => "'" + String(v).replace(/'/g, "''") + "'";
+    if (typeof value === 'string') {
+      return variable.multi || variable.includeAll ? quoteLiteral(value) : value.replace(/'/g, "''");
+    }
+    if (Array.isArray(value)) {
+      return value.map(quoteLiteral).join(',');
+    }
+    return value;
+  };
+
   targetContainsTemplate(target: InfluxQuery) {
-    // for flux-mode we just take target.query,
+    // for flux-mode we just take target.query, for sql-mode target.rawSql,
     // for influxql-mode we use InfluxQueryModel to create the text-representation
-    const queryText = this.isFlux ? target.query : buildRawQuery(target);
+    const queryText = this.isFlux ? target.query : this.isSql ? target.rawSql : buildRawQuery(target);
 
     return this.templateSrv.containsTemplate(queryText);
   }
@@ -277,6 +310,14 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
         };
       }
 
+      if (this.isSql) {
+        return {
+          ...query,
+          datasource: this.getRef(),
+          rawSql: this.templateSrv.replace(query.rawSql ?? '', scopedVars, this.interpolateSQLVariable),
+        };
+      }
+
       return {
         ...query,
         datasource: this.getRef(),
@@ -347,12 +388,14 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
   }
 
   async metricFindQuery(query: string, options?: any): Promise<MetricFindValue[]> {
-    if (this.isFlux || this.isMigrationToggleOnAndIsAccessProxy()) {
-      const target: InfluxQuery = {
-        refId: 'metricFindQuery',
-        query,
-        rawQuery: true,
-      };
+    if (this.isFlux || this.isSql || this.isMigrationToggleOnAndIsAccessProxy()) {
+      const target: InfluxQuery = this.isSql
+        ? { refId: 'metricFindQuery', rawSql: query, format: 'table' }
+        : {
+            refId: 'metricFindQuery',
+            query,
+            rawQuery: true,
+          };
       return lastValueFrom(
         super.query({
           ...options, // includes 'range'
@@ -696,9 +739,9 @@ export default class InfluxDatasource extends DataSourceWithBackend<InfluxQuery,
   }
 
   async annotationEvents(options: DataQueryRequest, annotation: InfluxQuery): Promise<AnnotationEvent[]> {
-    if (this.isFlux) {
+    if (this.isFlux || this.isSql) {
       return Promise.reject({
-        message: 'Flux requires the standard annotation query',
+        message: 'Flux and SQL require the standard annotation query',
       });
     }
 
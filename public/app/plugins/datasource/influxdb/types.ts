@@ -3,6 +3,7 @@ import { AdHocVariableFilter, DataQuery, DataSourceJsonData } from '@grafana/dat
 export enum InfluxVersion {
   InfluxQL = 'InfluxQL',
   Flux = 'Flux',
+  SQL = 'SQL',
 }
 
 export interface InfluxOptions extends DataSourceJsonData {
@@ -17,6 +18,10 @@ export interface InfluxOptions extends DataSourceJsonData {
   organization?: string;
   defaultBucket?: string;
   maxSeries?: number;
+
+  // With SQL
+  metadata?: Array<Record<string, string>>;
+  insecureGrpc?: boolean;
 }
 
 /**
@@ -28,7 +33,7 @@ export interface InfluxOptionsV1 extends InfluxOptions {
 }
 
 export interface InfluxSecureJsonData {
-  // For Flux
+  // For Flux and SQL
   token?: string;
 
   // In 1x a different password can be sent than then HTTP auth
@@ -53,6 +58,8 @@ export interface InfluxQueryTag {
 
 export type ResultFormat = 'time_series' | 'table' | 'logs';
 
+export type SQLFormat = 'time_series' | 'table';
+
 export interface InfluxQuery extends DataQuery {
   policy?: string;
   measurement?: string;
@@ -71,6 +78,9 @@ export interface InfluxQuery extends DataQuery {
   rawQuery?: boolean;
   query?: string;
   alias?: string;
+  // for SQL
+  rawSql?: string;
+  format?: SQLFormat;
   // for migrated InfluxQL annotations
   queryType?: string;
   fromAnnotations?: boolean;
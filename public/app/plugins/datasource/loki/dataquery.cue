@@ -45,7 +45,7 @@ composableKinds: DataQuery: {
 
 				#QueryEditorMode: "code" | "builder" @cuetsy(kind="enum")
 
-				#LokiQueryType: "range" | "instant" | "stream" @cuetsy(kind="enum")
+				#LokiQueryType: "range" | "instant" | "stream" | "volume" | "patterns" @cuetsy(kind="enum")
 
 				#SupportingQueryType: "logsVolume" | "logsSample" | "dataSample" @cuetsy(kind="enum")
 
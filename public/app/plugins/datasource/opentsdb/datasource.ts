@@ -17,7 +17,6 @@ import { lastValueFrom, merge, Observable, of } from 'rxjs';
 import { catchError, map } from 'rxjs/operators';
 
 import {
-  AnnotationEvent,
   DataQueryRequest,
   DataQueryResponse,
   DataSourceApi,
@@ -25,7 +24,7 @@ import {
   ScopedVars,
   toDataFrame,
 } from '@grafana/data';
-import { FetchResponse, getBackendSrv } from '@grafana/runtime';
+import { BackendDataSourceResponse, FetchResponse, getBackendSrv, toDataQueryResponse } from '@grafana/runtime';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 
 import { AnnotationEditor } from './components/AnnotationEditor';
@@ -79,17 +78,12 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
       for (const annotation of options.targets) {
         if (annotation.target) {
           streams.push(
-            new Observable((subscriber) => {
-              this.annotationEvent(options, annotation)
-                .then((events) => subscriber.next({ data: [toDataFrame(events)] }))
-                .catch((ex) => {
-                  // grafana fetch throws the error so for annotation consistency among datasources
-                  // we return an empty array which displays as 'no events found'
-                  // in the annnotation editor
-                  return subscriber.next({ data: [toDataFrame([])] });
-                })
-                .finally(() => subscriber.complete());
-            })
+            this.annotationEvent(options, annotation).pipe(
+              // grafana fetch throws the error so for annotation consistency among datasources
+              // we return an empty array which displays as 'no events found'
+              // in the annnotation editor
+              catchError(() => of({ data: [toDataFrame([])] }))
+            )
           );
         }
       }
@@ -159,40 +153,29 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     );
   }
 
-  annotationEvent(options: DataQueryRequest, annotation: OpenTsdbQuery): Promise<AnnotationEvent[]> {
-    const start = this.convertToTSDBTime(options.range.raw.from, false, options.timezone);
-    const end = this.convertToTSDBTime(options.range.raw.to, true, options.timezone);
-    const qs = [];
-    const eventList: any[] = [];
-
-    qs.push({ aggregator: 'sum', metric: annotation.target });
-
-    const queries = compact(qs);
+  // Annotations are queried through the backend, which returns a frame with the time, end time and text of the
+  // annotations of the metric or the global annotations.
+  annotationEvent(options: DataQueryRequest, annotation: OpenTsdbQuery): Observable<DataQueryResponse> {
+    const target: OpenTsdbQuery = {
+      refId: annotation.refId,
+      datasource: this.getRef(),
+      fromAnnotations: true,
+      target: this.templateSrv.replace(annotation.target ?? '', options.scopedVars),
+      isGlobal: annotation.isGlobal ?? false,
+    };
 
-    return lastValueFrom(
-      this.performTimeSeriesQuery(queries, start, end).pipe(
-        map((results) => {
-          if (results.data[0]) {
-            let annotationObject = results.data[0].annotations;
-            if (annotation.isGlobal) {
-              annotationObject = results.data[0].globalAnnotations;
-            }
-            if (annotationObject) {
-              each(annotationObject, (ann) => {
-                const event = {
-                  text: ann.description,
-                  time: Math.floor(ann.startTime) * 1000,
-                  annotation: annotation,
-                };
-
-                eventList.push(event);
-              });
-            }
-          }
-          return eventList;
-        })
-      )
-    );
+    return getBackendSrv()
+      .fetch<BackendDataSourceResponse>({
+        url: '/api/ds/query',
+        method: 'POST',
+        data: {
+          from: options.range.from.valueOf().toString(),
+          to: options.range.to.valueOf().toString(),
+          queries: [target],
+        },
+        requestId: annotation.name,
+      })
+      .pipe(map((res) => toDataQueryResponse(res)));
   }
 
   targetContainsTemplate(target: any) {
@@ -323,15 +306,12 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     relativeUrl: string,
     params?: { type?: string; q?: string; max?: number; m?: any; limit?: number }
   ): Observable<FetchResponse> {
-    const options = {
+    // The lookup and suggest endpoints are proxied by the backend, which adds the credentials of the data source
+    return getBackendSrv().fetch({
       method: 'GET',
-      url: this.url + relativeUrl,
+      url: `/api/datasources/uid/${this.uid}/resources${relativeUrl}`,
       params: params,
-    };
-
-    this._addCredentialOptions(options);
-
-    return getBackendSrv().fetch(options);
+    });
   }
 
   _addCredentialOptions(options: any) {
@@ -102,6 +102,7 @@ export function QueryEditor(props: Props) {
           onQueryTypeChange={(val) => {
             props.onChange({ ...query, queryType: val });
           }}
+          onQueryChange={props.onChange}
           app={props.app}
         />
       </EditorRow>
@@ -3,7 +3,7 @@ import React from 'react';
 import { useToggle } from 'react-use';
 
 import { CoreApp, GrafanaTheme2 } from '@grafana/data';
-import { Icon, useStyles2, RadioButtonGroup, Field, clearButtonStyles, Button } from '@grafana/ui';
+import { Icon, useStyles2, RadioButtonGroup, Field, clearButtonStyles, Button, Input } from '@grafana/ui';
 
 import { Query } from '../types';
 
@@ -12,6 +12,7 @@ import { Stack } from './Stack';
 export interface Props {
   query: Query;
   onQueryTypeChange: (val: Query['queryType']) => void;
+  onQueryChange: (query: Query) => void;
   app?: CoreApp;
 }
 
@@ -19,6 +20,7 @@ const rangeOptions: Array<{ value: Query['queryType']; label: string; descriptio
   { value: 'metrics', label: 'Metric', description: 'Return aggregated metrics' },
   { value: 'profile', label: 'Profile', description: 'Return profile' },
   { value: 'both', label: 'Both', description: 'Return both metric and profile data' },
+  { value: 'diff', label: 'Diff', description: 'Compare the profile to a baseline profile' },
 ];
 
 function getOptions(app?: CoreApp) {
@@ -31,7 +33,7 @@ function getOptions(app?: CoreApp) {
 /**
  * Base on QueryOptionGroup component from grafana/ui but that is not available yet.
  */
-export function QueryOptions({ query, onQueryTypeChange, app }: Props) {
+export function QueryOptions({ query, onQueryTypeChange, onQueryChange, app }: Props) {
   const [isOpen, toggleOpen] = useToggle(false);
   const styles = useStyles2(getStyles);
   const options = getOptions(app);
@@ -55,6 +57,34 @@ export function QueryOptions({ query, onQueryTypeChange, app }: Props) {
           <Field label={'Query Type'}>
             <RadioButtonGroup options={options} value={query.queryType} onChange={onQueryTypeChange} />
           </Field>
+          {query.queryType === 'diff' && (
+            <>
+              <Field
+                label={'Baseline label selector'}
+                description={'Label selector of the baseline profile. Defaults to the label selector of the query.'}
+              >
+                <Input
+                  value={query.baselineLabelSelector || ''}
+                  placeholder={query.labelSelector}
+                  onChange={(event: React.SyntheticEvent<HTMLInputElement>) => {
+                    onQueryChange({ ...query, baselineLabelSelector: event.currentTarget.value });
+                  }}
+                />
+              </Field>
+              <Field
+                label={'Baseline time shift'}
+                description={'Shifts the time range of the baseline profile back, for example 1h or 1d.'}
+              >
+                <Input
+                  value={query.baselineTimeShift || ''}
+                  placeholder="1d"
+                  onChange={(event: React.SyntheticEvent<HTMLInputElement>) => {
+                    onQueryChange({ ...query, baselineTimeShift: event.currentTarget.value });
+                  }}
+                />
+              </Field>
+            </>
+          )}
         </div>
       )}
     </Stack>
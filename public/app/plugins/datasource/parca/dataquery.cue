@@ -32,8 +32,12 @@ composableKinds: DataQuery: {
 				// Specifies the query label selectors.
 				labelSelector: string | *"{}"
 				// Specifies the type of profile to query.
-				profileTypeId:   string
-				#ParcaQueryType: "metrics" | "profile" | *"both" @cuetsy(kind="type")
+				profileTypeId: string
+				// Specifies the label selectors of the baseline profile of a diff query. Defaults to the label selectors of the query.
+				baselineLabelSelector?: string
+				// Shifts the time range of the baseline profile of a diff query back by the given duration, e.g. 1d.
+				baselineTimeShift?: string
+				#ParcaQueryType:    "metrics" | "profile" | *"both" | "diff" @cuetsy(kind="type")
 			}
 		}]
 		lenses: []
@@ -461,6 +461,60 @@ export const PromSettings = (props: Props) => {
               </InlineField>
             </div>
           </div>
+
+          <div className="gf-form-inline">
+            <div className="gf-form">
+              <InlineField
+                label="Max query points"
+                labelWidth={PROM_CONFIG_LABEL_WIDTH}
+                tooltip={
+                  <>
+                    Maximum number of points (series multiplied by samples) of a range query. When a query returns more
+                    points, the step is widened and the query is executed again. Leave empty for no limit.
+                  </>
+                }
+                interactive={true}
+                disabled={options.readOnly}
+              >
+                <Input
+                  className="width-20"
+                  type="number"
+                  min={0}
+                  value={options.jsonData.maxQueryPoints}
+                  onChange={onChangeHandler('maxQueryPoints', options, onOptionsChange)}
+                  spellCheck={false}
+                  placeholder="No limit"
+                />
+              </InlineField>
+            </div>
+          </div>
+
+          <div className="gf-form-inline">
+            <div className="gf-form">
+              <InlineField
+                label="Max response size"
+                labelWidth={PROM_CONFIG_LABEL_WIDTH}
+                tooltip={
+                  <>
+                    Maximum size in bytes of a query response. Queries with larger responses fail. Leave empty for no
+                    limit.
+                  </>
+                }
+                interactive={true}
+                disabled={options.readOnly}
+              >
+                <Input
+                  className="width-20"
+                  type="number"
+                  min={0}
+                  value={options.jsonData.maxResponseBytes}
+                  onChange={onChangeHandler('maxResponseBytes', options, onOptionsChange)}
+                  spellCheck={false}
+                  placeholder="No limit"
+                />
+              </InlineField>
+            </div>
+          </div>
         </div>
       </ConfigSubSection>
 
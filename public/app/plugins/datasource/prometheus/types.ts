@@ -46,6 +46,8 @@ export interface PromOptions extends DataSourceJsonData {
   incrementalQueryOverlapWindow?: string;
   disableRecordingRules?: boolean;
   sigV4Auth?: boolean;
+  maxQueryPoints?: number;
+  maxResponseBytes?: number;
 }
 
 export type ExemplarTraceIdDestination = {
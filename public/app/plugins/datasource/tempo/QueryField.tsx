@@ -1,7 +1,7 @@
 import { css } from '@emotion/css';
 import React from 'react';
 
-import { QueryEditorProps, SelectableValue } from '@grafana/data';
+import { CoreApp, QueryEditorProps, SelectableValue } from '@grafana/data';
 import { config, reportInteraction } from '@grafana/runtime';
 import {
   Button,
@@ -9,6 +9,7 @@ import {
   HorizontalGroup,
   InlineField,
   InlineFieldRow,
+  Input,
   Modal,
   RadioButtonGroup,
   Themeable2,
@@ -32,6 +33,8 @@ interface State {
 }
 
 const DEFAULT_QUERY_TYPE: TempoQueryType = 'traceqlSearch';
+// Only the metrics queries return time series that alert rules can be evaluated on
+const ALERTING_QUERY_TYPE: TempoQueryType = 'traceqlMetrics';
 
 class TempoQueryFieldComponent extends React.PureComponent<Props, State> {
   constructor(props: Props) {
@@ -46,6 +49,16 @@ class TempoQueryFieldComponent extends React.PureComponent<Props, State> {
   // otherwise if the user changes the query type and refreshes the page, no query type will be selected
   // which is inconsistent with how the UI was originally when they selected the Tempo data source.
   async componentDidMount() {
+    if (this.props.app === CoreApp.UnifiedAlerting) {
+      if (this.props.query.queryType !== ALERTING_QUERY_TYPE) {
+        this.props.onChange({
+          ...this.props.query,
+          queryType: ALERTING_QUERY_TYPE,
+        });
+      }
+      return;
+    }
+
     if (!this.props.query.queryType || this.props.query.queryType === 'clear') {
       this.props.onChange({
         ...this.props.query,
@@ -86,7 +99,9 @@ class TempoQueryFieldComponent extends React.PureComponent<Props, State> {
     let queryTypeOptions: Array<SelectableValue<TempoQueryType>> = [
       { value: 'traceqlSearch', label: 'Search' },
       { value: 'traceql', label: 'TraceQL' },
+      { value: 'traceqlMetrics', label: 'TraceQL Metrics' },
       { value: 'serviceMap', label: 'Service Graph' },
+      { value: 'serviceMapFromTraces', label: 'Service Graph (from traces)' },
     ];
 
     if (logsDatasourceUid) {
@@ -111,6 +126,10 @@ class TempoQueryFieldComponent extends React.PureComponent<Props, State> {
       queryTypeOptions.unshift({ value: 'nativeSearch', label: '[Deprecated] Search' });
     }
 
+    if (app === CoreApp.UnifiedAlerting) {
+      queryTypeOptions = queryTypeOptions.filter((option) => option.value === ALERTING_QUERY_TYPE);
+    }
+
     return (
       <>
         <Modal
@@ -205,6 +224,55 @@ class TempoQueryFieldComponent extends React.PureComponent<Props, State> {
             onChange={onChange}
           />
         )}
+        {query.queryType === 'serviceMapFromTraces' && (
+          <>
+            <QueryEditor
+              datasource={this.props.datasource}
+              query={query}
+              onRunQuery={this.props.onRunQuery}
+              onChange={onChange}
+            />
+            <InlineFieldRow>
+              <InlineField
+                label="Traces"
+                tooltip="Maximum number of traces matching the query used to compute the service graph. Defaults to 100, up to 500."
+              >
+                <Input
+                  width={20}
+                  type="number"
+                  placeholder="100"
+                  value={query.limit || ''}
+                  onChange={(e) => onChange({ ...query, limit: parseInt(e.currentTarget.value, 10) || undefined })}
+                  onBlur={this.props.onRunQuery}
+                />
+              </InlineField>
+            </InlineFieldRow>
+          </>
+        )}
+        {query.queryType === 'traceqlMetrics' && (
+          <>
+            <QueryEditor
+              datasource={this.props.datasource}
+              query={query}
+              onRunQuery={this.props.onRunQuery}
+              onChange={onChange}
+            />
+            <InlineFieldRow>
+              <InlineField
+                label="Step"
+                tooltip="The step of the returned time series, for example 30s or 1m. Defaults to the interval of the panel."
+              >
+                <Input
+                  width={20}
+                  placeholder="auto"
+                  value={query.step || ''}
+                  onChange={(e) => onChange({ ...query, step: e.currentTarget.value })}
+                  onBlur={this.props.onRunQuery}
+                />
+              </InlineField>
+            </InlineFieldRow>
+          </>
+        )}
       </>
     );
   }
@@ -45,10 +45,12 @@ composableKinds: DataQuery: {
 					// Defines the maximum number of traces that are returned from Tempo
 					limit?: int64
 					filters: [...#TraceqlFilter]
+					// For TraceQL metrics queries, the step of the returned time series. Use duration format, for example: 30s, 1m
+					step?: string
 				} @cuetsy(kind="interface") @grafana(TSVeneer="type")
 
 				// search = Loki search, nativeSearch = Tempo search for backwards compatibility
-				#TempoQueryType: "traceql" | "traceqlSearch" | "search" | "serviceMap" | "upload" | "nativeSearch" | "traceId" | "clear" @cuetsy(kind="type")
+				#TempoQueryType: "traceql" | "traceqlSearch" | "traceqlMetrics" | "search" | "serviceMap" | "serviceMapFromTraces" | "upload" | "nativeSearch" | "traceId" | "clear" @cuetsy(kind="type")
 
 				// The state of the TraceQL streaming search query
 				#SearchStreamingState: "pending" | "streaming" | "done" | "error" @cuetsy(kind="enum")
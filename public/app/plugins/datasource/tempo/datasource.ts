@@ -252,6 +252,27 @@ export class TempoDatasource extends DataSourceWithBackend<TempoQuery, TempoJson
         return of({ error: { message: error instanceof Error ? error.message : 'Unknown error occurred' }, data: [] });
       }
     }
+    if (targets.traceqlMetrics?.length) {
+      reportInteraction('grafana_traces_traceql_metrics_queried', {
+        datasourceType: 'tempo',
+        app: options.app ?? '',
+        grafana_version: config.buildInfo.version,
+      });
+
+      subQueries.push(this.handleTraceQLMetricsQuery(options, targets.traceqlMetrics));
+    }
+    if (targets.serviceMapFromTraces?.length) {
+      reportInteraction('grafana_traces_service_graph_from_traces_queried', {
+        datasourceType: 'tempo',
+        app: options.app ?? '',
+        grafana_version: config.buildInfo.version,
+      });
+
+      const validTargets = targets.serviceMapFromTraces.map(
+        (t): TempoQuery => ({ ...this.applyVariables(t, options.scopedVars), queryType: 'serviceMapFromTraces' })
+      );
+      subQueries.push(super.query({ ...options, targets: validTargets }));
+    }
     if (targets.traceqlSearch?.length) {
       try {
         const queryValue = generateQueryFromFilters(targets.traceqlSearch[0].filters);
@@ -403,6 +424,23 @@ export class TempoDatasource extends DataSourceWithBackend<TempoQuery, TempoJson
     );
   }
 
+  /**
+   * Runs TraceQL metrics queries in the backend, which returns them as time series.
+   * @param options
+   * @param targets
+   * @private
+   */
+  handleTraceQLMetricsQuery(options: DataQueryRequest<TempoQuery>, targets: TempoQuery[]): Observable<DataQueryResponse> {
+    const validTargets = targets
+      .filter((t) => t.query)
+      .map((t): TempoQuery => ({ ...this.applyVariables(t, options.scopedVars), queryType: 'traceqlMetrics' }));
+    if (!validTargets.length) {
+      return EMPTY;
+    }
+
+    return super.query({ ...options, targets: validTargets });
+  }
+
   traceIdQueryRequest(options: DataQueryRequest<TempoQuery>, targets: TempoQuery[]): DataQueryRequest<TempoQuery> {
     const request = {
       ...options,
@@ -11,6 +11,7 @@ import { CSVFileEditor } from './components/CSVFileEditor';
 import { CSVWavesEditor } from './components/CSVWaveEditor';
 import ErrorEditor from './components/ErrorEditor';
 import { GrafanaLiveEditor } from './components/GrafanaLiveEditor';
+import { LoadEditor } from './components/LoadEditor';
 import { NodeGraphEditor } from './components/NodeGraphEditor';
 import { PredictablePulseEditor } from './components/PredictablePulseEditor';
 import { RawFrameEditor } from './components/RawFrameEditor';
@@ -151,12 +152,19 @@ export const QueryEditor = ({ query, datasource, onChange, onRunQuery }: Props)
     onUpdate({ ...query, [field]: { ...(query as any)[field], [name]: newValue } });
   };
 
+  // An empty seed means new random values for every request
+  const onSeedChange = (e: FormEvent<HTMLInputElement>) => {
+    const { value } = e.currentTarget;
+    onUpdate({ ...query, seed: value === '' ? undefined : Number(value) });
+  };
+
   const onEndPointChange = ({ value }: SelectableValue) => {
     onUpdate({ ...query, stringInput: value });
   };
 
   const onStreamClientChange = onFieldChange('stream');
   const onPulseWaveChange = onFieldChange('pulseWave');
+  const onLoadChange = onFieldChange('load');
   const onUSAStatsChange = (usa?: USAQuery) => {
     onUpdate({ ...query, usa });
   };
@@ -177,8 +185,21 @@ export const QueryEditor = ({ query, datasource, onChange, onRunQuery }: Props)
   const show = useMemo(() => {
     const scenarioId = query.scenarioId ?? '';
     return {
-      labels: ['random_walk', 'predictable_pulse'].includes(scenarioId),
+      labels: ['random_walk', 'predictable_pulse', 'load'].includes(scenarioId),
       dropPercent: ['csv_content', 'csv_file'].includes(scenarioId),
+      seed: [
+        'random_walk',
+        'random_walk_with_error',
+        'random_walk_table',
+        'slow_query',
+        'exponential_heatmap_bucket_data',
+        'linear_heatmap_bucket_data',
+        'logs',
+        'csv_content',
+        'csv_file',
+        'load',
+        'live',
+      ].includes(scenarioId),
     };
   }, [query?.scenarioId]);
 
@@ -240,6 +261,18 @@ export const QueryEditor = ({ query, datasource, onChange, onRunQuery }: Props)
             />
           </InlineField>
         )}
+        {show.seed && (
+          <InlineField label="Seed" tooltip={'Return the same random values for every request with the same seed'}>
+            <Input
+              type="number"
+              width={12}
+              onChange={onSeedChange}
+              name="seed"
+              placeholder="random"
+              value={query.seed}
+            />
+          </InlineField>
+        )}
         {show.labels && (
           <InlineField
             label="Labels"
@@ -347,6 +380,7 @@ export const QueryEditor = ({ query, datasource, onChange, onRunQuery }: Props)
       {scenarioId === TestDataQueryType.PredictablePulse && (
         <PredictablePulseEditor onChange={onPulseWaveChange} query={query} ds={datasource} />
       )}
+      {scenarioId === TestDataQueryType.Load && <LoadEditor onChange={onLoadChange} query={query} ds={datasource} />}
       {scenarioId === TestDataQueryType.PredictableCSVWave && (
         <CSVWavesEditor onChange={onCSVWaveChange} waves={query.csvWave} />
       )}
@@ -0,0 +1,64 @@
+import React, { ChangeEvent } from 'react';
+
+import { InlineField, InlineFieldRow, Input } from '@grafana/ui';
+
+import { EditorProps } from '../QueryEditor';
+import { LoadQuery } from '../dataquery.gen';
+
+const fields: Array<{
+  label: string;
+  id: keyof LoadQuery;
+  placeholder: string;
+  tooltip: string;
+}> = [
+  { label: 'Series', id: 'seriesCount', placeholder: '1', tooltip: 'The number of series to return.' },
+  { label: 'Fields', id: 'fieldCount', placeholder: '1', tooltip: 'The number of value fields of each series.' },
+  {
+    label: 'Points',
+    id: 'pointCount',
+    placeholder: 'max data points',
+    tooltip: 'The number of points of each series, spread evenly over the time range.',
+  },
+  {
+    label: 'Labels',
+    id: 'labelCount',
+    placeholder: '0',
+    tooltip: 'The number of labels of each series. The first label is unique to each series, the next ones group them.',
+  },
+  {
+    label: 'Latency',
+    id: 'latencyMs',
+    placeholder: '0',
+    tooltip: 'The number of milliseconds to wait before returning the data.',
+  },
+  { label: 'Error rate', id: 'errorRate', placeholder: '0', tooltip: 'The chance, from 0 to 100, that the query fails.' },
+];
+
+export const LoadEditor = ({ onChange, query }: EditorProps) => {
+  // Convert values to numbers before saving
+  const onInputChange = (e: ChangeEvent<HTMLInputElement>) => {
+    const { name, value } = e.target;
+
+    onChange({ target: { name, value: Number(value) } });
+  };
+
+  return (
+    <InlineFieldRow>
+      {fields.map(({ label, id, placeholder, tooltip }) => {
+        return (
+          <InlineField label={label} labelWidth={14} key={id} tooltip={tooltip}>
+            <Input
+              width={32}
+              type="number"
+              name={id}
+              id={`load.${id}-${query.refId}`}
+              value={query.load?.[id]}
+              placeholder={placeholder}
+              onChange={onInputChange}
+            />
+          </InlineField>
+        );
+      })}
+    </InlineFieldRow>
+  );
+};
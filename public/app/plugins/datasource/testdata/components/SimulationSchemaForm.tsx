@@ -59,22 +59,34 @@ export const SimulationSchemaForm = ({ config, schema, onChange }: SchemaFormPro
 
   const styles = useStyles2(getStyles);
 
+  // Simulations without config fields, like the expression simulation, are only configured with JSON
+  const onlyJson = schema.fields.length === 0;
+
+  // The text area is reset when the saved config changes
+  const configText = JSON.stringify(config, null, 2);
+
   const onUpdateTextArea = (event: FormEvent<HTMLTextAreaElement>) => {
     const element = event.currentTarget;
-    onChange(JSON.parse(element.value));
+    try {
+      onChange(JSON.parse(element.value));
+    } catch {
+      // wait for valid JSON
+    }
   };
 
   return (
     <FieldSet label="Config">
-      <InlineSwitch
-        className={styles.jsonView}
-        label="JSON View"
-        showLabel
-        value={jsonView}
-        onChange={() => setJsonView(!jsonView)}
-      />
-      {jsonView ? (
-        <TextArea defaultValue={JSON.stringify(config, null, 2)} rows={7} onChange={onUpdateTextArea} />
+      {!onlyJson && (
+        <InlineSwitch
+          className={styles.jsonView}
+          label="JSON View"
+          showLabel
+          value={jsonView}
+          onChange={() => setJsonView(!jsonView)}
+        />
+      )}
+      {jsonView || onlyJson ? (
+        <TextArea key={configText} defaultValue={configText} rows={onlyJson ? 12 : 7} onBlur={onUpdateTextArea} />
       ) : (
         <>
           {schema.fields.map((field) => (
@@ -22,6 +22,7 @@ export enum TestDataQueryType {
   GrafanaAPI = 'grafana_api',
   LinearHeatmapBucketData = 'linear_heatmap_bucket_data',
   Live = 'live',
+  Load = 'load',
   Logs = 'logs',
   ManualEntry = 'manual_entry',
   NoDataPoints = 'no_data_points',
@@ -94,6 +95,18 @@ export interface CSVWave {
   valuesCSV?: string;
 }
 
+export interface LoadQuery {
+  /**
+   * Error rate percentage (the chance the query fails 0-100)
+   */
+  errorRate?: number;
+  fieldCount?: number;
+  labelCount?: number;
+  latencyMs?: number;
+  pointCount?: number;
+  seriesCount?: number;
+}
+
 /**
  * TODO: Should this live here given it's not used in the dataquery?
  */
@@ -119,11 +132,16 @@ export interface TestData extends common.DataQuery {
   labels?: string;
   levelColumn?: boolean;
   lines?: number;
+  load?: LoadQuery;
   nodes?: NodesQuery;
   points?: Array<Array<(string | number)>>;
   pulseWave?: PulseWaveQuery;
   rawFrameContent?: string;
   scenarioId?: TestDataQueryType;
+  /**
+   * Seed of the random scenarios, the same seed returns the same values
+   */
+  seed?: number;
   seriesCount?: number;
   sim?: SimulationQuery;
   spanCount?: number;
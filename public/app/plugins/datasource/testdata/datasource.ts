@@ -346,6 +346,7 @@ function runGrafanaLiveQuery(target: TestData, req: DataQueryRequest<TestData>):
       scope: LiveChannelScope.Plugin,
       namespace: 'testdata',
       path: target.channel,
+      data: target.seed !== undefined ? { seed: target.seed } : undefined,
     },
     key: `testStream.${liveQueryCounter++}`,
   });
@@ -32,6 +32,14 @@ const FlameGraphTooltip = ({ data, item, totalTicks, position }: Props) => {
         Self: <b>{tooltipData.unitSelf}</b> ({tooltipData.percentSelf}%)
         <br />
         Samples: <b>{tooltipData.samples}</b>
+        {tooltipData.baselineValue !== undefined && (
+          <>
+            <br />
+            Baseline: <b>{tooltipData.baselineValue}</b>
+            <br />
+            Comparison: <b>{tooltipData.comparisonValue}</b>
+          </>
+        )}
       </p>
     </div>
   );
@@ -53,6 +61,9 @@ type TooltipData = {
   unitValue: string;
   unitSelf: string;
   samples: string;
+  // Only set for diff flame graphs
+  baselineValue?: string;
+  comparisonValue?: string;
 };
 
 export const getTooltipData = (data: FlameGraphDataContainer, item: LevelItem, totalTicks: number): TooltipData => {
@@ -76,7 +87,7 @@ export const getTooltipData = (data: FlameGraphDataContainer, item: LevelItem, t
     }
   }
 
-  return {
+  const tooltipData: TooltipData = {
     name: data.getLabel(item.itemIndexes[0]),
     percentValue,
     percentSelf,
@@ -85,6 +96,16 @@ export const getTooltipData = (data: FlameGraphDataContainer, item: LevelItem, t
     unitSelf,
     samples: displayValue.numeric.toLocaleString(),
   };
+
+  if (data.isDiffFlamegraph()) {
+    const valueRight = data.getValueRight(item.itemIndexes);
+    const displayBaseline = data.valueDisplayProcessor(item.value - valueRight);
+    const displayComparison = data.valueDisplayProcessor(valueRight);
+    tooltipData.baselineValue = displayBaseline.text + (displayBaseline.suffix ?? '');
+    tooltipData.comparisonValue = displayComparison.text + (displayComparison.suffix ?? '');
+  }
+
+  return tooltipData;
 };
 
 const getStyles = (theme: GrafanaTheme2) => ({
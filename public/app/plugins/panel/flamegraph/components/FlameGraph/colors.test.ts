@@ -1,6 +1,6 @@
 import { createTheme } from '@grafana/data';
 
-import { getBarColorByPackage, getBarColorByValue } from './colors';
+import { getBarColorByDiff, getBarColorByPackage, getBarColorByValue } from './colors';
 
 describe('getBarColorByValue', () => {
   it('converts value to color', () => {
@@ -23,3 +23,16 @@ describe('getBarColorByPackage', () => {
     );
   });
 });
+
+describe('getBarColorByDiff', () => {
+  it('converts the change between the profiles to color', () => {
+    // same share of both profiles
+    expect(getBarColorByDiff(20, 10, 100, 100).toHslString()).toBe('hsl(0, 0%, 80%)');
+    // share doubled
+    expect(getBarColorByDiff(30, 20, 100, 100).toHslString()).toBe('hsl(0, 80%, 60%)');
+    // share halved
+    expect(getBarColorByDiff(30, 10, 100, 100).toHslString()).toBe('hsl(120, 40%, 70%)');
+    // only in the comparison profile
+    expect(getBarColorByDiff(10, 10, 100, 100).toHslString()).toBe('hsl(0, 80%, 60%)');
+  });
+});
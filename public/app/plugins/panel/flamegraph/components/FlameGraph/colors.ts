@@ -49,6 +49,30 @@ export function getBarColorByValue(value: number, totalTicks: number, rangeMin:
   return color({ h, s: 100, l });
 }
 
+// Colors a bar of a diff flame graph by the change of its share of the profile between the baseline (left) and the
+// comparison (right) profile. Bars that grew are red, bars that shrank are green and unchanged bars are gray.
+export function getBarColorByDiff(ticks: number, ticksRight: number, totalTicksLeft: number, totalTicksRight: number) {
+  const ticksLeft = ticks - ticksRight;
+  const shareLeft = totalTicksLeft > 0 ? ticksLeft / totalTicksLeft : 0;
+  const shareRight = totalTicksRight > 0 ? ticksRight / totalTicksRight : 0;
+
+  let change = 0;
+  if (shareLeft > 0) {
+    change = (shareRight - shareLeft) / shareLeft;
+  } else if (shareRight > 0) {
+    // The bar is only in the comparison profile
+    change = 1;
+  }
+
+  // A change of 100% or more gets the most intense color
+  const intensity = Math.min(1, Math.abs(change));
+  const h = change > 0 ? 0 : 120;
+  const s = 80 * intensity;
+  const l = 80 - 20 * intensity;
+
+  return color({ h, s, l });
+}
+
 export function getBarColorByPackage(label: string, theme: GrafanaTheme2) {
   const packageName = getPackageName(label);
   // TODO: similar thing happens in trace view with selecting colors of the spans, so maybe this could be unified.
@@ -69,3 +69,35 @@ describe('nestedSetToLevels', () => {
     expect(levels[1]).toEqual([n2, n3, n4]);
   });
 });
+
+describe('FlameGraphDataContainer', () => {
+  it('reads the values of the comparison profile of a diff flame graph', () => {
+    const frame = createDataFrame({
+      fields: [
+        { name: 'level', values: [0, 1, 1] },
+        { name: 'value', values: [30, 10, 20] },
+        { name: 'self', values: [0, 10, 20] },
+        { name: 'label', values: ['total', 'foo', 'bar'] },
+        { name: 'valueRight', values: [20, 0, 20] },
+        { name: 'selfRight', values: [0, 0, 20] },
+      ],
+    });
+    const container = new FlameGraphDataContainer(frame);
+    expect(container.isDiffFlamegraph()).toBe(true);
+    expect(container.getValueRight(0)).toBe(20);
+    expect(container.getValueRight([1, 2])).toBe(20);
+    expect(container.getSelfRight(2)).toBe(20);
+  });
+
+  it('is not a diff flame graph without comparison values', () => {
+    const frame = createDataFrame({
+      fields: [
+        { name: 'level', values: [0] },
+        { name: 'value', values: [10] },
+        { name: 'self', values: [10] },
+        { name: 'label', values: ['total'] },
+      ],
+    });
+    expect(new FlameGraphDataContainer(frame).isDiffFlamegraph()).toBe(false);
+  });
+});
@@ -75,6 +75,9 @@ export class FlameGraphDataContainer {
   levelField: Field;
   valueField: Field;
   selfField: Field;
+  // Only present in diff flame graphs, where value and self are the sum of the baseline and the comparison profile.
+  valueRightField?: Field;
+  selfRightField?: Field;
 
   labelDisplayProcessor: DisplayProcessor;
   valueDisplayProcessor: DisplayProcessor;
@@ -89,6 +92,8 @@ export class FlameGraphDataContainer {
     this.levelField = data.fields.find((f) => f.name === 'level')!;
     this.valueField = data.fields.find((f) => f.name === 'value')!;
     this.selfField = data.fields.find((f) => f.name === 'self')!;
+    this.valueRightField = data.fields.find((f) => f.name === 'valueRight');
+    this.selfRightField = data.fields.find((f) => f.name === 'selfRight');
 
     if (!(this.labelField && this.levelField && this.valueField && this.selfField)) {
       throw new Error('Malformed dataFrame: value, level and label and self fields are required.');
@@ -145,6 +150,24 @@ export class FlameGraphDataContainer {
     return this.valueDisplayProcessor(this.getSelf(index));
   }
 
+  isDiffFlamegraph() {
+    return Boolean(this.valueRightField && this.selfRightField);
+  }
+
+  getValueRight(index: number | number[]) {
+    let indexArray: number[] = typeof index === 'number' ? [index] : index;
+    return indexArray.reduce((acc, index) => {
+      return acc + (this.valueRightField?.values[index] ?? 0);
+    }, 0);
+  }
+
+  getSelfRight(index: number | number[]) {
+    let indexArray: number[] = typeof index === 'number' ? [index] : index;
+    return indexArray.reduce((acc, index) => {
+      return acc + (this.selfRightField?.values[index] ?? 0);
+    }, 0);
+  }
+
   getUniqueLabels() {
     return this.uniqueLabels;
   }
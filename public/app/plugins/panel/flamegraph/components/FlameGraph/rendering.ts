@@ -14,7 +14,7 @@ import {
 } from '../../constants';
 import { ClickedItemData, ColorScheme, TextAlign } from '../types';
 
-import { getBarColorByPackage, getBarColorByValue } from './colors';
+import { getBarColorByDiff, getBarColorByPackage, getBarColorByValue } from './colors';
 import { FlameGraphDataContainer, LevelItem } from './dataTransform';
 
 const ufuzzy = new uFuzzy();
@@ -58,6 +58,10 @@ export function useFlameRender(
     }
     ctx.clearRect(0, 0, ctx.canvas.width, ctx.canvas.height);
     const pixelsPerTick = (wrapperWidth * window.devicePixelRatio) / totalTicks / (rangeMax - rangeMin);
+    // The first item of the data frame is the root, so it holds the totals of both profiles of a diff flame graph
+    const diffTotals = data.isDiffFlamegraph()
+      ? { left: data.getValue(0) - data.getValueRight(0), right: data.getValueRight(0) }
+      : undefined;
 
     for (let levelIndex = 0; levelIndex < levels.length; levelIndex++) {
       const level = levels[levelIndex];
@@ -78,7 +82,8 @@ export function useFlameRender(
           foundLabels,
           textAlign,
           colorScheme,
-          theme
+          theme,
+          diffTotals
         );
       }
     }
@@ -129,6 +134,8 @@ type RectData = {
   y: number;
   collapsed: boolean;
   ticks: number;
+  // Ticks of the comparison profile, only set for diff flame graphs.
+  ticksRight?: number;
   label: string;
   unitLabel: string;
   itemIndex: number;
@@ -176,6 +183,7 @@ export function getRectDimensionsForLevel(
       y: levelIndex * PIXELS_PER_LEVEL,
       collapsed,
       ticks: curBarTicks,
+      ticksRight: data.isDiffFlamegraph() ? data.getValueRight(item.itemIndexes) : undefined,
       label: data.getLabel(item.itemIndexes[0]),
       unitLabel: unit,
       itemIndex: item.itemIndexes[0],
@@ -195,7 +203,8 @@ export function renderRect(
   foundNames: Set<string> | undefined,
   textAlign: TextAlign,
   colorScheme: ColorScheme,
-  theme: GrafanaTheme2
+  theme: GrafanaTheme2,
+  diffTotals?: { left: number; right: number }
 ) {
   if (rect.width < HIDE_THRESHOLD) {
     return;
@@ -204,10 +213,15 @@ export function renderRect(
   ctx.beginPath();
   ctx.rect(rect.x + (rect.collapsed ? 0 : BAR_BORDER_WIDTH), rect.y, rect.width, rect.height);
 
-  const color =
-    colorScheme === ColorScheme.ValueBased
-      ? getBarColorByValue(rect.ticks, totalTicks, rangeMin, rangeMax)
-      : getBarColorByPackage(rect.label, theme);
+  let color;
+  if (diffTotals && rect.ticksRight !== undefined) {
+    color = getBarColorByDiff(rect.ticks, rect.ticksRight, diffTotals.left, diffTotals.right);
+  } else {
+    color =
+      colorScheme === ColorScheme.ValueBased
+        ? getBarColorByValue(rect.ticks, totalTicks, rangeMin, rangeMax)
+        : getBarColorByPackage(rect.label, theme);
+  }
 
   if (foundNames) {
     // Means we are searching, we use color for matches and gray the rest